@@ -0,0 +1,54 @@
+#!/usr/bin/env bats
+# vim: set ft=sh sw=4 :
+
+load helper_print-info
+
+setup() {
+    # get the containing directory of this file
+    # use $BATS_TEST_FILENAME instead of ${BASH_SOURCE[0]} or $0,
+    # as those will point to the bats executable's location or the preprocessed file respectively
+    DIR="$( cd "$( dirname "$BATS_TEST_FILENAME" )" >/dev/null 2>&1 && pwd )"
+    # make executables in root of the repo visible to PATH
+    PATH="$DIR/../:$PATH"
+}
+
+@test "format & mount loop with btrfs" {
+    run sudo $(command -v ebs-bootstrap) -config /tmp/btrfs-bootstrap.yaml -mode force
+
+    print_run_info
+    [ "$status" -eq 0 ] &&
+    [[ "$output" = *"Successfully formatted /dev/loop"*" to btrfs"* ]] &&
+    [[ "$output" = *"Successfully mounted /dev/loop"*" to /tmp/btrfs"* ]]
+}
+
+@test "label mounted loop with btrfs" {
+    echo """
+---
+devices:
+  $(cat /tmp/loopdev-btrfs):
+    fs: btrfs
+    label: build-cache
+    mountPoint: /tmp/btrfs
+""" > /tmp/btrfs-bootstrap.yaml
+
+    run sudo $(command -v ebs-bootstrap) -config /tmp/btrfs-bootstrap.yaml -mode force
+
+    print_run_info
+    [ "$status" -eq 0 ] &&
+    [[ "$output" = *"Successfully labelled /dev/loop"*" to 'build-cache'"* ]] &&
+    [[ "$output" != *"Successfully unmounted"* ]]
+}
+
+@test "resize mounted loop with btrfs" {
+    run bash -c '
+      truncate -s 256M /tmp/btrfs-loop \
+      && sudo losetup -c $(cat /tmp/loopdev-btrfs)
+    '
+    [ "$status" -eq 0 ]
+
+    run sudo $(command -v ebs-bootstrap) -config /tmp/btrfs-bootstrap.yaml -mode force -resize
+
+    print_run_info
+    [ "$status" -eq 0 ] &&
+    [[ "$output" = *"Successfully resized the btrfs file system of /dev/loop"* ]]
+}
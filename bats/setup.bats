@@ -38,6 +38,19 @@ setup() {
     [[ "$output" = *"/tmp/xfs-loop"* ]]
 }
 
+@test "setup loopback device for btrfs" {
+    run bash -c '
+      dd if=/dev/zero of=/tmp/btrfs-loop bs=4096 count=32768 \
+      && sudo losetup -f /tmp/btrfs-loop \
+      && losetup --associated /tmp/btrfs-loop 2>&1 | tee /tmp/losetup \
+      && grep "/tmp/btrfs-loop" /tmp/losetup | cut -d ':' -f 1 > /tmp/loopdev-btrfs
+    '
+
+    print_run_info
+    [ "$status" -eq 0 ] &&
+    [[ "$output" = *"/tmp/btrfs-loop"* ]]
+}
+
 @test "setup ext4 config" {
     echo """
 ---
@@ -63,3 +76,16 @@ devices:
     run mkdir /tmp/xfs
     [ "$status" -eq 0 ]
 }
+
+@test "setup btrfs config" {
+    echo """
+---
+devices:
+  $(cat /tmp/loopdev-btrfs):
+    fs: btrfs
+    mountPoint: /tmp/btrfs
+""" > /tmp/btrfs-bootstrap.yaml
+
+    run mkdir /tmp/btrfs
+    [ "$status" -eq 0 ]
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -8,6 +9,8 @@ import (
 	"github.com/reecetech/ebs-bootstrap/internal/backend"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
 	"github.com/reecetech/ebs-bootstrap/internal/layer"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/report"
 	"github.com/reecetech/ebs-bootstrap/internal/service"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
@@ -17,30 +20,84 @@ func main() {
 
 	// Services
 	erf := utils.NewExecRunnerFactory()
-	ufs := service.NewUnixFileService()
-	lds := service.NewLinuxDeviceService(erf)
+	var ufs service.FileService = service.NewUnixFileService()
+	var lds service.DeviceService = service.NewSysfsDeviceService(erf)
 	uos := service.NewUnixOwnerService()
 	ans := service.NewAwsNitroNVMeService()
-	ls := service.NewLinuxLvmService(erf)
-	fssf := service.NewLinuxFileSystemServiceFactory(erf)
+	var ls service.LvmService = service.NewLinuxLvmService(erf)
+	var fssf service.FileSystemServiceFactory = service.NewLinuxFileSystemServiceFactory(erf)
+	var lss service.SystemdService = service.NewLinuxSystemdService(erf)
+	var lms service.MdadmService = service.NewLinuxMdadmService(erf)
+	var lcs service.CryptsetupService = service.NewLinuxCryptsetupService(erf)
+	var lws service.SwapService = service.NewLinuxSwapService(erf)
 
 	// Warnings
 	warnings(uos)
 
 	// Config + Flags
 	c, err := config.New(os.Args)
-	checkError(err)
+	checkError(report.NewTextReporter(), err)
+
+	// Reporter
+	r := report.New(c.GetOutput())
+
+	// Plan Mode: Simulate any modifications to the host
+	if c.GetCommand() == model.Plan {
+		s := service.NewSimulation(lds, ufs, ls, lms, lcs, lws)
+		lds = service.NewSimulatedDeviceService(s)
+		ufs = service.NewSimulatedFileService(s)
+		ls = service.NewSimulatedLvmService(s)
+		fssf = service.NewSimulatedFileSystemServiceFactory(s, fssf)
+		lss = service.NewSimulatedSystemdService(s)
+		lms = service.NewSimulatedMdadmService(s)
+		lcs = service.NewSimulatedCryptsetupService(s)
+		lws = service.NewSimulatedSwapService(s)
+	}
 
-	// Services
-	db := backend.NewLinuxDeviceBackend(lds, fssf)
+	// Backends
+	db := backend.NewLinuxDeviceBackend(lds, ans, fssf)
 	fb := backend.NewLinuxFileBackend(ufs)
 	ub := backend.NewLinuxOwnerBackend(uos)
 	dmb := backend.NewLinuxDeviceMetricsBackend(lds, fssf)
+	tb := backend.NewLinuxTuneBackend(lds, fssf)
+	cb := backend.NewLinuxCheckBackend(lds, fssf)
 	lb := backend.NewLinuxLvmBackend(ls)
+	sb := backend.NewLinuxSystemdBackend(ufs, lss)
+	rb := backend.NewLinuxRaidBackend(lds, lms)
+	eb := backend.NewLinuxEncryptionBackend(lds, ans, ufs, lcs)
+	swb := backend.NewLinuxSwapBackend(lws, ufs)
+	stb := backend.NewLinuxStateBackend(lds, ls)
 
 	// Executors
-	dae := action.NewDefaultActionExecutor()
-	le := layer.NewExponentialBackoffLayerExecutor(c, dae, layer.DefaultExponentialBackoffParameters())
+	var le layer.LayerExecutor
+	pae := action.NewPlanActionExecutor()
+	dae := action.NewDefaultActionExecutor(r)
+	if c.GetPromptStyle() == model.BatchPrompt {
+		dae = action.NewBatchActionExecutor(r)
+	}
+	if a := c.GetApprovals(); a != nil {
+		dae = action.NewApprovedActionExecutor(r, a)
+	}
+	if c.GetCommand() == model.Plan {
+		le = layer.NewPlanLayerExecutor(c, pae, r)
+	} else {
+		le = layer.NewExponentialBackoffLayerExecutor(c, dae, r, layer.DefaultExponentialBackoffParameters())
+	}
+
+	// Apply Mode: Execute the actions of a saved plan, but only if the devices
+	// have not drifted from the state that the plan was created from
+	if c.GetCommand() == model.Apply {
+		sp := c.GetSavedPlan()
+		checkError(r, stb.CheckDrift(sp.State))
+		actions, err := action.NewActionDecoder(lds, ufs, fssf, ls, lss, lms, lcs, lws).DecodeAll(sp.Actions)
+		checkError(r, err)
+		checkError(r, dae.Execute(actions))
+		r.Report(&model.Event{Kind: model.PlanApplied, Message: fmt.Sprintf("Applied %d action(s) from the saved plan", len(actions))})
+		return
+	}
+
+	// Instance Store Modifier
+	checkError(r, config.NewInstanceStoreModifier(ans, lds).Modify(c))
 
 	// Validate Config
 	validators := []config.Validator{
@@ -50,13 +107,54 @@ func main() {
 		config.NewMountOptionsValidator(),
 		config.NewOwnerValidator(uos),
 		config.NewLvmConsumptionValidator(),
+		config.NewFstabValidator(),
+		config.NewSystemdMountValidator(),
+		config.NewRaidValidator(),
+		config.NewVolumeGroupValidator(),
+		config.NewLogicalVolumeSizeValidator(),
+		config.NewEncryptionValidator(),
+		config.NewReformatValidator(),
+		config.NewFormatOptionsValidator(fssf),
+		config.NewTuneValidator(fssf),
+		config.NewUuidValidator(),
+		config.NewFsckValidator(),
+		config.NewSwapValidator(),
+		config.NewSelectorValidator(),
+		config.NewMatchValidator(),
+		config.NewInstanceStoreValidator(),
 	}
 	for _, v := range validators {
-		checkError(v.Validate(c))
+		checkError(r, v.Validate(c))
 	}
 
 	// NVMe Device Modifier
-	checkError(config.NewAwsNVMeDriverModifier(ans, lds).Modify(c))
+	checkError(r, config.NewAwsNVMeDriverModifier(ans, lds).Modify(c))
+
+	// Match Modifier
+	checkError(r, config.NewMatchModifier(ans, lds).Modify(c))
+
+	// Consistency Validator: Devices are only compared once their references (e.g.
+	// a block device mapping, selector or match) have been resolved, as two
+	// references can resolve to the same device
+	checkError(r, config.NewConsistencyValidator().Validate(c))
+
+	// Observe the configured devices before any action is planned, so that a
+	// saved plan can detect whether they drift before it is applied
+	var state *model.ObservedState
+	if len(c.GetPlanOutput()) > 0 {
+		state, err = stb.ObserveConfig(c)
+		checkError(r, err)
+	}
+
+	// RAID Layers
+	raidLayers := []layer.Layer{
+		layer.NewCreateRaidArrayLayer(rb),
+		layer.NewAssembleRaidArrayLayer(rb),
+	}
+	checkError(r, le.Execute(raidLayers))
+
+	// RAID Modifiers
+	checkError(r, config.NewRaidModifier().Modify(c))
 
 	// LVM Layers
 	lvmLayers := []layer.Layer{
@@ -67,32 +165,64 @@ func main() {
 		layer.NewActivateLogicalVolumeLayer(lb),
 		layer.NewResizeLogicalVolumeLayer(lb),
 	}
-	checkError(le.Execute(lvmLayers))
+	checkError(r, le.Execute(lvmLayers))
 
 	// LVM Modifiers
-	checkError(config.NewLvmModifier().Modify(c))
+	checkError(r, config.NewLvmModifier().Modify(c))
+
+	// Encryption Layers
+	encryptionLayers := []layer.Layer{
+		layer.NewFormatEncryptedDeviceLayer(eb),
+		layer.NewOpenEncryptedDeviceLayer(eb),
+	}
+	checkError(r, le.Execute(encryptionLayers))
+
+	// Encryption Modifiers
+	checkError(r, config.NewEncryptionModifier().Modify(c))
 
 	// Device Validator
-	checkError(config.NewDeviceValidator(lds).Validate(c))
+	checkError(r, config.NewDeviceValidator(lds).Validate(c))
 
 	// File System Layers
-	layers := []layer.Layer{
+	layers := fileSystemLayers(db, fb, ub, dmb, tb, cb, swb, sb)
+	checkError(r, le.Execute(layers))
+
+	if c.GetCommand() == model.Plan {
+		pae.Print(r)
+		if p := c.GetPlanOutput(); len(p) > 0 {
+			checkError(r, pae.Save(p, state))
+			r.Report(&model.Event{Kind: model.PlanSaved, Message: fmt.Sprintf("Saved plan to %s. Apply it with: ebs-bootstrap apply %s", p, p)})
+		}
+		return
+	}
+	r.Report(&model.Event{Kind: model.ValidationPassed, Message: "Passed all validation checks"})
+}
+
+// A file system is checked directly after it is formatted, so that it is
+// consistent before any other layer writes to it (e.g. a label or UUID)
+func fileSystemLayers(db backend.DeviceBackend, fb backend.FileBackend, ub backend.OwnerBackend, dmb backend.DeviceMetricsBackend, tb backend.TuneBackend, cb backend.CheckBackend, swb backend.SwapBackend, sb backend.SystemdBackend) []layer.Layer {
+	return []layer.Layer{
 		layer.NewFormatDeviceLayer(db),
+		layer.NewCheckFileSystemLayer(db, cb),
 		layer.NewLabelDeviceLayer(db),
+		layer.NewChangeUuidLayer(db),
+		layer.NewTuneFileSystemLayer(db, tb),
 		layer.NewCreateDirectoryLayer(fb),
 		layer.NewMountDeviceLayer(db, fb),
 		layer.NewResizeDeviceLayer(db, dmb),
 		layer.NewChangeOwnerLayer(ub, fb),
 		layer.NewChangePermissionsLayer(fb),
+		layer.NewCreateSwapFileLayer(swb),
+		layer.NewActivateSwapLayer(swb),
+		layer.NewUpdateFstabLayer(db, fb),
+		layer.NewUpdateMountUnitLayer(db, sb),
 	}
-	checkError(le.Execute(layers))
-
-	log.Println("🟢 Passed all validation checks")
 }
 
-func checkError(err error) {
+func checkError(r report.Reporter, err error) {
 	if err != nil {
-		log.Fatal(err)
+		r.Report(&model.Event{Kind: model.RunFailed, Error: err.Error()})
+		os.Exit(1)
 	}
 }
 
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/layer"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestFileSystemLayers(t *testing.T) {
+	expected := []string{
+		"FormatDeviceLayer",
+		"CheckFileSystemLayer",
+		"LabelDeviceLayer",
+		"ChangeUuidLayer",
+		"TuneFileSystemLayer",
+		"CreateDirectoryLayer",
+		"MountDeviceLayer",
+		"ResizeDeviceLayer",
+		"ChangeOwnerLayer",
+		"ChangePermissionsLayer",
+		"CreateSwapFileLayer",
+		"ActivateSwapLayer",
+		"UpdateFstabLayer",
+		"UpdateMountUnitLayer",
+	}
+	names := []string{}
+	for _, l := range fileSystemLayers(nil, nil, nil, nil, nil, nil, nil, nil) {
+		names = append(names, layer.Name(l))
+	}
+	utils.CheckOutput("fileSystemLayers()", t, expected, names)
+}
@@ -1,11 +1,17 @@
 package action
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/report"
 )
 
 type Action interface {
@@ -13,8 +19,48 @@ type Action interface {
 	Success() string
 	Prompt() string
 	Refuse() string
+	Plan() string
 	GetMode() model.Mode
 	SetMode(mode model.Mode) Action
+	// The device, as declared in the configuration, that the action
+	// was generated for. This allows actions that target a path (like
+	// the mount point of a device) to be attributed to a device
+	GetDevice() string
+	SetDevice(device string) Action
+	// A structured representation of the action, for the benefit of
+	// machine-readable output
+	GetKind() model.ActionKind
+	GetParameters() map[string]string
+}
+
+func NewEvent(kind model.EventKind, a Action) *model.Event {
+	return &model.Event{
+		Kind:       kind,
+		Device:     a.GetDevice(),
+		Action:     a.GetKind(),
+		Parameters: a.GetParameters(),
+		Mode:       a.GetMode(),
+	}
+}
+
+// NewFingerprint identifies an action, so that it can be approved in advance
+func NewFingerprint(a Action) model.Fingerprint {
+	return model.Fingerprint{
+		Action:     a.GetKind(),
+		Device:     a.GetDevice(),
+		Parameters: a.GetParameters(),
+	}
+}
+
+// NewPlannedAction serialises an action, so that it can be saved with a plan
+// and rebuilt by an ActionDecoder
+func NewPlannedAction(a Action) *model.PlannedAction {
+	return &model.PlannedAction{
+		Action:     a.GetKind(),
+		Device:     a.GetDevice(),
+		Mode:       a.GetMode(),
+		Parameters: a.GetParameters(),
+	}
 }
 
 type ActionExecutor interface {
@@ -23,20 +69,59 @@ type ActionExecutor interface {
 
 type DefaultActionExecutor struct {
 	read func(buffer *string) error
+	// The actions that were approved in advance. When provided, approval
+	// is never sought from stdin
+	approvals *model.Approvals
+	// Whether the actions in prompt mode are approved together, before
+	// any action is executed
+	batch    bool
+	reporter report.Reporter
 }
 
-func NewDefaultActionExecutor() *DefaultActionExecutor {
+func NewDefaultActionExecutor(r report.Reporter) *DefaultActionExecutor {
 	return &DefaultActionExecutor{
-		read: func(buffer *string) error {
-			_, err := fmt.Scanln(buffer)
+		read:     newLineReader(os.Stdin),
+		reporter: r,
+	}
+}
+
+// NewApprovedActionExecutor creates an executor for unattended use of prompt mode.
+// An action in prompt mode is only executed if its fingerprint was approved
+func NewApprovedActionExecutor(r report.Reporter, approvals *model.Approvals) *DefaultActionExecutor {
+	dae := NewDefaultActionExecutor(r)
+	dae.approvals = approvals
+	return dae
+}
+
+// NewBatchActionExecutor creates an executor that presents every action in prompt
+// mode as a numbered list, and seeks approval for all of them with a single prompt.
+// No action is executed until the prompt is answered
+func NewBatchActionExecutor(r report.Reporter) *DefaultActionExecutor {
+	dae := NewDefaultActionExecutor(r)
+	dae.batch = true
+	return dae
+}
+
+// newLineReader reads a response a line at a time, so that a response can contain
+// spaces (e.g. 1, 3-5). A final line without a newline is still a response
+func newLineReader(r io.Reader) func(buffer *string) error {
+	reader := bufio.NewReader(r)
+	return func(buffer *string) error {
+		line, err := reader.ReadString('\n')
+		if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
 			return err
-		},
+		}
+		*buffer = strings.TrimSpace(line)
+		return nil
 	}
 }
 
 func (dae *DefaultActionExecutor) Execute(actions []Action) error {
+	if dae.batch {
+		return dae.executeBatch(actions)
+	}
 	for _, a := range actions {
-		err := dae.execute(a)
+		err := dae.execute(a, dae.approve)
 		if err != nil {
 			return err
 		}
@@ -44,31 +129,97 @@ func (dae *DefaultActionExecutor) Execute(actions []Action) error {
 	return nil
 }
 
-func (dae *DefaultActionExecutor) execute(action Action) error {
+// executeBatch executes the actions that were selected from a single prompt. An
+// action that was not selected is refused and, as in Execute, no action that follows
+// it is executed. A later action can depend on an earlier one (e.g. a device is only
+// mounted once it is formatted)
+func (dae *DefaultActionExecutor) executeBatch(actions []Action) error {
+	// The positions of the actions in prompt mode
+	prompted := []int{}
+	for i, a := range actions {
+		if a.GetMode() == model.Prompt {
+			prompted = append(prompted, i)
+		}
+	}
+	selected := make([]bool, len(actions))
+	if len(prompted) > 0 {
+		list := make([]Action, len(prompted))
+		for i, p := range prompted {
+			list[i] = actions[p]
+		}
+		indices, err := dae.selectActions(list)
+		if err != nil {
+			return err
+		}
+		for _, i := range indices {
+			selected[prompted[i]] = true
+		}
+	}
+
+	for i, a := range actions {
+		err := dae.execute(a, func(action Action) error {
+			if !selected[i] {
+				return fmt.Errorf("🔴 Action rejected. %s", action.Refuse())
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dae *DefaultActionExecutor) execute(action Action, approve func(action Action) error) error {
+	var err error
 	switch action.GetMode() {
 	case model.Force:
 		break
 	case model.Prompt:
-		if !dae.shouldProceed(action) {
-			return fmt.Errorf("🔴 Action rejected. %s", action.Refuse())
-		}
+		err = approve(action)
 	case model.Healthcheck:
-		return fmt.Errorf("🔴 Healthcheck mode enabled. %s", action.Refuse())
+		err = fmt.Errorf("🔴 Healthcheck mode enabled. %s", action.Refuse())
 	default:
-		return fmt.Errorf("🔴 Unsupported mode was encountered. %s", action.Refuse())
+		err = fmt.Errorf("🔴 Unsupported mode was encountered. %s", action.Refuse())
+	}
+	if err != nil {
+		e := NewEvent(model.ActionRefused, action)
+		e.Error = err.Error()
+		dae.reporter.Report(e)
+		return err
 	}
 
 	if err := action.Execute(); err != nil {
+		e := NewEvent(model.ActionFailed, action)
+		e.Error = err.Error()
+		dae.reporter.Report(e)
 		return err
 	}
-	log.Printf("⭐ %s", action.Success())
+	e := NewEvent(model.ActionExecuted, action)
+	e.Message = action.Success()
+	dae.reporter.Report(e)
+	return nil
+}
+
+func (dae *DefaultActionExecutor) approve(action Action) error {
+	if dae.approvals != nil {
+		if !dae.approvals.IsApproved(NewFingerprint(action)) {
+			return fmt.Errorf("🔴 Action not approved. %s", action.Refuse())
+		}
+		return nil
+	}
+	if !dae.shouldProceed(action) {
+		return fmt.Errorf("🔴 Action rejected. %s", action.Refuse())
+	}
 	return nil
 }
 
 func (dae *DefaultActionExecutor) shouldProceed(action Action) bool {
 	prompt := action.Prompt()
 
-	fmt.Printf("🟣 %s? (y/n): ", prompt)
+	// Prompts are written to stderr, so that they never interleave with the
+	// events written to stdout (e.g. -output=json)
+	fmt.Fprintf(os.Stderr, "🟣 %s? (y/n): ", prompt)
 	var response string
 	err := dae.read(&response)
 	if err != nil {
@@ -81,3 +232,61 @@ func (dae *DefaultActionExecutor) shouldProceed(action Action) bool {
 	}
 	return false
 }
+
+// selectActions lists the actions and returns the indices of the actions that were
+// selected. Failing to read a response is an error, rather than a refusal of every action
+func (dae *DefaultActionExecutor) selectActions(actions []Action) ([]int, error) {
+	fmt.Fprintln(os.Stderr, "🟣 The following actions require approval:")
+	for i, a := range actions {
+		fmt.Fprintf(os.Stderr, "   %d. %s\n", i+1, a.Plan())
+	}
+	fmt.Fprint(os.Stderr, "🟣 Which actions would you like to execute? (all, none or e.g. 1,3-5): ")
+	var response string
+	if err := dae.read(&response); err != nil {
+		return nil, fmt.Errorf("🔴 Failed to read the selected actions: %v", err)
+	}
+	return parseSelection(response, len(actions))
+}
+
+// parseSelection decodes a selection of n actions. A selection is either all, none
+// or a list of action numbers and ranges, separated by commas or spaces (e.g. 1,3-5)
+func parseSelection(s string, n int) ([]int, error) {
+	indices := []int{}
+	switch strings.ToLower(s) {
+	case "all":
+		for i := 0; i < n; i++ {
+			indices = append(indices, i)
+		}
+		return indices, nil
+	case "", "none":
+		return indices, nil
+	}
+	invalid := fmt.Errorf("🔴 Selection '%s' is not valid. Expected all, none or action numbers between 1 and %d (e.g. 1,3-5)", s, n)
+	selected := map[int]bool{}
+	items := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+	for _, item := range items {
+		first, last, isRange := strings.Cut(item, "-")
+		if !isRange {
+			last = first
+		}
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, invalid
+		}
+		end, err := strconv.Atoi(last)
+		if err != nil || start < 1 || end > n || start > end {
+			return nil, invalid
+		}
+		for i := start; i <= end; i++ {
+			selected[i-1] = true
+		}
+	}
+	for i := 0; i < n; i++ {
+		if selected[i] {
+			indices = append(indices, i)
+		}
+	}
+	return indices, nil
+}
@@ -5,6 +5,7 @@ import "github.com/reecetech/ebs-bootstrap/internal/model"
 type MockAction struct {
 	execute func() error
 	mode    model.Mode
+	device  string
 }
 
 func (ma *MockAction) Execute() error {
@@ -23,6 +24,10 @@ func (ma *MockAction) Refuse() string {
 	return "Refused to execute action"
 }
 
+func (ma *MockAction) Plan() string {
+	return "Execute action"
+}
+
 func (ma *MockAction) GetMode() model.Mode {
 	return ma.mode
 }
@@ -31,3 +36,20 @@ func (ma *MockAction) SetMode(mode model.Mode) Action {
 	ma.mode = mode
 	return ma
 }
+
+func (ma *MockAction) GetDevice() string {
+	return ma.device
+}
+
+func (ma *MockAction) SetDevice(device string) Action {
+	ma.device = device
+	return ma
+}
+
+func (ma *MockAction) GetKind() model.ActionKind {
+	return model.ActionKind("mock")
+}
+
+func (ma *MockAction) GetParameters() map[string]string {
+	return map[string]string{}
+}
@@ -2,9 +2,12 @@ package action
 
 import (
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/report"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
 
@@ -27,6 +30,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 		Error         error
 		Mode          model.Mode
 		ExpectedError error
+		ExpectedEvent model.EventKind
 	}{
 		{
 			Name:          "Mode=Empty + Read=Disabled + Action=Success",
@@ -34,6 +38,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         nil,
 			Mode:          model.Empty,
 			ExpectedError: fmt.Errorf("🔴 Unsupported mode was encountered. Refused to execute action"),
+			ExpectedEvent: model.ActionRefused,
 		},
 		{
 			Name:          "Mode=Healthcheck + Read=Avoid + Action=Success",
@@ -41,6 +46,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         nil,
 			Mode:          model.Healthcheck,
 			ExpectedError: fmt.Errorf("🔴 Healthcheck mode enabled. Refused to execute action"),
+			ExpectedEvent: model.ActionRefused,
 		},
 		{
 			Name:          "Mode=Prompt + Read=Input<y> + Action=Success",
@@ -48,6 +54,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         nil,
 			Mode:          model.Prompt,
 			ExpectedError: nil,
+			ExpectedEvent: model.ActionExecuted,
 		},
 		{
 			Name:          "Mode=Prompt + Read=Input<yes> + Action=Success",
@@ -55,6 +62,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         nil,
 			Mode:          model.Prompt,
 			ExpectedError: nil,
+			ExpectedEvent: model.ActionExecuted,
 		},
 		{
 			Name:          "Mode=Prompt + Read=Failure + Action=Success",
@@ -62,6 +70,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         nil,
 			Mode:          model.Prompt,
 			ExpectedError: fmt.Errorf("🔴 Action rejected. Refused to execute action"),
+			ExpectedEvent: model.ActionRefused,
 		},
 		{
 			Name:          "Mode=Prompt + Read=Input<n> + Action=Success",
@@ -69,6 +78,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         nil,
 			Mode:          model.Prompt,
 			ExpectedError: fmt.Errorf("🔴 Action rejected. Refused to execute action"),
+			ExpectedEvent: model.ActionRefused,
 		},
 		{
 			Name:          "Mode=Force + Read=Disabled + Action=Success",
@@ -76,6 +86,7 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         nil,
 			Mode:          model.Force,
 			ExpectedError: nil,
+			ExpectedEvent: model.ActionExecuted,
 		},
 		{
 			Name:          "Mode=Force + Read=Disabled + Action=Failure",
@@ -83,18 +94,311 @@ func TestDefaultActionExecutor(t *testing.T) {
 			Error:         fmt.Errorf("🔴 Error encountered while executing action"),
 			Mode:          model.Force,
 			ExpectedError: fmt.Errorf("🔴 Error encountered while executing action"),
+			ExpectedEvent: model.ActionFailed,
 		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
+			mr := report.NewMockReporter()
 			dae := &DefaultActionExecutor{
-				read: subtest.Read,
+				read:     subtest.Read,
+				reporter: mr,
 			}
 			a := (&MockAction{
 				execute: func() error { return subtest.Error },
 			})
 			err := dae.Execute([]Action{a.SetMode(subtest.Mode)})
 			utils.CheckError("dae.Execute()", t, subtest.ExpectedError, err)
+
+			events := []model.EventKind{}
+			for _, e := range mr.Events {
+				events = append(events, e.Kind)
+			}
+			utils.CheckOutput("mr.Events", t, []model.EventKind{subtest.ExpectedEvent}, events)
+		})
+	}
+}
+
+func TestApprovedActionExecutor(t *testing.T) {
+	approvals := model.NewApprovals(model.Fingerprint{
+		Action: "mock",
+		Device: "/dev/xvdf",
+	})
+	subtests := []struct {
+		Name          string
+		Device        string
+		Mode          model.Mode
+		ExpectedError error
+		ExpectedEvent model.EventKind
+	}{
+		{
+			Name:          "Mode=Prompt + Approved",
+			Device:        "/dev/xvdf",
+			Mode:          model.Prompt,
+			ExpectedError: nil,
+			ExpectedEvent: model.ActionExecuted,
+		},
+		{
+			Name:          "Mode=Prompt + Not Approved",
+			Device:        "/dev/xvdg",
+			Mode:          model.Prompt,
+			ExpectedError: fmt.Errorf("🔴 Action not approved. Refused to execute action"),
+			ExpectedEvent: model.ActionRefused,
+		},
+		{
+			Name:          "Mode=Healthcheck + Approved",
+			Device:        "/dev/xvdf",
+			Mode:          model.Healthcheck,
+			ExpectedError: fmt.Errorf("🔴 Healthcheck mode enabled. Refused to execute action"),
+			ExpectedEvent: model.ActionRefused,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mr := report.NewMockReporter()
+			dae := NewApprovedActionExecutor(mr, approvals)
+			// Approval must never be sought from stdin
+			dae.read = func(buffer *string) error {
+				t.Errorf("dae.read() was called")
+				return fmt.Errorf("🔴 Standard Input Disabled")
+			}
+			a := (&MockAction{
+				execute: func() error { return nil },
+			})
+			err := dae.Execute([]Action{a.SetMode(subtest.Mode).SetDevice(subtest.Device)})
+			utils.CheckError("dae.Execute()", t, subtest.ExpectedError, err)
+
+			events := []model.EventKind{}
+			for _, e := range mr.Events {
+				events = append(events, e.Kind)
+			}
+			utils.CheckOutput("mr.Events", t, []model.EventKind{subtest.ExpectedEvent}, events)
+		})
+	}
+}
+
+func TestBatchActionExecutor(t *testing.T) {
+	subtests := []struct {
+		Name             string
+		Modes            []model.Mode
+		Input            string
+		ExpectedExecuted []string
+		ExpectedError    error
+		ExpectedEvents   []model.EventKind
+	}{
+		{
+			Name:             "Select All",
+			Modes:            []model.Mode{model.Prompt, model.Prompt, model.Prompt},
+			Input:            "all",
+			ExpectedExecuted: []string{"/dev/xvdf", "/dev/xvdg", "/dev/xvdh"},
+			ExpectedError:    nil,
+			ExpectedEvents:   []model.EventKind{model.ActionExecuted, model.ActionExecuted, model.ActionExecuted},
+		},
+		{
+			Name:             "Select None",
+			Modes:            []model.Mode{model.Prompt, model.Prompt},
+			Input:            "none",
+			ExpectedExecuted: []string{},
+			ExpectedError:    fmt.Errorf("🔴 Action rejected. Refused to execute action"),
+			ExpectedEvents:   []model.EventKind{model.ActionRefused},
+		},
+		{
+			Name:             "Select Subset",
+			Modes:            []model.Mode{model.Prompt, model.Prompt, model.Prompt},
+			Input:            "1, 3",
+			ExpectedExecuted: []string{"/dev/xvdf"},
+			ExpectedError:    fmt.Errorf("🔴 Action rejected. Refused to execute action"),
+			ExpectedEvents:   []model.EventKind{model.ActionExecuted, model.ActionRefused},
+		},
+		{
+			// A later action can depend on an earlier action that was not selected
+			Name:             "Select Later Action Only",
+			Modes:            []model.Mode{model.Prompt, model.Prompt},
+			Input:            "2",
+			ExpectedExecuted: []string{},
+			ExpectedError:    fmt.Errorf("🔴 Action rejected. Refused to execute action"),
+			ExpectedEvents:   []model.EventKind{model.ActionRefused},
+		},
+		{
+			Name:             "Force Mode Is Not Prompted",
+			Modes:            []model.Mode{model.Force, model.Prompt},
+			Input:            "1",
+			ExpectedExecuted: []string{"/dev/xvdf", "/dev/xvdg"},
+			ExpectedError:    nil,
+			ExpectedEvents:   []model.EventKind{model.ActionExecuted, model.ActionExecuted},
+		},
+		{
+			Name:             "Healthcheck Mode Is Refused",
+			Modes:            []model.Mode{model.Healthcheck, model.Prompt},
+			Input:            "all",
+			ExpectedExecuted: []string{},
+			ExpectedError:    fmt.Errorf("🔴 Healthcheck mode enabled. Refused to execute action"),
+			ExpectedEvents:   []model.EventKind{model.ActionRefused},
+		},
+		{
+			Name:             "Read Failure",
+			Modes:            []model.Mode{model.Prompt, model.Prompt},
+			Input:            "",
+			ExpectedExecuted: []string{},
+			ExpectedError:    fmt.Errorf("🔴 Failed to read the selected actions: EOF"),
+			ExpectedEvents:   []model.EventKind{},
+		},
+		{
+			Name:             "Invalid Selection",
+			Modes:            []model.Mode{model.Prompt, model.Prompt},
+			Input:            "1-3",
+			ExpectedExecuted: []string{},
+			ExpectedError:    fmt.Errorf("🔴 Selection '1-3' is not valid. Expected all, none or action numbers between 1 and 2 (e.g. 1,3-5)"),
+			ExpectedEvents:   []model.EventKind{},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mr := report.NewMockReporter()
+			dae := NewBatchActionExecutor(mr)
+			reads := 0
+			read := newLineReader(strings.NewReader(subtest.Input))
+			dae.read = func(buffer *string) error {
+				reads++
+				return read(buffer)
+			}
+			executed := []string{}
+			actions := []Action{}
+			for i, mode := range subtest.Modes {
+				device := fmt.Sprintf("/dev/xvd%c", 'f'+i)
+				a := &MockAction{
+					execute: func() error {
+						executed = append(executed, device)
+						return nil
+					},
+				}
+				actions = append(actions, a.SetMode(mode).SetDevice(device))
+			}
+			err := dae.Execute(actions)
+			utils.CheckError("dae.Execute()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("executed", t, subtest.ExpectedExecuted, executed)
+			// Approval is sought with a single prompt
+			utils.CheckOutput("reads", t, 1, reads)
+
+			events := []model.EventKind{}
+			for _, e := range mr.Events {
+				events = append(events, e.Kind)
+			}
+			utils.CheckOutput("mr.Events", t, subtest.ExpectedEvents, events)
+		})
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Selection      string
+		ExpectedOutput []int
+		ExpectedError  error
+	}{
+		{
+			Name:           "All",
+			Selection:      "ALL",
+			ExpectedOutput: []int{0, 1, 2, 3, 4},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "None",
+			Selection:      "none",
+			ExpectedOutput: []int{},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Empty",
+			Selection:      "",
+			ExpectedOutput: []int{},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Numbers and Ranges",
+			Selection:      "3-5,1",
+			ExpectedOutput: []int{0, 2, 3, 4},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Overlapping Ranges",
+			Selection:      "1-3,2-4",
+			ExpectedOutput: []int{0, 1, 2, 3},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Separated By Spaces",
+			Selection:      "1, 3-5",
+			ExpectedOutput: []int{0, 2, 3, 4},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Separated By Spaces Only",
+			Selection:      "1 3",
+			ExpectedOutput: []int{0, 2},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Out of Range",
+			Selection:      "0",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Selection '0' is not valid. Expected all, none or action numbers between 1 and 5 (e.g. 1,3-5)"),
+		},
+		{
+			Name:           "Reversed Range",
+			Selection:      "4-2",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Selection '4-2' is not valid. Expected all, none or action numbers between 1 and 5 (e.g. 1,3-5)"),
+		},
+		{
+			Name:           "Not a Number",
+			Selection:      "yes",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Selection 'yes' is not valid. Expected all, none or action numbers between 1 and 5 (e.g. 1,3-5)"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			indices, err := parseSelection(subtest.Selection, 5)
+			utils.CheckError("parseSelection()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("parseSelection()", t, subtest.ExpectedOutput, indices)
+		})
+	}
+}
+
+func TestNewLineReader(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Input          string
+		ExpectedOutput string
+		ExpectedError  error
+	}{
+		{
+			Name:           "Line With Spaces",
+			Input:          " 1, 3-5 \n2\n",
+			ExpectedOutput: "1, 3-5",
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Line Without Newline",
+			Input:          "1 3",
+			ExpectedOutput: "1 3",
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "No Input",
+			Input:          "",
+			ExpectedOutput: "",
+			ExpectedError:  io.EOF,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			read := newLineReader(strings.NewReader(subtest.Input))
+			var response string
+			err := read(&response)
+			utils.CheckError("read()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("read()", t, subtest.ExpectedOutput, response)
 		})
 	}
 }
@@ -0,0 +1,279 @@
+package action
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+// ActionDecoder rebuilds an action from its serialised form (see NewPlannedAction).
+// A rebuilt action is bound to the services of the decoder, rather than to the
+// services of the plan that it was serialised from
+type ActionDecoder struct {
+	deviceService            service.DeviceService
+	fileService              service.FileService
+	fileSystemServiceFactory service.FileSystemServiceFactory
+	lvmService               service.LvmService
+	systemdService           service.SystemdService
+	mdadmService             service.MdadmService
+	cryptsetupService        service.CryptsetupService
+	swapService              service.SwapService
+}
+
+func NewActionDecoder(ds service.DeviceService, fs service.FileService, fssf service.FileSystemServiceFactory, ls service.LvmService, ss service.SystemdService, ms service.MdadmService, cs service.CryptsetupService, sws service.SwapService) *ActionDecoder {
+	return &ActionDecoder{
+		deviceService:            ds,
+		fileService:              fs,
+		fileSystemServiceFactory: fssf,
+		lvmService:               ls,
+		systemdService:           ss,
+		mdadmService:             ms,
+		cryptsetupService:        cs,
+		swapService:              sws,
+	}
+}
+
+func (ad *ActionDecoder) Decode(pa *model.PlannedAction) (Action, error) {
+	p := &parameters{values: pa.Parameters}
+	a, err := ad.decode(pa.Action, p)
+	if err == nil {
+		err = p.err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: Failed to rebuild %s action. %s", pa.Device, pa.Action, err)
+	}
+	return a.SetMode(pa.Mode).SetDevice(pa.Device), nil
+}
+
+// DecodeAll rebuilds the actions of a saved plan, in their order of execution
+func (ad *ActionDecoder) DecodeAll(pas []*model.PlannedAction) ([]Action, error) {
+	actions := make([]Action, 0, len(pas))
+	for _, pa := range pas {
+		a, err := ad.Decode(pa)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+func (ad *ActionDecoder) decode(kind model.ActionKind, p *parameters) (Action, error) {
+	switch kind {
+	case model.CreateDirectoryAction:
+		return NewCreateDirectoryAction(p.get("path"), ad.fileService), nil
+	case model.ChangeOwnerAction:
+		uid := model.UserId(p.getUint("uid", 32))
+		gid := model.GroupId(p.getUint("gid", 32))
+		return NewChangeOwnerAction(p.get("path"), uid, gid, ad.fileService), nil
+	case model.ChangePermissionsAction:
+		// Permissions are described in octal (e.g. 0755)
+		perms, err := strconv.ParseUint(p.get("perms"), 0, 32)
+		p.check("perms", err)
+		return NewChangePermissionsAction(p.get("path"), model.FilePermissions(perms), ad.fileService), nil
+	case model.FormatAction:
+		fss, err := ad.getFileSystemService(p)
+		if err != nil {
+			return nil, err
+		}
+		options, err := model.ParseFormatOptions(p.get("options"))
+		if err != nil {
+			return nil, err
+		}
+		options.Label = p.values["label"]
+		return NewFormatDeviceAction(p.get("device"), options, fss), nil
+	case model.WipeAction:
+		signatures, err := model.ParseSignatures(p.get("signatures"))
+		if err != nil {
+			return nil, err
+		}
+		return NewWipeDeviceAction(p.get("device"), signatures, ad.deviceService), nil
+	case model.ReformatAction:
+		fss, err := ad.getFileSystemService(p)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := model.ParseFileSystem(p.get("existingFileSystem"))
+		if err != nil {
+			return nil, err
+		}
+		options, err := model.ParseFormatOptions(p.get("options"))
+		if err != nil {
+			return nil, err
+		}
+		options.Label = p.values["label"]
+		return NewReformatDeviceAction(p.get("device"), existing, options, ad.deviceService, fss), nil
+	case model.LabelAction:
+		fss, err := ad.getFileSystemService(p)
+		if err != nil {
+			return nil, err
+		}
+		return NewLabelDeviceAction(p.get("device"), p.get("label"), fss), nil
+	case model.TuneAction:
+		fss, err := ad.getFileSystemService(p)
+		if err != nil {
+			return nil, err
+		}
+		properties, err := model.ParseFileSystemProperties(p.get("properties"))
+		if err != nil {
+			return nil, err
+		}
+		return NewTuneFileSystemAction(p.get("device"), properties, fss), nil
+	case model.ChangeUuidAction:
+		fss, err := ad.getFileSystemService(p)
+		if err != nil {
+			return nil, err
+		}
+		return NewChangeUuidAction(p.get("device"), p.get("uuid"), fss), nil
+	case model.RepairFileSystemAction:
+		fss, err := ad.getFileSystemService(p)
+		if err != nil {
+			return nil, err
+		}
+		return NewRepairFileSystemAction(p.get("device"), fss), nil
+	case model.MountAction:
+		fs, err := model.ParseFileSystem(p.get("fileSystem"))
+		if err != nil {
+			return nil, err
+		}
+		options := model.MountOptions(p.get("options"))
+		return NewMountDeviceAction(p.get("source"), p.get("target"), fs, options, ad.deviceService), nil
+	case model.UnmountAction:
+		return NewUnmountDeviceAction(p.get("source"), p.get("target"), ad.deviceService), nil
+	case model.ResizeAction:
+		fss, err := ad.getFileSystemService(p)
+		if err != nil {
+			return nil, err
+		}
+		return NewResizeDeviceAction(p.get("device"), p.get("target"), fss), nil
+	case model.CreatePhysicalVolumeAction:
+		return NewCreatePhysicalVolumeAction(p.get("name"), ad.lvmService), nil
+	case model.CreateVolumeGroupAction:
+		return NewCreateVolumeGroupAction(p.get("name"), p.get("physicalVolume"), ad.lvmService), nil
+	case model.ExtendVolumeGroupAction:
+		return NewExtendVolumeGroupAction(p.get("name"), p.get("physicalVolume"), ad.lvmService), nil
+	case model.CreateLogicalVolumeAction:
+		size, err := model.ParseLvmSize(p.get("size"))
+		if err != nil {
+			return nil, err
+		}
+		return NewCreateLogicalVolumeAction(p.get("name"), *size, p.get("volumeGroup"), ad.lvmService), nil
+	case model.ActivateLogicalVolumeAction:
+		return NewActivateLogicalVolumeAction(p.get("name"), p.get("volumeGroup"), ad.lvmService), nil
+	case model.ResizePhysicalVolumeAction:
+		return NewResizePhysicalVolumeAction(p.get("name"), ad.lvmService), nil
+	case model.ResizeLogicalVolumeAction:
+		size, err := model.ParseLvmSize(p.get("size"))
+		if err != nil {
+			return nil, err
+		}
+		return NewResizeLogicalVolumeAction(p.get("name"), *size, p.get("volumeGroup"), ad.lvmService), nil
+	case model.UpdateFstabEntryAction:
+		fs, err := model.ParseFileSystem(p.get("fileSystem"))
+		if err != nil {
+			return nil, err
+		}
+		entry := &model.FstabEntry{
+			Source:     p.get("source"),
+			MountPoint: p.get("mountPoint"),
+			FileSystem: fs,
+			Options:    model.MountOptions(p.get("options")),
+			Dump:       uint(p.getUint("dump", 32)),
+			Pass:       uint(p.getUint("pass", 32)),
+		}
+		return NewUpdateFstabEntryAction(p.get("path"), entry, ad.fileService), nil
+	case model.UpdateMountUnitAction:
+		fs, err := model.ParseFileSystem(p.get("fileSystem"))
+		if err != nil {
+			return nil, err
+		}
+		unit := &model.MountUnit{
+			What:       p.get("what"),
+			Where:      p.get("where"),
+			Type:       fs,
+			Options:    model.MountOptions(p.get("options")),
+			RequiredBy: strings.Fields(p.get("requiredBy")),
+		}
+		return NewUpdateMountUnitAction(p.get("path"), unit, ad.fileService, ad.systemdService), nil
+	case model.CreateRaidArrayAction:
+		level, err := model.ParseRaidLevel(p.get("level"))
+		if err != nil {
+			return nil, err
+		}
+		chunkSize := p.getUint("chunkSize", 64)
+		return NewCreateRaidArrayAction(p.get("name"), level, chunkSize, p.getList("devices"), ad.mdadmService), nil
+	case model.AssembleRaidArrayAction:
+		return NewAssembleRaidArrayAction(p.get("name"), p.getList("devices"), ad.mdadmService), nil
+	case model.FormatEncryptedDeviceAction:
+		ephemeral, err := strconv.ParseBool(p.get("ephemeral"))
+		p.check("ephemeral", err)
+		return NewFormatEncryptedDeviceAction(p.get("device"), p.get("keyFile"), ephemeral, ad.cryptsetupService), nil
+	case model.ReformatEncryptedDeviceAction:
+		return NewReformatEncryptedDeviceAction(p.get("device"), p.get("keyFile"), ad.cryptsetupService), nil
+	case model.OpenEncryptedDeviceAction:
+		return NewOpenEncryptedDeviceAction(p.get("device"), p.get("name"), p.get("keyFile"), ad.cryptsetupService), nil
+	case model.CreateSwapFileAction:
+		size, err := model.ParseByteSize(p.get("size"))
+		p.check("size", err)
+		return NewCreateSwapFileAction(p.get("path"), size, ad.swapService), nil
+	case model.ActivateSwapAction:
+		var priority *int
+		if value, found := p.values["priority"]; found {
+			pr, err := strconv.Atoi(value)
+			p.check("priority", err)
+			priority = &pr
+		}
+		return NewActivateSwapAction(p.get("name"), priority, ad.swapService), nil
+	case model.DeactivateSwapAction:
+		return NewDeactivateSwapAction(p.get("name"), ad.swapService), nil
+	default:
+		return nil, fmt.Errorf("Action is not supported")
+	}
+}
+
+// The file system service of an action is selected by its file system parameter
+func (ad *ActionDecoder) getFileSystemService(p *parameters) (service.FileSystemService, error) {
+	fs, err := model.ParseFileSystem(p.get("fileSystem"))
+	if err != nil {
+		return nil, err
+	}
+	return ad.fileSystemServiceFactory.Select(fs)
+}
+
+// parameters retains the first parameter that was either missing or invalid, so
+// that an action can be decoded without checking each parameter in turn
+type parameters struct {
+	values map[string]string
+	err    error
+}
+
+func (p *parameters) get(key string) string {
+	value, found := p.values[key]
+	if !found && p.err == nil {
+		p.err = fmt.Errorf("Parameter '%s' is missing", key)
+	}
+	return value
+}
+
+func (p *parameters) getUint(key string, bitSize int) uint64 {
+	value, err := strconv.ParseUint(p.get(key), 10, bitSize)
+	p.check(key, err)
+	return value
+}
+
+func (p *parameters) getList(key string) []string {
+	value := p.get(key)
+	if len(value) == 0 {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+func (p *parameters) check(key string, err error) {
+	if err != nil && p.err == nil {
+		p.err = fmt.Errorf("Parameter '%s' has an invalid value '%s'", key, p.values[key])
+	}
+}
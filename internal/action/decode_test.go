@@ -0,0 +1,155 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestActionDecoderRoundTrip(t *testing.T) {
+	reserved := uint64(1)
+	lazy := false
+	maxMountCount := int64(-1)
+	priority := 10
+	ext4 := service.NewExt4Service(nil)
+	xfs := service.NewXfsService(nil)
+	actions := []Action{
+		NewCreateDirectoryAction("/mnt/app", nil),
+		NewChangeOwnerAction("/mnt/app", 1000, 1001, nil),
+		NewChangePermissionsAction("/mnt/app", 0755, nil),
+		NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{BlockSize: 4096, ReservedBlocksPercent: &reserved, LazyItableInit: &lazy, Label: "stateful"}, ext4),
+		NewFormatDeviceAction("/dev/xvdf", nil, xfs),
+		NewWipeDeviceAction("/dev/xvdf", []*model.Signature{{Type: "gpt", Offset: 0x200}, {Type: "PMBR", Offset: 0x1fe}}, nil),
+		NewReformatDeviceAction("/dev/xvdf", model.Ext4, &model.FormatOptions{StripeUnit: 64, StripeWidth: 2}, nil, xfs),
+		NewLabelDeviceAction("/dev/xvdf", "stateful", ext4),
+		NewTuneFileSystemAction("/dev/xvdf", &model.FileSystemProperties{MaxMountCount: &maxMountCount, Errors: model.ErrorsRemountRo, Features: []string{"metadata_csum", "extent"}}, ext4),
+		NewChangeUuidAction("/dev/xvdf", "5ac8c5b4-4d5b-4e6e-9ea0-0c6e84b7a3d5", ext4),
+		NewRepairFileSystemAction("/dev/xvdf", ext4),
+		NewMountDeviceAction("/dev/xvdf", "/mnt/app", model.Ext4, "defaults,nofail", nil),
+		NewUnmountDeviceAction("/dev/xvdf", "/mnt/app", nil),
+		NewResizeDeviceAction("/dev/xvdf", "/mnt/app", xfs),
+		NewCreatePhysicalVolumeAction("/dev/xvdf", nil),
+		NewCreateVolumeGroupAction("vg", "/dev/xvdf", nil),
+		NewExtendVolumeGroupAction("vg", "/dev/xvdg", nil),
+		NewCreateLogicalVolumeAction("lv", model.LvmSize{Value: 80, Unit: model.VolumeGroupPercent}, "vg", nil),
+		NewActivateLogicalVolumeAction("lv", "vg", nil),
+		NewResizePhysicalVolumeAction("/dev/xvdf", nil),
+		NewResizeLogicalVolumeAction("lv", model.LvmSize{Value: 20 << 30, Unit: model.Bytes}, "vg", nil),
+		NewUpdateFstabEntryAction("/etc/fstab", &model.FstabEntry{Source: "UUID=5ac8c5b4", MountPoint: "/mnt/app", FileSystem: model.Ext4, Options: "defaults", Dump: 0, Pass: 2}, nil),
+		NewUpdateMountUnitAction("/etc/systemd/system/mnt-app.mount", &model.MountUnit{What: "/dev/xvdf", Where: "/mnt/app", Type: model.Xfs, Options: "defaults", RequiredBy: []string{"app.service", "db.service"}}, nil, nil),
+		NewCreateRaidArrayAction("/dev/md0", model.Raid0, 512, []string{"/dev/xvdf", "/dev/xvdg"}, nil),
+		NewAssembleRaidArrayAction("/dev/md0", []string{"/dev/xvdf", "/dev/xvdg"}, nil),
+		NewFormatEncryptedDeviceAction("/dev/xvdf", "/etc/luks/key", true, nil),
+		NewReformatEncryptedDeviceAction("/dev/xvdf", "/run/ebs-bootstrap/keys/app.key", nil),
+		NewOpenEncryptedDeviceAction("/dev/xvdf", "app", "/etc/luks/key", nil),
+		NewCreateSwapFileAction("/swapfile", 4<<30, nil),
+		NewActivateSwapAction("/swapfile", &priority, nil),
+		NewActivateSwapAction("/dev/xvdh", nil, nil),
+		NewDeactivateSwapAction("/dev/xvdh", nil),
+	}
+	ad := NewActionDecoder(nil, nil, service.NewLinuxFileSystemServiceFactory(nil), nil, nil, nil, nil, nil)
+	for _, a := range actions {
+		a := a.SetMode(model.Prompt).SetDevice("/dev/xvdf")
+		t.Run(string(a.GetKind()), func(t *testing.T) {
+			// Actions are rebuilt from their JSON form, as they would be when saved
+			data, err := json.Marshal(NewPlannedAction(a))
+			utils.CheckError("json.Marshal()", t, nil, err)
+			var pa model.PlannedAction
+			utils.CheckError("json.Unmarshal()", t, nil, json.Unmarshal(data, &pa))
+
+			d, err := ad.Decode(&pa)
+			utils.CheckError("ad.Decode()", t, nil, err)
+			if err != nil {
+				return
+			}
+			utils.CheckOutput("d.GetKind()", t, a.GetKind(), d.GetKind())
+			utils.CheckOutput("d.GetMode()", t, a.GetMode(), d.GetMode())
+			utils.CheckOutput("d.GetDevice()", t, a.GetDevice(), d.GetDevice())
+			utils.CheckOutput("d.GetParameters()", t, a.GetParameters(), d.GetParameters())
+			utils.CheckOutput("d.Plan()", t, a.Plan(), d.Plan())
+		})
+	}
+}
+
+func TestActionDecoderErrors(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		PlannedAction *model.PlannedAction
+		ExpectedError error
+	}{
+		{
+			Name: "Unsupported Action",
+			PlannedAction: &model.PlannedAction{
+				Action: model.ActionKind("defragment"),
+				Device: "/dev/xvdf",
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed to rebuild defragment action. Action is not supported"),
+		},
+		{
+			Name: "Missing Parameter",
+			PlannedAction: &model.PlannedAction{
+				Action:     model.CreateDirectoryAction,
+				Device:     "/dev/xvdf",
+				Parameters: map[string]string{},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed to rebuild create-directory action. Parameter 'path' is missing"),
+		},
+		{
+			Name: "Invalid Parameter",
+			PlannedAction: &model.PlannedAction{
+				Action:     model.ChangeOwnerAction,
+				Device:     "/dev/xvdf",
+				Parameters: map[string]string{"path": "/mnt/app", "uid": "root", "gid": "0"},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed to rebuild change-owner action. Parameter 'uid' has an invalid value 'root'"),
+		},
+		{
+			Name: "Invalid Format Options",
+			PlannedAction: &model.PlannedAction{
+				Action:     model.FormatAction,
+				Device:     "/dev/xvdf",
+				Parameters: map[string]string{"device": "/dev/xvdf", "fileSystem": "ext4", "options": "blockSize=large"},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed to rebuild format action. Format option 'blockSize' has an invalid value 'large'"),
+		},
+		{
+			Name: "Unsupported File System",
+			PlannedAction: &model.PlannedAction{
+				Action:     model.LabelAction,
+				Device:     "/dev/xvdf",
+				Parameters: map[string]string{"device": "/dev/xvdf", "label": "stateful", "fileSystem": "LVM2_member"},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed to rebuild label action. A Physical Volume cannot be queried/modified"),
+		},
+	}
+	ad := NewActionDecoder(nil, nil, service.NewLinuxFileSystemServiceFactory(nil), nil, nil, nil, nil, nil)
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			_, err := ad.Decode(subtest.PlannedAction)
+			utils.CheckError("ad.Decode()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestActionDecoderDecodeAll(t *testing.T) {
+	ds := service.NewMockDeviceService()
+	ds.StubMount = func(source, target string, fs model.FileSystem, options model.MountOptions) error {
+		return nil
+	}
+	ad := NewActionDecoder(ds, nil, service.NewLinuxFileSystemServiceFactory(nil), nil, nil, nil, nil, nil)
+	actions, err := ad.DecodeAll([]*model.PlannedAction{
+		{
+			Action:     model.MountAction,
+			Device:     "/dev/xvdf",
+			Mode:       model.Force,
+			Parameters: map[string]string{"source": "/dev/xvdf", "target": "/mnt/app", "fileSystem": "ext4", "options": "defaults"},
+		},
+	})
+	utils.CheckError("ad.DecodeAll()", t, nil, err)
+	utils.CheckOutput("len(actions)", t, 1, len(actions))
+	utils.CheckError("actions[0].Execute()", t, nil, actions[0].Execute())
+}
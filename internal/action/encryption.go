@@ -0,0 +1,224 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type FormatEncryptedDeviceAction struct {
+	device            string
+	keyFile           string
+	ephemeral         bool
+	mode              model.Mode
+	configDevice      string
+	cryptsetupService service.CryptsetupService
+}
+
+func NewFormatEncryptedDeviceAction(device string, keyFile string, ephemeral bool, cs service.CryptsetupService) *FormatEncryptedDeviceAction {
+	return &FormatEncryptedDeviceAction{
+		device:            device,
+		keyFile:           keyFile,
+		ephemeral:         ephemeral,
+		mode:              model.Empty,
+		cryptsetupService: cs,
+	}
+}
+
+// An ephemeral key is generated immediately before the device is formatted, so
+// that a key is never generated for a device that is left unencrypted
+func (a *FormatEncryptedDeviceAction) Execute() error {
+	if a.ephemeral {
+		if err := a.cryptsetupService.CreateEphemeralKey(a.keyFile); err != nil {
+			return err
+		}
+	}
+	return a.cryptsetupService.Format(a.device, a.keyFile)
+}
+
+func (a *FormatEncryptedDeviceAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *FormatEncryptedDeviceAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *FormatEncryptedDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *FormatEncryptedDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *FormatEncryptedDeviceAction) GetKind() model.ActionKind {
+	return model.FormatEncryptedDeviceAction
+}
+
+func (a *FormatEncryptedDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":    a.device,
+		"keyFile":   a.keyFile,
+		"ephemeral": fmt.Sprint(a.ephemeral),
+	}
+}
+
+func (a *FormatEncryptedDeviceAction) Prompt() string {
+	return fmt.Sprintf("Would you like to format %s as a LUKS device with key %s", a.device, a.keyFile)
+}
+
+func (a *FormatEncryptedDeviceAction) Refuse() string {
+	return fmt.Sprintf("Refused to format %s as a LUKS device with key %s", a.device, a.keyFile)
+}
+
+func (a *FormatEncryptedDeviceAction) Success() string {
+	return fmt.Sprintf("Successfully formatted %s as a LUKS device with key %s", a.device, a.keyFile)
+}
+
+func (a *FormatEncryptedDeviceAction) Plan() string {
+	return fmt.Sprintf("Format %s as a LUKS device with key %s", a.device, a.keyFile)
+}
+
+// ReformatEncryptedDeviceAction destroys a LUKS device whose ephemeral key has been
+// lost, by formatting it again with a new ephemeral key
+type ReformatEncryptedDeviceAction struct {
+	device            string
+	keyFile           string
+	mode              model.Mode
+	configDevice      string
+	cryptsetupService service.CryptsetupService
+}
+
+func NewReformatEncryptedDeviceAction(device string, keyFile string, cs service.CryptsetupService) *ReformatEncryptedDeviceAction {
+	return &ReformatEncryptedDeviceAction{
+		device:            device,
+		keyFile:           keyFile,
+		mode:              model.Empty,
+		cryptsetupService: cs,
+	}
+}
+
+func (a *ReformatEncryptedDeviceAction) Execute() error {
+	if err := a.cryptsetupService.CreateEphemeralKey(a.keyFile); err != nil {
+		return err
+	}
+	return a.cryptsetupService.Format(a.device, a.keyFile)
+}
+
+func (a *ReformatEncryptedDeviceAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *ReformatEncryptedDeviceAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *ReformatEncryptedDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ReformatEncryptedDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ReformatEncryptedDeviceAction) GetKind() model.ActionKind {
+	return model.ReformatEncryptedDeviceAction
+}
+
+func (a *ReformatEncryptedDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":  a.device,
+		"keyFile": a.keyFile,
+	}
+}
+
+func (a *ReformatEncryptedDeviceAction) Prompt() string {
+	return fmt.Sprintf("⚠️ Would you like to DESTROY the existing LUKS device %s, and all of its data, and format it again with a new ephemeral key %s", a.device, a.keyFile)
+}
+
+func (a *ReformatEncryptedDeviceAction) Refuse() string {
+	return fmt.Sprintf("Refused to reformat LUKS device %s with a new ephemeral key %s", a.device, a.keyFile)
+}
+
+func (a *ReformatEncryptedDeviceAction) Success() string {
+	return fmt.Sprintf("Successfully reformatted LUKS device %s with a new ephemeral key %s. All data on the existing LUKS device was destroyed", a.device, a.keyFile)
+}
+
+func (a *ReformatEncryptedDeviceAction) Plan() string {
+	return fmt.Sprintf("⚠️ DESTROY the existing LUKS device %s, and all of its data, and format it again with a new ephemeral key %s", a.device, a.keyFile)
+}
+
+type OpenEncryptedDeviceAction struct {
+	device            string
+	name              string
+	keyFile           string
+	mode              model.Mode
+	configDevice      string
+	cryptsetupService service.CryptsetupService
+}
+
+func NewOpenEncryptedDeviceAction(device string, name string, keyFile string, cs service.CryptsetupService) *OpenEncryptedDeviceAction {
+	return &OpenEncryptedDeviceAction{
+		device:            device,
+		name:              name,
+		keyFile:           keyFile,
+		mode:              model.Empty,
+		cryptsetupService: cs,
+	}
+}
+
+func (a *OpenEncryptedDeviceAction) Execute() error {
+	return a.cryptsetupService.Open(a.device, a.name, a.keyFile)
+}
+
+func (a *OpenEncryptedDeviceAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *OpenEncryptedDeviceAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *OpenEncryptedDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *OpenEncryptedDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *OpenEncryptedDeviceAction) GetKind() model.ActionKind {
+	return model.OpenEncryptedDeviceAction
+}
+
+func (a *OpenEncryptedDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":  a.device,
+		"name":    a.name,
+		"keyFile": a.keyFile,
+	}
+}
+
+func (a *OpenEncryptedDeviceAction) Prompt() string {
+	return fmt.Sprintf("Would you like to open LUKS device %s as %s/%s", a.device, model.MapperDirectory, a.name)
+}
+
+func (a *OpenEncryptedDeviceAction) Refuse() string {
+	return fmt.Sprintf("Refused to open LUKS device %s as %s/%s", a.device, model.MapperDirectory, a.name)
+}
+
+func (a *OpenEncryptedDeviceAction) Success() string {
+	return fmt.Sprintf("Successfully opened LUKS device %s as %s/%s", a.device, model.MapperDirectory, a.name)
+}
+
+func (a *OpenEncryptedDeviceAction) Plan() string {
+	return fmt.Sprintf("Open LUKS device %s as %s/%s", a.device, model.MapperDirectory, a.name)
+}
@@ -0,0 +1,113 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestFormatEncryptedDeviceActionExecute(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Ephemeral     bool
+		ExpectedCalls []string
+	}{
+		{
+			Name:          "Key File",
+			Ephemeral:     false,
+			ExpectedCalls: []string{"Format(/dev/nvme1n1, /run/ebs-bootstrap/keys/scratch.key)"},
+		},
+		{
+			Name:      "Ephemeral Key",
+			Ephemeral: true,
+			ExpectedCalls: []string{
+				"CreateEphemeralKey(/run/ebs-bootstrap/keys/scratch.key)",
+				"Format(/dev/nvme1n1, /run/ebs-bootstrap/keys/scratch.key)",
+			},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			calls := []string{}
+			mcs := service.NewMockCryptsetupService()
+			mcs.StubCreateEphemeralKey = func(keyFile string) error {
+				calls = append(calls, "CreateEphemeralKey("+keyFile+")")
+				return nil
+			}
+			mcs.StubFormat = func(device string, keyFile string) error {
+				calls = append(calls, "Format("+device+", "+keyFile+")")
+				return nil
+			}
+			feda := NewFormatEncryptedDeviceAction("/dev/nvme1n1", "/run/ebs-bootstrap/keys/scratch.key", subtest.Ephemeral, mcs)
+			utils.ExpectErr("feda.Execute()", t, false, feda.Execute())
+			utils.CheckOutput("feda.Execute()", t, subtest.ExpectedCalls, calls)
+		})
+	}
+}
+
+func TestReformatEncryptedDeviceActionExecute(t *testing.T) {
+	calls := []string{}
+	mcs := service.NewMockCryptsetupService()
+	mcs.StubCreateEphemeralKey = func(keyFile string) error {
+		calls = append(calls, "CreateEphemeralKey("+keyFile+")")
+		return nil
+	}
+	mcs.StubFormat = func(device string, keyFile string) error {
+		calls = append(calls, "Format("+device+", "+keyFile+")")
+		return nil
+	}
+	reda := NewReformatEncryptedDeviceAction("/dev/nvme1n1", "/run/ebs-bootstrap/keys/scratch.key", mcs)
+	utils.ExpectErr("reda.Execute()", t, false, reda.Execute())
+	utils.CheckOutput("reda.Execute()", t, []string{
+		"CreateEphemeralKey(/run/ebs-bootstrap/keys/scratch.key)",
+		"Format(/dev/nvme1n1, /run/ebs-bootstrap/keys/scratch.key)",
+	}, calls)
+}
+
+func TestEncryptedDeviceActionMessages(t *testing.T) {
+	feda := NewFormatEncryptedDeviceAction("/dev/loop0", "/etc/secure.key", false, nil)
+	reda := NewReformatEncryptedDeviceAction("/dev/loop0", "/run/ebs-bootstrap/keys/scratch.key", nil)
+	oeda := NewOpenEncryptedDeviceAction("/dev/loop0", "secure", "/etc/secure.key", nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Format + Prompt",
+			Message:        feda.Prompt(),
+			ExpectedOutput: "Would you like to format /dev/loop0 as a LUKS device with key /etc/secure.key",
+		},
+		{
+			Name:           "Format + Refuse",
+			Message:        feda.Refuse(),
+			ExpectedOutput: "Refused to format /dev/loop0 as a LUKS device with key /etc/secure.key",
+		},
+		{
+			Name:           "Reformat + Prompt",
+			Message:        reda.Prompt(),
+			ExpectedOutput: "⚠️ Would you like to DESTROY the existing LUKS device /dev/loop0, and all of its data, and format it again with a new ephemeral key /run/ebs-bootstrap/keys/scratch.key",
+		},
+		{
+			Name:           "Reformat + Plan",
+			Message:        reda.Plan(),
+			ExpectedOutput: "⚠️ DESTROY the existing LUKS device /dev/loop0, and all of its data, and format it again with a new ephemeral key /run/ebs-bootstrap/keys/scratch.key",
+		},
+		{
+			Name:           "Open + Prompt",
+			Message:        oeda.Prompt(),
+			ExpectedOutput: "Would you like to open LUKS device /dev/loop0 as /dev/mapper/secure",
+		},
+		{
+			Name:           "Open + Success",
+			Message:        oeda.Success(),
+			ExpectedOutput: "Successfully opened LUKS device /dev/loop0 as /dev/mapper/secure",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
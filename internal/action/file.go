@@ -8,9 +8,10 @@ import (
 )
 
 type CreateDirectoryAction struct {
-	path        string
-	mode        model.Mode
-	fileService service.FileService
+	path         string
+	mode         model.Mode
+	configDevice string
+	fileService  service.FileService
 }
 
 func NewCreateDirectoryAction(p string, fs service.FileService) *CreateDirectoryAction {
@@ -34,6 +35,25 @@ func (a *CreateDirectoryAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *CreateDirectoryAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *CreateDirectoryAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *CreateDirectoryAction) GetKind() model.ActionKind {
+	return model.CreateDirectoryAction
+}
+
+func (a *CreateDirectoryAction) GetParameters() map[string]string {
+	return map[string]string{
+		"path": a.path,
+	}
+}
+
 func (a *CreateDirectoryAction) Prompt() string {
 	return fmt.Sprintf("Would you like to recursively create directory %s", a.path)
 }
@@ -46,12 +66,17 @@ func (a *CreateDirectoryAction) Success() string {
 	return fmt.Sprintf("Successfully created directory %s", a.path)
 }
 
+func (a *CreateDirectoryAction) Plan() string {
+	return fmt.Sprintf("Create directory %s", a.path)
+}
+
 type ChangeOwnerAction struct {
-	path        string
-	uid         model.UserId
-	gid         model.GroupId
-	mode        model.Mode
-	fileService service.FileService
+	path         string
+	uid          model.UserId
+	gid          model.GroupId
+	mode         model.Mode
+	configDevice string
+	fileService  service.FileService
 }
 
 func NewChangeOwnerAction(p string, uid model.UserId, gid model.GroupId, fs service.FileService) *ChangeOwnerAction {
@@ -77,6 +102,27 @@ func (a *ChangeOwnerAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *ChangeOwnerAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ChangeOwnerAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ChangeOwnerAction) GetKind() model.ActionKind {
+	return model.ChangeOwnerAction
+}
+
+func (a *ChangeOwnerAction) GetParameters() map[string]string {
+	return map[string]string{
+		"path": a.path,
+		"uid":  fmt.Sprint(a.uid),
+		"gid":  fmt.Sprint(a.gid),
+	}
+}
+
 func (a *ChangeOwnerAction) Prompt() string {
 	return fmt.Sprintf("Would you like to change ownership (%d:%d) of %s", a.uid, a.gid, a.path)
 }
@@ -89,11 +135,16 @@ func (a *ChangeOwnerAction) Success() string {
 	return fmt.Sprintf("Successfully changed ownership (%d:%d) of %s", a.uid, a.gid, a.path)
 }
 
+func (a *ChangeOwnerAction) Plan() string {
+	return fmt.Sprintf("Change ownership (%d:%d) of %s", a.uid, a.gid, a.path)
+}
+
 type ChangePermissionsAction struct {
-	path        string
-	perms       model.FilePermissions
-	mode        model.Mode
-	fileService service.FileService
+	path         string
+	perms        model.FilePermissions
+	mode         model.Mode
+	configDevice string
+	fileService  service.FileService
 }
 
 func NewChangePermissionsAction(p string, perms model.FilePermissions, fs service.FileService) *ChangePermissionsAction {
@@ -118,6 +169,26 @@ func (a *ChangePermissionsAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *ChangePermissionsAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ChangePermissionsAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ChangePermissionsAction) GetKind() model.ActionKind {
+	return model.ChangePermissionsAction
+}
+
+func (a *ChangePermissionsAction) GetParameters() map[string]string {
+	return map[string]string{
+		"path":  a.path,
+		"perms": fmt.Sprintf("%#o", a.perms),
+	}
+}
+
 func (a *ChangePermissionsAction) Prompt() string {
 	return fmt.Sprintf("Would you like to change permissions of %s to %#o", a.path, a.perms)
 }
@@ -129,3 +200,7 @@ func (a *ChangePermissionsAction) Refuse() string {
 func (a *ChangePermissionsAction) Success() string {
 	return fmt.Sprintf("Successfully change permissions of %s to %#o", a.path, a.perms)
 }
+
+func (a *ChangePermissionsAction) Plan() string {
+	return fmt.Sprintf("Change permissions of %s to %#o", a.path, a.perms)
+}
@@ -43,6 +43,11 @@ func TestCreateDirectoryActionMessages(t *testing.T) {
 			Message:        cda.Success(),
 			ExpectedOutput: "Successfully created directory /mnt/foo",
 		},
+		{
+			Name:           "Plan",
+			Message:        cda.Plan(),
+			ExpectedOutput: "Create directory /mnt/foo",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -86,6 +91,11 @@ func TestChangeOwnerActionMessages(t *testing.T) {
 			Message:        coa.Success(),
 			ExpectedOutput: "Successfully changed ownership (1000:2000) of /mnt/foo",
 		},
+		{
+			Name:           "Plan",
+			Message:        coa.Plan(),
+			ExpectedOutput: "Change ownership (1000:2000) of /mnt/foo",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -129,6 +139,11 @@ func TestChangePermissionsActionMessages(t *testing.T) {
 			Message:        cpa.Success(),
 			ExpectedOutput: "Successfully change permissions of /mnt/foo to 0755",
 		},
+		{
+			Name:           "Plan",
+			Message:        cpa.Plan(),
+			ExpectedOutput: "Change permissions of /mnt/foo to 0755",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -9,20 +9,23 @@ import (
 
 type FormatDeviceAction struct {
 	device            string
+	options           *model.FormatOptions
 	fileSystemService service.FileSystemService
 	mode              model.Mode
+	configDevice      string
 }
 
-func NewFormatDeviceAction(d string, fileSystemService service.FileSystemService) *FormatDeviceAction {
+func NewFormatDeviceAction(d string, options *model.FormatOptions, fileSystemService service.FileSystemService) *FormatDeviceAction {
 	return &FormatDeviceAction{
 		device:            d,
+		options:           options,
 		fileSystemService: fileSystemService,
 		mode:              model.Empty,
 	}
 }
 
 func (a *FormatDeviceAction) Execute() error {
-	return a.fileSystemService.Format(a.device)
+	return a.fileSystemService.Format(a.device, a.options)
 }
 
 func (a *FormatDeviceAction) GetMode() model.Mode {
@@ -34,6 +37,32 @@ func (a *FormatDeviceAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *FormatDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *FormatDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *FormatDeviceAction) GetKind() model.ActionKind {
+	return model.FormatAction
+}
+
+func (a *FormatDeviceAction) GetParameters() map[string]string {
+	parameters := map[string]string{
+		"device":     a.device,
+		"fileSystem": a.fileSystemService.GetFileSystem().String(),
+		"options":    a.options.String(),
+	}
+	// The label is not described by the format options
+	if a.options != nil && len(a.options.Label) > 0 {
+		parameters["label"] = a.options.Label
+	}
+	return parameters
+}
+
 func (a *FormatDeviceAction) Prompt() string {
 	return fmt.Sprintf("Would you like to format %s to %s", a.device, a.fileSystemService.GetFileSystem())
 }
@@ -45,3 +74,157 @@ func (a *FormatDeviceAction) Refuse() string {
 func (a *FormatDeviceAction) Success() string {
 	return fmt.Sprintf("Successfully formatted %s to %s", a.device, a.fileSystemService.GetFileSystem().String())
 }
+
+func (a *FormatDeviceAction) Plan() string {
+	if options := a.options.String(); len(options) > 0 {
+		return fmt.Sprintf("Format %s to %s (%s)", a.device, a.fileSystemService.GetFileSystem(), options)
+	}
+	return fmt.Sprintf("Format %s to %s", a.device, a.fileSystemService.GetFileSystem())
+}
+
+type WipeDeviceAction struct {
+	device        string
+	signatures    []*model.Signature
+	deviceService service.DeviceService
+	mode          model.Mode
+	configDevice  string
+}
+
+func NewWipeDeviceAction(d string, signatures []*model.Signature, deviceService service.DeviceService) *WipeDeviceAction {
+	return &WipeDeviceAction{
+		device:        d,
+		signatures:    signatures,
+		deviceService: deviceService,
+		mode:          model.Empty,
+	}
+}
+
+func (a *WipeDeviceAction) Execute() error {
+	return a.deviceService.Wipe(a.device)
+}
+
+func (a *WipeDeviceAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *WipeDeviceAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *WipeDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *WipeDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *WipeDeviceAction) GetKind() model.ActionKind {
+	return model.WipeAction
+}
+
+func (a *WipeDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":     a.device,
+		"signatures": model.JoinSignatures(a.signatures),
+	}
+}
+
+func (a *WipeDeviceAction) Prompt() string {
+	return fmt.Sprintf("Would you like to erase the signatures of %s: %s", a.device, model.JoinSignatures(a.signatures))
+}
+
+func (a *WipeDeviceAction) Refuse() string {
+	return fmt.Sprintf("Refused to erase the signatures of %s", a.device)
+}
+
+func (a *WipeDeviceAction) Success() string {
+	return fmt.Sprintf("Successfully erased the signatures of %s", a.device)
+}
+
+func (a *WipeDeviceAction) Plan() string {
+	return fmt.Sprintf("Erase the signatures of %s: %s", a.device, model.JoinSignatures(a.signatures))
+}
+
+// ReformatDeviceAction destroys an existing file system, by erasing its signature,
+// before the device is formatted to the requested file system
+type ReformatDeviceAction struct {
+	device            string
+	fileSystem        model.FileSystem
+	options           *model.FormatOptions
+	deviceService     service.DeviceService
+	fileSystemService service.FileSystemService
+	mode              model.Mode
+	configDevice      string
+}
+
+func NewReformatDeviceAction(d string, fileSystem model.FileSystem, options *model.FormatOptions, deviceService service.DeviceService, fileSystemService service.FileSystemService) *ReformatDeviceAction {
+	return &ReformatDeviceAction{
+		device:            d,
+		fileSystem:        fileSystem,
+		options:           options,
+		deviceService:     deviceService,
+		fileSystemService: fileSystemService,
+		mode:              model.Empty,
+	}
+}
+
+func (a *ReformatDeviceAction) Execute() error {
+	if err := a.deviceService.Wipe(a.device); err != nil {
+		return err
+	}
+	return a.fileSystemService.Format(a.device, a.options)
+}
+
+func (a *ReformatDeviceAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *ReformatDeviceAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *ReformatDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ReformatDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ReformatDeviceAction) GetKind() model.ActionKind {
+	return model.ReformatAction
+}
+
+func (a *ReformatDeviceAction) GetParameters() map[string]string {
+	parameters := map[string]string{
+		"device":             a.device,
+		"existingFileSystem": a.fileSystem.String(),
+		"fileSystem":         a.fileSystemService.GetFileSystem().String(),
+		"options":            a.options.String(),
+	}
+	if a.options != nil && len(a.options.Label) > 0 {
+		parameters["label"] = a.options.Label
+	}
+	return parameters
+}
+
+func (a *ReformatDeviceAction) Prompt() string {
+	return fmt.Sprintf("⚠️ Would you like to DESTROY the existing %s file system of %s, and all of its data, and reformat it to %s", a.fileSystem, a.device, a.fileSystemService.GetFileSystem())
+}
+
+func (a *ReformatDeviceAction) Refuse() string {
+	return fmt.Sprintf("Refused to reformat %s from %s to %s", a.device, a.fileSystem, a.fileSystemService.GetFileSystem())
+}
+
+func (a *ReformatDeviceAction) Success() string {
+	return fmt.Sprintf("Successfully reformatted %s from %s to %s. All data on the existing file system was destroyed", a.device, a.fileSystem, a.fileSystemService.GetFileSystem())
+}
+
+func (a *ReformatDeviceAction) Plan() string {
+	return fmt.Sprintf("⚠️ DESTROY the existing %s file system of %s, and all of its data, and reformat it to %s", a.fileSystem, a.device, a.fileSystemService.GetFileSystem())
+}
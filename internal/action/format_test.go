@@ -1,6 +1,7 @@
 package action
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/reecetech/ebs-bootstrap/internal/model"
@@ -10,19 +11,19 @@ import (
 
 func TestFormatDeviceActionExecute(t *testing.T) {
 	mfs := service.NewMockFileSystemService()
-	mfs.StubFormat = func(device string) error { return nil }
-	fda := NewFormatDeviceAction("/dev/xvdf", mfs)
+	mfs.StubFormat = func(device string, options *model.FormatOptions) error { return nil }
+	fda := NewFormatDeviceAction("/dev/xvdf", nil, mfs)
 	utils.ExpectErr("fda.Execute()", t, false, fda.Execute())
 }
 
 func TestFormatDeviceActionMode(t *testing.T) {
-	fda := NewFormatDeviceAction("/dev/xvdf", nil)
+	fda := NewFormatDeviceAction("/dev/xvdf", nil, nil)
 	fda.SetMode(model.Healthcheck)
 	utils.CheckOutput("fda.GetMode()", t, model.Healthcheck, fda.GetMode())
 }
 
 func TestFormatDeviceActionMessages(t *testing.T) {
-	fda := NewFormatDeviceAction("/dev/xvdf", service.NewExt4Service(nil))
+	fda := NewFormatDeviceAction("/dev/xvdf", nil, service.NewExt4Service(nil))
 	subtests := []struct {
 		Name           string
 		Message        string
@@ -43,6 +44,148 @@ func TestFormatDeviceActionMessages(t *testing.T) {
 			Message:        fda.Success(),
 			ExpectedOutput: "Successfully formatted /dev/xvdf to ext4",
 		},
+		{
+			Name:           "Plan",
+			Message:        fda.Plan(),
+			ExpectedOutput: "Format /dev/xvdf to ext4",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
+
+func TestFormatDeviceActionParameters(t *testing.T) {
+	reserved := uint64(0)
+	fda := NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{
+		BlockSize:             4096,
+		ReservedBlocksPercent: &reserved,
+		Label:                 "stateful",
+	}, service.NewExt4Service(nil))
+	utils.CheckOutput("fda.GetKind()", t, model.FormatAction, fda.GetKind())
+	utils.CheckOutput("fda.GetParameters()", t, map[string]string{
+		"device":     "/dev/xvdf",
+		"fileSystem": "ext4",
+		"options":    "blockSize=4096,reservedBlocksPercent=0",
+		"label":      "stateful",
+	}, fda.GetParameters())
+	utils.CheckOutput("fda.Plan()", t, "Format /dev/xvdf to ext4 (blockSize=4096,reservedBlocksPercent=0)", fda.Plan())
+}
+
+func TestWipeDeviceActionExecute(t *testing.T) {
+	mds := service.NewMockDeviceService()
+	mds.StubWipe = func(name string) error { return nil }
+	wda := NewWipeDeviceAction("/dev/xvdf", nil, mds)
+	utils.ExpectErr("wda.Execute()", t, false, wda.Execute())
+}
+
+func TestWipeDeviceActionMessages(t *testing.T) {
+	wda := NewWipeDeviceAction("/dev/xvdf", []*model.Signature{
+		{Type: "dos", Offset: 0x1fe},
+		{Type: "xfs", Offset: 0},
+	}, nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        wda.Prompt(),
+			ExpectedOutput: "Would you like to erase the signatures of /dev/xvdf: dos (offset 0x1fe), xfs (offset 0x0)",
+		},
+		{
+			Name:           "Refuse",
+			Message:        wda.Refuse(),
+			ExpectedOutput: "Refused to erase the signatures of /dev/xvdf",
+		},
+		{
+			Name:           "Success",
+			Message:        wda.Success(),
+			ExpectedOutput: "Successfully erased the signatures of /dev/xvdf",
+		},
+		{
+			Name:           "Plan",
+			Message:        wda.Plan(),
+			ExpectedOutput: "Erase the signatures of /dev/xvdf: dos (offset 0x1fe), xfs (offset 0x0)",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
+
+func TestReformatDeviceActionExecute(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		WipeError     error
+		ExpectedCalls []string
+		ExpectedError error
+	}{
+		{
+			Name:          "Wipe and Format",
+			WipeError:     nil,
+			ExpectedCalls: []string{"Wipe()", "Format()"},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "Failed to Wipe",
+			WipeError:     fmt.Errorf("🔴 /dev/nvme1n1: Failed to erase signatures"),
+			ExpectedCalls: []string{"Wipe()"},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Failed to erase signatures"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			calls := []string{}
+			mds := service.NewMockDeviceService()
+			mds.StubWipe = func(name string) error {
+				calls = append(calls, "Wipe()")
+				return subtest.WipeError
+			}
+			mfs := service.NewMockFileSystemService()
+			mfs.StubFormat = func(device string, options *model.FormatOptions) error {
+				calls = append(calls, "Format()")
+				return nil
+			}
+			rda := NewReformatDeviceAction("/dev/nvme1n1", model.Ext4, nil, mds, mfs)
+			utils.CheckError("rda.Execute()", t, subtest.ExpectedError, rda.Execute())
+			utils.CheckOutput("calls", t, subtest.ExpectedCalls, calls)
+		})
+	}
+}
+
+func TestReformatDeviceActionMessages(t *testing.T) {
+	rda := NewReformatDeviceAction("/dev/nvme1n1", model.Ext4, nil, nil, service.NewXfsService(nil))
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        rda.Prompt(),
+			ExpectedOutput: "⚠️ Would you like to DESTROY the existing ext4 file system of /dev/nvme1n1, and all of its data, and reformat it to xfs",
+		},
+		{
+			Name:           "Refuse",
+			Message:        rda.Refuse(),
+			ExpectedOutput: "Refused to reformat /dev/nvme1n1 from ext4 to xfs",
+		},
+		{
+			Name:           "Success",
+			Message:        rda.Success(),
+			ExpectedOutput: "Successfully reformatted /dev/nvme1n1 from ext4 to xfs. All data on the existing file system was destroyed",
+		},
+		{
+			Name:           "Plan",
+			Message:        rda.Plan(),
+			ExpectedOutput: "⚠️ DESTROY the existing ext4 file system of /dev/nvme1n1, and all of its data, and reformat it to xfs",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -0,0 +1,79 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type RepairFileSystemAction struct {
+	device            string
+	fileSystemService service.FileSystemService
+	mode              model.Mode
+	configDevice      string
+}
+
+func NewRepairFileSystemAction(d string, fileSystemService service.FileSystemService) *RepairFileSystemAction {
+	return &RepairFileSystemAction{
+		device:            d,
+		fileSystemService: fileSystemService,
+		mode:              model.Empty,
+	}
+}
+
+func (a *RepairFileSystemAction) Execute() error {
+	result, err := a.fileSystemService.Check(a.device, true)
+	if err != nil {
+		return err
+	}
+	if result == model.FsckUncorrected {
+		return fmt.Errorf("🔴 %s: The %s file system has errors that could not be corrected automatically", a.device, a.fileSystemService.GetFileSystem())
+	}
+	return nil
+}
+
+func (a *RepairFileSystemAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *RepairFileSystemAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *RepairFileSystemAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *RepairFileSystemAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *RepairFileSystemAction) GetKind() model.ActionKind {
+	return model.RepairFileSystemAction
+}
+
+func (a *RepairFileSystemAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":     a.device,
+		"fileSystem": a.fileSystemService.GetFileSystem().String(),
+	}
+}
+
+func (a *RepairFileSystemAction) Prompt() string {
+	return fmt.Sprintf("Would you like to repair the file system of %s", a.device)
+}
+
+func (a *RepairFileSystemAction) Refuse() string {
+	return fmt.Sprintf("Refused to repair the file system of %s", a.device)
+}
+
+func (a *RepairFileSystemAction) Success() string {
+	return fmt.Sprintf("Successfully repaired the file system of %s", a.device)
+}
+
+func (a *RepairFileSystemAction) Plan() string {
+	return fmt.Sprintf("Repair the file system of %s", a.device)
+}
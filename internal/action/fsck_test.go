@@ -0,0 +1,98 @@
+package action
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestRepairFileSystemActionExecute(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Result        model.FsckResult
+		Error         error
+		ExpectedError error
+	}{
+		{
+			Name:          "Corrected",
+			Result:        model.FsckCorrected,
+			Error:         nil,
+			ExpectedError: nil,
+		},
+		{
+			Name:          "Uncorrected",
+			Result:        model.FsckUncorrected,
+			Error:         nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: The ext4 file system has errors that could not be corrected automatically"),
+		},
+		{
+			Name:          "Error",
+			Result:        "",
+			Error:         fmt.Errorf("🔴 e2fsck: Bad magic number in super-block"),
+			ExpectedError: fmt.Errorf("🔴 e2fsck: Bad magic number in super-block"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mfs := service.NewMockFileSystemService()
+			mfs.StubGetFileSystem = func() model.FileSystem { return model.Ext4 }
+			mfs.StubCheck = func(name string, repair bool) (model.FsckResult, error) {
+				if !repair {
+					return "", fmt.Errorf("🔴 %s: Expected a repair", name)
+				}
+				return subtest.Result, subtest.Error
+			}
+			rfa := NewRepairFileSystemAction("/dev/xvdf", mfs)
+			utils.CheckError("rfa.Execute()", t, subtest.ExpectedError, rfa.Execute())
+		})
+	}
+}
+
+func TestRepairFileSystemActionParameters(t *testing.T) {
+	mfs := service.NewMockFileSystemService()
+	mfs.StubGetFileSystem = func() model.FileSystem { return model.Xfs }
+	rfa := NewRepairFileSystemAction("/dev/xvdf", mfs)
+	utils.CheckOutput("rfa.GetKind()", t, model.RepairFileSystemAction, rfa.GetKind())
+	utils.CheckOutput("rfa.GetParameters()", t, map[string]string{
+		"device":     "/dev/xvdf",
+		"fileSystem": "xfs",
+	}, rfa.GetParameters())
+}
+
+func TestRepairFileSystemActionMessages(t *testing.T) {
+	rfa := NewRepairFileSystemAction("/dev/xvdf", nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        rfa.Prompt(),
+			ExpectedOutput: "Would you like to repair the file system of /dev/xvdf",
+		},
+		{
+			Name:           "Refuse",
+			Message:        rfa.Refuse(),
+			ExpectedOutput: "Refused to repair the file system of /dev/xvdf",
+		},
+		{
+			Name:           "Success",
+			Message:        rfa.Success(),
+			ExpectedOutput: "Successfully repaired the file system of /dev/xvdf",
+		},
+		{
+			Name:           "Plan",
+			Message:        rfa.Plan(),
+			ExpectedOutput: "Repair the file system of /dev/xvdf",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package action
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+const (
+	DefaultFstabPermissions = model.FilePermissions(0644)
+)
+
+type UpdateFstabEntryAction struct {
+	path         string
+	entry        *model.FstabEntry
+	mode         model.Mode
+	configDevice string
+	fileService  service.FileService
+}
+
+func NewUpdateFstabEntryAction(p string, entry *model.FstabEntry, fs service.FileService) *UpdateFstabEntryAction {
+	return &UpdateFstabEntryAction{
+		path:        p,
+		entry:       entry,
+		mode:        model.Empty,
+		fileService: fs,
+	}
+}
+
+// The fstab file is read at the time of execution, rather than when the action
+// is created, so that multiple entries can be updated in succession without
+// losing any modifications made by an earlier action
+func (a *UpdateFstabEntryAction) Execute() error {
+	perms := DefaultFstabPermissions
+	content, err := a.fileService.ReadFile(a.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		f, err := a.fileService.GetFile(a.path)
+		if err != nil {
+			return err
+		}
+		perms = f.Permissions
+	}
+	updated := model.UpdateFstab(string(content), a.entry)
+	return a.fileService.WriteFile(a.path, []byte(updated), perms)
+}
+
+func (a *UpdateFstabEntryAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *UpdateFstabEntryAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *UpdateFstabEntryAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *UpdateFstabEntryAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *UpdateFstabEntryAction) GetKind() model.ActionKind {
+	return model.UpdateFstabEntryAction
+}
+
+func (a *UpdateFstabEntryAction) GetParameters() map[string]string {
+	return map[string]string{
+		"path":       a.path,
+		"source":     a.entry.Source,
+		"mountPoint": a.entry.MountPoint,
+		"fileSystem": a.entry.FileSystem.String(),
+		"options":    string(a.entry.Options),
+		"dump":       fmt.Sprint(a.entry.Dump),
+		"pass":       fmt.Sprint(a.entry.Pass),
+	}
+}
+
+func (a *UpdateFstabEntryAction) Prompt() string {
+	return fmt.Sprintf("Would you like to set the entry for %s in %s to '%s'", a.entry.MountPoint, a.path, a.entry)
+}
+
+func (a *UpdateFstabEntryAction) Refuse() string {
+	return fmt.Sprintf("Refused to set the entry for %s in %s to '%s'", a.entry.MountPoint, a.path, a.entry)
+}
+
+func (a *UpdateFstabEntryAction) Success() string {
+	return fmt.Sprintf("Successfully set the entry for %s in %s to '%s'", a.entry.MountPoint, a.path, a.entry)
+}
+
+func (a *UpdateFstabEntryAction) Plan() string {
+	return fmt.Sprintf("Set the entry for %s in %s to '%s'", a.entry.MountPoint, a.path, a.entry)
+}
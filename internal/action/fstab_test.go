@@ -0,0 +1,111 @@
+package action
+
+import (
+	"os"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestUpdateFstabEntryActionExecute(t *testing.T) {
+	entry := &model.FstabEntry{
+		Source:     "LABEL=stateful",
+		MountPoint: "/mnt/ebs",
+		FileSystem: model.Ext4,
+		Options:    "defaults",
+		Pass:       2,
+	}
+	subtests := []struct {
+		Name          string
+		Content       []byte
+		ReadError     error
+		ExpectedData  string
+		ExpectedPerms model.FilePermissions
+	}{
+		{
+			Name:          "Existing File",
+			Content:       []byte("# /etc/fstab\n"),
+			ReadError:     nil,
+			ExpectedData:  "# /etc/fstab\nLABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t2\n",
+			ExpectedPerms: model.FilePermissions(0600),
+		},
+		{
+			Name:          "Missing File",
+			Content:       nil,
+			ReadError:     os.ErrNotExist,
+			ExpectedData:  "LABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t2\n",
+			ExpectedPerms: DefaultFstabPermissions,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			var data string
+			var perms model.FilePermissions
+			mfs := service.NewMockFileService()
+			mfs.StubReadFile = func(p string) ([]byte, error) {
+				return subtest.Content, subtest.ReadError
+			}
+			mfs.StubGetFile = func(p string) (*model.File, error) {
+				return &model.File{Path: p, Type: model.RegularFile, Permissions: 0600}, nil
+			}
+			mfs.StubWriteFile = func(p string, d []byte, fp model.FilePermissions) error {
+				data = string(d)
+				perms = fp
+				return nil
+			}
+			ufea := NewUpdateFstabEntryAction("/etc/fstab", entry, mfs)
+			utils.ExpectErr("ufea.Execute()", t, false, ufea.Execute())
+			utils.CheckOutput("ufea.Execute()", t, subtest.ExpectedData, data)
+			utils.CheckOutput("ufea.Execute()", t, subtest.ExpectedPerms, perms)
+		})
+	}
+}
+
+func TestUpdateFstabEntryActionMode(t *testing.T) {
+	ufea := NewUpdateFstabEntryAction("/etc/fstab", &model.FstabEntry{}, nil)
+	ufea.SetMode(model.Healthcheck)
+	utils.CheckOutput("ufea.GetMode()", t, model.Healthcheck, ufea.GetMode())
+}
+
+func TestUpdateFstabEntryActionMessages(t *testing.T) {
+	ufea := NewUpdateFstabEntryAction("/etc/fstab", &model.FstabEntry{
+		Source:     "LABEL=stateful",
+		MountPoint: "/mnt/ebs",
+		FileSystem: model.Ext4,
+		Options:    "defaults",
+		Pass:       2,
+	}, nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        ufea.Prompt(),
+			ExpectedOutput: "Would you like to set the entry for /mnt/ebs in /etc/fstab to 'LABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t2'",
+		},
+		{
+			Name:           "Refuse",
+			Message:        ufea.Refuse(),
+			ExpectedOutput: "Refused to set the entry for /mnt/ebs in /etc/fstab to 'LABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t2'",
+		},
+		{
+			Name:           "Success",
+			Message:        ufea.Success(),
+			ExpectedOutput: "Successfully set the entry for /mnt/ebs in /etc/fstab to 'LABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t2'",
+		},
+		{
+			Name:           "Plan",
+			Message:        ufea.Plan(),
+			ExpectedOutput: "Set the entry for /mnt/ebs in /etc/fstab to 'LABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t2'",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
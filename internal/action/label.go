@@ -12,6 +12,7 @@ type LabelDeviceAction struct {
 	label             string
 	fileSystemService service.FileSystemService
 	mode              model.Mode
+	configDevice      string
 }
 
 func NewLabelDeviceAction(d string, label string, fileSystemService service.FileSystemService) *LabelDeviceAction {
@@ -36,6 +37,27 @@ func (a *LabelDeviceAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *LabelDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *LabelDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *LabelDeviceAction) GetKind() model.ActionKind {
+	return model.LabelAction
+}
+
+func (a *LabelDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":     a.device,
+		"label":      a.label,
+		"fileSystem": a.fileSystemService.GetFileSystem().String(),
+	}
+}
+
 func (a *LabelDeviceAction) Prompt() string {
 	return fmt.Sprintf("Would you like to label device %s to '%s'", a.device, a.label)
 }
@@ -47,3 +69,7 @@ func (a *LabelDeviceAction) Refuse() string {
 func (a *LabelDeviceAction) Success() string {
 	return fmt.Sprintf("Successfully labelled %s to '%s'", a.device, a.label)
 }
+
+func (a *LabelDeviceAction) Plan() string {
+	return fmt.Sprintf("Label %s to '%s'", a.device, a.label)
+}
@@ -43,6 +43,11 @@ func TestLabelDeviceActionMessages(t *testing.T) {
 			Message:        lda.Success(),
 			ExpectedOutput: "Successfully labelled /dev/xvdf to 'example'",
 		},
+		{
+			Name:           "Plan",
+			Message:        lda.Plan(),
+			ExpectedOutput: "Label /dev/xvdf to 'example'",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -8,9 +8,10 @@ import (
 )
 
 type CreatePhysicalVolumeAction struct {
-	name       string
-	mode       model.Mode
-	lvmService service.LvmService
+	name         string
+	mode         model.Mode
+	configDevice string
+	lvmService   service.LvmService
 }
 
 func NewCreatePhysicalVolumeAction(name string, ls service.LvmService) *CreatePhysicalVolumeAction {
@@ -34,6 +35,25 @@ func (a *CreatePhysicalVolumeAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *CreatePhysicalVolumeAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *CreatePhysicalVolumeAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *CreatePhysicalVolumeAction) GetKind() model.ActionKind {
+	return model.CreatePhysicalVolumeAction
+}
+
+func (a *CreatePhysicalVolumeAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name": a.name,
+	}
+}
+
 func (a *CreatePhysicalVolumeAction) Prompt() string {
 	return fmt.Sprintf("Would you like to create physical volume %s", a.name)
 }
@@ -46,10 +66,15 @@ func (a *CreatePhysicalVolumeAction) Success() string {
 	return fmt.Sprintf("Successfully created physical volume %s", a.name)
 }
 
+func (a *CreatePhysicalVolumeAction) Plan() string {
+	return fmt.Sprintf("Create physical volume %s", a.name)
+}
+
 type CreateVolumeGroupAction struct {
 	name           string
 	physicalVolume string
 	mode           model.Mode
+	configDevice   string
 	lvmService     service.LvmService
 }
 
@@ -75,6 +100,26 @@ func (a *CreateVolumeGroupAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *CreateVolumeGroupAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *CreateVolumeGroupAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *CreateVolumeGroupAction) GetKind() model.ActionKind {
+	return model.CreateVolumeGroupAction
+}
+
+func (a *CreateVolumeGroupAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name":           a.name,
+		"physicalVolume": a.physicalVolume,
+	}
+}
+
 func (a *CreateVolumeGroupAction) Prompt() string {
 	return fmt.Sprintf("Would you like to create volume group %s on physical volume %s", a.name, a.physicalVolume)
 }
@@ -87,26 +132,97 @@ func (a *CreateVolumeGroupAction) Success() string {
 	return fmt.Sprintf("Successfully created volume group %s on physical volume %s", a.name, a.physicalVolume)
 }
 
+func (a *CreateVolumeGroupAction) Plan() string {
+	return fmt.Sprintf("Create volume group %s on physical volume %s", a.name, a.physicalVolume)
+}
+
+type ExtendVolumeGroupAction struct {
+	name           string
+	physicalVolume string
+	mode           model.Mode
+	configDevice   string
+	lvmService     service.LvmService
+}
+
+func NewExtendVolumeGroupAction(name string, physicalVolume string, ls service.LvmService) *ExtendVolumeGroupAction {
+	return &ExtendVolumeGroupAction{
+		name:           name,
+		physicalVolume: physicalVolume,
+		mode:           model.Empty,
+		lvmService:     ls,
+	}
+}
+
+func (a *ExtendVolumeGroupAction) Execute() error {
+	return a.lvmService.ExtendVolumeGroup(a.name, a.physicalVolume)
+}
+
+func (a *ExtendVolumeGroupAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *ExtendVolumeGroupAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *ExtendVolumeGroupAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ExtendVolumeGroupAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ExtendVolumeGroupAction) GetKind() model.ActionKind {
+	return model.ExtendVolumeGroupAction
+}
+
+func (a *ExtendVolumeGroupAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name":           a.name,
+		"physicalVolume": a.physicalVolume,
+	}
+}
+
+func (a *ExtendVolumeGroupAction) Prompt() string {
+	return fmt.Sprintf("Would you like to extend volume group %s with physical volume %s", a.name, a.physicalVolume)
+}
+
+func (a *ExtendVolumeGroupAction) Refuse() string {
+	return fmt.Sprintf("Refused to extend volume group %s with physical volume %s", a.name, a.physicalVolume)
+}
+
+func (a *ExtendVolumeGroupAction) Success() string {
+	return fmt.Sprintf("Successfully extended volume group %s with physical volume %s", a.name, a.physicalVolume)
+}
+
+func (a *ExtendVolumeGroupAction) Plan() string {
+	return fmt.Sprintf("Extend volume group %s with physical volume %s", a.name, a.physicalVolume)
+}
+
 type CreateLogicalVolumeAction struct {
-	name               string
-	volumeGroupPercent uint64
-	volumeGroup        string
-	mode               model.Mode
-	lvmService         service.LvmService
+	name         string
+	size         model.LvmSize
+	volumeGroup  string
+	mode         model.Mode
+	configDevice string
+	lvmService   service.LvmService
 }
 
-func NewCreateLogicalVolumeAction(name string, volumeGroupPercent uint64, volumeGroup string, ls service.LvmService) *CreateLogicalVolumeAction {
+func NewCreateLogicalVolumeAction(name string, size model.LvmSize, volumeGroup string, ls service.LvmService) *CreateLogicalVolumeAction {
 	return &CreateLogicalVolumeAction{
-		name:               name,
-		volumeGroupPercent: volumeGroupPercent,
-		volumeGroup:        volumeGroup,
-		mode:               model.Empty,
-		lvmService:         ls,
+		name:        name,
+		size:        size,
+		volumeGroup: volumeGroup,
+		mode:        model.Empty,
+		lvmService:  ls,
 	}
 }
 
 func (a *CreateLogicalVolumeAction) Execute() error {
-	return a.lvmService.CreateLogicalVolume(a.name, a.volumeGroup, a.volumeGroupPercent)
+	return a.lvmService.CreateLogicalVolume(a.name, a.volumeGroup, a.size)
 }
 
 func (a *CreateLogicalVolumeAction) GetMode() model.Mode {
@@ -118,23 +234,49 @@ func (a *CreateLogicalVolumeAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *CreateLogicalVolumeAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *CreateLogicalVolumeAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *CreateLogicalVolumeAction) GetKind() model.ActionKind {
+	return model.CreateLogicalVolumeAction
+}
+
+func (a *CreateLogicalVolumeAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name":        a.name,
+		"volumeGroup": a.volumeGroup,
+		"size":        a.size.String(),
+	}
+}
+
 func (a *CreateLogicalVolumeAction) Prompt() string {
-	return fmt.Sprintf("Would you like to create logical volume %s that consumes %d%% of the space of volume group %s", a.name, a.volumeGroupPercent, a.volumeGroup)
+	return fmt.Sprintf("Would you like to create logical volume %s of size %s in volume group %s", a.name, a.size, a.volumeGroup)
 }
 
 func (a *CreateLogicalVolumeAction) Refuse() string {
-	return fmt.Sprintf("Refused to create logical volume %s that consumes %d%% of the space of volume group %s", a.name, a.volumeGroupPercent, a.volumeGroup)
+	return fmt.Sprintf("Refused to create logical volume %s of size %s in volume group %s", a.name, a.size, a.volumeGroup)
 }
 
 func (a *CreateLogicalVolumeAction) Success() string {
-	return fmt.Sprintf("Successfully created logical volume %s that consumes %d%% of the space of volume group %s", a.name, a.volumeGroupPercent, a.volumeGroup)
+	return fmt.Sprintf("Successfully created logical volume %s of size %s in volume group %s", a.name, a.size, a.volumeGroup)
+}
+
+func (a *CreateLogicalVolumeAction) Plan() string {
+	return fmt.Sprintf("Create logical volume %s of size %s in volume group %s", a.name, a.size, a.volumeGroup)
 }
 
 type ActivateLogicalVolumeAction struct {
-	name        string
-	volumeGroup string
-	mode        model.Mode
-	lvmService  service.LvmService
+	name         string
+	volumeGroup  string
+	mode         model.Mode
+	configDevice string
+	lvmService   service.LvmService
 }
 
 func NewActivateLogicalVolumeAction(name string, volumeGroup string, ls service.LvmService) *ActivateLogicalVolumeAction {
@@ -159,6 +301,26 @@ func (a *ActivateLogicalVolumeAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *ActivateLogicalVolumeAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ActivateLogicalVolumeAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ActivateLogicalVolumeAction) GetKind() model.ActionKind {
+	return model.ActivateLogicalVolumeAction
+}
+
+func (a *ActivateLogicalVolumeAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name":        a.name,
+		"volumeGroup": a.volumeGroup,
+	}
+}
+
 func (a *ActivateLogicalVolumeAction) Prompt() string {
 	return fmt.Sprintf("Would you like to activate logical volume %s in volume group %s", a.name, a.volumeGroup)
 }
@@ -171,10 +333,15 @@ func (a *ActivateLogicalVolumeAction) Success() string {
 	return fmt.Sprintf("Successfully activated logical volume %s in volume group %s", a.name, a.volumeGroup)
 }
 
+func (a *ActivateLogicalVolumeAction) Plan() string {
+	return fmt.Sprintf("Activate logical volume %s in volume group %s", a.name, a.volumeGroup)
+}
+
 type ResizePhysicalVolumeAction struct {
-	name       string
-	mode       model.Mode
-	lvmService service.LvmService
+	name         string
+	mode         model.Mode
+	configDevice string
+	lvmService   service.LvmService
 }
 
 func NewResizePhysicalVolumeAction(name string, ls service.LvmService) *ResizePhysicalVolumeAction {
@@ -198,6 +365,25 @@ func (a *ResizePhysicalVolumeAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *ResizePhysicalVolumeAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ResizePhysicalVolumeAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ResizePhysicalVolumeAction) GetKind() model.ActionKind {
+	return model.ResizePhysicalVolumeAction
+}
+
+func (a *ResizePhysicalVolumeAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name": a.name,
+	}
+}
+
 func (a *ResizePhysicalVolumeAction) Prompt() string {
 	return fmt.Sprintf("Would you like to resize physical volume %s", a.name)
 }
@@ -210,26 +396,31 @@ func (a *ResizePhysicalVolumeAction) Success() string {
 	return fmt.Sprintf("Successfully resized physical volume %s", a.name)
 }
 
+func (a *ResizePhysicalVolumeAction) Plan() string {
+	return fmt.Sprintf("Resize physical volume %s", a.name)
+}
+
 type ResizeLogicalVolumeAction struct {
-	name               string
-	volumeGroupPercent uint64
-	volumeGroup        string
-	mode               model.Mode
-	lvmService         service.LvmService
+	name         string
+	size         model.LvmSize
+	volumeGroup  string
+	mode         model.Mode
+	configDevice string
+	lvmService   service.LvmService
 }
 
-func NewResizeLogicalVolumeAction(name string, volumeGroupPercent uint64, volumeGroup string, ls service.LvmService) *ResizeLogicalVolumeAction {
+func NewResizeLogicalVolumeAction(name string, size model.LvmSize, volumeGroup string, ls service.LvmService) *ResizeLogicalVolumeAction {
 	return &ResizeLogicalVolumeAction{
-		name:               name,
-		volumeGroupPercent: volumeGroupPercent,
-		volumeGroup:        volumeGroup,
-		mode:               model.Empty,
-		lvmService:         ls,
+		name:        name,
+		size:        size,
+		volumeGroup: volumeGroup,
+		mode:        model.Empty,
+		lvmService:  ls,
 	}
 }
 
 func (a *ResizeLogicalVolumeAction) Execute() error {
-	return a.lvmService.ResizeLogicalVolume(a.name, a.volumeGroup, a.volumeGroupPercent)
+	return a.lvmService.ResizeLogicalVolume(a.name, a.volumeGroup, a.size)
 }
 
 func (a *ResizeLogicalVolumeAction) GetMode() model.Mode {
@@ -241,14 +432,39 @@ func (a *ResizeLogicalVolumeAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *ResizeLogicalVolumeAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ResizeLogicalVolumeAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ResizeLogicalVolumeAction) GetKind() model.ActionKind {
+	return model.ResizeLogicalVolumeAction
+}
+
+func (a *ResizeLogicalVolumeAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name":        a.name,
+		"volumeGroup": a.volumeGroup,
+		"size":        a.size.String(),
+	}
+}
+
 func (a *ResizeLogicalVolumeAction) Prompt() string {
-	return fmt.Sprintf("Would you like to resize logical volume %s to consume %d%% of the space of volume group %s", a.name, a.volumeGroupPercent, a.volumeGroup)
+	return fmt.Sprintf("Would you like to resize logical volume %s to size %s in volume group %s", a.name, a.size, a.volumeGroup)
 }
 
 func (a *ResizeLogicalVolumeAction) Refuse() string {
-	return fmt.Sprintf("Refused to resize logical volume %s to consume %d%% of the space of volume group %s", a.name, a.volumeGroupPercent, a.volumeGroup)
+	return fmt.Sprintf("Refused to resize logical volume %s to size %s in volume group %s", a.name, a.size, a.volumeGroup)
 }
 
 func (a *ResizeLogicalVolumeAction) Success() string {
-	return fmt.Sprintf("Successfully resized logical volume %s to consume %d%% of the space of volume group %s", a.name, a.volumeGroupPercent, a.volumeGroup)
+	return fmt.Sprintf("Successfully resized logical volume %s to size %s in volume group %s", a.name, a.size, a.volumeGroup)
+}
+
+func (a *ResizeLogicalVolumeAction) Plan() string {
+	return fmt.Sprintf("Resize logical volume %s to size %s in volume group %s", a.name, a.size, a.volumeGroup)
 }
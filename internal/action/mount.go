@@ -14,6 +14,7 @@ type MountDeviceAction struct {
 	options       model.MountOptions
 	deviceService service.DeviceService
 	mode          model.Mode
+	configDevice  string
 }
 
 func NewMountDeviceAction(source string, target string, fileSystem model.FileSystem, options model.MountOptions, deviceService service.DeviceService) *MountDeviceAction {
@@ -40,6 +41,28 @@ func (a *MountDeviceAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *MountDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *MountDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *MountDeviceAction) GetKind() model.ActionKind {
+	return model.MountAction
+}
+
+func (a *MountDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"source":     a.source,
+		"target":     a.target,
+		"fileSystem": a.fileSystem.String(),
+		"options":    string(a.options),
+	}
+}
+
 func (a *MountDeviceAction) Prompt() string {
 	return fmt.Sprintf("Would you like to mount %s to %s (%s)", a.source, a.target, a.options)
 }
@@ -52,11 +75,16 @@ func (a *MountDeviceAction) Success() string {
 	return fmt.Sprintf("Successfully mounted %s to %s (%s)", a.source, a.target, a.options)
 }
 
+func (a *MountDeviceAction) Plan() string {
+	return fmt.Sprintf("Mount %s to %s (%s)", a.source, a.target, a.options)
+}
+
 type UnmountDeviceAction struct {
 	source        string
 	target        string
 	deviceService service.DeviceService
 	mode          model.Mode
+	configDevice  string
 }
 
 func NewUnmountDeviceAction(source string, target string, deviceService service.DeviceService) *UnmountDeviceAction {
@@ -81,6 +109,26 @@ func (a *UnmountDeviceAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *UnmountDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *UnmountDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *UnmountDeviceAction) GetKind() model.ActionKind {
+	return model.UnmountAction
+}
+
+func (a *UnmountDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"source": a.source,
+		"target": a.target,
+	}
+}
+
 func (a *UnmountDeviceAction) Prompt() string {
 	return fmt.Sprintf("Would you like to unmount %s from %s", a.source, a.target)
 }
@@ -92,3 +140,7 @@ func (a *UnmountDeviceAction) Refuse() string {
 func (a *UnmountDeviceAction) Success() string {
 	return fmt.Sprintf("Successfully unmounted %s from %s", a.source, a.target)
 }
+
+func (a *UnmountDeviceAction) Plan() string {
+	return fmt.Sprintf("Unmount %s from %s", a.source, a.target)
+}
@@ -43,6 +43,11 @@ func TestMountDeviceActionMessages(t *testing.T) {
 			Message:        mda.Success(),
 			ExpectedOutput: "Successfully mounted /dev/xvdf to /mnt/foo (defaults)",
 		},
+		{
+			Name:           "Plan",
+			Message:        mda.Plan(),
+			ExpectedOutput: "Mount /dev/xvdf to /mnt/foo (defaults)",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -51,6 +56,17 @@ func TestMountDeviceActionMessages(t *testing.T) {
 	}
 }
 
+func TestMountDeviceActionParameters(t *testing.T) {
+	mda := NewMountDeviceAction("/dev/xvdf", "/mnt/foo", model.Ext4, "defaults", nil)
+	utils.CheckOutput("mda.GetKind()", t, model.MountAction, mda.GetKind())
+	utils.CheckOutput("mda.GetParameters()", t, map[string]string{
+		"source":     "/dev/xvdf",
+		"target":     "/mnt/foo",
+		"fileSystem": "ext4",
+		"options":    "defaults",
+	}, mda.GetParameters())
+}
+
 func TestUnmountDeviceActionExecute(t *testing.T) {
 	mds := service.NewMockDeviceService()
 	mds.StubUmount = func(source string, target string) error { return nil }
@@ -86,6 +102,11 @@ func TestUnmountDeviceActionMessages(t *testing.T) {
 			Message:        uda.Success(),
 			ExpectedOutput: "Successfully unmounted /dev/xvdf from /mnt/foo",
 		},
+		{
+			Name:           "Plan",
+			Message:        uda.Plan(),
+			ExpectedOutput: "Unmount /dev/xvdf from /mnt/foo",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
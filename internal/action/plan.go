@@ -0,0 +1,94 @@
+package action
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/report"
+)
+
+// PlanActionExecutor records every action that it is handed, instead of
+// seeking approval for it. Actions are still executed so that later layers can
+// observe the state they would produce. Therefore, it is essential that the
+// actions are bound to simulated services (see service.Simulation) and never
+// to services that would modify the host
+type PlanActionExecutor struct {
+	actions []Action
+}
+
+func NewPlanActionExecutor() *PlanActionExecutor {
+	return &PlanActionExecutor{
+		actions: []Action{},
+	}
+}
+
+func (pae *PlanActionExecutor) Execute(actions []Action) error {
+	for _, a := range actions {
+		if err := a.Execute(); err != nil {
+			return err
+		}
+		pae.actions = append(pae.actions, a)
+	}
+	return nil
+}
+
+func (pae *PlanActionExecutor) GetActions() []Action {
+	return pae.actions
+}
+
+// Print reports the planned actions grouped by device. Devices are listed in the
+// order that they were first encountered, and each action retains its position
+// in the overall order of execution
+func (pae *PlanActionExecutor) Print(r report.Reporter) {
+	if len(pae.actions) == 0 {
+		r.Report(&model.Event{Kind: model.PlanCreated, Message: "No changes. Devices already match the configuration"})
+		return
+	}
+	devices := []string{}
+	indices := map[string][]int{}
+	for i, a := range pae.actions {
+		d := a.GetDevice()
+		if _, found := indices[d]; !found {
+			devices = append(devices, d)
+		}
+		indices[d] = append(indices[d], i)
+	}
+	for _, d := range devices {
+		for _, i := range indices[d] {
+			a := pae.actions[i]
+			e := NewEvent(model.ActionPlanned, a)
+			e.Message = a.Plan()
+			e.Position = i + 1
+			r.Report(e)
+		}
+	}
+	r.Report(&model.Event{Kind: model.PlanCreated, Message: fmt.Sprintf("Plan: %d action(s) to be executed across %d device(s)", len(pae.actions), len(devices))})
+}
+
+// Save writes the planned actions to a file, along with the state of the devices
+// that the actions were planned against. The file is written directly to the
+// host, as it is not a modification of the devices being planned
+func (pae *PlanActionExecutor) Save(p string, state *model.ObservedState) error {
+	sp := &model.SavedPlan{
+		State:   state,
+		Actions: make([]*model.PlannedAction, len(pae.actions)),
+	}
+	for i, a := range pae.actions {
+		// An action retains its mode when applied. An action in healthcheck mode
+		// would always be refused, and so the plan could never be applied
+		if a.GetMode() == model.Healthcheck {
+			return fmt.Errorf("🔴 %s: Can not save a plan with an action in %s mode. Plan with -mode=%s or -mode=%s instead", a.GetDevice(), model.Healthcheck, model.Prompt, model.Force)
+		}
+		sp.Actions[i] = NewPlannedAction(a)
+	}
+	data, err := json.MarshalIndent(sp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("🔴 %s: %v", p, err)
+	}
+	if err := os.WriteFile(p, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("🔴 %s: %v", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/report"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestPlanActionExecutor(t *testing.T) {
+	subtests := []struct {
+		Name            string
+		Errors          []error
+		ExpectedDevices []string
+		ExpectedError   error
+	}{
+		{
+			Name:            "Record All Actions",
+			Errors:          []error{nil, nil},
+			ExpectedDevices: []string{"/dev/xvdf", "/dev/xvdg"},
+			ExpectedError:   nil,
+		},
+		{
+			Name:            "Simulation Failure",
+			Errors:          []error{nil, fmt.Errorf("🔴 Error encountered while simulating action")},
+			ExpectedDevices: []string{"/dev/xvdf"},
+			ExpectedError:   fmt.Errorf("🔴 Error encountered while simulating action"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			actions := []Action{}
+			for i, err := range subtest.Errors {
+				err := err
+				a := &MockAction{
+					execute: func() error { return err },
+				}
+				actions = append(actions, a.SetDevice(fmt.Sprintf("/dev/xvd%c", 'f'+i)))
+			}
+			pae := NewPlanActionExecutor()
+			err := pae.Execute(actions)
+			utils.CheckError("pae.Execute()", t, subtest.ExpectedError, err)
+
+			devices := []string{}
+			for _, a := range pae.GetActions() {
+				devices = append(devices, a.GetDevice())
+			}
+			utils.CheckOutput("pae.GetActions()", t, subtest.ExpectedDevices, devices)
+		})
+	}
+}
+
+func TestPlanActionExecutorPrint(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Devices        []string
+		ExpectedEvents []*model.Event
+	}{
+		{
+			Name:    "No Changes",
+			Devices: []string{},
+			ExpectedEvents: []*model.Event{
+				{Kind: model.PlanCreated, Message: "No changes. Devices already match the configuration"},
+			},
+		},
+		{
+			Name:    "Actions Grouped By Device",
+			Devices: []string{"/dev/xvdf", "/dev/xvdg", "/dev/xvdf"},
+			ExpectedEvents: []*model.Event{
+				{Kind: model.ActionPlanned, Device: "/dev/xvdf", Action: "mock", Parameters: map[string]string{}, Mode: model.Prompt, Message: "Execute action", Position: 1},
+				{Kind: model.ActionPlanned, Device: "/dev/xvdf", Action: "mock", Parameters: map[string]string{}, Mode: model.Prompt, Message: "Execute action", Position: 3},
+				{Kind: model.ActionPlanned, Device: "/dev/xvdg", Action: "mock", Parameters: map[string]string{}, Mode: model.Prompt, Message: "Execute action", Position: 2},
+				{Kind: model.PlanCreated, Message: "Plan: 3 action(s) to be executed across 2 device(s)"},
+			},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			actions := []Action{}
+			for _, d := range subtest.Devices {
+				a := &MockAction{
+					execute: func() error { return nil },
+				}
+				actions = append(actions, a.SetMode(model.Prompt).SetDevice(d))
+			}
+			pae := NewPlanActionExecutor()
+			utils.CheckError("pae.Execute()", t, nil, pae.Execute(actions))
+
+			mr := report.NewMockReporter()
+			pae.Print(mr)
+			utils.CheckOutput("pae.Print()", t, subtest.ExpectedEvents, mr.Events)
+		})
+	}
+}
+
+func TestPlanActionExecutorSave(t *testing.T) {
+	fs := service.NewMockFileService()
+	fs.StubCreateDirectory = func(p string) error {
+		return nil
+	}
+	state := &model.ObservedState{
+		Devices: []*model.ObservedDevice{
+			{Name: "/dev/xvdf", Present: true, FileSystem: model.Ext4, Size: 10737418240},
+		},
+	}
+	pae := NewPlanActionExecutor()
+	err := pae.Execute([]Action{
+		NewCreateDirectoryAction("/mnt/app", fs).SetMode(model.Force).SetDevice("/dev/xvdf"),
+	})
+	utils.CheckError("pae.Execute()", t, nil, err)
+
+	p := path.Join(t.TempDir(), "plan.json")
+	utils.CheckError("pae.Save()", t, nil, pae.Save(p, state))
+	data, err := os.ReadFile(p)
+	utils.CheckError("os.ReadFile()", t, nil, err)
+	sp, err := model.ParseSavedPlan(data)
+	utils.CheckError("model.ParseSavedPlan()", t, nil, err)
+	utils.CheckOutput("model.ParseSavedPlan()", t, &model.SavedPlan{
+		State: state,
+		Actions: []*model.PlannedAction{
+			{
+				Action:     model.CreateDirectoryAction,
+				Device:     "/dev/xvdf",
+				Mode:       model.Force,
+				Parameters: map[string]string{"path": "/mnt/app"},
+			},
+		},
+	}, sp)
+
+	err = pae.Save(path.Join(t.TempDir(), "missing", "plan.json"), state)
+	utils.ExpectErr("pae.Save()", t, true, err)
+
+	// A plan in healthcheck mode could never be applied, and so is never saved
+	p = path.Join(t.TempDir(), "healthcheck.json")
+	pae = NewPlanActionExecutor()
+	err = pae.Execute([]Action{
+		NewCreateDirectoryAction("/mnt/app", fs).SetMode(model.Healthcheck).SetDevice("/dev/xvdf"),
+	})
+	utils.CheckError("pae.Execute()", t, nil, err)
+	expected := fmt.Errorf("🔴 /dev/xvdf: Can not save a plan with an action in healthcheck mode. Plan with -mode=prompt or -mode=force instead")
+	utils.CheckError("pae.Save()", t, expected, pae.Save(p, state))
+	_, err = os.Stat(p)
+	utils.CheckOutput("os.IsNotExist()", t, true, os.IsNotExist(err))
+}
@@ -0,0 +1,147 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type CreateRaidArrayAction struct {
+	name         string
+	level        model.RaidLevel
+	chunkSize    uint64
+	devices      []string
+	mode         model.Mode
+	configDevice string
+	mdadmService service.MdadmService
+}
+
+func NewCreateRaidArrayAction(name string, level model.RaidLevel, chunkSize uint64, devices []string, ms service.MdadmService) *CreateRaidArrayAction {
+	return &CreateRaidArrayAction{
+		name:         name,
+		level:        level,
+		chunkSize:    chunkSize,
+		devices:      devices,
+		mode:         model.Empty,
+		mdadmService: ms,
+	}
+}
+
+func (a *CreateRaidArrayAction) Execute() error {
+	return a.mdadmService.CreateArray(a.name, a.level, a.chunkSize, a.devices)
+}
+
+func (a *CreateRaidArrayAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *CreateRaidArrayAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *CreateRaidArrayAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *CreateRaidArrayAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *CreateRaidArrayAction) GetKind() model.ActionKind {
+	return model.CreateRaidArrayAction
+}
+
+func (a *CreateRaidArrayAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name":      a.name,
+		"level":     string(a.level),
+		"chunkSize": fmt.Sprint(a.chunkSize),
+		"devices":   strings.Join(a.devices, ","),
+	}
+}
+
+func (a *CreateRaidArrayAction) Prompt() string {
+	return fmt.Sprintf("Would you like to create %s array %s from %s", a.level, a.name, strings.Join(a.devices, ", "))
+}
+
+func (a *CreateRaidArrayAction) Refuse() string {
+	return fmt.Sprintf("Refused to create %s array %s from %s", a.level, a.name, strings.Join(a.devices, ", "))
+}
+
+func (a *CreateRaidArrayAction) Success() string {
+	return fmt.Sprintf("Successfully created %s array %s from %s", a.level, a.name, strings.Join(a.devices, ", "))
+}
+
+func (a *CreateRaidArrayAction) Plan() string {
+	return fmt.Sprintf("Create %s array %s from %s", a.level, a.name, strings.Join(a.devices, ", "))
+}
+
+type AssembleRaidArrayAction struct {
+	name         string
+	devices      []string
+	mode         model.Mode
+	configDevice string
+	mdadmService service.MdadmService
+}
+
+func NewAssembleRaidArrayAction(name string, devices []string, ms service.MdadmService) *AssembleRaidArrayAction {
+	return &AssembleRaidArrayAction{
+		name:         name,
+		devices:      devices,
+		mode:         model.Empty,
+		mdadmService: ms,
+	}
+}
+
+func (a *AssembleRaidArrayAction) Execute() error {
+	return a.mdadmService.AssembleArray(a.name, a.devices)
+}
+
+func (a *AssembleRaidArrayAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *AssembleRaidArrayAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *AssembleRaidArrayAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *AssembleRaidArrayAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *AssembleRaidArrayAction) GetKind() model.ActionKind {
+	return model.AssembleRaidArrayAction
+}
+
+func (a *AssembleRaidArrayAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name":    a.name,
+		"devices": strings.Join(a.devices, ","),
+	}
+}
+
+func (a *AssembleRaidArrayAction) Prompt() string {
+	return fmt.Sprintf("Would you like to assemble array %s from %s", a.name, strings.Join(a.devices, ", "))
+}
+
+func (a *AssembleRaidArrayAction) Refuse() string {
+	return fmt.Sprintf("Refused to assemble array %s from %s", a.name, strings.Join(a.devices, ", "))
+}
+
+func (a *AssembleRaidArrayAction) Success() string {
+	return fmt.Sprintf("Successfully assembled array %s from %s", a.name, strings.Join(a.devices, ", "))
+}
+
+func (a *AssembleRaidArrayAction) Plan() string {
+	return fmt.Sprintf("Assemble array %s from %s", a.name, strings.Join(a.devices, ", "))
+}
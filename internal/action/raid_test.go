@@ -0,0 +1,58 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestCreateRaidArrayActionExecute(t *testing.T) {
+	var args []any
+	mms := service.NewMockMdadmService()
+	mms.StubCreateArray = func(name string, level model.RaidLevel, chunkSize uint64, devices []string) error {
+		args = []any{name, level, chunkSize, devices}
+		return nil
+	}
+	craa := NewCreateRaidArrayAction("scratch", model.Raid0, 256, []string{"/dev/nvme1n1", "/dev/nvme2n1"}, mms)
+	utils.ExpectErr("craa.Execute()", t, false, craa.Execute())
+	utils.CheckOutput("craa.Execute()", t, []any{"scratch", model.Raid0, uint64(256), []string{"/dev/nvme1n1", "/dev/nvme2n1"}}, args)
+}
+
+func TestRaidArrayActionMessages(t *testing.T) {
+	devices := []string{"/dev/nvme1n1", "/dev/nvme2n1"}
+	craa := NewCreateRaidArrayAction("scratch", model.Raid0, 0, devices, nil)
+	araa := NewAssembleRaidArrayAction("scratch", devices, nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Create + Prompt",
+			Message:        craa.Prompt(),
+			ExpectedOutput: "Would you like to create raid0 array scratch from /dev/nvme1n1, /dev/nvme2n1",
+		},
+		{
+			Name:           "Create + Success",
+			Message:        craa.Success(),
+			ExpectedOutput: "Successfully created raid0 array scratch from /dev/nvme1n1, /dev/nvme2n1",
+		},
+		{
+			Name:           "Assemble + Prompt",
+			Message:        araa.Prompt(),
+			ExpectedOutput: "Would you like to assemble array scratch from /dev/nvme1n1, /dev/nvme2n1",
+		},
+		{
+			Name:           "Assemble + Refuse",
+			Message:        araa.Refuse(),
+			ExpectedOutput: "Refused to assemble array scratch from /dev/nvme1n1, /dev/nvme2n1",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
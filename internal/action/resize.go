@@ -12,6 +12,7 @@ type ResizeDeviceAction struct {
 	target            string
 	fileSystemService service.FileSystemService
 	mode              model.Mode
+	configDevice      string
 }
 
 func NewResizeDeviceAction(d string, target string, fileSystemService service.FileSystemService) *ResizeDeviceAction {
@@ -36,6 +37,27 @@ func (a *ResizeDeviceAction) SetMode(mode model.Mode) Action {
 	return a
 }
 
+func (a *ResizeDeviceAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ResizeDeviceAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ResizeDeviceAction) GetKind() model.ActionKind {
+	return model.ResizeAction
+}
+
+func (a *ResizeDeviceAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":     a.device,
+		"target":     a.target,
+		"fileSystem": a.fileSystemService.GetFileSystem().String(),
+	}
+}
+
 func (a *ResizeDeviceAction) Prompt() string {
 	return fmt.Sprintf("Would you like to resize the %s file system of %s", a.fileSystemService.GetFileSystem(), a.device)
 }
@@ -47,3 +69,7 @@ func (a *ResizeDeviceAction) Refuse() string {
 func (a *ResizeDeviceAction) Success() string {
 	return fmt.Sprintf("Successfully resized the %s file system of %s", a.fileSystemService.GetFileSystem(), a.device)
 }
+
+func (a *ResizeDeviceAction) Plan() string {
+	return fmt.Sprintf("Resize the %s file system of %s", a.fileSystemService.GetFileSystem(), a.device)
+}
@@ -43,6 +43,11 @@ func TestResizeDeviceActionMessages(t *testing.T) {
 			Message:        rda.Success(),
 			ExpectedOutput: "Successfully resized the ext4 file system of /dev/xvdf",
 		},
+		{
+			Name:           "Plan",
+			Message:        rda.Plan(),
+			ExpectedOutput: "Resize the ext4 file system of /dev/xvdf",
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -0,0 +1,215 @@
+package action
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type CreateSwapFileAction struct {
+	path         string
+	size         uint64
+	mode         model.Mode
+	configDevice string
+	swapService  service.SwapService
+}
+
+func NewCreateSwapFileAction(p string, size uint64, ss service.SwapService) *CreateSwapFileAction {
+	return &CreateSwapFileAction{
+		path:        p,
+		size:        size,
+		mode:        model.Empty,
+		swapService: ss,
+	}
+}
+
+func (a *CreateSwapFileAction) Execute() error {
+	return a.swapService.CreateFile(a.path, a.size)
+}
+
+func (a *CreateSwapFileAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *CreateSwapFileAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *CreateSwapFileAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *CreateSwapFileAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *CreateSwapFileAction) GetKind() model.ActionKind {
+	return model.CreateSwapFileAction
+}
+
+func (a *CreateSwapFileAction) GetParameters() map[string]string {
+	return map[string]string{
+		"path": a.path,
+		"size": model.FormatByteSize(a.size),
+	}
+}
+
+func (a *CreateSwapFileAction) Prompt() string {
+	return fmt.Sprintf("Would you like to create a %s swap file at %s", model.FormatByteSize(a.size), a.path)
+}
+
+func (a *CreateSwapFileAction) Refuse() string {
+	return fmt.Sprintf("Refused to create a %s swap file at %s", model.FormatByteSize(a.size), a.path)
+}
+
+func (a *CreateSwapFileAction) Success() string {
+	return fmt.Sprintf("Successfully created a %s swap file at %s", model.FormatByteSize(a.size), a.path)
+}
+
+func (a *CreateSwapFileAction) Plan() string {
+	return fmt.Sprintf("Create a %s swap file at %s", model.FormatByteSize(a.size), a.path)
+}
+
+type ActivateSwapAction struct {
+	name         string
+	priority     *int
+	mode         model.Mode
+	configDevice string
+	swapService  service.SwapService
+}
+
+func NewActivateSwapAction(name string, priority *int, ss service.SwapService) *ActivateSwapAction {
+	return &ActivateSwapAction{
+		name:        name,
+		priority:    priority,
+		mode:        model.Empty,
+		swapService: ss,
+	}
+}
+
+func (a *ActivateSwapAction) Execute() error {
+	return a.swapService.Activate(a.name, a.priority)
+}
+
+func (a *ActivateSwapAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *ActivateSwapAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *ActivateSwapAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ActivateSwapAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ActivateSwapAction) GetKind() model.ActionKind {
+	return model.ActivateSwapAction
+}
+
+func (a *ActivateSwapAction) GetParameters() map[string]string {
+	parameters := map[string]string{
+		"name": a.name,
+	}
+	if a.priority != nil {
+		parameters["priority"] = strconv.Itoa(*a.priority)
+	}
+	return parameters
+}
+
+func (a *ActivateSwapAction) Prompt() string {
+	return fmt.Sprintf("Would you like to activate swap on %s%s", a.name, a.describePriority())
+}
+
+func (a *ActivateSwapAction) Refuse() string {
+	return fmt.Sprintf("Refused to activate swap on %s%s", a.name, a.describePriority())
+}
+
+func (a *ActivateSwapAction) Success() string {
+	return fmt.Sprintf("Successfully activated swap on %s%s", a.name, a.describePriority())
+}
+
+func (a *ActivateSwapAction) Plan() string {
+	return fmt.Sprintf("Activate swap on %s%s", a.name, a.describePriority())
+}
+
+// A swap area without a priority is assigned one by the kernel
+func (a *ActivateSwapAction) describePriority() string {
+	if a.priority == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (priority %d)", *a.priority)
+}
+
+type DeactivateSwapAction struct {
+	name         string
+	mode         model.Mode
+	configDevice string
+	swapService  service.SwapService
+}
+
+func NewDeactivateSwapAction(name string, ss service.SwapService) *DeactivateSwapAction {
+	return &DeactivateSwapAction{
+		name:        name,
+		mode:        model.Empty,
+		swapService: ss,
+	}
+}
+
+func (a *DeactivateSwapAction) Execute() error {
+	return a.swapService.Deactivate(a.name)
+}
+
+func (a *DeactivateSwapAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *DeactivateSwapAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *DeactivateSwapAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *DeactivateSwapAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *DeactivateSwapAction) GetKind() model.ActionKind {
+	return model.DeactivateSwapAction
+}
+
+func (a *DeactivateSwapAction) GetParameters() map[string]string {
+	return map[string]string{
+		"name": a.name,
+	}
+}
+
+func (a *DeactivateSwapAction) Prompt() string {
+	return fmt.Sprintf("Would you like to deactivate swap on %s", a.name)
+}
+
+func (a *DeactivateSwapAction) Refuse() string {
+	return fmt.Sprintf("Refused to deactivate swap on %s", a.name)
+}
+
+func (a *DeactivateSwapAction) Success() string {
+	return fmt.Sprintf("Successfully deactivated swap on %s", a.name)
+}
+
+func (a *DeactivateSwapAction) Plan() string {
+	return fmt.Sprintf("Deactivate swap on %s", a.name)
+}
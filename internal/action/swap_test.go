@@ -0,0 +1,164 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestCreateSwapFileActionExecute(t *testing.T) {
+	mss := service.NewMockSwapService()
+	mss.StubCreateFile = func(name string, size uint64) error { return nil }
+	csfa := NewCreateSwapFileAction("/mnt/scratch/swapfile", 4294967296, mss)
+	utils.ExpectErr("csfa.Execute()", t, false, csfa.Execute())
+}
+
+func TestCreateSwapFileActionMessages(t *testing.T) {
+	csfa := NewCreateSwapFileAction("/mnt/scratch/swapfile", 4294967296, nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        csfa.Prompt(),
+			ExpectedOutput: "Would you like to create a 4GiB swap file at /mnt/scratch/swapfile",
+		},
+		{
+			Name:           "Refuse",
+			Message:        csfa.Refuse(),
+			ExpectedOutput: "Refused to create a 4GiB swap file at /mnt/scratch/swapfile",
+		},
+		{
+			Name:           "Success",
+			Message:        csfa.Success(),
+			ExpectedOutput: "Successfully created a 4GiB swap file at /mnt/scratch/swapfile",
+		},
+		{
+			Name:           "Plan",
+			Message:        csfa.Plan(),
+			ExpectedOutput: "Create a 4GiB swap file at /mnt/scratch/swapfile",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
+
+func TestCreateSwapFileActionParameters(t *testing.T) {
+	csfa := NewCreateSwapFileAction("/mnt/scratch/swapfile", 4294967296, nil)
+	utils.CheckOutput("csfa.GetKind()", t, model.CreateSwapFileAction, csfa.GetKind())
+	utils.CheckOutput("csfa.GetParameters()", t, map[string]string{
+		"path": "/mnt/scratch/swapfile",
+		"size": "4GiB",
+	}, csfa.GetParameters())
+}
+
+func TestActivateSwapActionExecute(t *testing.T) {
+	priority := 10
+	mss := service.NewMockSwapService()
+	mss.StubActivate = func(name string, p *int) error {
+		utils.CheckOutput("priority", t, &priority, p)
+		return nil
+	}
+	asa := NewActivateSwapAction("/dev/nvme1n1", &priority, mss)
+	utils.ExpectErr("asa.Execute()", t, false, asa.Execute())
+}
+
+func TestActivateSwapActionMessages(t *testing.T) {
+	priority := 10
+	asa := NewActivateSwapAction("/dev/nvme1n1", &priority, nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        asa.Prompt(),
+			ExpectedOutput: "Would you like to activate swap on /dev/nvme1n1 (priority 10)",
+		},
+		{
+			Name:           "Refuse",
+			Message:        asa.Refuse(),
+			ExpectedOutput: "Refused to activate swap on /dev/nvme1n1 (priority 10)",
+		},
+		{
+			Name:           "Success",
+			Message:        asa.Success(),
+			ExpectedOutput: "Successfully activated swap on /dev/nvme1n1 (priority 10)",
+		},
+		{
+			Name:           "Plan",
+			Message:        asa.Plan(),
+			ExpectedOutput: "Activate swap on /dev/nvme1n1 (priority 10)",
+		},
+		{
+			Name:           "Plan Without Priority",
+			Message:        NewActivateSwapAction("/dev/nvme1n1", nil, nil).Plan(),
+			ExpectedOutput: "Activate swap on /dev/nvme1n1",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
+
+func TestActivateSwapActionParameters(t *testing.T) {
+	priority := -1
+	asa := NewActivateSwapAction("/dev/nvme1n1", &priority, nil)
+	utils.CheckOutput("asa.GetKind()", t, model.ActivateSwapAction, asa.GetKind())
+	utils.CheckOutput("asa.GetParameters()", t, map[string]string{
+		"name":     "/dev/nvme1n1",
+		"priority": "-1",
+	}, asa.GetParameters())
+}
+
+func TestDeactivateSwapActionExecute(t *testing.T) {
+	mss := service.NewMockSwapService()
+	mss.StubDeactivate = func(name string) error { return nil }
+	dsa := NewDeactivateSwapAction("/dev/nvme1n1", mss)
+	utils.ExpectErr("dsa.Execute()", t, false, dsa.Execute())
+}
+
+func TestDeactivateSwapActionMessages(t *testing.T) {
+	dsa := NewDeactivateSwapAction("/dev/nvme1n1", nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        dsa.Prompt(),
+			ExpectedOutput: "Would you like to deactivate swap on /dev/nvme1n1",
+		},
+		{
+			Name:           "Refuse",
+			Message:        dsa.Refuse(),
+			ExpectedOutput: "Refused to deactivate swap on /dev/nvme1n1",
+		},
+		{
+			Name:           "Success",
+			Message:        dsa.Success(),
+			ExpectedOutput: "Successfully deactivated swap on /dev/nvme1n1",
+		},
+		{
+			Name:           "Plan",
+			Message:        dsa.Plan(),
+			ExpectedOutput: "Deactivate swap on /dev/nvme1n1",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
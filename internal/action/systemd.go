@@ -0,0 +1,96 @@
+package action
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+const (
+	DefaultMountUnitPermissions = model.FilePermissions(0644)
+)
+
+type UpdateMountUnitAction struct {
+	path           string
+	unit           *model.MountUnit
+	mode           model.Mode
+	configDevice   string
+	fileService    service.FileService
+	systemdService service.SystemdService
+}
+
+func NewUpdateMountUnitAction(p string, unit *model.MountUnit, fs service.FileService, ss service.SystemdService) *UpdateMountUnitAction {
+	return &UpdateMountUnitAction{
+		path:           p,
+		unit:           unit,
+		mode:           model.Empty,
+		fileService:    fs,
+		systemdService: ss,
+	}
+}
+
+// The service manager must reload its configuration before it can enable a unit
+// that was written or modified. Enabling the unit creates the symbolic links that
+// are declared in the [Install] section of the unit
+func (a *UpdateMountUnitAction) Execute() error {
+	err := a.fileService.WriteFile(a.path, []byte(a.unit.String()), DefaultMountUnitPermissions)
+	if err != nil {
+		return err
+	}
+	err = a.systemdService.DaemonReload()
+	if err != nil {
+		return err
+	}
+	return a.systemdService.EnableUnit(a.unit.Name())
+}
+
+func (a *UpdateMountUnitAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *UpdateMountUnitAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *UpdateMountUnitAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *UpdateMountUnitAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *UpdateMountUnitAction) GetKind() model.ActionKind {
+	return model.UpdateMountUnitAction
+}
+
+func (a *UpdateMountUnitAction) GetParameters() map[string]string {
+	return map[string]string{
+		"path":       a.path,
+		"what":       a.unit.What,
+		"where":      a.unit.Where,
+		"fileSystem": a.unit.Type.String(),
+		"options":    string(a.unit.Options),
+		"requiredBy": strings.Join(a.unit.RequiredBy, " "),
+	}
+}
+
+func (a *UpdateMountUnitAction) Prompt() string {
+	return fmt.Sprintf("Would you like to write and enable the mount unit %s to mount %s to %s", a.path, a.unit.What, a.unit.Where)
+}
+
+func (a *UpdateMountUnitAction) Refuse() string {
+	return fmt.Sprintf("Refused to write and enable the mount unit %s to mount %s to %s", a.path, a.unit.What, a.unit.Where)
+}
+
+func (a *UpdateMountUnitAction) Success() string {
+	return fmt.Sprintf("Successfully wrote and enabled the mount unit %s to mount %s to %s", a.path, a.unit.What, a.unit.Where)
+}
+
+func (a *UpdateMountUnitAction) Plan() string {
+	return fmt.Sprintf("Write and enable the mount unit %s to mount %s to %s", a.path, a.unit.What, a.unit.Where)
+}
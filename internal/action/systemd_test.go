@@ -0,0 +1,113 @@
+package action
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestUpdateMountUnitActionExecute(t *testing.T) {
+	unit := &model.MountUnit{
+		What:    "/dev/disk/by-label/stateful",
+		Where:   "/mnt/ebs",
+		Type:    model.Ext4,
+		Options: "defaults",
+	}
+	subtests := []struct {
+		Name          string
+		WriteError    error
+		ReloadError   error
+		ExpectedCalls []string
+		ExpectedError error
+	}{
+		{
+			Name:          "Success",
+			WriteError:    nil,
+			ReloadError:   nil,
+			ExpectedCalls: []string{"WriteFile /etc/systemd/system/mnt-ebs.mount", "DaemonReload", "EnableUnit mnt-ebs.mount"},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "Write Failure",
+			WriteError:    fmt.Errorf("🔴 permission denied"),
+			ReloadError:   nil,
+			ExpectedCalls: []string{"WriteFile /etc/systemd/system/mnt-ebs.mount"},
+			ExpectedError: fmt.Errorf("🔴 permission denied"),
+		},
+		{
+			Name:          "Reload Failure",
+			WriteError:    nil,
+			ReloadError:   fmt.Errorf("🔴 exit status 1"),
+			ExpectedCalls: []string{"WriteFile /etc/systemd/system/mnt-ebs.mount", "DaemonReload"},
+			ExpectedError: fmt.Errorf("🔴 exit status 1"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			calls := []string{}
+			mfs := service.NewMockFileService()
+			mfs.StubWriteFile = func(p string, d []byte, fp model.FilePermissions) error {
+				calls = append(calls, "WriteFile "+p)
+				utils.CheckOutput("WriteFile()", t, unit.String(), string(d))
+				utils.CheckOutput("WriteFile()", t, DefaultMountUnitPermissions, fp)
+				return subtest.WriteError
+			}
+			mss := service.NewMockSystemdService()
+			mss.StubDaemonReload = func() error {
+				calls = append(calls, "DaemonReload")
+				return subtest.ReloadError
+			}
+			mss.StubEnableUnit = func(name string) error {
+				calls = append(calls, "EnableUnit "+name)
+				return nil
+			}
+			umua := NewUpdateMountUnitAction("/etc/systemd/system/mnt-ebs.mount", unit, mfs, mss)
+			err := umua.Execute()
+			utils.CheckError("umua.Execute()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("umua.Execute()", t, subtest.ExpectedCalls, calls)
+		})
+	}
+}
+
+func TestUpdateMountUnitActionMessages(t *testing.T) {
+	umua := NewUpdateMountUnitAction("/etc/systemd/system/mnt-ebs.mount", &model.MountUnit{
+		What:    "/dev/disk/by-label/stateful",
+		Where:   "/mnt/ebs",
+		Type:    model.Ext4,
+		Options: "defaults",
+	}, nil, nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        umua.Prompt(),
+			ExpectedOutput: "Would you like to write and enable the mount unit /etc/systemd/system/mnt-ebs.mount to mount /dev/disk/by-label/stateful to /mnt/ebs",
+		},
+		{
+			Name:           "Refuse",
+			Message:        umua.Refuse(),
+			ExpectedOutput: "Refused to write and enable the mount unit /etc/systemd/system/mnt-ebs.mount to mount /dev/disk/by-label/stateful to /mnt/ebs",
+		},
+		{
+			Name:           "Success",
+			Message:        umua.Success(),
+			ExpectedOutput: "Successfully wrote and enabled the mount unit /etc/systemd/system/mnt-ebs.mount to mount /dev/disk/by-label/stateful to /mnt/ebs",
+		},
+		{
+			Name:           "Plan",
+			Message:        umua.Plan(),
+			ExpectedOutput: "Write and enable the mount unit /etc/systemd/system/mnt-ebs.mount to mount /dev/disk/by-label/stateful to /mnt/ebs",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
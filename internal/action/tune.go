@@ -0,0 +1,75 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type TuneFileSystemAction struct {
+	device            string
+	properties        *model.FileSystemProperties
+	fileSystemService service.FileSystemService
+	mode              model.Mode
+	configDevice      string
+}
+
+func NewTuneFileSystemAction(d string, properties *model.FileSystemProperties, fileSystemService service.FileSystemService) *TuneFileSystemAction {
+	return &TuneFileSystemAction{
+		device:            d,
+		properties:        properties,
+		fileSystemService: fileSystemService,
+		mode:              model.Empty,
+	}
+}
+
+func (a *TuneFileSystemAction) Execute() error {
+	return a.fileSystemService.Tune(a.device, a.properties)
+}
+
+func (a *TuneFileSystemAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *TuneFileSystemAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *TuneFileSystemAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *TuneFileSystemAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *TuneFileSystemAction) GetKind() model.ActionKind {
+	return model.TuneAction
+}
+
+func (a *TuneFileSystemAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":     a.device,
+		"fileSystem": a.fileSystemService.GetFileSystem().String(),
+		"properties": a.properties.String(),
+	}
+}
+
+func (a *TuneFileSystemAction) Prompt() string {
+	return fmt.Sprintf("Would you like to tune the file system of %s (%s)", a.device, a.properties)
+}
+
+func (a *TuneFileSystemAction) Refuse() string {
+	return fmt.Sprintf("Refused to tune the file system of %s", a.device)
+}
+
+func (a *TuneFileSystemAction) Success() string {
+	return fmt.Sprintf("Successfully tuned the file system of %s (%s)", a.device, a.properties)
+}
+
+func (a *TuneFileSystemAction) Plan() string {
+	return fmt.Sprintf("Tune the file system of %s (%s)", a.device, a.properties)
+}
@@ -0,0 +1,63 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestTuneFileSystemActionExecute(t *testing.T) {
+	mfs := service.NewMockFileSystemService()
+	mfs.StubTune = func(name string, properties *model.FileSystemProperties) error { return nil }
+	tfa := NewTuneFileSystemAction("/dev/xvdf", &model.FileSystemProperties{Errors: model.ErrorsRemountRo}, mfs)
+	utils.ExpectErr("tfa.Execute()", t, false, tfa.Execute())
+}
+
+func TestTuneFileSystemActionParameters(t *testing.T) {
+	mfs := service.NewMockFileSystemService()
+	mfs.StubGetFileSystem = func() model.FileSystem { return model.Ext4 }
+	tfa := NewTuneFileSystemAction("/dev/xvdf", &model.FileSystemProperties{Errors: model.ErrorsRemountRo, Features: []string{"fast_commit"}}, mfs)
+	utils.CheckOutput("tfa.GetKind()", t, model.TuneAction, tfa.GetKind())
+	utils.CheckOutput("tfa.GetParameters()", t, map[string]string{
+		"device":     "/dev/xvdf",
+		"fileSystem": "ext4",
+		"properties": "errors=remount-ro,features=fast_commit",
+	}, tfa.GetParameters())
+}
+
+func TestTuneFileSystemActionMessages(t *testing.T) {
+	tfa := NewTuneFileSystemAction("/dev/xvdf", &model.FileSystemProperties{Errors: model.ErrorsRemountRo}, nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt",
+			Message:        tfa.Prompt(),
+			ExpectedOutput: "Would you like to tune the file system of /dev/xvdf (errors=remount-ro)",
+		},
+		{
+			Name:           "Refuse",
+			Message:        tfa.Refuse(),
+			ExpectedOutput: "Refused to tune the file system of /dev/xvdf",
+		},
+		{
+			Name:           "Success",
+			Message:        tfa.Success(),
+			ExpectedOutput: "Successfully tuned the file system of /dev/xvdf (errors=remount-ro)",
+		},
+		{
+			Name:           "Plan",
+			Message:        tfa.Plan(),
+			ExpectedOutput: "Tune the file system of /dev/xvdf (errors=remount-ro)",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
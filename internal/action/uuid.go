@@ -0,0 +1,84 @@
+package action
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type ChangeUuidAction struct {
+	device            string
+	uuid              string
+	fileSystemService service.FileSystemService
+	mode              model.Mode
+	configDevice      string
+}
+
+func NewChangeUuidAction(d string, uuid string, fileSystemService service.FileSystemService) *ChangeUuidAction {
+	return &ChangeUuidAction{
+		device:            d,
+		uuid:              uuid,
+		fileSystemService: fileSystemService,
+		mode:              model.Empty,
+	}
+}
+
+func (a *ChangeUuidAction) Execute() error {
+	return a.fileSystemService.SetUuid(a.device, a.uuid)
+}
+
+func (a *ChangeUuidAction) GetMode() model.Mode {
+	return a.mode
+}
+
+func (a *ChangeUuidAction) SetMode(mode model.Mode) Action {
+	a.mode = mode
+	return a
+}
+
+func (a *ChangeUuidAction) GetDevice() string {
+	return a.configDevice
+}
+
+func (a *ChangeUuidAction) SetDevice(device string) Action {
+	a.configDevice = device
+	return a
+}
+
+func (a *ChangeUuidAction) GetKind() model.ActionKind {
+	return model.ChangeUuidAction
+}
+
+func (a *ChangeUuidAction) GetParameters() map[string]string {
+	return map[string]string{
+		"device":     a.device,
+		"uuid":       a.uuid,
+		"fileSystem": a.fileSystemService.GetFileSystem().String(),
+	}
+}
+
+func (a *ChangeUuidAction) Prompt() string {
+	if a.uuid == model.RandomUuid {
+		return fmt.Sprintf("Would you like to assign a new random UUID to %s", a.device)
+	}
+	return fmt.Sprintf("Would you like to change the UUID of %s to %s", a.device, a.uuid)
+}
+
+func (a *ChangeUuidAction) Refuse() string {
+	return fmt.Sprintf("Refused to change the UUID of %s", a.device)
+}
+
+func (a *ChangeUuidAction) Success() string {
+	if a.uuid == model.RandomUuid {
+		return fmt.Sprintf("Successfully assigned a new random UUID to %s", a.device)
+	}
+	return fmt.Sprintf("Successfully changed the UUID of %s to %s", a.device, a.uuid)
+}
+
+func (a *ChangeUuidAction) Plan() string {
+	if a.uuid == model.RandomUuid {
+		return fmt.Sprintf("Assign a new random UUID to %s", a.device)
+	}
+	return fmt.Sprintf("Change the UUID of %s to %s", a.device, a.uuid)
+}
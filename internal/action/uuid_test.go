@@ -0,0 +1,62 @@
+package action
+
+import (
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestChangeUuidActionExecute(t *testing.T) {
+	mfs := service.NewMockFileSystemService()
+	mfs.StubSetUuid = func(name string, uuid string) error { return nil }
+	cua := NewChangeUuidAction("/dev/xvdf", model.RandomUuid, mfs)
+	utils.ExpectErr("cua.Execute()", t, false, cua.Execute())
+}
+
+func TestChangeUuidActionMessages(t *testing.T) {
+	random := NewChangeUuidAction("/dev/xvdf", model.RandomUuid, nil)
+	fixed := NewChangeUuidAction("/dev/xvdf", "31cf3150-f4b1-409a-9887-7f9da1fdd99c", nil)
+	subtests := []struct {
+		Name           string
+		Message        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Prompt (Random)",
+			Message:        random.Prompt(),
+			ExpectedOutput: "Would you like to assign a new random UUID to /dev/xvdf",
+		},
+		{
+			Name:           "Prompt (Fixed)",
+			Message:        fixed.Prompt(),
+			ExpectedOutput: "Would you like to change the UUID of /dev/xvdf to 31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+		},
+		{
+			Name:           "Refuse",
+			Message:        random.Refuse(),
+			ExpectedOutput: "Refused to change the UUID of /dev/xvdf",
+		},
+		{
+			Name:           "Success (Random)",
+			Message:        random.Success(),
+			ExpectedOutput: "Successfully assigned a new random UUID to /dev/xvdf",
+		},
+		{
+			Name:           "Success (Fixed)",
+			Message:        fixed.Success(),
+			ExpectedOutput: "Successfully changed the UUID of /dev/xvdf to 31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+		},
+		{
+			Name:           "Plan",
+			Message:        random.Plan(),
+			ExpectedOutput: "Assign a new random UUID to /dev/xvdf",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput(subtest.Name, t, subtest.ExpectedOutput, subtest.Message)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/reecetech/ebs-bootstrap/internal/action"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
@@ -12,8 +13,14 @@ import (
 type DeviceBackend interface {
 	GetBlockDevice(device string) (*model.BlockDevice, error)
 	Label(bd *model.BlockDevice, label string) ([]action.Action, error)
+	GetClones(bd *model.BlockDevice) []string
+	SetUuid(bd *model.BlockDevice, uuid string) ([]action.Action, error)
 	Resize(bd *model.BlockDevice) (action.Action, error)
-	Format(bd *model.BlockDevice, fileSystem model.FileSystem) (action.Action, error)
+	Format(bd *model.BlockDevice, fileSystem model.FileSystem, options *model.FormatOptions) (action.Action, error)
+	GetSignatures(bd *model.BlockDevice) []*model.Signature
+	Wipe(bd *model.BlockDevice, signatures []*model.Signature) action.Action
+	IsInstanceStore(bd *model.BlockDevice) bool
+	Reformat(bd *model.BlockDevice, fileSystem model.FileSystem, options *model.FormatOptions) ([]action.Action, error)
 	Mount(bd *model.BlockDevice, target string, options model.MountOptions) action.Action
 	Remount(bd *model.BlockDevice, target string, options model.MountOptions) action.Action
 	Umount(bd *model.BlockDevice) action.Action
@@ -22,21 +29,58 @@ type DeviceBackend interface {
 
 type LinuxDeviceBackend struct {
 	blockDevices             map[string]*model.BlockDevice
+	signatures               map[string][]*model.Signature
+	instanceStores           map[string]bool
+	clones                   map[string][]string
 	deviceService            service.DeviceService
+	nvmeService              service.NVMeService
 	fileSystemServiceFactory service.FileSystemServiceFactory
 }
 
-func NewLinuxDeviceBackend(ds service.DeviceService, fssf service.FileSystemServiceFactory) *LinuxDeviceBackend {
+func NewLinuxDeviceBackend(ds service.DeviceService, ns service.NVMeService, fssf service.FileSystemServiceFactory) *LinuxDeviceBackend {
 	return &LinuxDeviceBackend{
 		blockDevices:             map[string]*model.BlockDevice{},
+		signatures:               map[string][]*model.Signature{},
+		instanceStores:           map[string]bool{},
+		clones:                   map[string][]string{},
 		deviceService:            ds,
+		nvmeService:              ns,
 		fileSystemServiceFactory: fssf,
 	}
 }
 
 func NewMockLinuxDeviceBackend(blockDevices map[string]*model.BlockDevice) *LinuxDeviceBackend {
+	return NewMockLinuxDeviceBackendWithSignatures(blockDevices, map[string][]*model.Signature{})
+}
+
+func NewMockLinuxDeviceBackendWithSignatures(blockDevices map[string]*model.BlockDevice, signatures map[string][]*model.Signature) *LinuxDeviceBackend {
+	return &LinuxDeviceBackend{
+		blockDevices:             blockDevices,
+		signatures:               signatures,
+		instanceStores:           map[string]bool{},
+		clones:                   map[string][]string{},
+		deviceService:            nil,
+		fileSystemServiceFactory: service.NewLinuxFileSystemServiceFactory(nil),
+	}
+}
+
+func NewMockLinuxDeviceBackendWithInstanceStores(blockDevices map[string]*model.BlockDevice, instanceStores map[string]bool) *LinuxDeviceBackend {
 	return &LinuxDeviceBackend{
 		blockDevices:             blockDevices,
+		signatures:               map[string][]*model.Signature{},
+		instanceStores:           instanceStores,
+		clones:                   map[string][]string{},
+		deviceService:            nil,
+		fileSystemServiceFactory: service.NewLinuxFileSystemServiceFactory(nil),
+	}
+}
+
+func NewMockLinuxDeviceBackendWithClones(blockDevices map[string]*model.BlockDevice, clones map[string][]string) *LinuxDeviceBackend {
+	return &LinuxDeviceBackend{
+		blockDevices:             blockDevices,
+		signatures:               map[string][]*model.Signature{},
+		instanceStores:           map[string]bool{},
+		clones:                   clones,
 		deviceService:            nil,
 		fileSystemServiceFactory: service.NewLinuxFileSystemServiceFactory(nil),
 	}
@@ -74,11 +118,43 @@ func (db *LinuxDeviceBackend) Label(bd *model.BlockDevice, label string) ([]acti
 	return append(actions, a), nil
 }
 
+// GetClones reports every attached device (including the device itself) with a
+// file system that shares the UUID of the device. This is typical of volumes that
+// were restored from the same snapshot. Clones are only detected for a device
+// that requests a random UUID
+func (db *LinuxDeviceBackend) GetClones(bd *model.BlockDevice) []string {
+	return db.clones[bd.Name]
+}
+
+// The UUID of a mounted file system can either not be changed at all (xfs), or
+// only when the file system supports it (ext4 with metadata_csum_seed). Therefore,
+// a mounted device is always unmounted first. The mount layer is then responsible
+// for mounting it again
+func (db *LinuxDeviceBackend) SetUuid(bd *model.BlockDevice, uuid string) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	fss, err := db.fileSystemServiceFactory.Select(bd.FileSystem)
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: %s", bd.Name, err)
+	}
+	if len(bd.MountPoint) > 0 {
+		actions = append(actions, db.Umount(bd))
+	}
+	a := action.NewChangeUuidAction(
+		bd.Name,
+		uuid,
+		fss,
+	)
+	return append(actions, a), nil
+}
+
 func (db *LinuxDeviceBackend) Resize(bd *model.BlockDevice) (action.Action, error) {
 	fss, err := db.fileSystemServiceFactory.Select(bd.FileSystem)
 	if err != nil {
 		return nil, fmt.Errorf("🔴 %s: %s", bd.Name, err)
 	}
+	if fss.DoesResizeRequireUnmount() && len(bd.MountPoint) > 0 {
+		return nil, fmt.Errorf("🔴 %s: To resize the %s file system, device must not be in use", bd.Name, fss.GetFileSystem().String())
+	}
 	target := bd.Name
 	if fss.DoesResizeRequireMount() {
 		if len(bd.MountPoint) == 0 {
@@ -93,17 +169,72 @@ func (db *LinuxDeviceBackend) Resize(bd *model.BlockDevice) (action.Action, erro
 	), nil
 }
 
-func (db *LinuxDeviceBackend) Format(bd *model.BlockDevice, fileSystem model.FileSystem) (action.Action, error) {
+func (db *LinuxDeviceBackend) Format(bd *model.BlockDevice, fileSystem model.FileSystem, options *model.FormatOptions) (action.Action, error) {
 	fss, err := db.fileSystemServiceFactory.Select(fileSystem)
 	if err != nil {
 		return nil, fmt.Errorf("🔴 %s: %s", bd.Name, err)
 	}
 	return action.NewFormatDeviceAction(
 		bd.Name,
+		db.getFormatOptions(fss, options),
 		fss,
 	), nil
 }
 
+// A label is only assigned at format time when the file system supports it, and
+// the label does not exceed its maximum length. Otherwise, the label is left to
+// the label layer, which reports any label that is too long
+func (db *LinuxDeviceBackend) getFormatOptions(fss service.FileSystemService, options *model.FormatOptions) *model.FormatOptions {
+	if options == nil {
+		return nil
+	}
+	fo := *options
+	if !fss.DoesFormatSupportLabel() || len(fo.Label) > fss.GetMaximumLabelLength() {
+		fo.Label = ""
+	}
+	return &fo
+}
+
+// GetSignatures reports the signatures that were found on an unformatted device
+func (db *LinuxDeviceBackend) GetSignatures(bd *model.BlockDevice) []*model.Signature {
+	return db.signatures[bd.Name]
+}
+
+func (db *LinuxDeviceBackend) Wipe(bd *model.BlockDevice, signatures []*model.Signature) action.Action {
+	return action.NewWipeDeviceAction(bd.Name, signatures, db.deviceService)
+}
+
+// IsInstanceStore reports whether a device was positively identified as an
+// instance store volume, by the model number of its NVMe controller
+func (db *LinuxDeviceBackend) IsInstanceStore(bd *model.BlockDevice) bool {
+	return db.instanceStores[bd.Name]
+}
+
+// A mounted device is unmounted before its file system is destroyed. The
+// mount layer is then responsible for mounting the new file system. An active
+// swap area can not be unmounted, and is left for the operator to deactivate
+func (db *LinuxDeviceBackend) Reformat(bd *model.BlockDevice, fileSystem model.FileSystem, options *model.FormatOptions) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	fss, err := db.fileSystemServiceFactory.Select(fileSystem)
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: %s", bd.Name, err)
+	}
+	if bd.MountPoint == model.SwapMountPoint {
+		return nil, fmt.Errorf("🔴 %s: Can not reformat an active swap area", bd.Name)
+	}
+	if len(bd.MountPoint) > 0 {
+		actions = append(actions, db.Umount(bd))
+	}
+	a := action.NewReformatDeviceAction(
+		bd.Name,
+		bd.FileSystem,
+		db.getFormatOptions(fss, options),
+		db.deviceService,
+		fss,
+	)
+	return append(actions, a), nil
+}
+
 func (db *LinuxDeviceBackend) Mount(bd *model.BlockDevice, target string, options model.MountOptions) action.Action {
 	return action.NewMountDeviceAction(bd.Name, target, bd.FileSystem, options, db.deviceService)
 }
@@ -121,15 +252,89 @@ func (db *LinuxDeviceBackend) From(config *config.Config) error {
 	// after all objects have been successfully added. This avoids a partial
 	// state in the event of failure during one of intermediate steps.
 	db.blockDevices = nil
+	db.signatures = nil
+	db.instanceStores = nil
+	db.clones = nil
 	blockDevices := map[string]*model.BlockDevice{}
+	signatures := map[string][]*model.Signature{}
+	instanceStores := map[string]bool{}
+	clones := map[string][]string{}
+	var uuids map[string][]string
 
-	for name := range config.Devices {
-		d, err := db.deviceService.GetBlockDevice(name)
-		if err != nil {
-			return err
+	for name, cd := range config.Devices {
+		// A device that references a declared volume group is a logical volume
+		// that might not exist yet. Its physical volumes are loaded in its place
+		names := []string{name}
+		if _, declared := config.VolumeGroups[cd.Lvm]; declared {
+			names = config.GetPhysicalVolumes(name)
+		}
+		for _, n := range names {
+			d, err := db.deviceService.GetBlockDevice(n)
+			if err != nil {
+				return err
+			}
+			blockDevices[d.Name] = d
+			if d.FileSystem != model.Unformatted {
+				continue
+			}
+			s, err := db.deviceService.GetSignatures(d.Name)
+			if err != nil {
+				return err
+			}
+			signatures[d.Name] = s
+		}
+		// A device is only identified when it can be reformatted, as not every
+		// device is an NVMe device. A device that can not be identified is
+		// never considered to be an instance store volume
+		if cd.Reformat == model.ReformatOnMismatch {
+			nc, err := db.nvmeService.GetController(name)
+			instanceStores[name] = err == nil && nc.Model == service.AMZN_NVME_INS_MN
+		}
+		// Every attached device is only inspected when a device requests a
+		// random UUID, and then only once
+		if cd.Uuid == model.RandomUuid {
+			if uuids == nil {
+				u, err := db.getUuids()
+				if err != nil {
+					return err
+				}
+				uuids = u
+			}
+			bd, found := blockDevices[name]
+			if !found || len(bd.UUID) == 0 {
+				continue
+			}
+			if c := uuids[strings.ToLower(bd.UUID)]; len(c) > 1 {
+				clones[bd.Name] = c
+			}
 		}
-		blockDevices[d.Name] = d
 	}
 	db.blockDevices = blockDevices
+	db.signatures = signatures
+	db.instanceStores = instanceStores
+	db.clones = clones
 	return nil
 }
+
+// getUuids groups the attached devices by the UUID of their file system. Only
+// file systems that support a change of UUID are considered, as the devices of
+// a RAID array or multi-device btrfs file system legitimately share a UUID
+func (db *LinuxDeviceBackend) getUuids() (map[string][]string, error) {
+	names, err := db.deviceService.GetBlockDevices()
+	if err != nil {
+		return nil, err
+	}
+	uuids := map[string][]string{}
+	for _, name := range names {
+		bd, err := db.deviceService.GetBlockDevice(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(bd.UUID) == 0 || (bd.FileSystem != model.Ext4 && bd.FileSystem != model.Xfs) {
+			continue
+		}
+		uuid := strings.ToLower(bd.UUID)
+		uuids[uuid] = append(uuids[uuid], bd.Name)
+	}
+	return uuids, nil
+}
@@ -157,6 +157,85 @@ func TestLabel(t *testing.T) {
 	}
 }
 
+func TestSetUuid(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		BlockDevices   map[string]*model.BlockDevice
+		Device         string
+		Uuid           string
+		CmpOption      cmp.Option
+		ExpectedOutput []action.Action
+		ExpectedError  error
+	}{
+		{
+			Name: "Unmounted Block Device",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Xfs,
+				},
+			},
+			Device: "/dev/xvdf",
+			Uuid:   model.RandomUuid,
+			CmpOption: cmp.AllowUnexported(
+				action.ChangeUuidAction{},
+				service.XfsService{},
+			),
+			ExpectedOutput: []action.Action{
+				action.NewChangeUuidAction("/dev/xvdf", model.RandomUuid, service.NewXfsService(nil)),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Mounted Block Device",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					MountPoint: "/mnt/app",
+				},
+			},
+			Device: "/dev/xvdf",
+			Uuid:   "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+			CmpOption: cmp.AllowUnexported(
+				action.ChangeUuidAction{},
+				action.UnmountDeviceAction{},
+				service.Ext4Service{},
+			),
+			ExpectedOutput: []action.Action{
+				action.NewUnmountDeviceAction("/dev/xvdf", "/mnt/app", nil),
+				action.NewChangeUuidAction("/dev/xvdf", "31cf3150-f4b1-409a-9887-7f9da1fdd99c", service.NewExt4Service(nil)),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Unformatted Device",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			Device:         "/dev/xvdf",
+			Uuid:           model.RandomUuid,
+			CmpOption:      cmp.AllowUnexported(),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: An unformatted file system can not be queried/modified"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := NewMockLinuxDeviceBackend(subtest.BlockDevices)
+			bd, err := ldb.GetBlockDevice(subtest.Device)
+			utils.ExpectErr("ldb.GetBlockDevice()", t, false, err)
+
+			actions, err := ldb.SetUuid(bd, subtest.Uuid)
+			utils.CheckError("ldb.SetUuid()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ldb.SetUuid()", t, subtest.ExpectedOutput, actions, subtest.CmpOption)
+		})
+	}
+}
+
 func TestResize(t *testing.T) {
 	subtests := []struct {
 		Name           string
@@ -212,6 +291,20 @@ func TestResize(t *testing.T) {
 			ExpectedOutput: nil,
 			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: To resize the xfs file system, device must be mounted"),
 		},
+		{
+			Name: "Fail to Resize + Active Swap Area",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Swap,
+					MountPoint: model.SwapMountPoint,
+				},
+			},
+			Device:         "/dev/xvdf",
+			CmpOption:      cmp.AllowUnexported(),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: To resize the swap file system, device must not be in use"),
+		},
 		{
 			Name: "Fail To Resize + Unformatted Device",
 			BlockDevices: map[string]*model.BlockDevice{
@@ -245,6 +338,7 @@ func TestFormat(t *testing.T) {
 		BlockDevices map[string]*model.BlockDevice
 		Device       string
 		model.FileSystem
+		Options        *model.FormatOptions
 		CmpOption      cmp.Option
 		ExpectedOutput action.Action
 		ExpectedError  error
@@ -263,7 +357,61 @@ func TestFormat(t *testing.T) {
 				service.Ext4Service{},
 				action.FormatDeviceAction{},
 			),
-			ExpectedOutput: action.NewFormatDeviceAction("/dev/xvdf", service.NewExt4Service(nil)),
+			ExpectedOutput: action.NewFormatDeviceAction("/dev/xvdf", nil, service.NewExt4Service(nil)),
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Label Assigned at Format Time",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			Device:     "/dev/xvdf",
+			FileSystem: model.Ext4,
+			Options:    &model.FormatOptions{BlockSize: 4096, Label: "stateful"},
+			CmpOption: cmp.AllowUnexported(
+				service.Ext4Service{},
+				action.FormatDeviceAction{},
+			),
+			ExpectedOutput: action.NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{BlockSize: 4096, Label: "stateful"}, service.NewExt4Service(nil)),
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Label Exceeds Maximum Length at Format Time",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			Device:     "/dev/xvdf",
+			FileSystem: model.Ext4,
+			Options:    &model.FormatOptions{Label: "a-label-that-is-far-too-long"},
+			CmpOption: cmp.AllowUnexported(
+				service.Ext4Service{},
+				action.FormatDeviceAction{},
+			),
+			ExpectedOutput: action.NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{}, service.NewExt4Service(nil)),
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Label Not Supported at Format Time",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			Device:     "/dev/xvdf",
+			FileSystem: model.Xfs,
+			Options:    &model.FormatOptions{Label: "stateful"},
+			CmpOption: cmp.AllowUnexported(
+				service.XfsService{},
+				action.FormatDeviceAction{},
+			),
+			ExpectedOutput: action.NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{}, service.NewXfsService(nil)),
 			ExpectedError:  nil,
 		},
 		{
@@ -287,13 +435,93 @@ func TestFormat(t *testing.T) {
 			bd, err := ldb.GetBlockDevice(subtest.Device)
 			utils.ExpectErr("ldb.GetBlockDevice()", t, false, err)
 
-			action, err := ldb.Format(bd, subtest.FileSystem)
+			action, err := ldb.Format(bd, subtest.FileSystem, subtest.Options)
 			utils.CheckError("ldb.Format()", t, subtest.ExpectedError, err)
 			utils.CheckOutput("ldb.Format()", t, subtest.ExpectedOutput, action, subtest.CmpOption)
 		})
 	}
 }
 
+func TestReformat(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		BlockDevices   map[string]*model.BlockDevice
+		Device         string
+		FileSystem     model.FileSystem
+		CmpOption      cmp.Option
+		ExpectedOutput []action.Action
+		ExpectedError  error
+	}{
+		{
+			Name: "Unmounted Block Device",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Ext4,
+				},
+			},
+			Device:     "/dev/nvme1n1",
+			FileSystem: model.Xfs,
+			CmpOption: cmp.AllowUnexported(
+				service.XfsService{},
+				action.ReformatDeviceAction{},
+			),
+			ExpectedOutput: []action.Action{
+				action.NewReformatDeviceAction("/dev/nvme1n1", model.Ext4, nil, nil, service.NewXfsService(nil)),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Mounted Block Device",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Ext4,
+					MountPoint: "/mnt/scratch",
+				},
+			},
+			Device:     "/dev/nvme1n1",
+			FileSystem: model.Xfs,
+			CmpOption: cmp.AllowUnexported(
+				service.XfsService{},
+				action.UnmountDeviceAction{},
+				action.ReformatDeviceAction{},
+			),
+			ExpectedOutput: []action.Action{
+				action.NewUnmountDeviceAction("/dev/nvme1n1", "/mnt/scratch", nil),
+				action.NewReformatDeviceAction("/dev/nvme1n1", model.Ext4, nil, nil, service.NewXfsService(nil)),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Active Swap Area",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Swap,
+					MountPoint: model.SwapMountPoint,
+				},
+			},
+			Device:         "/dev/nvme1n1",
+			FileSystem:     model.Ext4,
+			CmpOption:      cmp.AllowUnexported(),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/nvme1n1: Can not reformat an active swap area"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := NewMockLinuxDeviceBackend(subtest.BlockDevices)
+			bd, err := ldb.GetBlockDevice(subtest.Device)
+			utils.ExpectErr("ldb.GetBlockDevice()", t, false, err)
+
+			actions, err := ldb.Reformat(bd, subtest.FileSystem, nil)
+			utils.CheckError("ldb.Reformat()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ldb.Reformat()", t, subtest.ExpectedOutput, actions, subtest.CmpOption)
+		})
+	}
+}
+
 func TestMount(t *testing.T) {
 	subtests := []struct {
 		Name         string
@@ -404,11 +632,13 @@ func TestUnmount(t *testing.T) {
 
 func TestLinuxDeviceBackendFrom(t *testing.T) {
 	subtests := []struct {
-		Name           string
-		Config         *config.Config
-		GetBlockDevice func(name string) (*model.BlockDevice, error)
-		ExpectedOutput map[string]*model.BlockDevice
-		ExpectedError  error
+		Name               string
+		Config             *config.Config
+		GetBlockDevice     func(name string) (*model.BlockDevice, error)
+		GetSignatures      func(name string) ([]*model.Signature, error)
+		ExpectedOutput     map[string]*model.BlockDevice
+		ExpectedSignatures map[string][]*model.Signature
+		ExpectedError      error
 	}{
 		{
 			Name: "Valid Block Device",
@@ -423,14 +653,76 @@ func TestLinuxDeviceBackendFrom(t *testing.T) {
 					FileSystem: model.Unformatted,
 				}, nil
 			},
+			GetSignatures: func(name string) ([]*model.Signature, error) {
+				return []*model.Signature{}, nil
+			},
+			ExpectedOutput: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			ExpectedSignatures: map[string][]*model.Signature{
+				"/dev/xvdf": {},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Unformatted Block Device with Partition Table",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Unformatted,
+				}, nil
+			},
+			GetSignatures: func(name string) ([]*model.Signature, error) {
+				return []*model.Signature{
+					{Type: "gpt", Offset: 0x200},
+					{Type: "PMBR", Offset: 0x1fe},
+				}, nil
+			},
 			ExpectedOutput: map[string]*model.BlockDevice{
 				"/dev/xvdf": {
 					Name:       "/dev/xvdf",
 					FileSystem: model.Unformatted,
 				},
 			},
+			ExpectedSignatures: map[string][]*model.Signature{
+				"/dev/xvdf": {
+					{Type: "gpt", Offset: 0x200},
+					{Type: "PMBR", Offset: 0x1fe},
+				},
+			},
 			ExpectedError: nil,
 		},
+		{
+			// The signatures of a formatted device are never queried
+			Name: "Formatted Block Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Ext4,
+				}, nil
+			},
+			ExpectedOutput: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			ExpectedSignatures: map[string][]*model.Signature{},
+			ExpectedError:      nil,
+		},
 		{
 			Name: "Invalid Block Device",
 			Config: &config.Config{
@@ -451,11 +743,142 @@ func TestLinuxDeviceBackendFrom(t *testing.T) {
 			if subtest.GetBlockDevice != nil {
 				mds.StubGetBlockDevice = subtest.GetBlockDevice
 			}
+			if subtest.GetSignatures != nil {
+				mds.StubGetSignatures = subtest.GetSignatures
+			}
 
-			ldb := NewLinuxDeviceBackend(mds, nil)
+			ldb := NewLinuxDeviceBackend(mds, nil, nil)
 			err := ldb.From(subtest.Config)
 			utils.CheckError("ldb.From()", t, subtest.ExpectedError, err)
 			utils.CheckOutput("ldb.From()", t, subtest.ExpectedOutput, ldb.blockDevices)
+			utils.CheckOutput("ldb.From()", t, subtest.ExpectedSignatures, ldb.signatures)
+		})
+	}
+}
+
+func TestLinuxDeviceBackendInstanceStores(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Reformat       model.ReformatPolicy
+		GetController  func(device string) (*model.NVMeController, error)
+		ExpectedOutput map[string]bool
+	}{
+		{
+			Name:     "Instance Store Volume",
+			Reformat: model.ReformatOnMismatch,
+			GetController: func(device string) (*model.NVMeController, error) {
+				return &model.NVMeController{Name: device, Model: service.AMZN_NVME_INS_MN, VirtualName: "ephemeral0"}, nil
+			},
+			ExpectedOutput: map[string]bool{"/dev/nvme1n1": true},
+		},
+		{
+			Name:     "EBS Volume",
+			Reformat: model.ReformatOnMismatch,
+			GetController: func(device string) (*model.NVMeController, error) {
+				return &model.NVMeController{Name: device, Model: service.AMZN_NVME_EBS_MN}, nil
+			},
+			ExpectedOutput: map[string]bool{"/dev/nvme1n1": false},
+		},
+		{
+			Name:     "Not an NVMe Device",
+			Reformat: model.ReformatOnMismatch,
+			GetController: func(device string) (*model.NVMeController, error) {
+				return nil, fmt.Errorf("🔴 ioctl error: inappropriate ioctl for device")
+			},
+			ExpectedOutput: map[string]bool{"/dev/nvme1n1": false},
+		},
+		{
+			// A device that can never be reformatted is not identified
+			Name:           "Reformat Policy Omitted",
+			Reformat:       "",
+			GetController:  nil,
+			ExpectedOutput: map[string]bool{},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mds := service.NewMockDeviceService()
+			mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{Name: name, FileSystem: model.Ext4}, nil
+			}
+			mns := service.NewMockNVMeService()
+			if subtest.GetController != nil {
+				mns.StubGetController = subtest.GetController
+			}
+
+			ldb := NewLinuxDeviceBackend(mds, mns, nil)
+			err := ldb.From(&config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Xfs, Reformat: subtest.Reformat},
+				},
+			})
+			utils.CheckError("ldb.From()", t, nil, err)
+			utils.CheckOutput("ldb.From()", t, subtest.ExpectedOutput, ldb.instanceStores)
+		})
+	}
+}
+
+func TestLinuxDeviceBackendClones(t *testing.T) {
+	blockDevices := map[string]*model.BlockDevice{
+		"/dev/nvme0n1": {Name: "/dev/nvme0n1", FileSystem: model.Xfs, UUID: "31cf3150-f4b1-409a-9887-7f9da1fdd99c", MountPoint: "/"},
+		"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.Xfs, UUID: "31cf3150-f4b1-409a-9887-7f9da1fdd99c"},
+		"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.Ext4, UUID: "6f3b1a4e-8d6c-4c2e-9f0a-1b2c3d4e5f60"},
+		"/dev/md0":     {Name: "/dev/md0", FileSystem: model.RaidMember, UUID: "0b1c2d3e-4f50-6172-8394-a5b6c7d8e9f0"},
+		"/dev/md1":     {Name: "/dev/md1", FileSystem: model.RaidMember, UUID: "0b1c2d3e-4f50-6172-8394-a5b6c7d8e9f0"},
+	}
+	subtests := []struct {
+		Name           string
+		Devices        map[string]config.Device
+		ExpectedOutput map[string][]string
+	}{
+		{
+			Name: "Cloned Device",
+			Devices: map[string]config.Device{
+				"/dev/nvme1n1": {Fs: model.Xfs, Uuid: model.RandomUuid},
+			},
+			ExpectedOutput: map[string][]string{
+				"/dev/nvme1n1": {"/dev/nvme0n1", "/dev/nvme1n1"},
+			},
+		},
+		{
+			Name: "Unique Device",
+			Devices: map[string]config.Device{
+				"/dev/nvme2n1": {Fs: model.Ext4, Uuid: model.RandomUuid},
+			},
+			ExpectedOutput: map[string][]string{},
+		},
+		{
+			// The members of a RAID array legitimately share a UUID
+			Name: "RAID Member",
+			Devices: map[string]config.Device{
+				"/dev/md0": {Uuid: model.RandomUuid},
+			},
+			ExpectedOutput: map[string][]string{},
+		},
+		{
+			// Clones are only detected for a device that requests a random UUID
+			Name: "Fixed UUID",
+			Devices: map[string]config.Device{
+				"/dev/nvme1n1": {Fs: model.Xfs, Uuid: "6f3b1a4e-8d6c-4c2e-9f0a-1b2c3d4e5f61"},
+			},
+			ExpectedOutput: map[string][]string{},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mds := service.NewMockDeviceService()
+			mds.StubGetBlockDevices = func() ([]string, error) {
+				return []string{"/dev/md0", "/dev/md1", "/dev/nvme0n1", "/dev/nvme1n1", "/dev/nvme2n1"}, nil
+			}
+			mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+				bd := *blockDevices[name]
+				return &bd, nil
+			}
+
+			ldb := NewLinuxDeviceBackend(mds, nil, nil)
+			err := ldb.From(&config.Config{Devices: subtest.Devices})
+			utils.CheckError("ldb.From()", t, nil, err)
+			utils.CheckOutput("ldb.From()", t, subtest.ExpectedOutput, ldb.clones)
 		})
 	}
 }
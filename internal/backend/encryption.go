@@ -0,0 +1,172 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type EncryptionBackend interface {
+	GetBlockDevice(name string) (*model.BlockDevice, error)
+	GetEncryptedDevice(name string) (*model.EncryptedDevice, error)
+	HasKeyFile(keyFile string) bool
+	GetSignatures(bd *model.BlockDevice) []*model.Signature
+	Wipe(bd *model.BlockDevice, signatures []*model.Signature) action.Action
+	Format(device string, keyFile string, ephemeral bool) action.Action
+	IsInstanceStore(bd *model.BlockDevice) bool
+	Reformat(device string, keyFile string) action.Action
+	Open(device string, name string, keyFile string) action.Action
+	From(config *config.Config) error
+}
+
+type LinuxEncryptionBackend struct {
+	blockDevices      map[string]*model.BlockDevice
+	encryptedDevices  map[string]*model.EncryptedDevice
+	keyFiles          map[string]bool
+	signatures        map[string][]*model.Signature
+	instanceStores    map[string]bool
+	deviceService     service.DeviceService
+	nvmeService       service.NVMeService
+	fileService       service.FileService
+	cryptsetupService service.CryptsetupService
+}
+
+func NewLinuxEncryptionBackend(ds service.DeviceService, ns service.NVMeService, fs service.FileService, cs service.CryptsetupService) *LinuxEncryptionBackend {
+	return &LinuxEncryptionBackend{
+		blockDevices:      map[string]*model.BlockDevice{},
+		encryptedDevices:  map[string]*model.EncryptedDevice{},
+		keyFiles:          map[string]bool{},
+		signatures:        map[string][]*model.Signature{},
+		instanceStores:    map[string]bool{},
+		deviceService:     ds,
+		nvmeService:       ns,
+		fileService:       fs,
+		cryptsetupService: cs,
+	}
+}
+
+func NewMockLinuxEncryptionBackend(blockDevices map[string]*model.BlockDevice, encryptedDevices map[string]*model.EncryptedDevice, keyFiles map[string]bool) *LinuxEncryptionBackend {
+	return NewMockLinuxEncryptionBackendWithInstanceStores(blockDevices, encryptedDevices, keyFiles, map[string][]*model.Signature{}, map[string]bool{})
+}
+
+func NewMockLinuxEncryptionBackendWithInstanceStores(blockDevices map[string]*model.BlockDevice, encryptedDevices map[string]*model.EncryptedDevice, keyFiles map[string]bool, signatures map[string][]*model.Signature, instanceStores map[string]bool) *LinuxEncryptionBackend {
+	return &LinuxEncryptionBackend{
+		blockDevices:      blockDevices,
+		encryptedDevices:  encryptedDevices,
+		keyFiles:          keyFiles,
+		signatures:        signatures,
+		instanceStores:    instanceStores,
+		deviceService:     nil,
+		nvmeService:       nil,
+		fileService:       nil,
+		cryptsetupService: nil,
+	}
+}
+
+func (eb *LinuxEncryptionBackend) GetBlockDevice(name string) (*model.BlockDevice, error) {
+	bd, exists := eb.blockDevices[name]
+	if !exists {
+		return nil, fmt.Errorf("🔴 %s: Could not find block device", name)
+	}
+	return bd, nil
+}
+
+// GetEncryptedDevice retrieves an active mapping by its name. A mapping that is
+// inactive, because the LUKS device has yet to be opened, does not exist
+func (eb *LinuxEncryptionBackend) GetEncryptedDevice(name string) (*model.EncryptedDevice, error) {
+	ed, exists := eb.encryptedDevices[name]
+	if !exists {
+		return nil, os.ErrNotExist
+	}
+	return ed, nil
+}
+
+func (eb *LinuxEncryptionBackend) HasKeyFile(keyFile string) bool {
+	return eb.keyFiles[keyFile]
+}
+
+func (eb *LinuxEncryptionBackend) GetSignatures(bd *model.BlockDevice) []*model.Signature {
+	return eb.signatures[bd.Name]
+}
+
+func (eb *LinuxEncryptionBackend) Wipe(bd *model.BlockDevice, signatures []*model.Signature) action.Action {
+	return action.NewWipeDeviceAction(bd.Name, signatures, eb.deviceService)
+}
+
+func (eb *LinuxEncryptionBackend) Format(device string, keyFile string, ephemeral bool) action.Action {
+	return action.NewFormatEncryptedDeviceAction(device, keyFile, ephemeral, eb.cryptsetupService)
+}
+
+// IsInstanceStore mirrors the device backend, so that a LUKS device is held to
+// the same safeguards before it is reformatted
+func (eb *LinuxEncryptionBackend) IsInstanceStore(bd *model.BlockDevice) bool {
+	return eb.instanceStores[bd.Name]
+}
+
+func (eb *LinuxEncryptionBackend) Reformat(device string, keyFile string) action.Action {
+	return action.NewReformatEncryptedDeviceAction(device, keyFile, eb.cryptsetupService)
+}
+
+func (eb *LinuxEncryptionBackend) Open(device string, name string, keyFile string) action.Action {
+	return action.NewOpenEncryptedDeviceAction(device, name, keyFile, eb.cryptsetupService)
+}
+
+func (eb *LinuxEncryptionBackend) From(config *config.Config) error {
+	eb.blockDevices = nil
+	eb.encryptedDevices = nil
+	eb.keyFiles = nil
+	eb.signatures = nil
+	eb.instanceStores = nil
+	blockDevices := map[string]*model.BlockDevice{}
+	encryptedDevices := map[string]*model.EncryptedDevice{}
+	keyFiles := map[string]bool{}
+	signatures := map[string][]*model.Signature{}
+	instanceStores := map[string]bool{}
+
+	for name, cd := range config.Devices {
+		if cd.Encryption == nil {
+			continue
+		}
+		bd, err := eb.deviceService.GetBlockDevice(name)
+		if err != nil {
+			return err
+		}
+		blockDevices[name] = bd
+		if bd.FileSystem == model.Unformatted {
+			s, err := eb.deviceService.GetSignatures(bd.Name)
+			if err != nil {
+				return err
+			}
+			signatures[bd.Name] = s
+		}
+		// As with a file system, a LUKS device is only identified when it can be
+		// reformatted
+		if cd.Reformat == model.ReformatOnMismatch {
+			nc, err := eb.nvmeService.GetController(name)
+			instanceStores[name] = err == nil && nc.Model == service.AMZN_NVME_INS_MN
+		}
+		ed, err := eb.cryptsetupService.GetEncryptedDevice(cd.Encryption.Name)
+		if err == nil {
+			encryptedDevices[ed.Name] = ed
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		keyFile := config.GetKeyFile(name)
+		_, err = eb.fileService.GetFile(keyFile)
+		if err == nil {
+			keyFiles[keyFile] = true
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+	eb.blockDevices = blockDevices
+	eb.encryptedDevices = encryptedDevices
+	eb.keyFiles = keyFiles
+	eb.signatures = signatures
+	eb.instanceStores = instanceStores
+	return nil
+}
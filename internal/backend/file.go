@@ -11,30 +11,46 @@ import (
 	"github.com/reecetech/ebs-bootstrap/internal/service"
 )
 
+const (
+	FstabPath = "/etc/fstab"
+)
+
 type FileBackend interface {
 	CreateDirectory(p string) action.Action
 	ChangeOwner(p string, uid model.UserId, gid model.GroupId) action.Action
 	ChangePermissions(p string, perms model.FilePermissions) action.Action
 	GetDirectory(p string) (*model.File, error)
 	IsMount(p string) bool
+	GetFstabEntry(mountPoint string) (*model.FstabEntry, error)
+	UpdateFstabEntry(entry *model.FstabEntry) action.Action
 	From(config *config.Config) error
 }
 
 type LinuxFileBackend struct {
 	files       map[string]*model.File
+	fstab       []*model.FstabEntry
+	fstabPath   string
 	fileService service.FileService
 }
 
 func NewLinuxFileBackend(fs service.FileService) *LinuxFileBackend {
 	return &LinuxFileBackend{
 		files:       map[string]*model.File{},
+		fstab:       []*model.FstabEntry{},
+		fstabPath:   FstabPath,
 		fileService: fs,
 	}
 }
 
 func NewMockLinuxFileBackend(files map[string]*model.File) *LinuxFileBackend {
+	return NewMockLinuxFileBackendWithFstab(files, []*model.FstabEntry{})
+}
+
+func NewMockLinuxFileBackendWithFstab(files map[string]*model.File, fstab []*model.FstabEntry) *LinuxFileBackend {
 	return &LinuxFileBackend{
 		files:       files,
+		fstab:       fstab,
+		fstabPath:   FstabPath,
 		fileService: nil,
 	}
 }
@@ -88,8 +104,25 @@ func (lfb *LinuxFileBackend) IsMount(p string) bool {
 	return child.InodeNo == parent.InodeNo
 }
 
+// GetFstabEntry retrieves the first entry in /etc/fstab that targets the mount
+// point. This reflects the behaviour of mount(8), which consults the first
+// matching entry when only a mount point is provided
+func (lfb *LinuxFileBackend) GetFstabEntry(mountPoint string) (*model.FstabEntry, error) {
+	for _, fe := range lfb.fstab {
+		if fe.MountPoint == mountPoint {
+			return fe, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+func (lfb *LinuxFileBackend) UpdateFstabEntry(entry *model.FstabEntry) action.Action {
+	return action.NewUpdateFstabEntryAction(lfb.fstabPath, entry, lfb.fileService)
+}
+
 func (lfb *LinuxFileBackend) From(config *config.Config) error {
 	lfb.files = nil
+	lfb.fstab = nil
 	files := map[string]*model.File{}
 
 	for _, cd := range config.Devices {
@@ -115,5 +148,19 @@ func (lfb *LinuxFileBackend) From(config *config.Config) error {
 		}
 	}
 	lfb.files = files
+
+	// Only read /etc/fstab if the entry of at least one device is managed
+	for name := range config.Devices {
+		if !config.GetFstab(name) {
+			continue
+		}
+		data, err := lfb.fileService.ReadFile(lfb.fstabPath)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		lfb.fstab = model.ParseFstab(string(data))
+		return nil
+	}
+	lfb.fstab = []*model.FstabEntry{}
 	return nil
 }
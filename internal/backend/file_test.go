@@ -405,3 +405,107 @@ func TestLinuxFileBackendFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateFstabEntry(t *testing.T) {
+	lfb := NewMockLinuxFileBackend(nil)
+	entry := &model.FstabEntry{
+		Source:     "LABEL=stateful",
+		MountPoint: "/mnt/ebs",
+		FileSystem: model.Ext4,
+		Options:    "defaults",
+		Pass:       2,
+	}
+	a := lfb.UpdateFstabEntry(entry)
+	utils.CheckOutput("lfb.UpdateFstabEntry()", t, action.NewUpdateFstabEntryAction(FstabPath, entry, nil), a, cmp.AllowUnexported(action.UpdateFstabEntryAction{}))
+}
+
+func TestFstabFrom(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Config         *config.Config
+		ReadFile       func(p string) ([]byte, error)
+		MountPoint     string
+		ExpectedOutput *model.FstabEntry
+		ExpectedError  error
+	}{
+		{
+			Name: "Managed Entry Exists",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Options: config.Options{Fstab: true},
+					},
+				},
+			},
+			ReadFile: func(p string) ([]byte, error) {
+				return []byte("# comment\nLABEL=stateful /mnt/ebs ext4 defaults 0 2\nLABEL=other /mnt/ebs xfs defaults 0 0\n"), nil
+			},
+			MountPoint: "/mnt/ebs",
+			ExpectedOutput: &model.FstabEntry{
+				Source:     "LABEL=stateful",
+				MountPoint: "/mnt/ebs",
+				FileSystem: model.Ext4,
+				Options:    "defaults",
+				Pass:       2,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Missing Fstab File",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Options: config.Options{Fstab: true},
+					},
+				},
+			},
+			ReadFile: func(p string) ([]byte, error) {
+				return nil, os.ErrNotExist
+			},
+			MountPoint:     "/mnt/ebs",
+			ExpectedOutput: nil,
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Skip + No Managed Entries",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {},
+				},
+			},
+			ReadFile:       nil,
+			MountPoint:     "/mnt/ebs",
+			ExpectedOutput: nil,
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Failure to Read Fstab File",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Options: config.Options{Fstab: true},
+					},
+				},
+			},
+			ReadFile: func(p string) ([]byte, error) {
+				return nil, fmt.Errorf("🔴 %s: Permission denied", p)
+			},
+			MountPoint:     "/mnt/ebs",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /etc/fstab: Permission denied"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			fs := service.NewMockFileService()
+			if subtest.ReadFile != nil {
+				fs.StubReadFile = subtest.ReadFile
+			}
+			lfb := NewLinuxFileBackend(fs)
+			err := lfb.From(subtest.Config)
+			utils.CheckError("lfb.From()", t, subtest.ExpectedError, err)
+			fe, _ := lfb.GetFstabEntry(subtest.MountPoint)
+			utils.CheckOutput("lfb.GetFstabEntry()", t, subtest.ExpectedOutput, fe)
+		})
+	}
+}
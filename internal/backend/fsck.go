@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type CheckBackend interface {
+	GetCheckResult(name string) (model.FsckResult, error)
+	Repair(bd *model.BlockDevice) (action.Action, error)
+	From(config *config.Config) error
+}
+
+type LinuxCheckBackend struct {
+	results                  map[string]model.FsckResult
+	deviceService            service.DeviceService
+	fileSystemServiceFactory service.FileSystemServiceFactory
+}
+
+func NewLinuxCheckBackend(ds service.DeviceService, fssf service.FileSystemServiceFactory) *LinuxCheckBackend {
+	return &LinuxCheckBackend{
+		results:                  map[string]model.FsckResult{},
+		deviceService:            ds,
+		fileSystemServiceFactory: fssf,
+	}
+}
+
+func NewMockLinuxCheckBackend(results map[string]model.FsckResult) *LinuxCheckBackend {
+	return &LinuxCheckBackend{
+		results:                  results,
+		deviceService:            nil,
+		fileSystemServiceFactory: service.NewLinuxFileSystemServiceFactory(nil),
+	}
+}
+
+func (cb *LinuxCheckBackend) GetCheckResult(name string) (model.FsckResult, error) {
+	result, exists := cb.results[name]
+	if !exists {
+		return "", fmt.Errorf("🔴 %s: Could not find the result of a file system check", name)
+	}
+	return result, nil
+}
+
+func (cb *LinuxCheckBackend) Repair(bd *model.BlockDevice) (action.Action, error) {
+	fss, err := cb.fileSystemServiceFactory.Select(bd.FileSystem)
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: %s", bd.Name, err)
+	}
+	return action.NewRepairFileSystemAction(bd.Name, fss), nil
+}
+
+// A file system can only be checked reliably while it is unmounted. The read-only
+// check is therefore skipped for devices that are already mounted
+func (cb *LinuxCheckBackend) From(config *config.Config) error {
+	cb.results = nil
+	results := map[string]model.FsckResult{}
+
+	for name, cd := range config.Devices {
+		if cd.Fsck == model.FsckUnassigned {
+			continue
+		}
+		bd, err := cb.deviceService.GetBlockDevice(name)
+		if err != nil {
+			return err
+		}
+		if len(bd.MountPoint) > 0 {
+			continue
+		}
+		fss, err := cb.fileSystemServiceFactory.Select(bd.FileSystem)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: %s", bd.Name, err)
+		}
+		result, err := fss.Check(bd.Name, false)
+		if err != nil {
+			return err
+		}
+		results[bd.Name] = result
+	}
+	cb.results = results
+	return nil
+}
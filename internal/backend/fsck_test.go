@@ -0,0 +1,164 @@
+package backend
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestGetCheckResult(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Device         string
+		Results        map[string]model.FsckResult
+		ExpectedOutput model.FsckResult
+		ExpectedError  error
+	}{
+		{
+			Name:   "Valid Device",
+			Device: "/dev/xvdf",
+			Results: map[string]model.FsckResult{
+				"/dev/xvdf": model.FsckUncorrected,
+			},
+			ExpectedOutput: model.FsckUncorrected,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Invalid Device",
+			Device:         "/dev/xvdf",
+			Results:        map[string]model.FsckResult{},
+			ExpectedOutput: "",
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: Could not find the result of a file system check"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			cb := NewMockLinuxCheckBackend(subtest.Results)
+			result, err := cb.GetCheckResult(subtest.Device)
+			utils.CheckError("cb.GetCheckResult()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("cb.GetCheckResult()", t, subtest.ExpectedOutput, result)
+		})
+	}
+}
+
+func TestLinuxCheckBackendFrom(t *testing.T) {
+	fssf := service.NewLinuxFileSystemServiceFactory(nil)
+
+	subtests := []struct {
+		Name           string
+		Config         *config.Config
+		GetBlockDevice func(name string) (*model.BlockDevice, error)
+		Check          func(name string, repair bool) (model.FsckResult, error)
+		ExpectedOutput map[string]model.FsckResult
+		ExpectedError  error
+	}{
+		{
+			Name: "Unmounted Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fsck: model.FsckCheck},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Ext4,
+				}, nil
+			},
+			Check: func(name string, repair bool) (model.FsckResult, error) {
+				if repair {
+					return "", fmt.Errorf("🔴 %s: Expected a read-only check", name)
+				}
+				return model.FsckUncorrected, nil
+			},
+			ExpectedOutput: map[string]model.FsckResult{
+				"/dev/xvdf": model.FsckUncorrected,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Mounted Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fsck: model.FsckRepair},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Ext4,
+					MountPoint: "/mnt/app",
+				}, nil
+			},
+			ExpectedOutput: map[string]model.FsckResult{},
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Device Without File System Check",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {},
+				},
+			},
+			ExpectedOutput: map[string]model.FsckResult{},
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Unformatted File System",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fsck: model.FsckCheck},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Unformatted,
+				}, nil
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: An unformatted file system can not be queried/modified"),
+		},
+		{
+			Name: "Failure to Check File System",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fsck: model.FsckCheck},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Ext4,
+				}, nil
+			},
+			Check: func(name string, repair bool) (model.FsckResult, error) {
+				return "", fmt.Errorf("🔴 e2fsck is either not installed or accessible from $PATH")
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 e2fsck is either not installed or accessible from $PATH"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ds := service.NewMockDeviceService()
+			if subtest.GetBlockDevice != nil {
+				ds.StubGetBlockDevice = subtest.GetBlockDevice
+			}
+			fss := service.NewMockFileSystemService()
+			if subtest.Check != nil {
+				fss.StubCheck = subtest.Check
+			}
+
+			cb := NewLinuxCheckBackend(ds, service.NewMockFileSystemServiceFactory(fssf, fss))
+
+			err := cb.From(subtest.Config)
+			utils.CheckError("cb.From()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("cb.From()", t, subtest.ExpectedOutput, cb.results)
+		})
+	}
+}
@@ -51,7 +51,8 @@ const (
 type LvmBackend interface {
 	CreatePhysicalVolume(name string) action.Action
 	CreateVolumeGroup(name string, physicalVolume string) action.Action
-	CreateLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) action.Action
+	ExtendVolumeGroup(name string, physicalVolume string) action.Action
+	CreateLogicalVolume(name string, volumeGroup string, size model.LvmSize) action.Action
 	ActivateLogicalVolume(name string, volumeGroup string) action.Action
 	GetVolumeGroups(name string) []*model.VolumeGroup
 	GetLogicalVolume(name string, volumeGroup string) (*model.LogicalVolume, error)
@@ -59,8 +60,8 @@ type LvmBackend interface {
 	SearchVolumeGroup(physicalVolume string) (*model.VolumeGroup, error)
 	ShouldResizePhysicalVolume(name string) (bool, error)
 	ResizePhysicalVolume(name string) action.Action
-	ShouldResizeLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) (bool, error)
-	ResizeLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) action.Action
+	ShouldResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) (bool, error)
+	ResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) action.Action
 	From(config *config.Config) error
 }
 
@@ -76,6 +77,13 @@ func NewLinuxLvmBackend(ls service.LvmService) *LinuxLvmBackend {
 	}
 }
 
+func NewMockLinuxLvmBackend(lg *datastructures.LvmGraph) *LinuxLvmBackend {
+	return &LinuxLvmBackend{
+		lvmGraph:   lg,
+		lvmService: nil,
+	}
+}
+
 func (lb *LinuxLvmBackend) GetVolumeGroups(name string) []*model.VolumeGroup {
 	vgs := []*model.VolumeGroup{}
 	vgn, err := lb.lvmGraph.GetVolumeGroup(name)
@@ -154,8 +162,12 @@ func (lb *LinuxLvmBackend) CreateVolumeGroup(name string, physicalVolume string)
 	return action.NewCreateVolumeGroupAction(name, physicalVolume, lb.lvmService)
 }
 
-func (lb *LinuxLvmBackend) CreateLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) action.Action {
-	return action.NewCreateLogicalVolumeAction(name, volumeGroupPercent, volumeGroup, lb.lvmService)
+func (lb *LinuxLvmBackend) ExtendVolumeGroup(name string, physicalVolume string) action.Action {
+	return action.NewExtendVolumeGroupAction(name, physicalVolume, lb.lvmService)
+}
+
+func (lb *LinuxLvmBackend) CreateLogicalVolume(name string, volumeGroup string, size model.LvmSize) action.Action {
+	return action.NewCreateLogicalVolumeAction(name, size, volumeGroup, lb.lvmService)
 }
 
 func (lb *LinuxLvmBackend) ActivateLogicalVolume(name string, volumeGroup string) action.Action {
@@ -178,13 +190,26 @@ func (lb *LinuxLvmBackend) ResizePhysicalVolume(name string) action.Action {
 	return action.NewResizePhysicalVolumeAction(name, lb.lvmService)
 }
 
-func (lb *LinuxLvmBackend) ShouldResizeLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) (bool, error) {
-	left := float64(volumeGroupPercent) - LogicalVolumeResizeTolerance
-	right := float64(volumeGroupPercent) + LogicalVolumeResizeTolerance
+// ShouldResizeLogicalVolume determines whether a logical volume has to grow to reach
+// its configured size. A logical volume is never shrunk. The size of a logical volume
+// that consumes a percentage of the free space of its volume group is only
+// established when it is created, as the free space shrinks as the logical
+// volume grows
+func (lb *LinuxLvmBackend) ShouldResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) (bool, error) {
 	lvn, err := lb.lvmGraph.GetLogicalVolume(name, volumeGroup)
 	if err != nil {
 		return false, err
 	}
+	switch size.Unit {
+	case model.FreePercent:
+		return false, nil
+	case model.Bytes:
+		// lvcreate and lvextend round an absolute size up to the next extent
+		return lvn.Size < size.Value, nil
+	}
+	volumeGroupPercent := size.Value
+	left := float64(volumeGroupPercent) - LogicalVolumeResizeTolerance
+	right := float64(volumeGroupPercent) + LogicalVolumeResizeTolerance
 	vgn := lb.lvmGraph.GetParents(lvn, model.VolumeGroupKind)
 	if len(vgn) == 0 {
 		return false, fmt.Errorf("🔴 %s: Logical volume has no volume group", name)
@@ -196,8 +221,8 @@ func (lb *LinuxLvmBackend) ShouldResizeLogicalVolume(name string, volumeGroup st
 	return usedPerecent < left, nil
 }
 
-func (lb *LinuxLvmBackend) ResizeLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) action.Action {
-	return action.NewResizeLogicalVolumeAction(name, volumeGroupPercent, volumeGroup, lb.lvmService)
+func (lb *LinuxLvmBackend) ResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) action.Action {
+	return action.NewResizeLogicalVolumeAction(name, size, volumeGroup, lb.lvmService)
 }
 
 func (db *LinuxLvmBackend) From(config *config.Config) error {
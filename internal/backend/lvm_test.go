@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/datastructures"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestShouldResizeLogicalVolume(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		LogicalVolume  uint64
+		Size           model.LvmSize
+		ExpectedOutput bool
+		ExpectedError  error
+	}{
+		{
+			Name:           "Percentage of Volume Group Within Tolerance",
+			LogicalVolume:  400 << 20,
+			Size:           model.LvmSize{Value: 40, Unit: model.VolumeGroupPercent},
+			ExpectedOutput: false,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Percentage of Volume Group Has Grown",
+			LogicalVolume:  200 << 20,
+			Size:           model.LvmSize{Value: 40, Unit: model.VolumeGroupPercent},
+			ExpectedOutput: true,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Percentage of Volume Group Would Shrink",
+			LogicalVolume:  800 << 20,
+			Size:           model.LvmSize{Value: 40, Unit: model.VolumeGroupPercent},
+			ExpectedOutput: false,
+			ExpectedError:  fmt.Errorf("🔴 db: Logical volume data is using 80%% of volume group db, which exceeds the expected usage of 40%%"),
+		},
+		{
+			Name:           "Absolute Size Not Reached",
+			LogicalVolume:  200 << 20,
+			Size:           model.LvmSize{Value: 300 << 20, Unit: model.Bytes},
+			ExpectedOutput: true,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Absolute Size Would Shrink",
+			LogicalVolume:  400 << 20,
+			Size:           model.LvmSize{Value: 300 << 20, Unit: model.Bytes},
+			ExpectedOutput: false,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Percentage of Free Space",
+			LogicalVolume:  200 << 20,
+			Size:           model.LvmSize{Value: 100, Unit: model.FreePercent},
+			ExpectedOutput: false,
+			ExpectedError:  nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lg := datastructures.NewLvmGraph()
+			utils.ExpectErr("lg.AddDevice()", t, false, lg.AddDevice("/dev/xvdf", 1000<<20))
+			utils.ExpectErr("lg.AddPhysicalVolume()", t, false, lg.AddPhysicalVolume("/dev/xvdf", 1000<<20))
+			utils.ExpectErr("lg.AddVolumeGroup()", t, false, lg.AddVolumeGroup("db", "/dev/xvdf", 1000<<20))
+			utils.ExpectErr("lg.AddLogicalVolume()", t, false, lg.AddLogicalVolume("data", "db", model.LogicalVolumeActive, subtest.LogicalVolume))
+
+			lb := NewMockLinuxLvmBackend(lg)
+			shouldResize, err := lb.ShouldResizeLogicalVolume("data", "db", subtest.Size)
+			utils.CheckError("lb.ShouldResizeLogicalVolume()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("lb.ShouldResizeLogicalVolume()", t, subtest.ExpectedOutput, shouldResize)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type RaidBackend interface {
+	GetArray(name string) (*model.RaidArray, error)
+	GetMember(name string) (*model.BlockDevice, error)
+	CreateArray(name string, level model.RaidLevel, chunkSize uint64, devices []string) action.Action
+	AssembleArray(name string, devices []string) action.Action
+	GetSignatures(bd *model.BlockDevice) []*model.Signature
+	Wipe(bd *model.BlockDevice, signatures []*model.Signature) action.Action
+	From(config *config.Config) error
+}
+
+type LinuxRaidBackend struct {
+	arrays        map[string]*model.RaidArray
+	members       map[string]*model.BlockDevice
+	signatures    map[string][]*model.Signature
+	deviceService service.DeviceService
+	mdadmService  service.MdadmService
+}
+
+func NewLinuxRaidBackend(ds service.DeviceService, ms service.MdadmService) *LinuxRaidBackend {
+	return &LinuxRaidBackend{
+		arrays:        map[string]*model.RaidArray{},
+		members:       map[string]*model.BlockDevice{},
+		signatures:    map[string][]*model.Signature{},
+		deviceService: ds,
+		mdadmService:  ms,
+	}
+}
+
+func NewMockLinuxRaidBackend(arrays map[string]*model.RaidArray, members map[string]*model.BlockDevice) *LinuxRaidBackend {
+	return NewMockLinuxRaidBackendWithSignatures(arrays, members, map[string][]*model.Signature{})
+}
+
+func NewMockLinuxRaidBackendWithSignatures(arrays map[string]*model.RaidArray, members map[string]*model.BlockDevice, signatures map[string][]*model.Signature) *LinuxRaidBackend {
+	return &LinuxRaidBackend{
+		arrays:        arrays,
+		members:       members,
+		signatures:    signatures,
+		deviceService: nil,
+		mdadmService:  nil,
+	}
+}
+
+// GetArray retrieves an active array by its name. An array that is inactive, because
+// it has either never been created or has yet to be assembled, does not exist
+func (rb *LinuxRaidBackend) GetArray(name string) (*model.RaidArray, error) {
+	ra, exists := rb.arrays[name]
+	if !exists {
+		return nil, os.ErrNotExist
+	}
+	return ra, nil
+}
+
+func (rb *LinuxRaidBackend) GetMember(name string) (*model.BlockDevice, error) {
+	bd, exists := rb.members[name]
+	if !exists {
+		return nil, fmt.Errorf("🔴 %s: Could not find block device", name)
+	}
+	return bd, nil
+}
+
+func (rb *LinuxRaidBackend) CreateArray(name string, level model.RaidLevel, chunkSize uint64, devices []string) action.Action {
+	return action.NewCreateRaidArrayAction(name, level, chunkSize, devices, rb.mdadmService)
+}
+
+func (rb *LinuxRaidBackend) AssembleArray(name string, devices []string) action.Action {
+	return action.NewAssembleRaidArrayAction(name, devices, rb.mdadmService)
+}
+
+func (rb *LinuxRaidBackend) GetSignatures(bd *model.BlockDevice) []*model.Signature {
+	return rb.signatures[bd.Name]
+}
+
+func (rb *LinuxRaidBackend) Wipe(bd *model.BlockDevice, signatures []*model.Signature) action.Action {
+	return action.NewWipeDeviceAction(bd.Name, signatures, rb.deviceService)
+}
+
+func (rb *LinuxRaidBackend) From(config *config.Config) error {
+	rb.arrays = nil
+	rb.members = nil
+	rb.signatures = nil
+	arrays := map[string]*model.RaidArray{}
+	members := map[string]*model.BlockDevice{}
+	signatures := map[string][]*model.Signature{}
+
+	configured := false
+	for _, cd := range config.Devices {
+		if cd.Raid == nil {
+			continue
+		}
+		configured = true
+		for _, member := range cd.Raid.Devices {
+			bd, err := rb.deviceService.GetBlockDevice(member)
+			if err != nil {
+				return err
+			}
+			members[member] = bd
+			if bd.FileSystem != model.Unformatted {
+				continue
+			}
+			s, err := rb.deviceService.GetSignatures(bd.Name)
+			if err != nil {
+				return err
+			}
+			signatures[bd.Name] = s
+		}
+	}
+	// Only query mdadm if at least one RAID array is configured, as it is
+	// not installed on every host
+	if configured {
+		ras, err := rb.mdadmService.GetArrays()
+		if err != nil {
+			return err
+		}
+		for _, ra := range ras {
+			arrays[ra.Name] = ra
+		}
+	}
+	rb.arrays = arrays
+	rb.members = members
+	rb.signatures = signatures
+	return nil
+}
@@ -0,0 +1,114 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+// StateBackend observes the state that a plan is created from. The same state is
+// observed again before a saved plan is applied, so that any drift can be detected
+type StateBackend interface {
+	Observe(devices []string, lvm bool) (*model.ObservedState, error)
+	ObserveConfig(config *config.Config) (*model.ObservedState, error)
+	CheckDrift(state *model.ObservedState) error
+}
+
+type LinuxStateBackend struct {
+	deviceService service.DeviceService
+	lvmService    service.LvmService
+}
+
+func NewLinuxStateBackend(ds service.DeviceService, ls service.LvmService) *LinuxStateBackend {
+	return &LinuxStateBackend{
+		deviceService: ds,
+		lvmService:    ls,
+	}
+}
+
+// Observe the named devices and, when requested, the LVM graph. A device that can
+// not be found is observed as not present, as it might only be created by a plan
+func (sb *LinuxStateBackend) Observe(devices []string, lvm bool) (*model.ObservedState, error) {
+	state := &model.ObservedState{
+		Devices: make([]*model.ObservedDevice, len(devices)),
+	}
+	for i, name := range devices {
+		od := &model.ObservedDevice{Name: name}
+		if bd, err := sb.deviceService.GetBlockDevice(name); err == nil {
+			od.Present = true
+			od.FileSystem = bd.FileSystem
+			od.Label = bd.Label
+			od.UUID = bd.UUID
+			od.MountPoint = bd.MountPoint
+			od.Size = bd.Size
+		}
+		state.Devices[i] = od
+	}
+	if !lvm {
+		return state, nil
+	}
+	pvs, err := sb.lvmService.GetPhysicalVolumes()
+	if err != nil {
+		return nil, err
+	}
+	vgs, err := sb.lvmService.GetVolumeGroups()
+	if err != nil {
+		return nil, err
+	}
+	lvs, err := sb.lvmService.GetLogicalVolumes()
+	if err != nil {
+		return nil, err
+	}
+	state.Lvm = &model.ObservedLvm{
+		PhysicalVolumes: pvs,
+		VolumeGroups:    vgs,
+		LogicalVolumes:  lvs,
+	}
+	return state, nil
+}
+
+// ObserveConfig observes the configured devices, along with the members of each
+// RAID array and the physical volumes of each volume group. A plan is created from
+// the state of these devices, even though they are not configured themselves. The
+// LVM graph is only observed when LVM is configured for a device
+func (sb *LinuxStateBackend) ObserveConfig(c *config.Config) (*model.ObservedState, error) {
+	lvm := false
+	devices := c.GetDevices()
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if len(cd.Lvm) > 0 {
+			lvm = true
+		}
+		if cd.Raid != nil {
+			devices = append(devices, cd.Raid.Devices...)
+		}
+		devices = append(devices, c.GetPhysicalVolumes(name)...)
+	}
+	observed := make([]string, 0, len(devices))
+	for _, name := range devices {
+		if !slices.Contains(observed, name) {
+			observed = append(observed, name)
+		}
+	}
+	return sb.Observe(observed, lvm)
+}
+
+// CheckDrift observes the devices of a saved plan again, and reports whether they
+// have drifted from the state that the plan was created from
+func (sb *LinuxStateBackend) CheckDrift(state *model.ObservedState) error {
+	devices := make([]string, len(state.Devices))
+	for i, od := range state.Devices {
+		devices[i] = od.Name
+	}
+	live, err := sb.Observe(devices, state.Lvm != nil)
+	if err != nil {
+		return err
+	}
+	if err := state.Drift(live); err != nil {
+		return fmt.Errorf("🔴 Devices have drifted since the plan was created. %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestLinuxStateBackendObserve(t *testing.T) {
+	ds := service.NewMockDeviceService()
+	ds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		if name != "/dev/xvdf" {
+			return nil, fmt.Errorf("🔴 %s: Could not find block device", name)
+		}
+		return &model.BlockDevice{
+			Name:       "/dev/xvdf",
+			FileSystem: model.Ext4,
+			Label:      "stateful",
+			UUID:       "5ac8c5b4-4d5b-4e6e-9ea0-0c6e84b7a3d5",
+			MountPoint: "/mnt/app",
+			Size:       1073741824,
+		}, nil
+	}
+	ls := service.NewMockLvmService()
+	ls.StubGetPhysicalVolumes = func() ([]*model.PhysicalVolume, error) {
+		return []*model.PhysicalVolume{{Name: "/dev/xvdg", Size: 1069547520}}, nil
+	}
+	ls.StubGetVolumeGroups = func() ([]*model.VolumeGroup, error) {
+		return []*model.VolumeGroup{}, nil
+	}
+	ls.StubGetLogicalVolumes = func() ([]*model.LogicalVolume, error) {
+		return []*model.LogicalVolume{}, nil
+	}
+	devices := []*model.ObservedDevice{
+		{Name: "/dev/xvdf", Present: true, FileSystem: model.Ext4, Label: "stateful", UUID: "5ac8c5b4-4d5b-4e6e-9ea0-0c6e84b7a3d5", MountPoint: "/mnt/app", Size: 1073741824},
+		{Name: "/dev/md0", Present: false},
+	}
+
+	subtests := []struct {
+		Name           string
+		Lvm            bool
+		ExpectedOutput *model.ObservedState
+	}{
+		{
+			Name:           "Devices Only",
+			Lvm:            false,
+			ExpectedOutput: &model.ObservedState{Devices: devices},
+		},
+		{
+			Name: "Devices and LVM Graph",
+			Lvm:  true,
+			ExpectedOutput: &model.ObservedState{
+				Devices: devices,
+				Lvm: &model.ObservedLvm{
+					PhysicalVolumes: []*model.PhysicalVolume{{Name: "/dev/xvdg", Size: 1069547520}},
+					VolumeGroups:    []*model.VolumeGroup{},
+					LogicalVolumes:  []*model.LogicalVolume{},
+				},
+			},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sb := NewLinuxStateBackend(ds, ls)
+			state, err := sb.Observe([]string{"/dev/xvdf", "/dev/md0"}, subtest.Lvm)
+			utils.CheckError("sb.Observe()", t, nil, err)
+			utils.CheckOutput("sb.Observe()", t, subtest.ExpectedOutput, state)
+		})
+	}
+}
+
+func TestLinuxStateBackendObserveConfig(t *testing.T) {
+	ds := service.NewMockDeviceService()
+	ds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		return &model.BlockDevice{Name: name, Size: 1073741824}, nil
+	}
+	ls := service.NewMockLvmService()
+	ls.StubGetPhysicalVolumes = func() ([]*model.PhysicalVolume, error) {
+		return []*model.PhysicalVolume{}, nil
+	}
+	ls.StubGetVolumeGroups = func() ([]*model.VolumeGroup, error) {
+		return []*model.VolumeGroup{}, nil
+	}
+	ls.StubGetLogicalVolumes = func() ([]*model.LogicalVolume, error) {
+		return []*model.LogicalVolume{}, nil
+	}
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"/dev/md0": {
+				Raid: &config.Raid{Level: model.Raid0, Devices: []string{"/dev/xvdf", "/dev/xvdg"}},
+			},
+			"data": {
+				Fs:  model.Xfs,
+				Lvm: "vg",
+			},
+		},
+		VolumeGroups: map[string]config.VolumeGroup{
+			"vg": {Devices: []string{"/dev/md0", "/dev/xvdh"}},
+		},
+	}
+	sb := NewLinuxStateBackend(ds, ls)
+	state, err := sb.ObserveConfig(c)
+	utils.CheckError("sb.ObserveConfig()", t, nil, err)
+
+	// The members of a RAID array and the physical volumes of a volume group are
+	// observed, so that a saved plan is not applied once they have drifted
+	names := make([]string, len(state.Devices))
+	for i, od := range state.Devices {
+		names[i] = od.Name
+	}
+	slices.Sort(names)
+	utils.CheckOutput("state.Devices", t, []string{"/dev/md0", "/dev/xvdf", "/dev/xvdg", "/dev/xvdh", "data"}, names)
+	utils.CheckOutput("state.Lvm != nil", t, true, state.Lvm != nil)
+}
+
+func TestLinuxStateBackendCheckDrift(t *testing.T) {
+	fs := model.Unformatted
+	ds := service.NewMockDeviceService()
+	ds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		return &model.BlockDevice{Name: name, FileSystem: fs, Size: 1073741824}, nil
+	}
+	state := &model.ObservedState{
+		Devices: []*model.ObservedDevice{
+			{Name: "/dev/xvdf", Present: true, FileSystem: model.Unformatted, Size: 1073741824},
+		},
+	}
+	sb := NewLinuxStateBackend(ds, nil)
+	utils.CheckError("sb.CheckDrift()", t, nil, sb.CheckDrift(state))
+
+	// The device was formatted after the plan was created
+	fs = model.Xfs
+	expected := fmt.Errorf("🔴 Devices have drifted since the plan was created. /dev/xvdf: File system drifted. Expected=unformatted, Actual=xfs")
+	utils.CheckError("sb.CheckDrift()", t, expected, sb.CheckDrift(state))
+}
@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type SwapBackend interface {
+	GetSwapArea(name string) (*model.SwapArea, error)
+	GetSwapFile(p string) (*model.File, error)
+	CreateSwapFile(p string, size uint64) action.Action
+	ChangePermissions(p string, perms model.FilePermissions) action.Action
+	Activate(name string, priority *int) action.Action
+	Deactivate(name string) action.Action
+	From(config *config.Config) error
+}
+
+type LinuxSwapBackend struct {
+	// The active swap area of each swap device and swap file. An inactive
+	// swap area is nil
+	swapAreas   map[string]*model.SwapArea
+	swapFiles   map[string]*model.File
+	swapService service.SwapService
+	fileService service.FileService
+}
+
+func NewLinuxSwapBackend(ss service.SwapService, fs service.FileService) *LinuxSwapBackend {
+	return &LinuxSwapBackend{
+		swapAreas:   map[string]*model.SwapArea{},
+		swapFiles:   map[string]*model.File{},
+		swapService: ss,
+		fileService: fs,
+	}
+}
+
+func NewMockLinuxSwapBackend(swapAreas map[string]*model.SwapArea) *LinuxSwapBackend {
+	return NewMockLinuxSwapBackendWithFiles(swapAreas, map[string]*model.File{})
+}
+
+func NewMockLinuxSwapBackendWithFiles(swapAreas map[string]*model.SwapArea, swapFiles map[string]*model.File) *LinuxSwapBackend {
+	return &LinuxSwapBackend{
+		swapAreas:   swapAreas,
+		swapFiles:   swapFiles,
+		swapService: nil,
+		fileService: nil,
+	}
+}
+
+func (lsb *LinuxSwapBackend) GetSwapArea(name string) (*model.SwapArea, error) {
+	sa, exists := lsb.swapAreas[name]
+	if !exists {
+		return nil, fmt.Errorf("🔴 %s: Could not find the state of a swap area", name)
+	}
+	return sa, nil
+}
+
+func (lsb *LinuxSwapBackend) GetSwapFile(p string) (*model.File, error) {
+	f, exists := lsb.swapFiles[p]
+	if !exists {
+		return nil, os.ErrNotExist
+	}
+	if f.Type != model.RegularFile {
+		return nil, fmt.Errorf("🔴 %s: Swap file is not a regular file", p)
+	}
+	return f, nil
+}
+
+func (lsb *LinuxSwapBackend) CreateSwapFile(p string, size uint64) action.Action {
+	return action.NewCreateSwapFileAction(p, size, lsb.swapService)
+}
+
+func (lsb *LinuxSwapBackend) ChangePermissions(p string, perms model.FilePermissions) action.Action {
+	return action.NewChangePermissionsAction(p, perms, lsb.fileService)
+}
+
+func (lsb *LinuxSwapBackend) Activate(name string, priority *int) action.Action {
+	return action.NewActivateSwapAction(name, priority, lsb.swapService)
+}
+
+func (lsb *LinuxSwapBackend) Deactivate(name string) action.Action {
+	return action.NewDeactivateSwapAction(name, lsb.swapService)
+}
+
+func (lsb *LinuxSwapBackend) From(config *config.Config) error {
+	lsb.swapAreas = nil
+	lsb.swapFiles = nil
+	swapAreas := map[string]*model.SwapArea{}
+	swapFiles := map[string]*model.File{}
+
+	for name, cd := range config.Devices {
+		if cd.Fs != model.Swap {
+			continue
+		}
+		sa, err := lsb.swapService.GetSwapArea(name)
+		if err != nil {
+			return err
+		}
+		swapAreas[name] = sa
+	}
+	for name := range config.SwapFiles {
+		f, err := lsb.fileService.GetFile(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				swapAreas[name] = nil
+				continue
+			}
+			return err
+		}
+		swapFiles[name] = f
+		sa, err := lsb.swapService.GetSwapArea(name)
+		if err != nil {
+			return err
+		}
+		swapAreas[name] = sa
+	}
+	lsb.swapAreas = swapAreas
+	lsb.swapFiles = swapFiles
+	return nil
+}
@@ -0,0 +1,193 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestGetSwapArea(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Device         string
+		SwapAreas      map[string]*model.SwapArea
+		ExpectedOutput *model.SwapArea
+		ExpectedError  error
+	}{
+		{
+			Name:   "Active Swap Area",
+			Device: "/dev/nvme1n1",
+			SwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", Size: 8589930496, Priority: -2},
+			},
+			ExpectedOutput: &model.SwapArea{Name: "/dev/nvme1n1", Size: 8589930496, Priority: -2},
+			ExpectedError:  nil,
+		},
+		{
+			Name:   "Inactive Swap Area",
+			Device: "/dev/nvme1n1",
+			SwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1": nil,
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Unknown Swap Area",
+			Device:         "/dev/nvme1n1",
+			SwapAreas:      map[string]*model.SwapArea{},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/nvme1n1: Could not find the state of a swap area"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sb := NewMockLinuxSwapBackend(subtest.SwapAreas)
+			sa, err := sb.GetSwapArea(subtest.Device)
+			utils.CheckError("sb.GetSwapArea()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("sb.GetSwapArea()", t, subtest.ExpectedOutput, sa)
+		})
+	}
+}
+
+func TestGetSwapFile(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Path           string
+		SwapFiles      map[string]*model.File
+		ExpectedOutput *model.File
+		ExpectedError  error
+	}{
+		{
+			Name: "Regular File",
+			Path: "/mnt/scratch/swapfile",
+			SwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.RegularFile, Permissions: 0600},
+			},
+			ExpectedOutput: &model.File{Path: "/mnt/scratch/swapfile", Type: model.RegularFile, Permissions: 0600},
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Directory",
+			Path: "/mnt/scratch/swapfile",
+			SwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.Directory, Permissions: 0755},
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /mnt/scratch/swapfile: Swap file is not a regular file"),
+		},
+		{
+			Name:           "Non-existent File",
+			Path:           "/mnt/scratch/swapfile",
+			SwapFiles:      map[string]*model.File{},
+			ExpectedOutput: nil,
+			ExpectedError:  os.ErrNotExist,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sb := NewMockLinuxSwapBackendWithFiles(map[string]*model.SwapArea{}, subtest.SwapFiles)
+			f, err := sb.GetSwapFile(subtest.Path)
+			utils.CheckError("sb.GetSwapFile()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("sb.GetSwapFile()", t, subtest.ExpectedOutput, f)
+		})
+	}
+}
+
+func TestLinuxSwapBackendFrom(t *testing.T) {
+	subtests := []struct {
+		Name              string
+		Config            *config.Config
+		GetFile           func(file string) (*model.File, error)
+		GetSwapArea       func(name string) (*model.SwapArea, error)
+		ExpectedSwapAreas map[string]*model.SwapArea
+		ExpectedSwapFiles map[string]*model.File
+		ExpectedError     error
+	}{
+		{
+			Name: "Swap Devices",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap},
+					"/dev/nvme2n1": {Fs: model.Swap},
+					"/dev/xvdf":    {Fs: model.Ext4},
+				},
+			},
+			GetSwapArea: func(name string) (*model.SwapArea, error) {
+				if name == "/dev/nvme1n1" {
+					return &model.SwapArea{Name: name, Size: 8589930496, Priority: -2}, nil
+				}
+				return nil, nil
+			},
+			ExpectedSwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", Size: 8589930496, Priority: -2},
+				"/dev/nvme2n1": nil,
+			},
+			ExpectedSwapFiles: map[string]*model.File{},
+			ExpectedError:     nil,
+		},
+		{
+			Name: "Swap Files",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+					"/mnt/app/swapfile":     {Size: "1GiB"},
+				},
+			},
+			GetFile: func(file string) (*model.File, error) {
+				if file == "/mnt/scratch/swapfile" {
+					return &model.File{Path: file, Type: model.RegularFile, Permissions: 0600}, nil
+				}
+				return nil, os.ErrNotExist
+			},
+			GetSwapArea: func(name string) (*model.SwapArea, error) {
+				return &model.SwapArea{Name: name, Size: 4294963200, Priority: 5}, nil
+			},
+			ExpectedSwapAreas: map[string]*model.SwapArea{
+				"/mnt/scratch/swapfile": {Name: "/mnt/scratch/swapfile", Size: 4294963200, Priority: 5},
+				"/mnt/app/swapfile":     nil,
+			},
+			ExpectedSwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.RegularFile, Permissions: 0600},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Failure to Read Swap Areas",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap},
+				},
+			},
+			GetSwapArea: func(name string) (*model.SwapArea, error) {
+				return nil, fmt.Errorf("🔴 Failed to decode /proc/swaps")
+			},
+			ExpectedSwapAreas: nil,
+			ExpectedSwapFiles: nil,
+			ExpectedError:     fmt.Errorf("🔴 Failed to decode /proc/swaps"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mfs := service.NewMockFileService()
+			if subtest.GetFile != nil {
+				mfs.StubGetFile = subtest.GetFile
+			}
+			mss := service.NewMockSwapService()
+			if subtest.GetSwapArea != nil {
+				mss.StubGetSwapArea = subtest.GetSwapArea
+			}
+
+			sb := NewLinuxSwapBackend(mss, mfs)
+			err := sb.From(subtest.Config)
+			utils.CheckError("sb.From()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("sb.From()", t, subtest.ExpectedSwapAreas, sb.swapAreas)
+			utils.CheckOutput("sb.From()", t, subtest.ExpectedSwapFiles, sb.swapFiles)
+		})
+	}
+}
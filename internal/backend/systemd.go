@@ -0,0 +1,84 @@
+package backend
+
+import (
+	"os"
+	"path"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+const (
+	SystemdUnitDirectory = "/etc/systemd/system"
+)
+
+type SystemdBackend interface {
+	GetMountUnit(mountPoint string) (string, error)
+	UpdateMountUnit(unit *model.MountUnit) action.Action
+	From(config *config.Config) error
+}
+
+type LinuxSystemdBackend struct {
+	// The contents of each mount unit, indexed by the mount point of the unit
+	units          map[string]string
+	directory      string
+	fileService    service.FileService
+	systemdService service.SystemdService
+}
+
+func NewLinuxSystemdBackend(fs service.FileService, ss service.SystemdService) *LinuxSystemdBackend {
+	return &LinuxSystemdBackend{
+		units:          map[string]string{},
+		directory:      SystemdUnitDirectory,
+		fileService:    fs,
+		systemdService: ss,
+	}
+}
+
+func NewMockLinuxSystemdBackend(units map[string]string) *LinuxSystemdBackend {
+	return &LinuxSystemdBackend{
+		units:          units,
+		directory:      SystemdUnitDirectory,
+		fileService:    nil,
+		systemdService: nil,
+	}
+}
+
+func (lsb *LinuxSystemdBackend) GetMountUnit(mountPoint string) (string, error) {
+	unit, exists := lsb.units[mountPoint]
+	if !exists {
+		return "", os.ErrNotExist
+	}
+	return unit, nil
+}
+
+func (lsb *LinuxSystemdBackend) UpdateMountUnit(unit *model.MountUnit) action.Action {
+	return action.NewUpdateMountUnitAction(lsb.path(unit.Where), unit, lsb.fileService, lsb.systemdService)
+}
+
+func (lsb *LinuxSystemdBackend) From(config *config.Config) error {
+	lsb.units = nil
+	units := map[string]string{}
+
+	for name, cd := range config.Devices {
+		if len(cd.MountPoint) == 0 || !config.GetSystemdMount(name) {
+			continue
+		}
+		data, err := lsb.fileService.ReadFile(lsb.path(cd.MountPoint))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		units[cd.MountPoint] = string(data)
+	}
+	lsb.units = units
+	return nil
+}
+
+func (lsb *LinuxSystemdBackend) path(mountPoint string) string {
+	return path.Join(lsb.directory, model.SystemdEscapePath(mountPoint)+model.MountUnitSuffix)
+}
@@ -0,0 +1,186 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestUpdateMountUnit(t *testing.T) {
+	lsb := NewMockLinuxSystemdBackend(nil)
+	unit := &model.MountUnit{
+		What:    "/dev/disk/by-label/stateful",
+		Where:   "/mnt/app-data",
+		Type:    model.Ext4,
+		Options: "defaults",
+	}
+	a := lsb.UpdateMountUnit(unit)
+	expected := action.NewUpdateMountUnitAction(`/etc/systemd/system/mnt-app\x2ddata.mount`, unit, nil, nil)
+	utils.CheckOutput("lsb.UpdateMountUnit()", t, expected, a, cmp.AllowUnexported(action.UpdateMountUnitAction{}))
+}
+
+func TestLinuxSystemdBackendFrom(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Config         *config.Config
+		ReadFile       func(p string) ([]byte, error)
+		ExpectedOutput map[string]string
+		ExpectedError  error
+	}{
+		{
+			Name: "Managed Unit Exists",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/ebs",
+						Options:    config.Options{SystemdMount: true},
+					},
+				},
+			},
+			ReadFile: func(p string) ([]byte, error) {
+				if p != "/etc/systemd/system/mnt-ebs.mount" {
+					return nil, fmt.Errorf("🔴 Unexpected path: %s", p)
+				}
+				return []byte("[Mount]\n"), nil
+			},
+			ExpectedOutput: map[string]string{
+				"/mnt/ebs": "[Mount]\n",
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Managed Unit Does Not Exist",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/ebs",
+						Options:    config.Options{SystemdMount: true},
+					},
+				},
+			},
+			ReadFile: func(p string) ([]byte, error) {
+				return nil, os.ErrNotExist
+			},
+			ExpectedOutput: map[string]string{},
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Unmanaged Unit",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/ebs",
+					},
+				},
+			},
+			ReadFile:       nil,
+			ExpectedOutput: map[string]string{},
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Unreadable Unit",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/ebs",
+						Options:    config.Options{SystemdMount: true},
+					},
+				},
+			},
+			ReadFile: func(p string) ([]byte, error) {
+				return nil, fmt.Errorf("🔴 permission denied")
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 permission denied"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mfs := service.NewMockFileService()
+			if subtest.ReadFile != nil {
+				mfs.StubReadFile = subtest.ReadFile
+			}
+			lsb := NewLinuxSystemdBackend(mfs, nil)
+			err := lsb.From(subtest.Config)
+			utils.CheckError("lsb.From()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("lsb.From()", t, subtest.ExpectedOutput, lsb.units)
+		})
+	}
+}
+
+// The mount unit is written to, and read back from, a temporary directory that
+// stands in for /etc/systemd/system. This verifies the generated unit without
+// depending on the presence of systemd
+func TestLinuxSystemdBackendTemporaryRoot(t *testing.T) {
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"/dev/xvdf": {
+				Fs:         model.Ext4,
+				Label:      "stateful",
+				MountPoint: "/var/lib/postgresql",
+				RequiredBy: []string{"postgresql.service"},
+				Options:    config.Options{SystemdMount: true},
+			},
+		},
+	}
+	unit := &model.MountUnit{
+		What:       "/dev/disk/by-label/stateful",
+		Where:      "/var/lib/postgresql",
+		Type:       model.Ext4,
+		Options:    "defaults,nofail",
+		RequiredBy: []string{"postgresql.service"},
+	}
+	enabled := []string{}
+	mss := service.NewMockSystemdService()
+	mss.StubDaemonReload = func() error {
+		return nil
+	}
+	mss.StubEnableUnit = func(name string) error {
+		enabled = append(enabled, name)
+		return nil
+	}
+
+	lsb := NewLinuxSystemdBackend(service.NewUnixFileService(), mss)
+	lsb.directory = t.TempDir()
+
+	err := lsb.From(c)
+	utils.CheckError("lsb.From()", t, nil, err)
+	_, err = lsb.GetMountUnit("/var/lib/postgresql")
+	utils.CheckError("lsb.GetMountUnit()", t, os.ErrNotExist, err)
+
+	err = lsb.UpdateMountUnit(unit).Execute()
+	utils.CheckError("a.Execute()", t, nil, err)
+	utils.CheckOutput("EnableUnit()", t, []string{"var-lib-postgresql.mount"}, enabled)
+
+	data, err := os.ReadFile(path.Join(lsb.directory, "var-lib-postgresql.mount"))
+	utils.CheckError("os.ReadFile()", t, nil, err)
+	utils.CheckOutput("os.ReadFile()", t, `# This file is managed by ebs-bootstrap. Manual changes will be overwritten
+[Unit]
+Description=Mount /dev/disk/by-label/stateful to /var/lib/postgresql
+Before=postgresql.service
+
+[Mount]
+What=/dev/disk/by-label/stateful
+Where=/var/lib/postgresql
+Type=ext4
+Options=defaults,nofail
+
+[Install]
+WantedBy=local-fs.target
+RequiredBy=postgresql.service
+`, string(data))
+
+	err = lsb.From(c)
+	utils.CheckError("lsb.From()", t, nil, err)
+	actual, err := lsb.GetMountUnit("/var/lib/postgresql")
+	utils.CheckError("lsb.GetMountUnit()", t, nil, err)
+	utils.CheckOutput("lsb.GetMountUnit()", t, unit.String(), actual)
+}
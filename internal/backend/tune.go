@@ -0,0 +1,94 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+)
+
+type TuneBackend interface {
+	GetProperties(name string) (*model.FileSystemProperties, error)
+	Tune(bd *model.BlockDevice, properties *model.FileSystemProperties) ([]action.Action, error)
+	From(config *config.Config) error
+}
+
+type LinuxTuneBackend struct {
+	properties               map[string]*model.FileSystemProperties
+	deviceService            service.DeviceService
+	fileSystemServiceFactory service.FileSystemServiceFactory
+}
+
+func NewLinuxTuneBackend(ds service.DeviceService, fssf service.FileSystemServiceFactory) *LinuxTuneBackend {
+	return &LinuxTuneBackend{
+		properties:               map[string]*model.FileSystemProperties{},
+		deviceService:            ds,
+		fileSystemServiceFactory: fssf,
+	}
+}
+
+func NewMockLinuxTuneBackend(properties map[string]*model.FileSystemProperties) *LinuxTuneBackend {
+	return &LinuxTuneBackend{
+		properties:               properties,
+		deviceService:            nil,
+		fileSystemServiceFactory: service.NewLinuxFileSystemServiceFactory(nil),
+	}
+}
+
+func (tb *LinuxTuneBackend) GetProperties(name string) (*model.FileSystemProperties, error) {
+	properties, exists := tb.properties[name]
+	if !exists {
+		return nil, fmt.Errorf("🔴 %s: Could not find file system properties", name)
+	}
+	return properties, nil
+}
+
+// Certain file systems like xfs can only be tuned while unmounted. For these file
+// systems, the tune action is prepended with an unmount action (if the device is
+// already mounted). The mount layer is then responsible for mounting it again
+func (tb *LinuxTuneBackend) Tune(bd *model.BlockDevice, properties *model.FileSystemProperties) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	fss, err := tb.fileSystemServiceFactory.Select(bd.FileSystem)
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: %s", bd.Name, err)
+	}
+	if fss.DoesTuneRequireUnmount() && len(bd.MountPoint) > 0 {
+		a := action.NewUnmountDeviceAction(bd.Name, bd.MountPoint, tb.deviceService)
+		actions = append(actions, a)
+	}
+	a := action.NewTuneFileSystemAction(
+		bd.Name,
+		properties,
+		fss,
+	)
+	return append(actions, a), nil
+}
+
+// The properties of a file system are only read when the device is to be tuned
+func (tb *LinuxTuneBackend) From(config *config.Config) error {
+	tb.properties = nil
+	properties := map[string]*model.FileSystemProperties{}
+
+	for name, cd := range config.Devices {
+		if cd.Tune == nil {
+			continue
+		}
+		bd, err := tb.deviceService.GetBlockDevice(name)
+		if err != nil {
+			return err
+		}
+		fss, err := tb.fileSystemServiceFactory.Select(bd.FileSystem)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: %s", bd.Name, err)
+		}
+		fp, err := fss.GetProperties(bd.Name)
+		if err != nil {
+			return err
+		}
+		properties[bd.Name] = fp
+	}
+	tb.properties = properties
+	return nil
+}
@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestGetProperties(t *testing.T) {
+	zero := uint64(0)
+	subtests := []struct {
+		Name           string
+		Device         string
+		Properties     map[string]*model.FileSystemProperties
+		ExpectedOutput *model.FileSystemProperties
+		ExpectedError  error
+	}{
+		{
+			Name:   "Valid Device",
+			Device: "/dev/xvdf",
+			Properties: map[string]*model.FileSystemProperties{
+				"/dev/xvdf": {ReservedBlocksPercent: &zero},
+			},
+			ExpectedOutput: &model.FileSystemProperties{ReservedBlocksPercent: &zero},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Invalid Device",
+			Device:         "/dev/xvdf",
+			Properties:     map[string]*model.FileSystemProperties{},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: Could not find file system properties"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			tb := NewMockLinuxTuneBackend(subtest.Properties)
+			properties, err := tb.GetProperties(subtest.Device)
+			utils.CheckError("tb.GetProperties()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("tb.GetProperties()", t, subtest.ExpectedOutput, properties)
+		})
+	}
+}
+
+func TestLinuxTuneBackendFrom(t *testing.T) {
+	fssf := service.NewLinuxFileSystemServiceFactory(nil)
+	zero := uint64(0)
+
+	subtests := []struct {
+		Name           string
+		Config         *config.Config
+		GetBlockDevice func(name string) (*model.BlockDevice, error)
+		GetProperties  func(name string) (*model.FileSystemProperties, error)
+		ExpectedOutput map[string]*model.FileSystemProperties
+		ExpectedError  error
+	}{
+		{
+			Name: "Valid Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Tune: &config.Tune{ReservedBlocksPercent: &zero}},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Ext4,
+				}, nil
+			},
+			GetProperties: func(name string) (*model.FileSystemProperties, error) {
+				return &model.FileSystemProperties{ReservedBlocksPercent: &zero}, nil
+			},
+			ExpectedOutput: map[string]*model.FileSystemProperties{
+				"/dev/xvdf": {ReservedBlocksPercent: &zero},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Device Without Tune Section",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {},
+				},
+			},
+			ExpectedOutput: map[string]*model.FileSystemProperties{},
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Unformatted File System",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Tune: &config.Tune{ReservedBlocksPercent: &zero}},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Unformatted,
+				}, nil
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: An unformatted file system can not be queried/modified"),
+		},
+		{
+			Name: "Failure to Get File System Properties",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Tune: &config.Tune{ReservedBlocksPercent: &zero}},
+				},
+			},
+			GetBlockDevice: func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{
+					Name:       name,
+					FileSystem: model.Ext4,
+				}, nil
+			},
+			GetProperties: func(name string) (*model.FileSystemProperties, error) {
+				return nil, fmt.Errorf("🔴 tune2fs is either not installed or accessible from $PATH")
+			},
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 tune2fs is either not installed or accessible from $PATH"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ds := service.NewMockDeviceService()
+			if subtest.GetBlockDevice != nil {
+				ds.StubGetBlockDevice = subtest.GetBlockDevice
+			}
+			fss := service.NewMockFileSystemService()
+			if subtest.GetProperties != nil {
+				fss.StubGetProperties = subtest.GetProperties
+			}
+
+			tb := NewLinuxTuneBackend(ds, service.NewMockFileSystemServiceFactory(fssf, fss))
+
+			err := tb.From(subtest.Config)
+			utils.CheckError("tb.From()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("tb.From()", t, subtest.ExpectedOutput, tb.properties)
+		})
+	}
+}
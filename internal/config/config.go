@@ -5,6 +5,9 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path"
+	"slices"
+	"strings"
 
 	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"gopkg.in/yaml.v2"
@@ -14,10 +17,17 @@ const (
 	DefaultMode           = model.Healthcheck
 	DefaultMountOptions   = model.MountOptions("defaults")
 	DefaultLvmConsumption = 100
+	DefaultFsckPass       = uint(2)
 )
 
 type Flag struct {
+	Command        string
 	Config         string
+	Output         string
+	Approve        string
+	PromptStyle    string
+	Out            string
+	Plan           string
 	Mode           string
 	Remount        bool
 	MountOptions   string
@@ -26,14 +36,126 @@ type Flag struct {
 }
 
 type Device struct {
-	Fs          model.FileSystem      `yaml:"fs"`
-	MountPoint  string                `yaml:"mountPoint"`
-	User        string                `yaml:"user"`
-	Group       string                `yaml:"group"`
-	Label       string                `yaml:"label"`
+	Fs         model.FileSystem `yaml:"fs"`
+	MountPoint string           `yaml:"mountPoint"`
+	User       string           `yaml:"user"`
+	Group      string           `yaml:"group"`
+	Label      string           `yaml:"label"`
+	// The UUID of the file system. Either a fixed UUID, or random to assign a
+	// new UUID when the file system shares its UUID with another attached device
+	Uuid        string                `yaml:"uuid"`
 	Permissions model.FilePermissions `yaml:"permissions"`
 	Lvm         string                `yaml:"lvm"`
-	Options     `yaml:",inline"`
+	// The size of the logical volume (e.g. 80%VG, 100%FREE or 20GiB). When omitted,
+	// the logical volume consumes lvmConsumption% of its volume group
+	Size       string      `yaml:"size"`
+	Raid       *Raid       `yaml:"raid"`
+	Encryption *Encryption `yaml:"encryption"`
+	Selector   *Selector   `yaml:"selector"`
+	Match      *Match      `yaml:"match"`
+	// Units that must not start until the device is mounted. Only
+	// applicable to devices that are mounted by a systemd mount unit
+	RequiredBy []string `yaml:"requiredBy"`
+	// Permits the signatures (e.g. a partition table) of an unformatted device
+	// to be erased before it is formatted, or before it becomes a RAID member
+	AllowWipe bool `yaml:"allowWipe"`
+	// Permits an instance store volume with an existing file system, other than
+	// the one that was requested, to be reformatted. Defaults to never
+	Reformat      model.ReformatPolicy `yaml:"reformat"`
+	FormatOptions *FormatOptions       `yaml:"formatOptions"`
+	Tune          *Tune                `yaml:"tune"`
+	// Checks the file system for errors before it is mounted. A repair is only
+	// attempted when errors are found, and is subject to the mode of the device
+	Fsck model.FsckMode `yaml:"fsck"`
+	// The options of a device with a swap file system. A swap area is
+	// activated, rather than mounted
+	Swap    *Swap `yaml:"swap"`
+	Options `yaml:",inline"`
+}
+
+// The priority of a swap area is between -1 and 32767. A pointer is used to
+// distinguish an omitted priority, which is left for the kernel to assign
+type Swap struct {
+	Priority *int `yaml:"priority"`
+}
+
+// A swap file that is created upon a mounted file system. The key of a swap
+// file is its absolute path. An existing swap file is never resized
+type SwapFile struct {
+	Size     string     `yaml:"size"`
+	Priority *int       `yaml:"priority"`
+	Mode     model.Mode `yaml:"mode"`
+}
+
+// The options that are passed to mkfs when a device is formatted. Each option is
+// validated against the file system of the device
+type FormatOptions struct {
+	BlockSize             uint64  `yaml:"blockSize"`
+	InodeRatio            uint64  `yaml:"inodeRatio"`
+	ReservedBlocksPercent *uint64 `yaml:"reservedBlocksPercent"`
+	LazyItableInit        *bool   `yaml:"lazyItableInit"`
+	Reflink               *bool   `yaml:"reflink"`
+	// The stripe unit (KiB) and stripe width (number of data devices) of
+	// the RAID array that the file system is created upon
+	StripeUnit  uint64 `yaml:"stripeUnit"`
+	StripeWidth uint64 `yaml:"stripeWidth"`
+}
+
+// The properties of an existing file system that are tuned in place. A property
+// that is omitted is left unchanged
+type Tune struct {
+	ReservedBlocksPercent *uint64              `yaml:"reservedBlocksPercent"`
+	MaxMountCount         *int64               `yaml:"maxMountCount"`
+	Errors                model.ErrorBehaviour `yaml:"errors"`
+	// Features are only ever enabled. A feature that is omitted is left unchanged
+	Features []string `yaml:"features"`
+}
+
+// The key of a device with a RAID configuration is the name of the array, which
+// is exposed to the host as /dev/md/<name>
+type Raid struct {
+	Level model.RaidLevel `yaml:"level"`
+	// The chunk size (KiB) of a striped array. mdadm selects a default when omitted
+	ChunkSize uint64   `yaml:"chunkSize"`
+	Devices   []string `yaml:"devices"`
+}
+
+// A device with an encryption configuration is formatted as a LUKS2 device. The
+// decrypted contents of the device are exposed to the host as /dev/mapper/<name>
+type Encryption struct {
+	Name    string `yaml:"name"`
+	KeyFile string `yaml:"keyFile"`
+	// A random key is generated for a device whose contents need not outlive the
+	// host, such as an instance store volume. The key does not survive a reboot
+	Ephemeral bool `yaml:"ephemeral"`
+}
+
+// A selector identifies a device by a property that does not depend on the name
+// it is given by the host. The key of a device with a selector is only a name
+type Selector struct {
+	VolumeId string `yaml:"volumeId"`
+}
+
+// A match selects the only device whose attributes satisfy every provided criterion.
+// The key of a device with a match is only a name
+type Match struct {
+	// The size range (e.g. 100GiB) of the device, inclusive of both bounds
+	MinSize string `yaml:"minSize"`
+	MaxSize string `yaml:"maxSize"`
+	// The model number of an NVMe device (e.g. Amazon EC2 NVMe Instance Storage)
+	Model  string `yaml:"model"`
+	Serial string `yaml:"serial"`
+	Label  string `yaml:"label"`
+	Uuid   string `yaml:"uuid"`
+	// The index of an instance store volume, as per its virtual name (e.g. 0 for
+	// ephemeral0). A pointer is used to distinguish an omitted index from 0
+	InstanceStore *uint `yaml:"instanceStore"`
+}
+
+// A volume group that spans one or more devices. The logical volume of the volume
+// group is configured by a device that references it with the lvm attribute
+type VolumeGroup struct {
+	Devices []string `yaml:"devices"`
 }
 
 type Options struct {
@@ -42,14 +164,30 @@ type Options struct {
 	MountOptions   model.MountOptions `yaml:"mountOptions"`
 	Resize         bool               `yaml:"resize"`
 	LvmConsumption uint64             `yaml:"lvmConsumption"`
+	Fstab          bool               `yaml:"fstab"`
+	// A pointer is used to distinguish an omitted pass number from a pass
+	// number of 0, which disables the file system check at boot
+	FsckPass     *uint `yaml:"fsckPass"`
+	SystemdMount bool  `yaml:"systemdMount"`
 }
 
-// We don't export "overrides" as this is an attribute that is used
-// internally to store the state of flag overrides
+// The unexported attributes are used internally to store the state of the
+// flags and of the devices expanded from a template
 type Config struct {
-	Defaults  Options           `yaml:"defaults"`
-	Devices   map[string]Device `yaml:"devices"`
-	overrides Options
+	Defaults     Options                `yaml:"defaults"`
+	Devices      map[string]Device      `yaml:"devices"`
+	VolumeGroups map[string]VolumeGroup `yaml:"volumeGroups"`
+	// A template that is expanded into a device for each instance store volume
+	InstanceStore *Device             `yaml:"instanceStore"`
+	SwapFiles     map[string]SwapFile `yaml:"swapFiles"`
+	overrides     Options
+	command       model.Command
+	output        model.Output
+	approvals     *model.Approvals
+	promptStyle   model.PromptStyle
+	planOutput    string
+	savedPlan     *model.SavedPlan
+	expansions    []string
 }
 
 func New(args []string) (*Config, error) {
@@ -60,26 +198,90 @@ func New(args []string) (*Config, error) {
 		return nil, fmt.Errorf("🔴 Failed to parse provided flags")
 	}
 
-	// Load config file into memory
-	file, err := os.ReadFile(f.Config)
+	// Validate the subcommand and output format before the config file is loaded
+	command, err := model.ParseCommand(f.Command)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("🔴 %s: File not found", f.Config)
-		}
-		return nil, fmt.Errorf("🔴 %s: %v", f.Config, err)
+		return nil, err
+	}
+	output, err := model.ParseOutput(f.Output)
+	if err != nil {
+		return nil, err
+	}
+	promptStyle, err := model.ParsePromptStyle(f.PromptStyle)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(f.Out) > 0 && command != model.Plan {
+		return nil, fmt.Errorf("🔴 A plan can only be saved with -out by the %s command", model.Plan)
+	}
+	// Pre-approved actions are never prompted for, and so can not be approved in a batch
+	if len(f.Approve) > 0 && promptStyle == model.BatchPrompt {
+		return nil, fmt.Errorf("🔴 Pre-approved actions can not be provided with -approve to the %s prompt style", model.BatchPrompt)
 	}
 
 	// Create config structure
 	c := &Config{}
 
-	// Unmarshal YAML file from memory into struct
-	err = yaml.UnmarshalStrict(file, c)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		return nil, fmt.Errorf("🔴 %s: Failed to ingest malformed config", f.Config)
+	// A saved plan is applied without the config file that it was created from,
+	// as the plan already records every action to be executed
+	if command == model.Apply {
+		if len(f.Plan) == 0 {
+			return nil, fmt.Errorf("🔴 Must provide the path of a saved plan to %s", model.Apply)
+		}
+		// Each action of a saved plan retains the mode that it was planned with
+		if len(f.Mode) > 0 {
+			return nil, fmt.Errorf("🔴 A mode can not be provided with -mode to %s. Provide it when the plan is created instead", model.Apply)
+		}
+		data, err := os.ReadFile(f.Plan)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("🔴 %s: File not found", f.Plan)
+			}
+			return nil, fmt.Errorf("🔴 %s: %v", f.Plan, err)
+		}
+		c.savedPlan, err = model.ParseSavedPlan(data)
+		if err != nil {
+			return nil, fmt.Errorf("🔴 %s: %s", f.Plan, err)
+		}
+	} else {
+		// Load config file into memory
+		file, err := os.ReadFile(f.Config)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("🔴 %s: File not found", f.Config)
+			}
+			return nil, fmt.Errorf("🔴 %s: %v", f.Config, err)
+		}
+
+		// Unmarshal YAML file from memory into struct
+		err = yaml.UnmarshalStrict(file, c)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, fmt.Errorf("🔴 %s: Failed to ingest malformed config", f.Config)
+		}
+	}
+
+	// Load the pre-approved actions, if provided
+	if len(f.Approve) > 0 {
+		data, err := os.ReadFile(f.Approve)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("🔴 %s: File not found", f.Approve)
+			}
+			return nil, fmt.Errorf("🔴 %s: %v", f.Approve, err)
+		}
+		c.approvals, err = model.ParseApprovals(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("🔴 %s: %s", f.Approve, err)
+		}
 	}
 
-	// Inject flag overrides into config
+	// Inject subcommand, output format, prompt style, plan output and flag overrides into config
+	c.command = command
+	c.output = output
+	c.promptStyle = promptStyle
+	c.planOutput = f.Out
 	return c.setOverrides(f), nil
 }
 
@@ -96,17 +298,39 @@ func parseFlags(program string, args []string) (*Flag, error) {
 	// to supply the configuration file
 	flags.StringVar(&f.Config, "config", "/etc/ebs-bootstrap/config.yml", "path to config file")
 	flags.StringVar(&f.Mode, "mode", "", "override for mode")
+	flags.StringVar(&f.Output, "output", "", "output format (text or json)")
+	flags.StringVar(&f.Approve, "approve", "", "path to file of pre-approved actions for prompt mode")
+	flags.StringVar(&f.PromptStyle, "prompt-style", "", "how approval is sought in prompt mode (individual or batch)")
+	flags.StringVar(&f.Out, "out", "", "path to save the plan to (plan only)")
 	flags.BoolVar(&f.Remount, "remount", false, "override for remount")
 	flags.StringVar(&f.MountOptions, "mount-options", "", "override for mount options")
 	flags.BoolVar(&f.Resize, "resize", false, "override for resize filesystem")
 	flags.Uint64Var(&f.LvmConsumption, "lvm-consumption", 0, "override for lvm consumption")
 
+	// A subcommand (e.g. "plan") can be provided before any flags
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		f.Command = args[0]
+		args = args[1:]
+	}
+
 	// Actually parse the flag
 	err := flags.Parse(args)
 	if err != nil {
 		return nil, fmt.Errorf(buf.String())
 	}
 
+	// The path of a saved plan (e.g. "apply plan.json") can be followed by further
+	// flags. Parsing stops at the path, and so resumes after it
+	if flags.NArg() > 0 {
+		f.Plan = flags.Arg(0)
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return nil, fmt.Errorf(buf.String())
+		}
+		if flags.NArg() > 0 {
+			return nil, fmt.Errorf("Unexpected argument: %s\n", flags.Arg(0))
+		}
+	}
+
 	return f, nil
 }
 
@@ -119,6 +343,149 @@ func (c *Config) setOverrides(f *Flag) *Config {
 	return c
 }
 
+func (c *Config) GetCommand() model.Command {
+	return c.command
+}
+
+func (c *Config) GetOutput() model.Output {
+	return c.output
+}
+
+// GetApprovals returns the actions that were approved in advance with -approve.
+// Without -approve, approval is sought interactively
+func (c *Config) GetApprovals() *model.Approvals {
+	return c.approvals
+}
+
+// GetPromptStyle returns whether the actions of prompt mode are approved individually,
+// or together with a single prompt
+func (c *Config) GetPromptStyle() model.PromptStyle {
+	return c.promptStyle
+}
+
+// GetPlanOutput returns the path that a plan is saved to with -out. Without -out,
+// a plan is only printed
+func (c *Config) GetPlanOutput() string {
+	return c.planOutput
+}
+
+// GetSavedPlan returns the plan that is applied by the apply command
+func (c *Config) GetSavedPlan() *model.SavedPlan {
+	return c.savedPlan
+}
+
+// GetDevices returns the names of the configured devices in a deterministic order.
+// Devices are ordered by the depth of their mount point, so that a device is always
+// processed after any device whose mount point is an ancestor of its own
+func (c *Config) GetDevices() []string {
+	names := make([]string, 0, len(c.Devices))
+	for name := range c.Devices {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		ma, mb := c.Devices[a].MountPoint, c.Devices[b].MountPoint
+		if d := model.PathDepth(ma) - model.PathDepth(mb); d != 0 {
+			return d
+		}
+		if ma != mb {
+			return strings.Compare(ma, mb)
+		}
+		return strings.Compare(a, b)
+	})
+	return names
+}
+
+// GetFormatOptions translates the format options of a device. The label of the
+// device is included, so that it can be assigned at format time
+func (c *Config) GetFormatOptions(name string) *model.FormatOptions {
+	cd, found := c.Devices[name]
+	if !found {
+		return nil
+	}
+	fo := &model.FormatOptions{Label: cd.Label}
+	if cd.FormatOptions != nil {
+		fo.BlockSize = cd.FormatOptions.BlockSize
+		fo.InodeRatio = cd.FormatOptions.InodeRatio
+		fo.ReservedBlocksPercent = cd.FormatOptions.ReservedBlocksPercent
+		fo.LazyItableInit = cd.FormatOptions.LazyItableInit
+		fo.Reflink = cd.FormatOptions.Reflink
+		fo.StripeUnit = cd.FormatOptions.StripeUnit
+		fo.StripeWidth = cd.FormatOptions.StripeWidth
+	}
+	return fo
+}
+
+// GetTuneProperties translates the tune section of a device. A device without a
+// tune section has no properties to tune
+func (c *Config) GetTuneProperties(name string) *model.FileSystemProperties {
+	cd, found := c.Devices[name]
+	if !found || cd.Tune == nil {
+		return nil
+	}
+	return &model.FileSystemProperties{
+		ReservedBlocksPercent: cd.Tune.ReservedBlocksPercent,
+		MaxMountCount:         cd.Tune.MaxMountCount,
+		Errors:                cd.Tune.Errors,
+		Features:              cd.Tune.Features,
+	}
+}
+
+// GetSwapPriority returns the priority of the swap area of a device, or of a
+// swap file. A nil priority is left for the kernel to assign
+func (c *Config) GetSwapPriority(name string) *int {
+	if sf, found := c.SwapFiles[name]; found {
+		return sf.Priority
+	}
+	cd, found := c.Devices[name]
+	if !found || cd.Swap == nil {
+		return nil
+	}
+	return cd.Swap.Priority
+}
+
+// GetSwapFiles returns the paths of the configured swap files in a deterministic order
+func (c *Config) GetSwapFiles() []string {
+	names := make([]string, 0, len(c.SwapFiles))
+	for name := range c.SwapFiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GetSwapFileSize returns the size of a swap file in bytes. An invalid size is
+// reported by the swap validator
+func (c *Config) GetSwapFileSize(name string) uint64 {
+	sf, found := c.SwapFiles[name]
+	if !found {
+		return 0
+	}
+	size, err := model.ParseByteSize(sf.Size)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+// GetSwapFileMode resolves the mode of a swap file. A swap file is not a device,
+// so the mode of a swap file falls back to the default mode of every device
+func (c *Config) GetSwapFileMode(name string) model.Mode {
+	sf, found := c.SwapFiles[name]
+	if !found {
+		return DefaultMode
+	}
+	if c.overrides.Mode != model.Empty {
+		return c.overrides.Mode
+	}
+	if sf.Mode != model.Empty {
+		return sf.Mode
+	}
+	if c.Defaults.Mode != model.Empty {
+		return c.Defaults.Mode
+	}
+	return DefaultMode
+}
+
 func (c *Config) GetMode(name string) model.Mode {
 	cd, found := c.Devices[name]
 	if !found {
@@ -185,3 +552,115 @@ func (c *Config) GetLvmConsumption(name string) uint64 {
 	}
 	return DefaultLvmConsumption
 }
+
+func (c *Config) GetFstab(name string) bool {
+	cd, found := c.Devices[name]
+	if !found {
+		return false
+	}
+	return c.Defaults.Fstab || cd.Fstab
+}
+
+func (c *Config) GetFsckPass(name string) uint {
+	cd, found := c.Devices[name]
+	if !found {
+		return DefaultFsckPass
+	}
+	if cd.FsckPass != nil {
+		return *cd.FsckPass
+	}
+	if c.Defaults.FsckPass != nil {
+		return *c.Defaults.FsckPass
+	}
+	return DefaultFsckPass
+}
+
+func (c *Config) GetSystemdMount(name string) bool {
+	cd, found := c.Devices[name]
+	if !found {
+		return false
+	}
+	return c.Defaults.SystemdMount || cd.SystemdMount
+}
+
+// GetPhysicalVolumes returns the devices that make up the volume group of a device.
+// A volume group declared under volumeGroups can span several devices. Otherwise,
+// the device itself is the only physical volume of its volume group
+func (c *Config) GetPhysicalVolumes(name string) []string {
+	cd, found := c.Devices[name]
+	if !found || len(cd.Lvm) == 0 {
+		return nil
+	}
+	if vg, declared := c.VolumeGroups[cd.Lvm]; declared {
+		return vg.Devices
+	}
+	return []string{name}
+}
+
+// isPhysicalVolume reports whether a device is a physical volume of a declared volume group
+func (c *Config) isPhysicalVolume(name string) bool {
+	for _, vg := range c.VolumeGroups {
+		if slices.Contains(vg.Devices, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLogicalVolume returns the name of the logical volume of a device. A device that
+// references a declared volume group is keyed by the name of its logical volume.
+// Otherwise, the logical volume is named after its volume group
+func (c *Config) GetLogicalVolume(name string) string {
+	cd, found := c.Devices[name]
+	if !found || len(cd.Lvm) == 0 {
+		return ""
+	}
+	if _, declared := c.VolumeGroups[cd.Lvm]; declared {
+		return name
+	}
+	return cd.Lvm
+}
+
+// GetLogicalVolumeSize returns the size of the logical volume of a device. An explicit
+// size takes precedence over the percentage of the volume group that is otherwise
+// consumed, as determined by lvmConsumption
+func (c *Config) GetLogicalVolumeSize(name string) (model.LvmSize, error) {
+	cd, found := c.Devices[name]
+	if found && len(cd.Size) > 0 {
+		size, err := model.ParseLvmSize(cd.Size)
+		if err != nil {
+			return model.LvmSize{}, fmt.Errorf("🔴 %s: %s", name, err)
+		}
+		return *size, nil
+	}
+	return model.LvmSize{Value: c.GetLvmConsumption(name), Unit: model.VolumeGroupPercent}, nil
+}
+
+// GetKeyFile returns the path of the key of an encrypted device. An ephemeral key
+// is written to a tmpfs, where it is named after the mapping of the device
+func (c *Config) GetKeyFile(name string) string {
+	cd, found := c.Devices[name]
+	if !found || cd.Encryption == nil {
+		return ""
+	}
+	if cd.Encryption.Ephemeral {
+		return path.Join(model.EphemeralKeyDirectory, cd.Encryption.Name+".key")
+	}
+	return cd.Encryption.KeyFile
+}
+
+// GetVolumeId returns the id of the EBS volume that a device refers to. A device can
+// either be keyed by the id of its volume, or select its volume with a selector
+func (c *Config) GetVolumeId(name string) string {
+	cd, found := c.Devices[name]
+	if !found {
+		return ""
+	}
+	if cd.Selector != nil && len(cd.Selector.VolumeId) > 0 {
+		return cd.Selector.VolumeId
+	}
+	if model.IsVolumeId(name) {
+		return name
+	}
+	return ""
+}
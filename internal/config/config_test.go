@@ -50,6 +50,8 @@ devices:
 						},
 					},
 				},
+				output:      model.TextOutput,
+				promptStyle: model.IndividualPrompt,
 			},
 			ExpectedError: nil,
 		},
@@ -106,6 +108,16 @@ func TestFlagParsing(t *testing.T) {
 			Args:          []string{"ebs-bootstrap", "-unsupported-flag"},
 			ExpectedError: fmt.Errorf("🔴 Failed to parse provided flags"),
 		},
+		{
+			Name:          "Unsupported Command",
+			Args:          []string{"ebs-bootstrap", "unsupported-command"},
+			ExpectedError: fmt.Errorf("🔴 Command 'unsupported-command' is not supported"),
+		},
+		{
+			Name:          "Unsupported Output",
+			Args:          []string{"ebs-bootstrap", "-output", "yaml"},
+			ExpectedError: fmt.Errorf("🔴 Output 'yaml' is not supported"),
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -115,6 +127,298 @@ func TestFlagParsing(t *testing.T) {
 	}
 }
 
+func TestCommandParsing(t *testing.T) {
+	c, err := createConfigFile([]byte(`---
+devices:
+  /dev/xvdf: ~`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(c)
+	subtests := []struct {
+		Name           string
+		Args           []string
+		ExpectedOutput model.Command
+		ExpectedMode   model.Mode
+	}{
+		{
+			Name:           "No Command",
+			Args:           []string{"ebs-bootstrap", "-config", c},
+			ExpectedOutput: model.Bootstrap,
+			ExpectedMode:   model.Healthcheck,
+		},
+		{
+			Name:           "Plan Command",
+			Args:           []string{"ebs-bootstrap", "plan", "-config", c},
+			ExpectedOutput: model.Plan,
+			ExpectedMode:   model.Healthcheck,
+		},
+		{
+			Name:           "Plan Command + Mode Flag",
+			Args:           []string{"ebs-bootstrap", "plan", "-config", c, "-mode", string(model.Force)},
+			ExpectedOutput: model.Plan,
+			ExpectedMode:   model.Force,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c, err := New(subtest.Args)
+			utils.CheckError("config.New()", t, nil, err)
+			utils.CheckOutput("c.GetCommand()", t, subtest.ExpectedOutput, c.GetCommand())
+			utils.CheckOutput("c.GetMode()", t, subtest.ExpectedMode, c.GetMode("/dev/xvdf"))
+		})
+	}
+}
+
+func TestOutputParsing(t *testing.T) {
+	c, err := createConfigFile([]byte(`---
+devices:
+  /dev/xvdf: ~`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(c)
+	subtests := []struct {
+		Name           string
+		Args           []string
+		ExpectedOutput model.Output
+	}{
+		{
+			Name:           "Default Output",
+			Args:           []string{"ebs-bootstrap", "-config", c},
+			ExpectedOutput: model.TextOutput,
+		},
+		{
+			Name:           "JSON Output",
+			Args:           []string{"ebs-bootstrap", "-config", c, "-output", "json"},
+			ExpectedOutput: model.JsonOutput,
+		},
+		{
+			Name:           "Plan Command + JSON Output",
+			Args:           []string{"ebs-bootstrap", "plan", "-config", c, "-output=json"},
+			ExpectedOutput: model.JsonOutput,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c, err := New(subtest.Args)
+			utils.CheckError("config.New()", t, nil, err)
+			utils.CheckOutput("c.GetOutput()", t, subtest.ExpectedOutput, c.GetOutput())
+		})
+	}
+}
+
+func TestPromptStyleParsing(t *testing.T) {
+	c, err := createConfigFile([]byte(`---
+devices:
+  /dev/xvdf: ~`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(c)
+	subtests := []struct {
+		Name                string
+		Args                []string
+		ExpectedPromptStyle model.PromptStyle
+		ExpectedError       error
+	}{
+		{
+			Name:                "Default Prompt Style",
+			Args:                []string{"ebs-bootstrap", "-config", c},
+			ExpectedPromptStyle: model.IndividualPrompt,
+			ExpectedError:       nil,
+		},
+		{
+			Name:                "Batch Prompt Style",
+			Args:                []string{"ebs-bootstrap", "-config", c, "-mode", "prompt", "-prompt-style=batch"},
+			ExpectedPromptStyle: model.BatchPrompt,
+			ExpectedError:       nil,
+		},
+		{
+			Name:                "Unsupported Prompt Style",
+			Args:                []string{"ebs-bootstrap", "-config", c, "-prompt-style=interactive"},
+			ExpectedPromptStyle: model.PromptStyle(""),
+			ExpectedError:       fmt.Errorf("🔴 Prompt style 'interactive' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c, err := New(subtest.Args)
+			utils.CheckError("config.New()", t, subtest.ExpectedError, err)
+			if err != nil {
+				return
+			}
+			utils.CheckOutput("c.GetPromptStyle()", t, subtest.ExpectedPromptStyle, c.GetPromptStyle())
+		})
+	}
+}
+
+func TestApprovalsParsing(t *testing.T) {
+	c, err := createConfigFile([]byte(`---
+devices:
+  /dev/xvdf: ~`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(c)
+	a, err := createConfigFile([]byte(`{"action":"format","device":"/dev/xvdf","parameters":{"device":"/dev/xvdf","fileSystem":"ext4"}}`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(a)
+	m, err := createConfigFile([]byte(`format /dev/xvdf`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(m)
+
+	format := model.Fingerprint{
+		Action:     model.FormatAction,
+		Device:     "/dev/xvdf",
+		Parameters: map[string]string{"device": "/dev/xvdf", "fileSystem": "ext4"},
+	}
+	subtests := []struct {
+		Name             string
+		Args             []string
+		ExpectedApproved bool
+		ExpectedError    error
+	}{
+		{
+			Name:             "No Approvals",
+			Args:             []string{"ebs-bootstrap", "-config", c},
+			ExpectedApproved: false,
+			ExpectedError:    nil,
+		},
+		{
+			Name:             "Approvals",
+			Args:             []string{"ebs-bootstrap", "-config", c, "-approve", a},
+			ExpectedApproved: true,
+			ExpectedError:    nil,
+		},
+		{
+			Name:             "Approvals With Batch Prompt Style",
+			Args:             []string{"ebs-bootstrap", "-config", c, "-approve", a, "-prompt-style=batch"},
+			ExpectedApproved: false,
+			ExpectedError:    fmt.Errorf("🔴 Pre-approved actions can not be provided with -approve to the batch prompt style"),
+		},
+		{
+			Name:             "Non-existent Approvals",
+			Args:             []string{"ebs-bootstrap", "-config", c, "-approve", "/doesnt-exist"},
+			ExpectedApproved: false,
+			ExpectedError:    fmt.Errorf("🔴 /doesnt-exist: File not found"),
+		},
+		{
+			Name:             "Malformed Approvals",
+			Args:             []string{"ebs-bootstrap", "-config", c, "-approve", m},
+			ExpectedApproved: false,
+			ExpectedError:    fmt.Errorf("🔴 %s: Line 1 is not a valid action fingerprint", m),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c, err := New(subtest.Args)
+			utils.CheckError("config.New()", t, subtest.ExpectedError, err)
+			if err != nil {
+				return
+			}
+			approved := c.GetApprovals() != nil && c.GetApprovals().IsApproved(format)
+			utils.CheckOutput("c.GetApprovals()", t, subtest.ExpectedApproved, approved)
+		})
+	}
+}
+
+func TestSavedPlanParsing(t *testing.T) {
+	c, err := createConfigFile([]byte(`---
+devices:
+  /dev/xvdf: ~`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(c)
+	p, err := createConfigFile([]byte(`{"state":{"devices":[{"name":"/dev/xvdf","present":true}]},"actions":[]}`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(p)
+	m, err := createConfigFile([]byte(`format /dev/xvdf`))
+	utils.CheckError("createConfigFile()", t, nil, err)
+	defer os.Remove(m)
+
+	subtests := []struct {
+		Name               string
+		Args               []string
+		ExpectedSavedPlan  bool
+		ExpectedPlanOutput string
+		ExpectedError      error
+	}{
+		{
+			Name:               "Save Plan",
+			Args:               []string{"ebs-bootstrap", "plan", "-config", c, "-out", "/tmp/plan.json"},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "/tmp/plan.json",
+			ExpectedError:      nil,
+		},
+		{
+			Name:               "Save Plan Without Plan Command",
+			Args:               []string{"ebs-bootstrap", "-config", c, "-out", "/tmp/plan.json"},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "",
+			ExpectedError:      fmt.Errorf("🔴 A plan can only be saved with -out by the plan command"),
+		},
+		{
+			Name:               "Apply Plan Without Config",
+			Args:               []string{"ebs-bootstrap", "apply", "-config", "/doesnt-exist", p},
+			ExpectedSavedPlan:  true,
+			ExpectedPlanOutput: "",
+			ExpectedError:      nil,
+		},
+		{
+			Name:               "Apply Without Plan",
+			Args:               []string{"ebs-bootstrap", "apply"},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "",
+			ExpectedError:      fmt.Errorf("🔴 Must provide the path of a saved plan to apply"),
+		},
+		{
+			Name:               "Apply Plan With Mode",
+			Args:               []string{"ebs-bootstrap", "apply", "-mode", "force", p},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "",
+			ExpectedError:      fmt.Errorf("🔴 A mode can not be provided with -mode to apply. Provide it when the plan is created instead"),
+		},
+		{
+			Name:               "Apply Plan Followed By Mode",
+			Args:               []string{"ebs-bootstrap", "apply", p, "-mode", "force"},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "",
+			ExpectedError:      fmt.Errorf("🔴 A mode can not be provided with -mode to apply. Provide it when the plan is created instead"),
+		},
+		{
+			Name:               "Apply Plan Followed By Flags",
+			Args:               []string{"ebs-bootstrap", "apply", p, "-output", "json"},
+			ExpectedSavedPlan:  true,
+			ExpectedPlanOutput: "",
+			ExpectedError:      nil,
+		},
+		{
+			Name:               "Apply Plan With Unexpected Argument",
+			Args:               []string{"ebs-bootstrap", "apply", p, "plan.json"},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "",
+			ExpectedError:      fmt.Errorf("🔴 Failed to parse provided flags"),
+		},
+		{
+			Name:               "Apply Non-existent Plan",
+			Args:               []string{"ebs-bootstrap", "apply", "/doesnt-exist"},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "",
+			ExpectedError:      fmt.Errorf("🔴 /doesnt-exist: File not found"),
+		},
+		{
+			Name:               "Apply Malformed Plan",
+			Args:               []string{"ebs-bootstrap", "apply", m},
+			ExpectedSavedPlan:  false,
+			ExpectedPlanOutput: "",
+			ExpectedError:      fmt.Errorf("🔴 %s: Plan is not valid JSON", m),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c, err := New(subtest.Args)
+			utils.CheckError("config.New()", t, subtest.ExpectedError, err)
+			if err != nil {
+				return
+			}
+			utils.CheckOutput("c.GetSavedPlan()", t, subtest.ExpectedSavedPlan, c.GetSavedPlan() != nil)
+			utils.CheckOutput("c.GetPlanOutput()", t, subtest.ExpectedPlanOutput, c.GetPlanOutput())
+		})
+	}
+}
+
 func TestOptions(t *testing.T) {
 	device := "/dev/xvdf"
 	subtests := []struct {
@@ -323,3 +627,253 @@ func createConfigFile(data []byte) (string, error) {
 	}
 	return f.Name(), nil
 }
+
+func TestFstabOptions(t *testing.T) {
+	device := "/dev/xvdf"
+	subtests := []struct {
+		Name             string
+		Data             []byte
+		ExpectedFstab    bool
+		ExpectedFsckPass uint
+	}{
+		{
+			Name: "Device Options",
+			Data: []byte(fmt.Sprintf(`---
+devices:
+  %s:
+    fstab: true
+    fsckPass: 0`, device)),
+			ExpectedFstab:    true,
+			ExpectedFsckPass: 0,
+		},
+		{
+			Name: "Default Options",
+			Data: []byte(fmt.Sprintf(`---
+defaults:
+  fstab: true
+  fsckPass: 1
+devices:
+  %s: ~`, device)),
+			ExpectedFstab:    true,
+			ExpectedFsckPass: 1,
+		},
+		{
+			Name: "Omitted Options",
+			Data: []byte(fmt.Sprintf(`---
+devices:
+  %s: ~`, device)),
+			ExpectedFstab:    false,
+			ExpectedFsckPass: DefaultFsckPass,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			configPath, err := createConfigFile(subtest.Data)
+			utils.CheckError("createConfigFile()", t, nil, err)
+			defer os.Remove(configPath)
+
+			c, err := New([]string{"ebs-bootstrap", "-config", configPath})
+			utils.CheckError("config.New()", t, nil, err)
+			utils.CheckOutput("c.GetFstab()", t, subtest.ExpectedFstab, c.GetFstab(device))
+			utils.CheckOutput("c.GetFsckPass()", t, subtest.ExpectedFsckPass, c.GetFsckPass(device))
+		})
+	}
+}
+
+func TestSwapOptions(t *testing.T) {
+	device := "/dev/nvme1n1"
+	swapFile := "/mnt/scratch/swapfile"
+	subtests := []struct {
+		Name                   string
+		Data                   []byte
+		ExpectedDevicePriority *int
+		ExpectedFilePriority   *int
+		ExpectedFileSize       uint64
+		ExpectedFileMode       model.Mode
+	}{
+		{
+			Name: "Swap Options",
+			Data: []byte(fmt.Sprintf(`---
+defaults:
+  mode: prompt
+devices:
+  %s:
+    fs: swap
+    swap:
+      priority: 10
+swapFiles:
+  %s:
+    size: 4GiB
+    priority: -1
+    mode: force`, device, swapFile)),
+			ExpectedDevicePriority: intPtr(10),
+			ExpectedFilePriority:   intPtr(-1),
+			ExpectedFileSize:       4294967296,
+			ExpectedFileMode:       model.Force,
+		},
+		{
+			Name: "Default Options",
+			Data: []byte(fmt.Sprintf(`---
+defaults:
+  mode: prompt
+devices:
+  %s:
+    fs: swap
+swapFiles:
+  %s:
+    size: 512MiB`, device, swapFile)),
+			ExpectedDevicePriority: nil,
+			ExpectedFilePriority:   nil,
+			ExpectedFileSize:       536870912,
+			ExpectedFileMode:       model.Prompt,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			configPath, err := createConfigFile(subtest.Data)
+			utils.CheckError("createConfigFile()", t, nil, err)
+			defer os.Remove(configPath)
+
+			c, err := New([]string{"ebs-bootstrap", "-config", configPath})
+			utils.CheckError("config.New()", t, nil, err)
+			utils.CheckOutput("c.GetSwapPriority()", t, subtest.ExpectedDevicePriority, c.GetSwapPriority(device))
+			utils.CheckOutput("c.GetSwapPriority()", t, subtest.ExpectedFilePriority, c.GetSwapPriority(swapFile))
+			utils.CheckOutput("c.GetSwapFiles()", t, []string{swapFile}, c.GetSwapFiles())
+			utils.CheckOutput("c.GetSwapFileSize()", t, subtest.ExpectedFileSize, c.GetSwapFileSize(swapFile))
+			utils.CheckOutput("c.GetSwapFileMode()", t, subtest.ExpectedFileMode, c.GetSwapFileMode(swapFile))
+		})
+	}
+}
+
+func TestEncryptionOptions(t *testing.T) {
+	device := "/dev/xvdf"
+	subtests := []struct {
+		Name            string
+		Data            []byte
+		ExpectedKeyFile string
+	}{
+		{
+			Name: "Key File",
+			Data: []byte(fmt.Sprintf(`---
+devices:
+  %s:
+    encryption:
+      name: secure
+      keyFile: /etc/ebs-bootstrap/secure.key`, device)),
+			ExpectedKeyFile: "/etc/ebs-bootstrap/secure.key",
+		},
+		{
+			Name: "Ephemeral Key",
+			Data: []byte(fmt.Sprintf(`---
+devices:
+  %s:
+    encryption:
+      name: scratch
+      ephemeral: true`, device)),
+			ExpectedKeyFile: "/run/ebs-bootstrap/keys/scratch.key",
+		},
+		{
+			Name: "Omitted Encryption",
+			Data: []byte(fmt.Sprintf(`---
+devices:
+  %s: ~`, device)),
+			ExpectedKeyFile: "",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			configPath, err := createConfigFile(subtest.Data)
+			utils.CheckError("createConfigFile()", t, nil, err)
+			defer os.Remove(configPath)
+
+			c, err := New([]string{"ebs-bootstrap", "-config", configPath})
+			utils.CheckError("config.New()", t, nil, err)
+			utils.CheckOutput("c.GetKeyFile()", t, subtest.ExpectedKeyFile, c.GetKeyFile(device))
+		})
+	}
+}
+
+func TestLogicalVolumeSize(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Device         Device
+		ExpectedOutput model.LvmSize
+		ExpectedError  error
+	}{
+		{
+			Name:           "Explicit Size",
+			Device:         Device{Lvm: "db", Size: "20GiB"},
+			ExpectedOutput: model.LvmSize{Value: 20 << 30, Unit: model.Bytes},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Lvm Consumption",
+			Device:         Device{Lvm: "db", Options: Options{LvmConsumption: 80}},
+			ExpectedOutput: model.LvmSize{Value: 80, Unit: model.VolumeGroupPercent},
+			ExpectedError:  nil,
+		},
+		{
+			// A size that can not be parsed never falls back to lvmConsumption
+			Name:           "Invalid Size",
+			Device:         Device{Lvm: "db", Size: "20G", Options: Options{LvmConsumption: 80}},
+			ExpectedOutput: model.LvmSize{},
+			ExpectedError:  fmt.Errorf("🔴 data: Logical volume size '20G' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c := &Config{Devices: map[string]Device{"data": subtest.Device}}
+			size, err := c.GetLogicalVolumeSize("data")
+			utils.CheckError("c.GetLogicalVolumeSize()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("c.GetLogicalVolumeSize()", t, subtest.ExpectedOutput, size)
+		})
+	}
+}
+
+func TestDeviceOrder(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Data           []byte
+		ExpectedOutput []string
+	}{
+		{
+			Name: "Nested Mount Points",
+			Data: []byte(`---
+devices:
+  /dev/xvdh:
+    mountPoint: /data/logs/archive
+  /dev/xvdg:
+    mountPoint: /data/logs
+  /dev/xvdf:
+    mountPoint: /data`),
+			ExpectedOutput: []string{"/dev/xvdf", "/dev/xvdg", "/dev/xvdh"},
+		},
+		{
+			Name: "Mount Points of Equal Depth",
+			Data: []byte(`---
+devices:
+  /dev/xvdf:
+    mountPoint: /mnt/foo
+  /dev/xvdg:
+    mountPoint: /mnt/bar
+  /dev/xvdh: ~
+  /dev/xvdi: ~`),
+			ExpectedOutput: []string{"/dev/xvdh", "/dev/xvdi", "/dev/xvdg", "/dev/xvdf"},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			configPath, err := createConfigFile(subtest.Data)
+			utils.CheckError("createConfigFile()", t, nil, err)
+			defer os.Remove(configPath)
+
+			c, err := New([]string{"ebs-bootstrap", "-config", configPath})
+			utils.CheckError("config.New()", t, nil, err)
+			utils.CheckOutput("c.GetDevices()", t, subtest.ExpectedOutput, c.GetDevices())
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
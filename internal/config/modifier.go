@@ -3,8 +3,11 @@ package config
 import (
 	"fmt"
 	"log"
+	"path"
+	"slices"
 	"strings"
 
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"github.com/reecetech/ebs-bootstrap/internal/service"
 )
 
@@ -29,6 +32,9 @@ func (andm *AwsNitroNVMeModifier) Modify(c *Config) error {
 	if err != nil {
 		return err
 	}
+	// The id of each EBS volume is only queried if at least one device refers
+	// to an EBS volume by its id
+	volumes := andm.getVolumeIds(c)
 	for _, name := range bds {
 		// Check if device already exists in the config
 		// No need to make additional queries if this is the case
@@ -44,25 +50,369 @@ func (andm *AwsNitroNVMeModifier) Modify(c *Config) error {
 			return err
 		}
 		log.Printf("🔵 Nitro NVMe detected: %s -> %s", name, bdm)
-		cd, exists := c.Devices[bdm]
-		// We can detect AWS NVMe Devices, but this doesn't neccesarily
-		// mean they will be managed through configuration
-		if !exists {
+		if err := andm.replace(c, bdm, name); err != nil {
+			return err
+		}
+		if len(volumes) == 0 {
+			continue
+		}
+		id, err := andm.nvmeService.GetVolumeId(name)
+		if err != nil {
+			return err
+		}
+		// An instance store volume does not have a volume id
+		if len(id) == 0 {
+			continue
+		}
+		log.Printf("🔵 EBS volume detected: %s -> %s", name, id)
+		if err := andm.replace(c, id, name); err != nil {
+			return err
+		}
+		delete(volumes, id)
+	}
+	// Report the volume with the lowest id, so that the error is deterministic
+	unresolved := make([]string, 0, len(volumes))
+	for id := range volumes {
+		unresolved = append(unresolved, id)
+	}
+	slices.Sort(unresolved)
+	if len(unresolved) > 0 {
+		return fmt.Errorf("🔴 %s: Could not find a device for EBS volume %s", volumes[unresolved[0]], unresolved[0])
+	}
+	return nil
+}
+
+// getVolumeIds returns each EBS volume id that is referenced by the config, paired
+// with the name of the device, RAID array or volume group that references it
+func (andm *AwsNitroNVMeModifier) getVolumeIds(c *Config) map[string]string {
+	volumes := map[string]string{}
+	for name, cd := range c.Devices {
+		if id := c.GetVolumeId(name); len(id) > 0 {
+			volumes[id] = name
+		}
+		if cd.Raid == nil {
+			continue
+		}
+		for _, member := range cd.Raid.Devices {
+			if model.IsVolumeId(member) {
+				volumes[member] = name
+			}
+		}
+	}
+	for name, vg := range c.VolumeGroups {
+		for _, member := range vg.Devices {
+			if model.IsVolumeId(member) {
+				volumes[member] = name
+			}
+		}
+	}
+	return volumes
+}
+
+// replace substitutes every reference to a device with the actual name of the device.
+// A device can be referenced by the key of a device configuration, by a selector or
+// as the member of a RAID array or a volume group. Two device configurations that
+// reference the same device can not both be replaced by its actual name
+func (andm *AwsNitroNVMeModifier) replace(c *Config, reference string, name string) error {
+	for _, cd := range c.Devices {
+		if cd.Raid == nil {
+			continue
+		}
+		for i, member := range cd.Raid.Devices {
+			if member == reference {
+				cd.Raid.Devices[i] = name
+			}
+		}
+	}
+	for _, vg := range c.VolumeGroups {
+		for i, member := range vg.Devices {
+			if member == reference {
+				vg.Devices[i] = name
+			}
+		}
+	}
+	for key, cd := range c.Devices {
+		if cd.Selector == nil || cd.Selector.VolumeId != reference {
+			continue
+		}
+		if _, exists := c.Devices[name]; exists {
+			return fmt.Errorf("🔴 %s: %s is already a configured device", key, name)
+		}
+		// The device is now referenced by its actual name, which makes the
+		// selector redundant
+		cd.Selector = nil
+		c.Devices[name] = cd
+		delete(c.Devices, key)
+		return nil
+	}
+	cd, exists := c.Devices[reference]
+	// We can detect AWS NVMe Devices, but this doesn't neccesarily
+	// mean they will be managed through configuration
+	if !exists {
+		return nil
+	}
+	if _, exists := c.Devices[name]; exists {
+		return fmt.Errorf("🔴 %s: %s is already a configured device", reference, name)
+	}
+	// Delete the original reference to the device configuration from the
+	// block device mapping retrieved from the NVMe IoCtl interface and
+	// replace it with the actual device name
+	//	Before:
+	// 		/dev/sdb => *config.Device (a)
+	//	After:
+	//		/dev/nvme0n1 => *config.Device (a)
+	c.Devices[name] = cd
+	delete(c.Devices, reference)
+	return nil
+}
+
+const (
+	InstanceStoreIndexPlaceholder = "{index}"
+	InstanceStoreNamePlaceholder  = "{name}"
+	InstanceStoreBdmPlaceholder   = "{bdm}"
+)
+
+// InstanceStoreModifier expands the instanceStore template into a device for each
+// instance store volume that is attached to the host. The number of instance store
+// volumes depends on the instance type, so the expansion must precede any validator
+// that compares the attributes of devices
+type InstanceStoreModifier struct {
+	nvmeService   service.NVMeService
+	deviceService service.DeviceService
+}
+
+func NewInstanceStoreModifier(nvmeService service.NVMeService, deviceService service.DeviceService) *InstanceStoreModifier {
+	return &InstanceStoreModifier{
+		nvmeService:   nvmeService,
+		deviceService: deviceService,
+	}
+}
+
+func (ism *InstanceStoreModifier) Modify(c *Config) error {
+	if c.InstanceStore == nil {
+		return nil
+	}
+	bds, err := ism.deviceService.GetBlockDevices()
+	if err != nil {
+		return err
+	}
+	if c.Devices == nil {
+		c.Devices = map[string]Device{}
+	}
+	for _, name := range bds {
+		if !strings.HasPrefix(name, "/dev/nvme") {
 			continue
 		}
-		// Delete the original reference to the device configuration from the
-		// block device mapping retrieved from the NVMe IoCtl interface and
-		// replace it with the actual device name
-		//	Before:
-		// 		/dev/sdb => *config.Device (a)
-		//	After:
-		//		/dev/nvme0n1 => *config.Device (a)
+		nc, err := ism.nvmeService.GetController(name)
+		if err != nil {
+			return err
+		}
+		if nc.Model != service.AMZN_NVME_INS_MN {
+			continue
+		}
+		bdm, err := ism.nvmeService.GetBlockDeviceMapping(name)
+		if err != nil {
+			return err
+		}
+		// A device that is explicitly configured takes precedence over the template
+		_, exists := c.Devices[name]
+		_, mapped := c.Devices[bdm]
+		if exists || mapped {
+			continue
+		}
+		r := strings.NewReplacer(
+			InstanceStoreIndexPlaceholder, strings.TrimPrefix(nc.VirtualName, "ephemeral"),
+			InstanceStoreNamePlaceholder, path.Base(name),
+			InstanceStoreBdmPlaceholder, path.Base(bdm),
+		)
+		cd := cloneDevice(c.InstanceStore)
+		cd.MountPoint = r.Replace(cd.MountPoint)
+		cd.Label = r.Replace(cd.Label)
+		log.Printf("🔵 Instance store detected: %s -> %s", nc.VirtualName, name)
 		c.Devices[name] = cd
-		delete(c.Devices, bdm)
+		c.expansions = append(c.expansions, name)
 	}
+	slices.Sort(c.expansions)
 	return nil
 }
 
+// cloneDevice copies a device, including every value that it references, so that
+// no expansion of a template shares a value with another expansion
+func cloneDevice(d *Device) Device {
+	cd := *d
+	cd.RequiredBy = slices.Clone(d.RequiredBy)
+	cd.FsckPass = clonePointer(d.FsckPass)
+	if d.Raid != nil {
+		raid := *d.Raid
+		raid.Devices = slices.Clone(d.Raid.Devices)
+		cd.Raid = &raid
+	}
+	cd.Encryption = clonePointer(d.Encryption)
+	cd.Selector = clonePointer(d.Selector)
+	if d.Match != nil {
+		match := *d.Match
+		match.InstanceStore = clonePointer(d.Match.InstanceStore)
+		cd.Match = &match
+	}
+	if d.FormatOptions != nil {
+		fo := *d.FormatOptions
+		fo.ReservedBlocksPercent = clonePointer(d.FormatOptions.ReservedBlocksPercent)
+		fo.LazyItableInit = clonePointer(d.FormatOptions.LazyItableInit)
+		fo.Reflink = clonePointer(d.FormatOptions.Reflink)
+		cd.FormatOptions = &fo
+	}
+	if d.Tune != nil {
+		tune := *d.Tune
+		tune.ReservedBlocksPercent = clonePointer(d.Tune.ReservedBlocksPercent)
+		tune.MaxMountCount = clonePointer(d.Tune.MaxMountCount)
+		tune.Features = slices.Clone(d.Tune.Features)
+		cd.Tune = &tune
+	}
+	if d.Swap != nil {
+		swap := *d.Swap
+		swap.Priority = clonePointer(d.Swap.Priority)
+		cd.Swap = &swap
+	}
+	return cd
+}
+
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// MatchModifier replaces the name of each device that is selected by a match with
+// the actual name of the only device that satisfies it
+type MatchModifier struct {
+	nvmeService   service.NVMeService
+	deviceService service.DeviceService
+}
+
+func NewMatchModifier(nvmeService service.NVMeService, deviceService service.DeviceService) *MatchModifier {
+	return &MatchModifier{
+		nvmeService:   nvmeService,
+		deviceService: deviceService,
+	}
+}
+
+// The attributes of a block device that can satisfy a match. The controller
+// of a device that is not an NVMe device is nil
+type matchCandidate struct {
+	blockDevice *model.BlockDevice
+	size        uint64
+	controller  *model.NVMeController
+}
+
+func (mm *MatchModifier) Modify(c *Config) error {
+	// Process the devices in a stable order, so that any error is deterministic
+	keys := []string{}
+	for name, cd := range c.Devices {
+		if cd.Match != nil {
+			keys = append(keys, name)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	slices.Sort(keys)
+	candidates, err := mm.getCandidates()
+	if err != nil {
+		return err
+	}
+	matched := map[string]string{}
+	for _, key := range keys {
+		cd := c.Devices[key]
+		names := []string{}
+		for _, mc := range candidates {
+			if mm.matches(cd.Match, mc) {
+				names = append(names, mc.blockDevice.Name)
+			}
+		}
+		if len(names) == 0 {
+			return fmt.Errorf("🔴 %s: No device satisfies the match", key)
+		}
+		if len(names) > 1 {
+			return fmt.Errorf("🔴 %s: More than one device satisfies the match (%s)", key, strings.Join(names, ", "))
+		}
+		name := names[0]
+		if other, exists := matched[name]; exists {
+			return fmt.Errorf("🔴 %s: %s is already selected by %s", key, name, other)
+		}
+		if _, exists := c.Devices[name]; exists {
+			return fmt.Errorf("🔴 %s: %s is already a configured device", key, name)
+		}
+		matched[name] = key
+		log.Printf("🔵 Match detected: %s -> %s", key, name)
+		// The device is now referenced by its actual name, which makes the
+		// match redundant
+		cd.Match = nil
+		c.Devices[name] = cd
+		delete(c.Devices, key)
+	}
+	return nil
+}
+
+func (mm *MatchModifier) getCandidates() ([]*matchCandidate, error) {
+	bds, err := mm.deviceService.GetBlockDevices()
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]*matchCandidate, 0, len(bds))
+	for _, name := range bds {
+		bd, err := mm.deviceService.GetBlockDevice(name)
+		if err != nil {
+			return nil, err
+		}
+		size, err := mm.deviceService.GetSize(name)
+		if err != nil {
+			return nil, err
+		}
+		mc := &matchCandidate{blockDevice: bd, size: size}
+		if strings.HasPrefix(name, "/dev/nvme") {
+			mc.controller, err = mm.nvmeService.GetController(name)
+			if err != nil {
+				return nil, err
+			}
+		}
+		candidates = append(candidates, mc)
+	}
+	return candidates, nil
+}
+
+// The criteria of a match have already been validated by MatchValidator
+func (mm *MatchModifier) matches(m *Match, mc *matchCandidate) bool {
+	if len(m.MinSize) > 0 {
+		if size, _ := model.ParseByteSize(m.MinSize); mc.size < size {
+			return false
+		}
+	}
+	if len(m.MaxSize) > 0 {
+		if size, _ := model.ParseByteSize(m.MaxSize); mc.size > size {
+			return false
+		}
+	}
+	if len(m.Label) > 0 && mc.blockDevice.Label != m.Label {
+		return false
+	}
+	if len(m.Uuid) > 0 && mc.blockDevice.UUID != m.Uuid {
+		return false
+	}
+	nc := mc.controller
+	if len(m.Model) > 0 && (nc == nil || nc.Model != m.Model) {
+		return false
+	}
+	if len(m.Serial) > 0 && (nc == nil || nc.Serial != m.Serial) {
+		return false
+	}
+	if m.InstanceStore != nil && (nc == nil || nc.VirtualName != fmt.Sprintf("ephemeral%d", *m.InstanceStore)) {
+		return false
+	}
+	return true
+}
+
 type LvmModifier struct{}
 
 func NewLvmModifier() *LvmModifier {
@@ -79,10 +429,77 @@ func (lm *LvmModifier) Modify(c *Config) error {
 	for _, key := range keys {
 		device := c.Devices[key]
 		if len(device.Lvm) > 0 {
-			ldn := fmt.Sprintf("/dev/%s/%s", device.Lvm, device.Lvm)
+			ldn := fmt.Sprintf("/dev/%s/%s", device.Lvm, c.GetLogicalVolume(key))
+			// The device now refers to the logical volume itself, rather
+			// than the physical volumes that it was created from
+			device.Lvm = ""
 			c.Devices[ldn] = device
 			delete(c.Devices, key)
 		}
 	}
 	return nil
 }
+
+type RaidModifier struct{}
+
+func NewRaidModifier() *RaidModifier {
+	return &RaidModifier{}
+}
+
+func (rm *RaidModifier) Modify(c *Config) error {
+	// Fetch a copy of the original keys as we are updating the
+	// config in-place and it is unsafe to iterate over it directly
+	keys := make([]string, 0, len(c.Devices))
+	for name := range c.Devices {
+		keys = append(keys, name)
+	}
+	for _, key := range keys {
+		device := c.Devices[key]
+		if device.Raid != nil {
+			rdn := path.Join(model.RaidDirectory, key)
+			// An array that is a physical volume of a volume group is referenced
+			// by its name. It is only managed as a physical volume from now on
+			pv := false
+			for _, vg := range c.VolumeGroups {
+				for i, member := range vg.Devices {
+					if member == key {
+						vg.Devices[i] = rdn
+						pv = true
+					}
+				}
+			}
+			if !pv {
+				c.Devices[rdn] = device
+			}
+			delete(c.Devices, key)
+		}
+	}
+	return nil
+}
+
+type EncryptionModifier struct{}
+
+func NewEncryptionModifier() *EncryptionModifier {
+	return &EncryptionModifier{}
+}
+
+func (em *EncryptionModifier) Modify(c *Config) error {
+	// Fetch a copy of the original keys as we are updating the
+	// config in-place and it is unsafe to iterate over it directly
+	keys := make([]string, 0, len(c.Devices))
+	for name := range c.Devices {
+		keys = append(keys, name)
+	}
+	for _, key := range keys {
+		device := c.Devices[key]
+		if device.Encryption != nil {
+			mdn := path.Join(model.MapperDirectory, device.Encryption.Name)
+			// The device now refers to the decrypted contents of the LUKS
+			// device, rather than the LUKS device itself
+			device.Encryption = nil
+			c.Devices[mdn] = device
+			delete(c.Devices, key)
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"github.com/reecetech/ebs-bootstrap/internal/service"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
@@ -15,6 +16,7 @@ func TestAwsNitroNVMeModifier(t *testing.T) {
 		Config                *Config
 		GetBlockDevices       func() ([]string, error)
 		GetBlockDeviceMapping func(name string) (string, error)
+		GetVolumeId           func(name string) (string, error)
 		ExpectedOutput        *Config
 		ExpectedError         error
 	}{
@@ -61,6 +63,185 @@ func TestAwsNitroNVMeModifier(t *testing.T) {
 			},
 			ExpectedError: nil,
 		},
+		{
+			Name: "Instance Store Devices as RAID Members (Nitro Instance)",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{
+							Level:   model.Raid0,
+							Devices: []string{"/dev/sdb", "/dev/sdc"},
+						},
+					},
+				},
+			},
+			GetBlockDevices: func() ([]string, error) {
+				return []string{"/dev/nvme1n1", "/dev/nvme2n1"}, nil
+			},
+			GetBlockDeviceMapping: func(name string) (string, error) {
+				switch name {
+				case "/dev/nvme1n1":
+					return "/dev/sdb", nil
+				default:
+					return "/dev/sdc", nil
+				}
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{
+							Level:   model.Raid0,
+							Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+						},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "NVMe Device as Physical Volume of Volume Group",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"/dev/sdb", "/dev/nvme2n1"}},
+				},
+			},
+			GetBlockDevices: func() ([]string, error) {
+				return []string{"/dev/nvme1n1"}, nil
+			},
+			GetBlockDeviceMapping: func(name string) (string, error) {
+				return "/dev/sdb", nil
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "EBS Devices Referenced by Volume Id (Nitro Instance)",
+			Config: &Config{
+				Devices: map[string]Device{
+					"vol-0123456789abcdef0": {Fs: model.Ext4},
+					"data": {
+						Fs:       model.Xfs,
+						Selector: &Selector{VolumeId: "vol-0fedcba9876543210"},
+					},
+					"scratch": {
+						Raid: &Raid{
+							Level:   model.Raid0,
+							Devices: []string{"vol-01234567", "/dev/sdd"},
+						},
+					},
+				},
+			},
+			GetBlockDevices: func() ([]string, error) {
+				return []string{"/dev/nvme1n1", "/dev/nvme2n1", "/dev/nvme3n1", "/dev/nvme4n1"}, nil
+			},
+			GetBlockDeviceMapping: func(name string) (string, error) {
+				switch name {
+				case "/dev/nvme1n1":
+					return "/dev/sdb", nil
+				case "/dev/nvme2n1":
+					return "/dev/sdc", nil
+				case "/dev/nvme3n1":
+					return "/dev/sde", nil
+				default: // Instance Store
+					return "/dev/sdd", nil
+				}
+			},
+			GetVolumeId: func(name string) (string, error) {
+				switch name {
+				case "/dev/nvme1n1":
+					return "vol-0123456789abcdef0", nil
+				case "/dev/nvme2n1":
+					return "vol-0fedcba9876543210", nil
+				case "/dev/nvme3n1":
+					return "vol-01234567", nil
+				default: // Instance Store
+					return "", nil
+				}
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Ext4},
+					"/dev/nvme2n1": {Fs: model.Xfs},
+					"scratch": {
+						Raid: &Raid{
+							Level:   model.Raid0,
+							Devices: []string{"/dev/nvme3n1", "/dev/nvme4n1"},
+						},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "EBS Volume Is Not Attached",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {
+						Selector: &Selector{VolumeId: "vol-0fedcba9876543210"},
+					},
+				},
+			},
+			GetBlockDevices: func() ([]string, error) {
+				return []string{"/dev/nvme1n1"}, nil
+			},
+			GetBlockDeviceMapping: func(name string) (string, error) {
+				return "/dev/sdb", nil
+			},
+			GetVolumeId: func(name string) (string, error) {
+				return "vol-0123456789abcdef0", nil
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"data": {
+						Selector: &Selector{VolumeId: "vol-0fedcba9876543210"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 data: Could not find a device for EBS volume vol-0fedcba9876543210"),
+		},
+		{
+			Name: "Block Device Mapping And Volume Id Reference The Same Device",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/sdb": {Fs: model.Ext4, MountPoint: "/mnt/app"},
+					"data": {
+						Fs:         model.Xfs,
+						MountPoint: "/mnt/data",
+						Selector:   &Selector{VolumeId: "vol-0123456789abcdef0"},
+					},
+				},
+			},
+			GetBlockDevices: func() ([]string, error) {
+				return []string{"/dev/nvme1n1"}, nil
+			},
+			GetBlockDeviceMapping: func(name string) (string, error) {
+				return "/dev/sdb", nil
+			},
+			GetVolumeId: func(name string) (string, error) {
+				return "vol-0123456789abcdef0", nil
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Ext4, MountPoint: "/mnt/app"},
+					"data": {
+						Fs:         model.Xfs,
+						MountPoint: "/mnt/data",
+						Selector:   &Selector{VolumeId: "vol-0123456789abcdef0"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 data: /dev/nvme1n1 is already a configured device"),
+		},
 		{
 			Name: "NVMe Device that is not AWS-managed",
 			Config: &Config{
@@ -110,6 +291,9 @@ func TestAwsNitroNVMeModifier(t *testing.T) {
 			if subtest.GetBlockDeviceMapping != nil {
 				ns.StubGetBlockDeviceMapping = subtest.GetBlockDeviceMapping
 			}
+			if subtest.GetVolumeId != nil {
+				ns.StubGetVolumeId = subtest.GetVolumeId
+			}
 
 			andm := NewAwsNVMeDriverModifier(ns, ds)
 			err := andm.Modify(subtest.Config)
@@ -118,3 +302,402 @@ func TestAwsNitroNVMeModifier(t *testing.T) {
 		})
 	}
 }
+
+func TestRaidModifier(t *testing.T) {
+	c := &Config{
+		Devices: map[string]Device{
+			"scratch": {
+				Fs: model.Xfs,
+				Raid: &Raid{
+					Level:   model.Raid0,
+					Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+				},
+			},
+			"/dev/sdd": {
+				Fs: model.Ext4,
+			},
+		},
+	}
+	err := NewRaidModifier().Modify(c)
+	utils.CheckError("rm.Modify()", t, nil, err)
+	utils.CheckOutput("rm.Modify()", t, &Config{
+		Devices: map[string]Device{
+			"/dev/md/scratch": {
+				Fs: model.Xfs,
+				Raid: &Raid{
+					Level:   model.Raid0,
+					Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+				},
+			},
+			"/dev/sdd": {
+				Fs: model.Ext4,
+			},
+		},
+	}, c, cmp.AllowUnexported(Config{}))
+}
+
+func TestRaidModifierPhysicalVolume(t *testing.T) {
+	c := &Config{
+		Devices: map[string]Device{
+			"scratch": {
+				Raid: &Raid{
+					Level:   model.Raid0,
+					Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+				},
+			},
+			"data": {
+				Fs:  model.Xfs,
+				Lvm: "db",
+			},
+		},
+		VolumeGroups: map[string]VolumeGroup{
+			"db": {Devices: []string{"scratch"}},
+		},
+	}
+	err := NewRaidModifier().Modify(c)
+	utils.CheckError("rm.Modify()", t, nil, err)
+	utils.CheckOutput("rm.Modify()", t, &Config{
+		Devices: map[string]Device{
+			"data": {
+				Fs:  model.Xfs,
+				Lvm: "db",
+			},
+		},
+		VolumeGroups: map[string]VolumeGroup{
+			"db": {Devices: []string{"/dev/md/scratch"}},
+		},
+	}, c, cmp.AllowUnexported(Config{}))
+}
+
+func TestLvmModifier(t *testing.T) {
+	c := &Config{
+		Devices: map[string]Device{
+			"/dev/xvdf": {
+				Fs:  model.Ext4,
+				Lvm: "ifmx-etc",
+			},
+			"data": {
+				Fs:  model.Xfs,
+				Lvm: "db",
+			},
+		},
+		VolumeGroups: map[string]VolumeGroup{
+			"db": {Devices: []string{"/dev/xvdg", "/dev/xvdh"}},
+		},
+	}
+	err := NewLvmModifier().Modify(c)
+	utils.CheckError("lm.Modify()", t, nil, err)
+	utils.CheckOutput("lm.Modify()", t, &Config{
+		Devices: map[string]Device{
+			"/dev/ifmx-etc/ifmx-etc": {
+				Fs: model.Ext4,
+			},
+			"/dev/db/data": {
+				Fs: model.Xfs,
+			},
+		},
+		VolumeGroups: map[string]VolumeGroup{
+			"db": {Devices: []string{"/dev/xvdg", "/dev/xvdh"}},
+		},
+	}, c, cmp.AllowUnexported(Config{}))
+}
+
+func TestEncryptionModifier(t *testing.T) {
+	c := &Config{
+		Devices: map[string]Device{
+			"/dev/nvme1n1": {
+				Fs:         model.Xfs,
+				Encryption: &Encryption{Name: "scratch", Ephemeral: true},
+			},
+			"/dev/sdd": {
+				Fs: model.Ext4,
+			},
+		},
+	}
+	err := NewEncryptionModifier().Modify(c)
+	utils.CheckError("em.Modify()", t, nil, err)
+	utils.CheckOutput("em.Modify()", t, &Config{
+		Devices: map[string]Device{
+			"/dev/mapper/scratch": {
+				Fs: model.Xfs,
+			},
+			"/dev/sdd": {
+				Fs: model.Ext4,
+			},
+		},
+	}, c, cmp.AllowUnexported(Config{}))
+}
+
+func TestMatchModifier(t *testing.T) {
+	index := func(i uint) *uint {
+		return &i
+	}
+	blockDevices := map[string]*model.BlockDevice{
+		"/dev/nvme0n1": {Name: "/dev/nvme0n1"},
+		"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.Ext4, Label: "stateful", UUID: "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a"},
+		"/dev/nvme2n1": {Name: "/dev/nvme2n1"},
+		"/dev/nvme3n1": {Name: "/dev/nvme3n1"},
+	}
+	sizes := map[string]uint64{
+		"/dev/nvme0n1": 8 << 30,
+		"/dev/nvme1n1": 100 << 30,
+		"/dev/nvme2n1": 900 << 30,
+		"/dev/nvme3n1": 900 << 30,
+	}
+	controllers := map[string]*model.NVMeController{
+		"/dev/nvme0n1": {Name: "/dev/nvme0n1", Model: service.AMZN_NVME_EBS_MN, Serial: "vol0aaaaaaaaaaaaaaaa"},
+		"/dev/nvme1n1": {Name: "/dev/nvme1n1", Model: service.AMZN_NVME_EBS_MN, Serial: "vol0123456789abcdef0"},
+		"/dev/nvme2n1": {Name: "/dev/nvme2n1", Model: service.AMZN_NVME_INS_MN, Serial: "AWS1111111111111111", VirtualName: "ephemeral0"},
+		"/dev/nvme3n1": {Name: "/dev/nvme3n1", Model: service.AMZN_NVME_INS_MN, Serial: "AWS2222222222222222", VirtualName: "ephemeral1"},
+	}
+	subtests := []struct {
+		Name           string
+		Config         *Config
+		ExpectedOutput *Config
+		ExpectedError  error
+	}{
+		{
+			Name: "Match By Each Criterion",
+			Config: &Config{
+				Devices: map[string]Device{
+					"root":     {Match: &Match{MaxSize: "8GiB"}},
+					"stateful": {Match: &Match{Label: "stateful"}},
+					"scratch":  {Match: &Match{Model: service.AMZN_NVME_INS_MN, InstanceStore: index(1)}},
+					"temp":     {Match: &Match{MinSize: "500GiB", Serial: "AWS1111111111111111"}},
+				},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme0n1": {},
+					"/dev/nvme1n1": {},
+					"/dev/nvme2n1": {},
+					"/dev/nvme3n1": {},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Match By File System UUID",
+			Config: &Config{
+				Devices: map[string]Device{
+					"stateful": {Fs: model.Ext4, Match: &Match{Uuid: "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a"}},
+				},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Ext4},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "No Device Satisfies Match",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {Match: &Match{InstanceStore: index(2)}},
+				},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"scratch": {Match: &Match{InstanceStore: index(2)}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: No device satisfies the match"),
+		},
+		{
+			Name: "More Than One Device Satisfies Match",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {Match: &Match{Model: service.AMZN_NVME_INS_MN}},
+				},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"scratch": {Match: &Match{Model: service.AMZN_NVME_INS_MN}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: More than one device satisfies the match (/dev/nvme2n1, /dev/nvme3n1)"),
+		},
+		{
+			Name: "Device Selected Twice",
+			Config: &Config{
+				Devices: map[string]Device{
+					"a": {Match: &Match{Label: "stateful"}},
+					"b": {Match: &Match{MinSize: "50GiB", MaxSize: "200GiB"}},
+				},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {},
+					"b":            {Match: &Match{MinSize: "50GiB", MaxSize: "200GiB"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 b: /dev/nvme1n1 is already selected by a"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ds := service.NewMockDeviceService()
+			ds.StubGetBlockDevices = func() ([]string, error) {
+				return []string{"/dev/nvme0n1", "/dev/nvme1n1", "/dev/nvme2n1", "/dev/nvme3n1"}, nil
+			}
+			ds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+				return blockDevices[name], nil
+			}
+			ds.StubGetSize = func(name string) (uint64, error) {
+				return sizes[name], nil
+			}
+			ns := service.NewMockNVMeService()
+			ns.StubGetController = func(name string) (*model.NVMeController, error) {
+				return controllers[name], nil
+			}
+
+			mm := NewMatchModifier(ns, ds)
+			err := mm.Modify(subtest.Config)
+			utils.CheckError("mm.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("mm.Modify()", t, subtest.ExpectedOutput, subtest.Config, cmp.AllowUnexported(Config{}))
+		})
+	}
+}
+
+func TestInstanceStoreModifier(t *testing.T) {
+	controllers := map[string]*model.NVMeController{
+		"/dev/nvme0n1": {Name: "/dev/nvme0n1", Model: service.AMZN_NVME_EBS_MN, Serial: "vol0aaaaaaaaaaaaaaaa"},
+		"/dev/nvme1n1": {Name: "/dev/nvme1n1", Model: service.AMZN_NVME_INS_MN, Serial: "AWS1111111111111111", VirtualName: "ephemeral0"},
+		"/dev/nvme2n1": {Name: "/dev/nvme2n1", Model: service.AMZN_NVME_INS_MN, Serial: "AWS2222222222222222", VirtualName: "ephemeral1"},
+	}
+	bdms := map[string]string{
+		"/dev/nvme0n1": "/dev/xvda",
+		"/dev/nvme1n1": "/dev/sdb",
+		"/dev/nvme2n1": "/dev/ephemeral1",
+	}
+	subtests := []struct {
+		Name           string
+		Config         *Config
+		ExpectedOutput *Config
+		ExpectedError  error
+	}{
+		{
+			Name: "Expand Placeholders",
+			Config: &Config{
+				InstanceStore: &Device{Fs: model.Xfs, MountPoint: "/mnt/scratch{index}", Label: "{bdm}-{name}"},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Xfs, MountPoint: "/mnt/scratch0", Label: "sdb-nvme1n1"},
+					"/dev/nvme2n1": {Fs: model.Xfs, MountPoint: "/mnt/scratch1", Label: "ephemeral1-nvme2n1"},
+				},
+				InstanceStore: &Device{Fs: model.Xfs, MountPoint: "/mnt/scratch{index}", Label: "{bdm}-{name}"},
+				expansions:    []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Explicitly Configured Device Takes Precedence",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/sdb": {Fs: model.Ext4, MountPoint: "/mnt/cache"},
+				},
+				InstanceStore: &Device{Fs: model.Xfs, MountPoint: "/mnt/scratch{index}"},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/sdb":     {Fs: model.Ext4, MountPoint: "/mnt/cache"},
+					"/dev/nvme2n1": {Fs: model.Xfs, MountPoint: "/mnt/scratch1"},
+				},
+				InstanceStore: &Device{Fs: model.Xfs, MountPoint: "/mnt/scratch{index}"},
+				expansions:    []string{"/dev/nvme2n1"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Without Template",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvda": {Fs: model.Ext4},
+				},
+			},
+			ExpectedOutput: &Config{
+				Devices: map[string]Device{
+					"/dev/xvda": {Fs: model.Ext4},
+				},
+			},
+			ExpectedError: nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ds := service.NewMockDeviceService()
+			ds.StubGetBlockDevices = func() ([]string, error) {
+				return []string{"/dev/nvme0n1", "/dev/nvme1n1", "/dev/nvme2n1"}, nil
+			}
+			ns := service.NewMockNVMeService()
+			ns.StubGetController = func(name string) (*model.NVMeController, error) {
+				return controllers[name], nil
+			}
+			ns.StubGetBlockDeviceMapping = func(name string) (string, error) {
+				return bdms[name], nil
+			}
+
+			ism := NewInstanceStoreModifier(ns, ds)
+			err := ism.Modify(subtest.Config)
+			utils.CheckError("ism.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ism.Modify()", t, subtest.ExpectedOutput, subtest.Config, cmp.AllowUnexported(Config{}))
+		})
+	}
+}
+
+func TestInstanceStoreModifierCopiesTemplate(t *testing.T) {
+	controllers := map[string]*model.NVMeController{
+		"/dev/nvme1n1": {Name: "/dev/nvme1n1", Model: service.AMZN_NVME_INS_MN, VirtualName: "ephemeral0"},
+		"/dev/nvme2n1": {Name: "/dev/nvme2n1", Model: service.AMZN_NVME_INS_MN, VirtualName: "ephemeral1"},
+	}
+	reserved := uint64(1)
+	maxMountCount := int64(-1)
+	priority := 10
+	c := &Config{
+		InstanceStore: &Device{
+			Fs:            model.Xfs,
+			MountPoint:    "/mnt/scratch{index}",
+			RequiredBy:    []string{"app.service"},
+			Encryption:    &Encryption{Name: "scratch", Ephemeral: true},
+			Raid:          &Raid{Level: model.Raid0, Devices: []string{"/dev/nvme1n1"}},
+			FormatOptions: &FormatOptions{ReservedBlocksPercent: &reserved},
+			Tune:          &Tune{MaxMountCount: &maxMountCount, Features: []string{"extent"}},
+			Swap:          &Swap{Priority: &priority},
+		},
+	}
+	ds := service.NewMockDeviceService()
+	ds.StubGetBlockDevices = func() ([]string, error) {
+		return []string{"/dev/nvme1n1", "/dev/nvme2n1"}, nil
+	}
+	ns := service.NewMockNVMeService()
+	ns.StubGetController = func(name string) (*model.NVMeController, error) {
+		return controllers[name], nil
+	}
+	ns.StubGetBlockDeviceMapping = func(name string) (string, error) {
+		return name, nil
+	}
+	ism := NewInstanceStoreModifier(ns, ds)
+	utils.CheckError("ism.Modify()", t, nil, ism.Modify(c))
+
+	// Modifying one expansion must leave the template and every other expansion intact
+	first := c.Devices["/dev/nvme1n1"]
+	first.RequiredBy[0] = "db.service"
+	first.Encryption.Name = "cache"
+	first.Raid.Devices[0] = "/dev/nvme3n1"
+	*first.FormatOptions.ReservedBlocksPercent = 5
+	*first.Tune.MaxMountCount = 20
+	first.Tune.Features[0] = "metadata_csum"
+	*first.Swap.Priority = 1
+
+	for _, d := range []Device{*c.InstanceStore, c.Devices["/dev/nvme2n1"]} {
+		utils.CheckOutput("d.RequiredBy", t, []string{"app.service"}, d.RequiredBy)
+		utils.CheckOutput("d.Encryption", t, &Encryption{Name: "scratch", Ephemeral: true}, d.Encryption)
+		utils.CheckOutput("d.Raid", t, &Raid{Level: model.Raid0, Devices: []string{"/dev/nvme1n1"}}, d.Raid)
+		utils.CheckOutput("d.FormatOptions.ReservedBlocksPercent", t, uint64(1), *d.FormatOptions.ReservedBlocksPercent)
+		utils.CheckOutput("d.Tune.MaxMountCount", t, int64(-1), *d.Tune.MaxMountCount)
+		utils.CheckOutput("d.Tune.Features", t, []string{"extent"}, d.Tune.Features)
+		utils.CheckOutput("d.Swap.Priority", t, 10, *d.Swap.Priority)
+	}
+}
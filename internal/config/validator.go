@@ -1,8 +1,10 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/reecetech/ebs-bootstrap/internal/model"
@@ -49,12 +51,25 @@ func (fsv *FileSystemValidator) Validate(c *Config) error {
 		if err != nil {
 			return fmt.Errorf("🔴 %s: %s", name, err)
 		}
+		if fs == model.Unknown {
+			return fmt.Errorf("🔴 %s: Must provide a supported file system", name)
+		}
 		if fs == model.Unformatted {
+			// A RAID array that is a physical volume of a volume group is not formatted
+			if device.Raid != nil && c.isPhysicalVolume(name) {
+				continue
+			}
 			return fmt.Errorf("🔴 %s: Must provide a supported file system", name)
 		}
 		if fs == model.Lvm {
 			return fmt.Errorf("🔴 %s: Refer to %s on how to manage LVM file systems", name, LvmWikiDocumentationUrl)
 		}
+		if fs == model.RaidMember {
+			return fmt.Errorf("🔴 %s: Use the raid attribute to manage RAID arrays", name)
+		}
+		if fs == model.Luks {
+			return fmt.Errorf("🔴 %s: Use the encryption attribute to manage LUKS devices", name)
+		}
 	}
 	return nil
 }
@@ -85,6 +100,14 @@ func (fsv *ModeValidator) Validate(c *Config) error {
 			return fmt.Errorf("🔴 %s: '%s' is not a supported mode", name, mode)
 		}
 	}
+
+	for name, sf := range c.SwapFiles {
+		mode := string(sf.Mode)
+		_, err := model.ParseMode(mode)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: '%s' is not a supported mode", name, mode)
+		}
+	}
 	return nil
 }
 
@@ -194,3 +217,656 @@ func (lcv *LvmConsumptionValidator) Validate(c *Config) error {
 func (lcv *LvmConsumptionValidator) isValid(lc uint64) bool {
 	return lc <= 100
 }
+
+type FstabValidator struct{}
+
+func NewFstabValidator() *FstabValidator {
+	return &FstabValidator{}
+}
+
+func (fv *FstabValidator) Validate(c *Config) error {
+	if !fv.isValid(c.Defaults.FsckPass) {
+		return fmt.Errorf("🔴 '%d' (defaults) must be a fsck pass number between 0 and 2 (inclusive)", *c.Defaults.FsckPass)
+	}
+	for name, device := range c.Devices {
+		if !fv.isValid(device.FsckPass) {
+			return fmt.Errorf("🔴 %s: '%d' must be a fsck pass number between 0 and 2 (inclusive)", name, *device.FsckPass)
+		}
+		if device.Fstab && len(device.MountPoint) == 0 {
+			return fmt.Errorf("🔴 %s: Must provide a mount point to manage an /etc/fstab entry", name)
+		}
+	}
+	return nil
+}
+
+func (fv *FstabValidator) isValid(pass *uint) bool {
+	return pass == nil || *pass <= 2
+}
+
+type SystemdMountValidator struct{}
+
+func NewSystemdMountValidator() *SystemdMountValidator {
+	return &SystemdMountValidator{}
+}
+
+// A mount unit supersedes the /etc/fstab entry of a mount point. When both exist,
+// the systemd-fstab-generator produces a conflicting unit of the same name
+func (smv *SystemdMountValidator) Validate(c *Config) error {
+	for name, device := range c.Devices {
+		systemdMount := c.GetSystemdMount(name)
+		if systemdMount && len(device.MountPoint) == 0 {
+			return fmt.Errorf("🔴 %s: Must provide a mount point to manage a systemd mount unit", name)
+		}
+		if systemdMount && c.GetFstab(name) {
+			return fmt.Errorf("🔴 %s: Can not manage both an /etc/fstab entry and a systemd mount unit", name)
+		}
+		if !systemdMount && len(device.RequiredBy) > 0 {
+			return fmt.Errorf("🔴 %s: Must enable systemdMount to declare units that require the mount", name)
+		}
+		for _, unit := range device.RequiredBy {
+			if !smv.isValid(unit) {
+				return fmt.Errorf("🔴 %s: '%s' is not a valid systemd unit name", name, unit)
+			}
+		}
+	}
+	return nil
+}
+
+func (smv *SystemdMountValidator) isValid(unit string) bool {
+	if strings.ContainsAny(unit, "/ \t\n") {
+		return false
+	}
+	dot := strings.LastIndex(unit, ".")
+	return dot > 0 && dot < len(unit)-1
+}
+
+type RaidValidator struct{}
+
+func NewRaidValidator() *RaidValidator {
+	return &RaidValidator{}
+}
+
+func (rv *RaidValidator) Validate(c *Config) error {
+	members := map[string]string{}
+	for name, device := range c.Devices {
+		if device.Raid == nil {
+			continue
+		}
+		if strings.Contains(name, "/") {
+			return fmt.Errorf("🔴 %s: The name of a RAID array must not contain '/'", name)
+		}
+		level, err := model.ParseRaidLevel(string(device.Raid.Level))
+		if err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+		if device.Raid.ChunkSize > 0 && level != model.Raid0 {
+			return fmt.Errorf("🔴 %s: A chunk size can only be provided for a %s array", name, model.Raid0)
+		}
+		if len(device.Raid.Devices) < 2 {
+			return fmt.Errorf("🔴 %s: Must provide at least two member devices for a RAID array", name)
+		}
+		for _, member := range device.Raid.Devices {
+			if _, exists := c.Devices[member]; exists {
+				return fmt.Errorf("🔴 %s: %s can not be both a RAID member and a configured device", name, member)
+			}
+			if array, exists := members[member]; exists {
+				return fmt.Errorf("🔴 %s: %s is already a member of RAID array %s", name, member, array)
+			}
+			members[member] = name
+		}
+	}
+	return nil
+}
+
+type VolumeGroupValidator struct{}
+
+func NewVolumeGroupValidator() *VolumeGroupValidator {
+	return &VolumeGroupValidator{}
+}
+
+func (vgv *VolumeGroupValidator) Validate(c *Config) error {
+	members := map[string]string{}
+	for name, vg := range c.VolumeGroups {
+		if strings.Contains(name, "/") {
+			return fmt.Errorf("🔴 %s: The name of a volume group must not contain '/'", name)
+		}
+		if len(vg.Devices) == 0 {
+			return fmt.Errorf("🔴 %s: Must provide at least one device for a volume group", name)
+		}
+		for _, member := range vg.Devices {
+			// A RAID array is referenced by its name and can be a member of a volume group
+			if d, exists := c.Devices[member]; exists && d.Raid == nil {
+				return fmt.Errorf("🔴 %s: %s can not be both a physical volume and a configured device", name, member)
+			}
+			if d, exists := c.Devices[member]; exists && len(d.Fs) > 0 {
+				return fmt.Errorf("🔴 %s: RAID array %s can not have a file system as it is a physical volume", name, member)
+			}
+			if other, exists := members[member]; exists {
+				return fmt.Errorf("🔴 %s: %s is already a physical volume of volume group %s", name, member, other)
+			}
+			members[member] = name
+		}
+	}
+	references := map[string]bool{}
+	for name, device := range c.Devices {
+		if _, declared := c.VolumeGroups[device.Lvm]; !declared {
+			continue
+		}
+		if strings.Contains(name, "/") {
+			return fmt.Errorf("🔴 %s: The name of a logical volume must not contain '/'", name)
+		}
+		references[device.Lvm] = true
+	}
+	for name := range c.VolumeGroups {
+		if _, exists := references[name]; !exists {
+			return fmt.Errorf("🔴 %s: Volume group is not used by any device", name)
+		}
+	}
+	return nil
+}
+
+type LogicalVolumeSizeValidator struct{}
+
+func NewLogicalVolumeSizeValidator() *LogicalVolumeSizeValidator {
+	return &LogicalVolumeSizeValidator{}
+}
+
+func (lvsv *LogicalVolumeSizeValidator) Validate(c *Config) error {
+	// The sizes of the logical volumes of a volume group are validated together. A
+	// logical volume that does not fit would otherwise only fail to be created once
+	// the logical volumes before it were created
+	consumption := map[string]uint64{}
+	free := map[string][]string{}
+	absolute := map[string][]string{}
+	vgs := []string{}
+	for _, name := range c.GetDevices() {
+		device := c.Devices[name]
+		if len(device.Size) > 0 && len(device.Lvm) == 0 {
+			return fmt.Errorf("🔴 %s: A size can only be provided for a device with lvm", name)
+		}
+		if len(device.Lvm) == 0 {
+			continue
+		}
+		size, err := c.GetLogicalVolumeSize(name)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(vgs, device.Lvm) {
+			vgs = append(vgs, device.Lvm)
+		}
+		switch size.Unit {
+		case model.VolumeGroupPercent:
+			consumption[device.Lvm] += size.Value
+		case model.FreePercent:
+			free[device.Lvm] = append(free[device.Lvm], name)
+		default:
+			absolute[device.Lvm] = append(absolute[device.Lvm], name)
+		}
+	}
+	slices.Sort(vgs)
+	for _, vg := range vgs {
+		percent := consumption[vg]
+		if percent > 100 {
+			return fmt.Errorf("🔴 %s: The logical volumes of the volume group consume %d%% of it, which exceeds 100%%", vg, percent)
+		}
+		// The free space of a volume group is only known once the logical volumes
+		// before it are created. A second %FREE logical volume would receive a
+		// percentage of what remains, rather than of the original free space
+		if len(free[vg]) > 1 {
+			return fmt.Errorf("🔴 %s: Only one logical volume of the volume group can consume its free space. Consumed by %s", vg, strings.Join(free[vg], ", "))
+		}
+		if remaining := append(absolute[vg], free[vg]...); percent == 100 && len(remaining) > 0 {
+			return fmt.Errorf("🔴 %s: The logical volumes of the volume group consume 100%% of it, which leaves no space for %s", vg, strings.Join(remaining, ", "))
+		}
+	}
+	return nil
+}
+
+type EncryptionValidator struct{}
+
+func NewEncryptionValidator() *EncryptionValidator {
+	return &EncryptionValidator{}
+}
+
+func (ev *EncryptionValidator) Validate(c *Config) error {
+	mappings := map[string]string{}
+	for name, device := range c.Devices {
+		e := device.Encryption
+		if e == nil {
+			continue
+		}
+		if len(e.Name) == 0 {
+			return fmt.Errorf("🔴 %s: Must provide a name for an encrypted device", name)
+		}
+		if strings.Contains(e.Name, "/") {
+			return fmt.Errorf("🔴 %s: The name of an encrypted device must not contain '/'", name)
+		}
+		if other, exists := mappings[e.Name]; exists {
+			return fmt.Errorf("🔴 %s: The name %s is already used by encrypted device %s", name, e.Name, other)
+		}
+		mappings[e.Name] = name
+		if e.Ephemeral && len(e.KeyFile) > 0 {
+			return fmt.Errorf("🔴 %s: Can not provide a key file for an encrypted device with an ephemeral key", name)
+		}
+		if !e.Ephemeral && len(e.KeyFile) == 0 {
+			return fmt.Errorf("🔴 %s: Must provide either a key file or an ephemeral key for an encrypted device", name)
+		}
+		if len(e.KeyFile) > 0 && !path.IsAbs(e.KeyFile) {
+			return fmt.Errorf("🔴 %s: %s is not an absolute path", name, e.KeyFile)
+		}
+		if device.Raid != nil && c.isPhysicalVolume(name) {
+			return fmt.Errorf("🔴 %s: A RAID array that is a physical volume can not be encrypted", name)
+		}
+	}
+	return nil
+}
+
+type ReformatValidator struct{}
+
+func NewReformatValidator() *ReformatValidator {
+	return &ReformatValidator{}
+}
+
+// Only a device that is itself an instance store volume can be reformatted. A
+// RAID array, logical volume or encrypted device is never an instance store
+// volume, even when it is built upon one. The exception is an encrypted device
+// with an ephemeral key, which can be formatted again once its key is lost
+func (rv *ReformatValidator) Validate(c *Config) error {
+	for name, device := range c.Devices {
+		if len(device.Reformat) == 0 {
+			continue
+		}
+		rp, err := model.ParseReformatPolicy(string(device.Reformat))
+		if err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+		if rp != model.ReformatOnMismatch {
+			continue
+		}
+		if device.Encryption != nil && device.Encryption.Ephemeral {
+			continue
+		}
+		if device.Raid != nil || device.Encryption != nil || len(device.Lvm) > 0 {
+			return fmt.Errorf("🔴 %s: A reformat policy of %s can only be applied to an instance store volume", name, rp)
+		}
+	}
+	return nil
+}
+
+type FormatOptionsValidator struct {
+	fileSystemServiceFactory service.FileSystemServiceFactory
+}
+
+func NewFormatOptionsValidator(fssf service.FileSystemServiceFactory) *FormatOptionsValidator {
+	return &FormatOptionsValidator{
+		fileSystemServiceFactory: fssf,
+	}
+}
+
+// The format options of a device are validated by the file system that it is to
+// be formatted to, as each file system supports a different set of options
+func (fov *FormatOptionsValidator) Validate(c *Config) error {
+	for name, device := range c.Devices {
+		if device.FormatOptions == nil {
+			continue
+		}
+		fss, err := fov.fileSystemServiceFactory.Select(device.Fs)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+		if err := fss.ValidateFormatOptions(c.GetFormatOptions(name)); err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+type TuneValidator struct {
+	fileSystemServiceFactory service.FileSystemServiceFactory
+}
+
+func NewTuneValidator(fssf service.FileSystemServiceFactory) *TuneValidator {
+	return &TuneValidator{
+		fileSystemServiceFactory: fssf,
+	}
+}
+
+func (tv *TuneValidator) Validate(c *Config) error {
+	for name, device := range c.Devices {
+		if device.Tune == nil {
+			continue
+		}
+		fss, err := tv.fileSystemServiceFactory.Select(device.Fs)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+		if err := fss.ValidateProperties(c.GetTuneProperties(name)); err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+	}
+	return nil
+}
+
+type UuidValidator struct{}
+
+func NewUuidValidator() *UuidValidator {
+	return &UuidValidator{}
+}
+
+// A fixed UUID can only be assigned to a single device. Otherwise, the devices
+// would share a UUID, which is precisely what the UUID of a device is meant to avoid
+func (uv *UuidValidator) Validate(c *Config) error {
+	uuids := map[string]string{}
+	for _, name := range c.GetDevices() {
+		device := c.Devices[name]
+		if len(device.Uuid) == 0 {
+			continue
+		}
+		if device.Uuid != model.RandomUuid && !model.IsUuid(device.Uuid) {
+			return fmt.Errorf("🔴 %s: '%s' is not a valid UUID. Expected either %s or a UUID", name, device.Uuid, model.RandomUuid)
+		}
+		if device.Fs != model.Ext4 && device.Fs != model.Xfs {
+			return fmt.Errorf("🔴 %s: The UUID of the %s file system can not be changed", name, device.Fs.String())
+		}
+		if device.Uuid == model.RandomUuid {
+			continue
+		}
+		uuid := strings.ToLower(device.Uuid)
+		if other, found := uuids[uuid]; found {
+			return fmt.Errorf("🔴 %s: UUID %s is already assigned to %s", name, device.Uuid, other)
+		}
+		uuids[uuid] = name
+	}
+	return nil
+}
+
+type FsckValidator struct{}
+
+func NewFsckValidator() *FsckValidator {
+	return &FsckValidator{}
+}
+
+func (fv *FsckValidator) Validate(c *Config) error {
+	for name, device := range c.Devices {
+		if len(device.Fsck) == 0 {
+			continue
+		}
+		fm, err := model.ParseFsckMode(string(device.Fsck))
+		if err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+		if device.Fs == model.Unformatted {
+			return fmt.Errorf("🔴 %s: An unformatted file system can not be checked", name)
+		}
+		if device.Fs == model.Swap {
+			return fmt.Errorf("🔴 %s: A swap area can not be checked", name)
+		}
+		if fm == model.FsckRepair && device.Fs == model.Btrfs {
+			return fmt.Errorf("🔴 %s: The %s file system can not be repaired automatically. Set fsck to %s", name, device.Fs.String(), model.FsckCheck)
+		}
+	}
+	return nil
+}
+
+type SwapValidator struct{}
+
+func NewSwapValidator() *SwapValidator {
+	return &SwapValidator{}
+}
+
+// A swap area is activated rather than mounted. Therefore, a device with a swap
+// file system can not have a mount point. A swap file is created upon a file
+// system that is mounted by another device, or by the host
+func (sv *SwapValidator) Validate(c *Config) error {
+	for _, name := range c.GetDevices() {
+		device := c.Devices[name]
+		if device.Fs != model.Swap {
+			if device.Swap != nil {
+				return fmt.Errorf("🔴 %s: Swap options can only be applied to a device with a swap file system", name)
+			}
+			continue
+		}
+		if len(device.MountPoint) > 0 {
+			return fmt.Errorf("🔴 %s: A swap area can not be mounted", name)
+		}
+		if !sv.isValid(c.GetSwapPriority(name)) {
+			return fmt.Errorf("🔴 %s: Swap priority must be between %d and %d", name, model.MinimumSwapPriority, model.MaximumSwapPriority)
+		}
+	}
+	for _, name := range c.GetSwapFiles() {
+		if !path.IsAbs(name) {
+			return fmt.Errorf("🔴 %s: Swap file is not an absolute path", name)
+		}
+		if _, exists := c.Devices[name]; exists {
+			return fmt.Errorf("🔴 %s: Swap file is already a configured device", name)
+		}
+		if len(c.SwapFiles[name].Size) == 0 {
+			return fmt.Errorf("🔴 %s: Must provide the size of a swap file", name)
+		}
+		if _, err := model.ParseByteSize(c.SwapFiles[name].Size); err != nil {
+			return fmt.Errorf("🔴 %s: %s", name, err)
+		}
+		if !sv.isValid(c.GetSwapPriority(name)) {
+			return fmt.Errorf("🔴 %s: Swap priority must be between %d and %d", name, model.MinimumSwapPriority, model.MaximumSwapPriority)
+		}
+	}
+	return nil
+}
+
+func (sv *SwapValidator) isValid(priority *int) bool {
+	return priority == nil || (*priority >= model.MinimumSwapPriority && *priority <= model.MaximumSwapPriority)
+}
+
+type SelectorValidator struct{}
+
+func NewSelectorValidator() *SelectorValidator {
+	return &SelectorValidator{}
+}
+
+func (sv *SelectorValidator) Validate(c *Config) error {
+	volumes := map[string]bool{}
+	for name, device := range c.Devices {
+		if strings.HasPrefix(name, "vol-") && !model.IsVolumeId(name) {
+			return fmt.Errorf("🔴 %s: Not a valid EBS volume id", name)
+		}
+		if device.Selector != nil {
+			if model.IsVolumeId(name) {
+				return fmt.Errorf("🔴 %s: Can not provide a selector for a device that is referenced by its volume id", name)
+			}
+			if !model.IsVolumeId(device.Selector.VolumeId) {
+				return fmt.Errorf("🔴 %s: '%s' is not a valid EBS volume id", name, device.Selector.VolumeId)
+			}
+		}
+		id := c.GetVolumeId(name)
+		if len(id) == 0 {
+			continue
+		}
+		if volumes[id] {
+			return fmt.Errorf("🔴 %s: EBS volume is selected by more than one device", id)
+		}
+		volumes[id] = true
+	}
+	return nil
+}
+
+type MatchValidator struct{}
+
+func NewMatchValidator() *MatchValidator {
+	return &MatchValidator{}
+}
+
+func (mv *MatchValidator) Validate(c *Config) error {
+	for name, device := range c.Devices {
+		m := device.Match
+		if m == nil {
+			continue
+		}
+		if device.Selector != nil || model.IsVolumeId(name) {
+			return fmt.Errorf("🔴 %s: Can not select a device by both a match and its volume id", name)
+		}
+		if device.Raid != nil {
+			return fmt.Errorf("🔴 %s: A RAID array can not be selected by a match", name)
+		}
+		if _, declared := c.VolumeGroups[device.Lvm]; declared {
+			return fmt.Errorf("🔴 %s: A logical volume can not be selected by a match", name)
+		}
+		if *m == (Match{}) {
+			return fmt.Errorf("🔴 %s: Must provide at least one criterion for a match", name)
+		}
+		sizes := []uint64{0, 0}
+		for i, size := range []string{m.MinSize, m.MaxSize} {
+			if len(size) == 0 {
+				continue
+			}
+			b, err := model.ParseByteSize(size)
+			if err != nil {
+				return fmt.Errorf("🔴 %s: %s", name, err)
+			}
+			sizes[i] = b
+		}
+		if sizes[1] > 0 && sizes[0] > sizes[1] {
+			return fmt.Errorf("🔴 %s: The minimum size of a match can not exceed its maximum size", name)
+		}
+	}
+	return nil
+}
+
+type InstanceStoreValidator struct{}
+
+func NewInstanceStoreValidator() *InstanceStoreValidator {
+	return &InstanceStoreValidator{}
+}
+
+// Validate ensures that the devices expanded from the instanceStore template can
+// coexist. A mount point or label without a placeholder is shared by every instance
+// store volume, which collides as soon as more than one volume is attached
+func (isv *InstanceStoreValidator) Validate(c *Config) error {
+	t := c.InstanceStore
+	if t == nil {
+		return nil
+	}
+	if t.Raid != nil || len(t.Lvm) > 0 || t.Encryption != nil || t.Selector != nil || t.Match != nil {
+		return fmt.Errorf("🔴 instanceStore: A template can only configure the file system of each instance store volume")
+	}
+	// Compare against the devices in a stable order, so that any error is deterministic
+	names := make([]string, 0, len(c.Devices))
+	for name := range c.Devices {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range c.expansions {
+		expansion := c.Devices[name]
+		for _, other := range names {
+			if other == name {
+				continue
+			}
+			device := c.Devices[other]
+			if len(expansion.MountPoint) > 0 && expansion.MountPoint == device.MountPoint {
+				return fmt.Errorf("🔴 %s: Expansion of instanceStore collides with %s at mount point %s", name, other, device.MountPoint)
+			}
+			if len(expansion.Label) > 0 && expansion.Label == device.Label {
+				return fmt.Errorf("🔴 %s: Expansion of instanceStore collides with %s at label %s", name, other, device.Label)
+			}
+		}
+	}
+	return nil
+}
+
+type ConsistencyValidator struct{}
+
+func NewConsistencyValidator() *ConsistencyValidator {
+	return &ConsistencyValidator{}
+}
+
+// Validate compares the attributes of every pair of devices. A conflict between two
+// devices would otherwise only surface once the configuration is partially applied.
+// Every conflict is reported, rather than only the first
+func (cv *ConsistencyValidator) Validate(c *Config) error {
+	// Compare the devices in a stable order, so that the conflicts are deterministic
+	names := make([]string, 0, len(c.Devices))
+	for name := range c.Devices {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	errs := []error{}
+	mountPoints := map[string]string{}
+	labels := map[string]string{}
+	volumeGroups := map[string]string{}
+	for _, name := range names {
+		device := c.Devices[name]
+		if len(device.MountPoint) > 0 {
+			mp := path.Clean(device.MountPoint)
+			if other, exists := mountPoints[mp]; exists {
+				errs = append(errs, fmt.Errorf("🔴 %s: Mount point %s is already used by %s", name, mp, other))
+			} else {
+				mountPoints[mp] = name
+			}
+		}
+		if len(device.Label) > 0 {
+			if other, exists := labels[device.Label]; exists {
+				errs = append(errs, fmt.Errorf("🔴 %s: Label %s is already used by %s", name, device.Label, other))
+			} else {
+				labels[device.Label] = name
+			}
+		}
+		// The volume group of a device that does not reference a declared volume
+		// group is created from that device alone, so it can not be shared
+		if _, declared := c.VolumeGroups[device.Lvm]; len(device.Lvm) > 0 && !declared {
+			if other, exists := volumeGroups[device.Lvm]; exists {
+				errs = append(errs, fmt.Errorf("🔴 %s: Volume group %s is already used by %s", name, device.Lvm, other))
+			} else {
+				volumeGroups[device.Lvm] = name
+			}
+		}
+	}
+	errs = append(errs, cv.validateLvm(c, names)...)
+	return errors.Join(errs...)
+}
+
+// A device that is consumed by LVM can not also be used directly. This includes a
+// device that is both a physical volume and a member of another device, as well
+// as a device that is configured by the path of a logical volume
+func (cv *ConsistencyValidator) validateLvm(c *Config, names []string) []error {
+	errs := []error{}
+	members := map[string]string{}
+	addMember := func(member string, owner string) {
+		if other, exists := members[member]; exists {
+			errs = append(errs, fmt.Errorf("🔴 %s: Can not be a member of %s as it is already a member of %s", member, owner, other))
+			return
+		}
+		members[member] = owner
+	}
+	for _, name := range names {
+		if raid := c.Devices[name].Raid; raid != nil {
+			for _, member := range raid.Devices {
+				addMember(member, "RAID array "+name)
+			}
+		}
+	}
+	vgs := make([]string, 0, len(c.VolumeGroups))
+	for name := range c.VolumeGroups {
+		vgs = append(vgs, name)
+	}
+	slices.Sort(vgs)
+	for _, name := range vgs {
+		for _, member := range c.VolumeGroups[name].Devices {
+			addMember(member, "volume group "+name)
+		}
+	}
+	for _, name := range names {
+		device := c.Devices[name]
+		if len(device.Lvm) == 0 {
+			continue
+		}
+		if _, declared := c.VolumeGroups[device.Lvm]; !declared {
+			if other, exists := members[name]; exists {
+				errs = append(errs, fmt.Errorf("🔴 %s: Can not be a physical volume of volume group %s as it is a member of %s", name, device.Lvm, other))
+			}
+		}
+		ldn := fmt.Sprintf("/dev/%s/%s", device.Lvm, c.GetLogicalVolume(name))
+		if _, exists := c.Devices[ldn]; exists {
+			errs = append(errs, fmt.Errorf("🔴 %s: Logical volume %s is already a configured device", name, ldn))
+		}
+	}
+	return errs
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -198,6 +199,17 @@ func TestFileSystemValidator(t *testing.T) {
 			},
 			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: File system 'jfs' is not supported"),
 		},
+		{
+			Name: "Unknown File System",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs: model.Unknown,
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Must provide a supported file system"),
+		},
 		{
 			Name: "No File System Provided",
 			Config: &Config{
@@ -218,6 +230,35 @@ func TestFileSystemValidator(t *testing.T) {
 			},
 			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Refer to %s on how to manage LVM file systems", LvmWikiDocumentationUrl),
 		},
+		{
+			Name: "RAID Array as Physical Volume",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/xvdf", "/dev/xvdg"}},
+					},
+					"data": {
+						Fs:  model.Xfs,
+						Lvm: "db",
+					},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"scratch"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "RAID Member File System",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs: model.RaidMember,
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Use the raid attribute to manage RAID arrays"),
+		},
 	}
 	for _, subtest := range subtests {
 		fsv := NewFileSystemValidator()
@@ -307,6 +348,18 @@ func TestModeValidator(t *testing.T) {
 			},
 			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: '%s' is not a supported mode", Invalid),
 		},
+		{
+			Name: "Invalid Mode (Swap File)",
+			Config: &Config{
+				SwapFiles: map[string]SwapFile{
+					"/mnt/scratch/swapfile": {
+						Size: "1GiB",
+						Mode: Invalid,
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: '%s' is not a supported mode", Invalid),
+		},
 	}
 	for _, subtest := range subtests {
 		mv := NewModeValidator()
@@ -406,3 +459,1280 @@ func TestOwnerValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestFstabValidator(t *testing.T) {
+	valid := uint(2)
+	invalid := uint(3)
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Fstab Configuration",
+			Config: &Config{
+				Defaults: Options{
+					FsckPass: &valid,
+				},
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/app",
+						Options: Options{
+							Fstab: true,
+						},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Invalid Fsck Pass (Defaults)",
+			Config: &Config{
+				Defaults: Options{
+					FsckPass: &invalid,
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 '3' (defaults) must be a fsck pass number between 0 and 2 (inclusive)"),
+		},
+		{
+			Name: "Invalid Fsck Pass (Device)",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Options: Options{
+							FsckPass: &invalid,
+						},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: '3' must be a fsck pass number between 0 and 2 (inclusive)"),
+		},
+		{
+			Name: "Fstab Without Mount Point",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Options: Options{
+							Fstab: true,
+						},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Must provide a mount point to manage an /etc/fstab entry"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			fv := NewFstabValidator()
+			err := fv.Validate(subtest.Config)
+			utils.CheckError("fv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestSystemdMountValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Systemd Mount Configuration",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						MountPoint: "/var/lib/postgresql",
+						RequiredBy: []string{"postgresql.service"},
+						Options: Options{
+							SystemdMount: true,
+						},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Systemd Mount Without Mount Point",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Options: Options{
+							SystemdMount: true,
+						},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Must provide a mount point to manage a systemd mount unit"),
+		},
+		{
+			Name: "Systemd Mount and Fstab Entry",
+			Config: &Config{
+				Defaults: Options{
+					Fstab: true,
+				},
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/app",
+						Options: Options{
+							SystemdMount: true,
+						},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Can not manage both an /etc/fstab entry and a systemd mount unit"),
+		},
+		{
+			Name: "Required By Without Systemd Mount",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/app",
+						RequiredBy: []string{"app.service"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Must enable systemdMount to declare units that require the mount"),
+		},
+		{
+			Name: "Invalid Unit Name",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/app",
+						RequiredBy: []string{"app"},
+						Options: Options{
+							SystemdMount: true,
+						},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: 'app' is not a valid systemd unit name"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			smv := NewSystemdMountValidator()
+			err := smv.Validate(subtest.Config)
+			utils.CheckError("smv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestRaidValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid RAID Configuration",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{Level: model.Raid0, ChunkSize: 256, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+					},
+					"mirror": {
+						Raid: &Raid{Level: model.Raid1, Devices: []string{"/dev/sdd", "/dev/sde"}},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Name Contains Slash",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/md/scratch": {
+						Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/md/scratch: The name of a RAID array must not contain '/'"),
+		},
+		{
+			Name: "Unsupported Level",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{Level: "raid5", Devices: []string{"/dev/sdb", "/dev/sdc", "/dev/sdd"}},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: RAID level 'raid5' is not supported"),
+		},
+		{
+			Name: "Chunk Size For Mirror",
+			Config: &Config{
+				Devices: map[string]Device{
+					"mirror": {
+						Raid: &Raid{Level: model.Raid1, ChunkSize: 256, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 mirror: A chunk size can only be provided for a raid0 array"),
+		},
+		{
+			Name: "Single Member",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb"}},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: Must provide at least two member devices for a RAID array"),
+		},
+		{
+			Name: "Member Is Configured Device",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+					},
+					"/dev/sdb": {},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: /dev/sdb can not be both a RAID member and a configured device"),
+		},
+		{
+			Name: "Duplicate Member",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb", "/dev/sdb"}},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: /dev/sdb is already a member of RAID array scratch"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			rv := NewRaidValidator()
+			err := rv.Validate(subtest.Config)
+			utils.CheckError("rv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestVolumeGroupValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Volume Group",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db", Fs: model.Ext4},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"/dev/sdb", "/dev/sdc"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "RAID Array As Physical Volume",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+					},
+					"data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"scratch"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Formatted RAID Array As Physical Volume",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Fs:   model.Xfs,
+						Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+					},
+					"data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"scratch"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: RAID array scratch can not have a file system as it is a physical volume"),
+		},
+		{
+			Name: "Volume Group Without Devices",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: Must provide at least one device for a volume group"),
+		},
+		{
+			Name: "Physical Volume Is Configured Device",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data":     {Lvm: "db"},
+					"/dev/sdb": {},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"/dev/sdb"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: /dev/sdb can not be both a physical volume and a configured device"),
+		},
+		{
+			Name: "Physical Volume Shared By Volume Groups",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"/dev/sdb", "/dev/sdb"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: /dev/sdb is already a physical volume of volume group db"),
+		},
+		{
+			Name: "Logical Volume Name With Slash",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/db/data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"/dev/sdb"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/db/data: The name of a logical volume must not contain '/'"),
+		},
+		{
+			Name: "Unused Volume Group",
+			Config: &Config{
+				Devices: map[string]Device{},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"/dev/sdb"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: Volume group is not used by any device"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			vgv := NewVolumeGroupValidator()
+			err := vgv.Validate(subtest.Config)
+			utils.CheckError("vgv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestLogicalVolumeSizeValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Sizes",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data":  {Lvm: "db", Size: "70%VG"},
+					"logs":  {Lvm: "db", Size: "20GiB"},
+					"wal":   {Lvm: "db", Size: "20%VG"},
+					"cache": {Lvm: "db", Size: "100%FREE"},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Absolute Size Without Space",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db", Size: "70%VG"},
+					"logs": {Lvm: "db", Size: "20GiB"},
+					"wal":  {Lvm: "db", Size: "30%VG"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: The logical volumes of the volume group consume 100%% of it, which leaves no space for logs"),
+		},
+		{
+			Name: "Free Space Without Space",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data":  {Lvm: "db", Size: "100%VG"},
+					"cache": {Lvm: "db", Size: "50%FREE"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: The logical volumes of the volume group consume 100%% of it, which leaves no space for cache"),
+		},
+		{
+			Name: "Free Space Consumed Twice",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data":  {Lvm: "db", Size: "20GiB"},
+					"cache": {Lvm: "db", Size: "50%FREE"},
+					"logs":  {Lvm: "db", Size: "50%FREE"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: Only one logical volume of the volume group can consume its free space. Consumed by cache, logs"),
+		},
+		{
+			Name: "Size Without Lvm",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Size: "20GiB"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: A size can only be provided for a device with lvm"),
+		},
+		{
+			Name: "Invalid Size",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db", Size: "20G"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 data: Logical volume size '20G' is not supported"),
+		},
+		{
+			Name: "Volume Group Overcommitted",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Lvm: "db", Size: "80%VG"},
+					"logs": {Lvm: "db"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 db: The logical volumes of the volume group consume 180%% of it, which exceeds 100%%"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lvsv := NewLogicalVolumeSizeValidator()
+			err := lvsv.Validate(subtest.Config)
+			utils.CheckError("lvsv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestEncryptionValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Encryption Configuration",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						Encryption: &Encryption{Name: "secure", KeyFile: "/etc/ebs-bootstrap/secure.key"},
+					},
+					"/dev/nvme1n1": {
+						Fs:         model.Xfs,
+						Encryption: &Encryption{Name: "scratch", Ephemeral: true},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Missing Name",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Encryption: &Encryption{KeyFile: "/etc/ebs-bootstrap/secure.key"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Must provide a name for an encrypted device"),
+		},
+		{
+			Name: "Name Contains Slash",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Encryption: &Encryption{Name: "/dev/mapper/secure", KeyFile: "/etc/ebs-bootstrap/secure.key"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: The name of an encrypted device must not contain '/'"),
+		},
+		{
+			Name: "Missing Key",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Encryption: &Encryption{Name: "secure"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Must provide either a key file or an ephemeral key for an encrypted device"),
+		},
+		{
+			Name: "Key File And Ephemeral Key",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Encryption: &Encryption{Name: "secure", KeyFile: "/etc/ebs-bootstrap/secure.key", Ephemeral: true},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Can not provide a key file for an encrypted device with an ephemeral key"),
+		},
+		{
+			Name: "Relative Key File",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Encryption: &Encryption{Name: "secure", KeyFile: "secure.key"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: secure.key is not an absolute path"),
+		},
+		{
+			Name: "RAID Array As Physical Volume",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid:       &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+						Encryption: &Encryption{Name: "scratch", Ephemeral: true},
+					},
+					"data": {Lvm: "db"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"db": {Devices: []string{"scratch"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: A RAID array that is a physical volume can not be encrypted"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ev := NewEncryptionValidator()
+			err := ev.Validate(subtest.Config)
+			utils.CheckError("ev.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestReformatValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Reformat Policies",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Xfs, Reformat: model.ReformatOnMismatch},
+					"/dev/xvdf":    {Fs: model.Ext4, Reformat: model.ReformatNever},
+					"/dev/xvdg":    {Fs: model.Ext4},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Unsupported Reformat Policy",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Xfs, Reformat: "always"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Reformat policy 'always' is not supported"),
+		},
+		{
+			Name: "Reformat Encrypted Device With Ephemeral Key",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {
+						Fs:         model.Xfs,
+						Reformat:   model.ReformatOnMismatch,
+						Encryption: &Encryption{Name: "scratch", Ephemeral: true},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Reformat Encrypted Device With Key File",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {
+						Fs:         model.Xfs,
+						Reformat:   model.ReformatOnMismatch,
+						Encryption: &Encryption{Name: "secure", KeyFile: "/etc/secure.key"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: A reformat policy of onMismatch can only be applied to an instance store volume"),
+		},
+		{
+			Name: "Reformat RAID Array",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Fs:       model.Xfs,
+						Reformat: model.ReformatOnMismatch,
+						Raid:     &Raid{Level: model.Raid0, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: A reformat policy of onMismatch can only be applied to an instance store volume"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			rv := NewReformatValidator()
+			err := rv.Validate(subtest.Config)
+			utils.CheckError("rv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestFormatOptionsValidator(t *testing.T) {
+	reflink := true
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Format Options",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs:            model.Xfs,
+						FormatOptions: &FormatOptions{BlockSize: 4096, Reflink: &reflink},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Format Options Not Supported by File System",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs:            model.Ext4,
+						FormatOptions: &FormatOptions{Reflink: &reflink},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Format option 'reflink' is not supported by the ext4 file system"),
+		},
+		{
+			Name: "Format Options of an Unformatted Device",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						FormatOptions: &FormatOptions{BlockSize: 4096},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: An unformatted file system can not be queried/modified"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			fov := NewFormatOptionsValidator(service.NewLinuxFileSystemServiceFactory(nil))
+			err := fov.Validate(subtest.Config)
+			utils.CheckError("fov.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestTuneValidator(t *testing.T) {
+	reserved := uint64(0)
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Tune Properties",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Tune: &Tune{ReservedBlocksPercent: &reserved, Errors: model.ErrorsRemountRo},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Tune Property Not Supported by File System",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs:   model.Xfs,
+						Tune: &Tune{ReservedBlocksPercent: &reserved},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Property 'reservedBlocksPercent' can not be tuned on the xfs file system"),
+		},
+		{
+			Name: "Unsupported Error Behaviour",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Tune: &Tune{Errors: model.ErrorBehaviour("halt")},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Error behaviour 'halt' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			tv := NewTuneValidator(service.NewLinuxFileSystemServiceFactory(nil))
+			err := tv.Validate(subtest.Config)
+			utils.CheckError("tv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestUuidValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid UUIDs",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Xfs, Uuid: model.RandomUuid},
+					"/dev/xvdg": {Fs: model.Ext4, Uuid: "31cf3150-f4b1-409a-9887-7f9da1fdd99c"},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Malformed UUID",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Xfs, Uuid: "generate"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: 'generate' is not a valid UUID. Expected either random or a UUID"),
+		},
+		{
+			Name: "Unsupported File System",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Btrfs, Uuid: model.RandomUuid},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: The UUID of the btrfs file system can not be changed"),
+		},
+		{
+			Name: "Duplicate Fixed UUID",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Xfs, Uuid: "31cf3150-f4b1-409a-9887-7f9da1fdd99c"},
+					"/dev/xvdg": {Fs: model.Xfs, Uuid: "31CF3150-F4B1-409A-9887-7F9DA1FDD99C"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdg: UUID 31CF3150-F4B1-409A-9887-7F9DA1FDD99C is already assigned to /dev/xvdf"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			uv := NewUuidValidator()
+			err := uv.Validate(subtest.Config)
+			utils.CheckError("uv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestFsckValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid File System Checks",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Ext4, Fsck: model.FsckRepair},
+					"/dev/xvdg": {Fs: model.Xfs, Fsck: model.FsckRepair},
+					"/dev/xvdh": {Fs: model.Btrfs, Fsck: model.FsckCheck},
+					"/dev/xvdi": {Fs: model.Ext4},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Unsupported File System Check",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Ext4, Fsck: "force"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: File system check 'force' is not supported"),
+		},
+		{
+			Name: "Unformatted File System",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Unformatted, Fsck: model.FsckCheck},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: An unformatted file system can not be checked"),
+		},
+		{
+			Name: "Swap Area",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Swap, Fsck: model.FsckCheck},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: A swap area can not be checked"),
+		},
+		{
+			Name: "Repair of btrfs",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Btrfs, Fsck: model.FsckRepair},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: The btrfs file system can not be repaired automatically. Set fsck to check"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			fv := NewFsckValidator()
+			err := fv.Validate(subtest.Config)
+			utils.CheckError("fv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestSwapValidator(t *testing.T) {
+	priority := 10
+	invalid := 32768
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Swap Areas",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Swap, Swap: &Swap{Priority: &priority}},
+					"/dev/nvme2n1": {Fs: model.Swap},
+					"/dev/xvdf":    {Fs: model.Ext4, MountPoint: "/mnt/scratch"},
+				},
+				SwapFiles: map[string]SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB", Priority: &priority},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Swap Options Without Swap File System",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Ext4, Swap: &Swap{Priority: &priority}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Swap options can only be applied to a device with a swap file system"),
+		},
+		{
+			Name: "Swap Area With Mount Point",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Swap, MountPoint: "/mnt/swap"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: A swap area can not be mounted"),
+		},
+		{
+			Name: "Swap Area With Invalid Priority",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Swap, Swap: &Swap{Priority: &invalid}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Swap priority must be between -1 and 32767"),
+		},
+		{
+			Name: "Swap File With Relative Path",
+			Config: &Config{
+				SwapFiles: map[string]SwapFile{
+					"swapfile": {Size: "4GiB"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 swapfile: Swap file is not an absolute path"),
+		},
+		{
+			Name: "Swap File Is a Configured Device",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Swap},
+				},
+				SwapFiles: map[string]SwapFile{
+					"/dev/nvme1n1": {Size: "4GiB"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Swap file is already a configured device"),
+		},
+		{
+			Name: "Swap File Without Size",
+			Config: &Config{
+				SwapFiles: map[string]SwapFile{
+					"/mnt/scratch/swapfile": {},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: Must provide the size of a swap file"),
+		},
+		{
+			Name: "Swap File With Invalid Size",
+			Config: &Config{
+				SwapFiles: map[string]SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4G"},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: Size '4G' is not supported"),
+		},
+		{
+			Name: "Swap File With Invalid Priority",
+			Config: &Config{
+				SwapFiles: map[string]SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB", Priority: &invalid},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: Swap priority must be between -1 and 32767"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sv := NewSwapValidator()
+			err := sv.Validate(subtest.Config)
+			utils.CheckError("sv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestSelectorValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Volume Ids",
+			Config: &Config{
+				Devices: map[string]Device{
+					"vol-0123456789abcdef0": {Fs: model.Ext4},
+					"data": {
+						Fs:       model.Xfs,
+						Selector: &Selector{VolumeId: "vol-0fedcba9876543210"},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Malformed Volume Id Key",
+			Config: &Config{
+				Devices: map[string]Device{
+					"vol-0123": {Fs: model.Ext4},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 vol-0123: Not a valid EBS volume id"),
+		},
+		{
+			Name: "Malformed Volume Id Selector",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {
+						Selector: &Selector{VolumeId: "vol0123456789abcdef0"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 data: 'vol0123456789abcdef0' is not a valid EBS volume id"),
+		},
+		{
+			Name: "Selector For Volume Id Key",
+			Config: &Config{
+				Devices: map[string]Device{
+					"vol-0123456789abcdef0": {
+						Selector: &Selector{VolumeId: "vol-0123456789abcdef0"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 vol-0123456789abcdef0: Can not provide a selector for a device that is referenced by its volume id"),
+		},
+		{
+			Name: "Volume Selected Twice",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {
+						Selector: &Selector{VolumeId: "vol-0123456789abcdef0"},
+					},
+					"logs": {
+						Selector: &Selector{VolumeId: "vol-0123456789abcdef0"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 vol-0123456789abcdef0: EBS volume is selected by more than one device"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sv := NewSelectorValidator()
+			err := sv.Validate(subtest.Config)
+			utils.CheckError("sv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestMatchValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Match",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {Fs: model.Xfs, Match: &Match{MinSize: "100GiB", MaxSize: "1TiB", Model: "Amazon EC2 NVMe Instance Storage"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Empty Match",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {Match: &Match{}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: Must provide at least one criterion for a match"),
+		},
+		{
+			Name: "Match And Selector",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {
+						Selector: &Selector{VolumeId: "vol-0123456789abcdef0"},
+						Match:    &Match{Label: "data"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 data: Can not select a device by both a match and its volume id"),
+		},
+		{
+			Name: "Match For RAID Array",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {
+						Raid:  &Raid{Level: model.Raid0, Devices: []string{"/dev/sdb", "/dev/sdc"}},
+						Match: &Match{Label: "scratch"},
+					},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: A RAID array can not be selected by a match"),
+		},
+		{
+			Name: "Invalid Size",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {Match: &Match{MinSize: "100G"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: Size '100G' is not supported"),
+		},
+		{
+			Name: "Inverted Size Range",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {Match: &Match{MinSize: "1TiB", MaxSize: "100GiB"}},
+				},
+			},
+			ExpectedError: fmt.Errorf("🔴 scratch: The minimum size of a match can not exceed its maximum size"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mv := NewMatchValidator()
+			err := mv.Validate(subtest.Config)
+			utils.CheckError("mv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestInstanceStoreValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Distinct Expansions",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Xfs, MountPoint: "/mnt/scratch0"},
+					"/dev/nvme2n1": {Fs: model.Xfs, MountPoint: "/mnt/scratch1"},
+				},
+				InstanceStore: &Device{Fs: model.Xfs, MountPoint: "/mnt/scratch{index}"},
+				expansions:    []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Expansions Share Mount Point",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme1n1": {Fs: model.Xfs, MountPoint: "/mnt/scratch"},
+					"/dev/nvme2n1": {Fs: model.Xfs, MountPoint: "/mnt/scratch"},
+				},
+				InstanceStore: &Device{Fs: model.Xfs, MountPoint: "/mnt/scratch"},
+				expansions:    []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Expansion of instanceStore collides with /dev/nvme2n1 at mount point /mnt/scratch"),
+		},
+		{
+			Name: "Expansion Shares Label With Device",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/nvme0n1": {Fs: model.Ext4, Label: "scratch0"},
+					"/dev/nvme1n1": {Fs: model.Xfs, Label: "scratch0"},
+				},
+				InstanceStore: &Device{Fs: model.Xfs, Label: "scratch{index}"},
+				expansions:    []string{"/dev/nvme1n1"},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Expansion of instanceStore collides with /dev/nvme0n1 at label scratch0"),
+		},
+		{
+			Name: "Template With RAID Configuration",
+			Config: &Config{
+				InstanceStore: &Device{Fs: model.Xfs, Raid: &Raid{Level: model.Raid0}},
+			},
+			ExpectedError: fmt.Errorf("🔴 instanceStore: A template can only configure the file system of each instance store volume"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			isv := NewInstanceStoreValidator()
+			err := isv.Validate(subtest.Config)
+			utils.CheckError("isv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestConsistencyValidator(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *Config
+		ExpectedError error
+	}{
+		{
+			Name: "Consistent Devices",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Ext4, MountPoint: "/mnt/app", Label: "app"},
+					"/dev/xvdg": {Fs: model.Ext4, MountPoint: "/mnt/application", Label: "application"},
+					"/dev/xvdh": {Fs: model.Ext4, MountPoint: "/mnt/data", Lvm: "data"},
+					"/dev/xvdi": {Fs: model.Ext4, MountPoint: "/mnt/logs", Lvm: "logs"},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Logical Volumes of Declared Volume Group",
+			Config: &Config{
+				Devices: map[string]Device{
+					"data": {Fs: model.Ext4, MountPoint: "/mnt/data", Lvm: "vg", Size: "50%VG"},
+					"logs": {Fs: model.Ext4, MountPoint: "/mnt/logs", Lvm: "vg", Size: "50%VG"},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"vg": {Devices: []string{"/dev/xvdf", "/dev/xvdg"}},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Every Conflict Is Reported",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Ext4, MountPoint: "/mnt/app", Label: "app"},
+					"/dev/xvdg": {Fs: model.Ext4, MountPoint: "/mnt/app/", Label: "app"},
+					"/dev/xvdh": {Fs: model.Ext4, MountPoint: "/mnt/app/logs"},
+					"/dev/xvdi": {Fs: model.Ext4, Lvm: "data"},
+					"/dev/xvdj": {Fs: model.Ext4, Lvm: "data"},
+				},
+			},
+			ExpectedError: errors.Join(
+				fmt.Errorf("🔴 /dev/xvdg: Mount point /mnt/app is already used by /dev/xvdf"),
+				fmt.Errorf("🔴 /dev/xvdg: Label app is already used by /dev/xvdf"),
+				fmt.Errorf("🔴 /dev/xvdj: Volume group data is already used by /dev/xvdi"),
+			),
+		},
+		{
+			Name: "Nested Mount Points Are Not Reported",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/xvdf": {Fs: model.Ext4, MountPoint: "/mnt/app"},
+					"/dev/xvdg": {Fs: model.Ext4, MountPoint: "/mnt/app/logs"},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Conflicting LVM Usage",
+			Config: &Config{
+				Devices: map[string]Device{
+					"/dev/data/data": {Fs: model.Ext4, MountPoint: "/mnt/data"},
+					"/dev/xvdf":      {Fs: model.Ext4, Lvm: "data"},
+					"/dev/xvdg":      {Fs: model.Ext4, Lvm: "logs"},
+					"scratch":        {Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/xvdg", "/dev/xvdh"}}},
+				},
+			},
+			ExpectedError: errors.Join(
+				fmt.Errorf("🔴 /dev/xvdf: Logical volume /dev/data/data is already a configured device"),
+				fmt.Errorf("🔴 /dev/xvdg: Can not be a physical volume of volume group logs as it is a member of RAID array scratch"),
+			),
+		},
+		{
+			Name: "Duplicate Membership",
+			Config: &Config{
+				Devices: map[string]Device{
+					"scratch": {Raid: &Raid{Level: model.Raid0, Devices: []string{"/dev/xvdf", "/dev/xvdg"}}},
+				},
+				VolumeGroups: map[string]VolumeGroup{
+					"data": {Devices: []string{"/dev/xvdg", "/dev/xvdh"}},
+					"logs": {Devices: []string{"/dev/xvdh"}},
+				},
+			},
+			ExpectedError: errors.Join(
+				fmt.Errorf("🔴 /dev/xvdg: Can not be a member of volume group data as it is already a member of RAID array scratch"),
+				fmt.Errorf("🔴 /dev/xvdh: Can not be a member of volume group logs as it is already a member of volume group data"),
+			),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			cv := NewConsistencyValidator()
+			err := cv.Validate(subtest.Config)
+			utils.CheckError("cv.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
@@ -25,7 +25,8 @@ func (fdl *CreateDirectoryLayer) From(c *config.Config) error {
 
 func (fdl *CreateDirectoryLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
@@ -41,14 +42,15 @@ func (fdl *CreateDirectoryLayer) Modify(c *config.Config) ([]action.Action, erro
 		}
 
 		mode := c.GetMode(name)
-		a := fdl.fileBackend.CreateDirectory(cd.MountPoint).SetMode(mode)
+		a := fdl.fileBackend.CreateDirectory(cd.MountPoint).SetMode(mode).SetDevice(name)
 		actions = append(actions, a)
 	}
 	return actions, nil
 }
 
 func (fdl *CreateDirectoryLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
@@ -52,7 +52,7 @@ func TestCreateDirectoryLayerModify(t *testing.T) {
 			Files:     map[string]*model.File{},
 			CmpOption: cmp.AllowUnexported(action.CreateDirectoryAction{}),
 			ExpectedOuput: []action.Action{
-				action.NewCreateDirectoryAction("/mnt/foo", nil).SetMode(config.DefaultMode),
+				action.NewCreateDirectoryAction("/mnt/foo", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
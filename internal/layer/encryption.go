@@ -0,0 +1,196 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type FormatEncryptedDeviceLayer struct {
+	encryptionBackend backend.EncryptionBackend
+}
+
+func NewFormatEncryptedDeviceLayer(eb backend.EncryptionBackend) *FormatEncryptedDeviceLayer {
+	return &FormatEncryptedDeviceLayer{
+		encryptionBackend: eb,
+	}
+}
+
+func (fedl *FormatEncryptedDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Encryption == nil {
+			continue
+		}
+		_, err := fedl.encryptionBackend.GetEncryptedDevice(cd.Encryption.Name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		bd, err := fedl.encryptionBackend.GetBlockDevice(name)
+		if err != nil {
+			return nil, err
+		}
+		keyFile := c.GetKeyFile(name)
+		exists := fedl.encryptionBackend.HasKeyFile(keyFile)
+		mode := c.GetMode(name)
+		if bd.FileSystem == model.Luks {
+			if !cd.Encryption.Ephemeral || exists {
+				continue
+			}
+			// An ephemeral key does not survive a reboot, but the LUKS header does.
+			// The device is only formatted again under the same safeguards as the
+			// reformat of a file system, as a misconfigured key file path would
+			// otherwise destroy data that could still be recovered
+			if cd.Reformat != model.ReformatOnMismatch {
+				return nil, fmt.Errorf("🔴 %s: Can not open LUKS device as its ephemeral key %s has been lost. Enable a reformat policy of %s to format it again", name, keyFile, model.ReformatOnMismatch)
+			}
+			if !fedl.encryptionBackend.IsInstanceStore(bd) {
+				return nil, fmt.Errorf("🔴 %s: Can not reformat a LUKS device that is not an instance store volume", name)
+			}
+			if mode != model.Force {
+				return nil, fmt.Errorf("🔴 %s: Can not reformat a LUKS device with a lost ephemeral key unless in %s mode", name, model.Force)
+			}
+			a := fedl.encryptionBackend.Reformat(name, keyFile)
+			actions = append(actions, a.SetMode(mode).SetDevice(name))
+			continue
+		}
+		// cryptsetup does not seek confirmation before it overwrites a device, so we
+		// must refuse to format any device that carries an existing signature
+		if bd.FileSystem != model.Unformatted {
+			return nil, fmt.Errorf("🔴 %s: Can not encrypt a device with an existing %s file system", name, bd.FileSystem.String())
+		}
+		if !cd.Encryption.Ephemeral && !exists {
+			return nil, fmt.Errorf("🔴 %s: Key file %s does not exist", name, keyFile)
+		}
+		wa, err := wipeSignatures(fedl.encryptionBackend, bd, cd.AllowWipe, "encrypt")
+		if err != nil {
+			return nil, err
+		}
+		if wa != nil {
+			actions = append(actions, wa.SetMode(mode).SetDevice(name))
+		}
+		// An ephemeral key that survived an earlier attempt to format the
+		// device is reused, rather than generated again
+		a := fedl.encryptionBackend.Format(name, keyFile, cd.Encryption.Ephemeral && !exists)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
+	}
+	return actions, nil
+}
+
+func (fedl *FormatEncryptedDeviceLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Encryption == nil {
+			continue
+		}
+		bd, err := fedl.encryptionBackend.GetBlockDevice(name)
+		if err != nil {
+			return err
+		}
+		if bd.FileSystem != model.Luks {
+			return fmt.Errorf("🔴 %s: Failed LUKS device validation checks. Expected=%s, Actual=%s", name, model.Luks, bd.FileSystem)
+		}
+	}
+	return nil
+}
+
+func (fedl *FormatEncryptedDeviceLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (fedl *FormatEncryptedDeviceLayer) From(c *config.Config) error {
+	return fedl.encryptionBackend.From(c)
+}
+
+func (fedl *FormatEncryptedDeviceLayer) ShouldProcess(c *config.Config) bool {
+	return shouldProcessEncryption(c)
+}
+
+type OpenEncryptedDeviceLayer struct {
+	encryptionBackend backend.EncryptionBackend
+}
+
+func NewOpenEncryptedDeviceLayer(eb backend.EncryptionBackend) *OpenEncryptedDeviceLayer {
+	return &OpenEncryptedDeviceLayer{
+		encryptionBackend: eb,
+	}
+}
+
+func (oedl *OpenEncryptedDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Encryption == nil {
+			continue
+		}
+		_, err := oedl.encryptionBackend.GetEncryptedDevice(cd.Encryption.Name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		bd, err := oedl.encryptionBackend.GetBlockDevice(name)
+		if err != nil {
+			return nil, err
+		}
+		if bd.FileSystem != model.Luks {
+			return nil, fmt.Errorf("🔴 %s: Can not open a device with an existing %s file system as a LUKS device", name, bd.FileSystem.String())
+		}
+		keyFile := c.GetKeyFile(name)
+		if !oedl.encryptionBackend.HasKeyFile(keyFile) {
+			if cd.Encryption.Ephemeral {
+				return nil, fmt.Errorf("🔴 %s: Can not open LUKS device as its ephemeral key %s has been lost", name, keyFile)
+			}
+			return nil, fmt.Errorf("🔴 %s: Key file %s does not exist", name, keyFile)
+		}
+		mode := c.GetMode(name)
+		a := oedl.encryptionBackend.Open(name, cd.Encryption.Name, keyFile)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
+	}
+	return actions, nil
+}
+
+func (oedl *OpenEncryptedDeviceLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Encryption == nil {
+			continue
+		}
+		_, err := oedl.encryptionBackend.GetEncryptedDevice(cd.Encryption.Name)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: Failed LUKS device validation checks. %s/%s is not open", name, model.MapperDirectory, cd.Encryption.Name)
+		}
+	}
+	return nil
+}
+
+func (oedl *OpenEncryptedDeviceLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (oedl *OpenEncryptedDeviceLayer) From(c *config.Config) error {
+	return oedl.encryptionBackend.From(c)
+}
+
+func (oedl *OpenEncryptedDeviceLayer) ShouldProcess(c *config.Config) bool {
+	return shouldProcessEncryption(c)
+}
+
+func shouldProcessEncryption(c *config.Config) bool {
+	for _, cd := range c.Devices {
+		if cd.Encryption != nil {
+			return true
+		}
+	}
+	return false
+}
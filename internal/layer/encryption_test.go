@@ -0,0 +1,303 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestFormatEncryptedDeviceLayerModify(t *testing.T) {
+	keyFile := "/run/ebs-bootstrap/keys/scratch.key"
+	subtests := []struct {
+		Name             string
+		Mode             model.Mode
+		Reformat         model.ReformatPolicy
+		AllowWipe        bool
+		BlockDevices     map[string]*model.BlockDevice
+		EncryptedDevices map[string]*model.EncryptedDevice
+		KeyFiles         map[string]bool
+		Signatures       map[string][]*model.Signature
+		InstanceStores   map[string]bool
+		CmpOption        cmp.Option
+		ExpectedOuput    []action.Action
+		ExpectedError    error
+	}{
+		{
+			Name: "Format Unformatted Device With New Ephemeral Key",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Unformatted},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			CmpOption:        cmp.AllowUnexported(action.FormatEncryptedDeviceAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewFormatEncryptedDeviceAction("/dev/loop0", keyFile, true, nil).SetMode(config.DefaultMode).SetDevice("/dev/loop0"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Format Unformatted Device With Existing Ephemeral Key",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Unformatted},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{keyFile: true},
+			CmpOption:        cmp.AllowUnexported(action.FormatEncryptedDeviceAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewFormatEncryptedDeviceAction("/dev/loop0", keyFile, false, nil).SetMode(config.DefaultMode).SetDevice("/dev/loop0"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Existing LUKS Device",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{keyFile: true},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    []action.Action{},
+			ExpectedError:    nil,
+		},
+		{
+			Name: "Existing LUKS Device With Lost Ephemeral Key",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Can not open LUKS device as its ephemeral key /run/ebs-bootstrap/keys/scratch.key has been lost. Enable a reformat policy of onMismatch to format it again"),
+		},
+		{
+			Name: "Existing LUKS Device With Lost Ephemeral Key + Mode=Force",
+			Mode: model.Force,
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			InstanceStores:   map[string]bool{"/dev/loop0": true},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Can not open LUKS device as its ephemeral key /run/ebs-bootstrap/keys/scratch.key has been lost. Enable a reformat policy of onMismatch to format it again"),
+		},
+		{
+			Name:     "Existing LUKS Device With Lost Ephemeral Key + Reformat=OnMismatch + Not Instance Store",
+			Mode:     model.Force,
+			Reformat: model.ReformatOnMismatch,
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			InstanceStores:   map[string]bool{},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Can not reformat a LUKS device that is not an instance store volume"),
+		},
+		{
+			Name:     "Existing LUKS Device With Lost Ephemeral Key + Reformat=OnMismatch + Mode=Prompt",
+			Mode:     model.Prompt,
+			Reformat: model.ReformatOnMismatch,
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			InstanceStores:   map[string]bool{"/dev/loop0": true},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Can not reformat a LUKS device with a lost ephemeral key unless in force mode"),
+		},
+		{
+			Name:     "Existing LUKS Device With Lost Ephemeral Key + Reformat=OnMismatch + Mode=Force",
+			Mode:     model.Force,
+			Reformat: model.ReformatOnMismatch,
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			InstanceStores:   map[string]bool{"/dev/loop0": true},
+			CmpOption:        cmp.AllowUnexported(action.ReformatEncryptedDeviceAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewReformatEncryptedDeviceAction("/dev/loop0", keyFile, nil).SetMode(model.Force).SetDevice("/dev/loop0"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Refuse Unformatted Device With Existing Signatures",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Unformatted},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			Signatures: map[string][]*model.Signature{
+				"/dev/loop0": {{Type: "PMBR", Offset: 0x1fe}, {Type: "gpt", Offset: 0x200}},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/loop0: Can not encrypt a device with existing signatures: PMBR (offset 0x1fe), gpt (offset 0x200). Set allowWipe to erase them"),
+		},
+		{
+			Name:      "Wipe Unformatted Device With Existing Signatures",
+			AllowWipe: true,
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Unformatted},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			Signatures: map[string][]*model.Signature{
+				"/dev/loop0": {{Type: "gpt", Offset: 0x200}},
+			},
+			CmpOption: cmp.AllowUnexported(action.WipeDeviceAction{}, action.FormatEncryptedDeviceAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewWipeDeviceAction("/dev/loop0", []*model.Signature{{Type: "gpt", Offset: 0x200}}, nil).SetMode(config.DefaultMode).SetDevice("/dev/loop0"),
+				action.NewFormatEncryptedDeviceAction("/dev/loop0", keyFile, true, nil).SetMode(config.DefaultMode).SetDevice("/dev/loop0"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:         "Open LUKS Device",
+			BlockDevices: map[string]*model.BlockDevice{},
+			EncryptedDevices: map[string]*model.EncryptedDevice{
+				"scratch": {Name: "scratch", Device: "/dev/loop0"},
+			},
+			KeyFiles:      map[string]bool{keyFile: true},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Refuse Device With Existing Signature",
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Ext4},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Can not encrypt a device with an existing ext4 file system"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c := &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/loop0": {
+						Fs:         model.Xfs,
+						Encryption: &config.Encryption{Name: "scratch", Ephemeral: true},
+						AllowWipe:  subtest.AllowWipe,
+						Reformat:   subtest.Reformat,
+						Options: config.Options{
+							Mode: subtest.Mode,
+						},
+					},
+				},
+			}
+			leb := backend.NewMockLinuxEncryptionBackendWithInstanceStores(subtest.BlockDevices, subtest.EncryptedDevices, subtest.KeyFiles, subtest.Signatures, subtest.InstanceStores)
+			fedl := NewFormatEncryptedDeviceLayer(leb)
+			actions, err := fedl.Modify(c)
+			utils.CheckError("fedl.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("fedl.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestOpenEncryptedDeviceLayerModify(t *testing.T) {
+	subtests := []struct {
+		Name             string
+		Encryption       *config.Encryption
+		BlockDevices     map[string]*model.BlockDevice
+		EncryptedDevices map[string]*model.EncryptedDevice
+		KeyFiles         map[string]bool
+		CmpOption        cmp.Option
+		ExpectedOuput    []action.Action
+		ExpectedError    error
+	}{
+		{
+			Name:       "Open LUKS Device",
+			Encryption: &config.Encryption{Name: "secure", KeyFile: "/etc/secure.key"},
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{"/etc/secure.key": true},
+			CmpOption:        cmp.AllowUnexported(action.OpenEncryptedDeviceAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewOpenEncryptedDeviceAction("/dev/loop0", "secure", "/etc/secure.key", nil).SetMode(config.DefaultMode).SetDevice("/dev/loop0"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:         "LUKS Device Already Open",
+			Encryption:   &config.Encryption{Name: "secure", KeyFile: "/etc/secure.key"},
+			BlockDevices: map[string]*model.BlockDevice{},
+			EncryptedDevices: map[string]*model.EncryptedDevice{
+				"secure": {Name: "secure", Device: "/dev/loop0"},
+			},
+			KeyFiles:      map[string]bool{"/etc/secure.key": true},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name:       "Missing Key File",
+			Encryption: &config.Encryption{Name: "secure", KeyFile: "/etc/secure.key"},
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Key file /etc/secure.key does not exist"),
+		},
+		{
+			Name:       "Lost Ephemeral Key",
+			Encryption: &config.Encryption{Name: "scratch", Ephemeral: true},
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Luks},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Can not open LUKS device as its ephemeral key /run/ebs-bootstrap/keys/scratch.key has been lost"),
+		},
+		{
+			Name:       "Device Is Not A LUKS Device",
+			Encryption: &config.Encryption{Name: "secure", KeyFile: "/etc/secure.key"},
+			BlockDevices: map[string]*model.BlockDevice{
+				"/dev/loop0": {Name: "/dev/loop0", FileSystem: model.Xfs},
+			},
+			EncryptedDevices: map[string]*model.EncryptedDevice{},
+			KeyFiles:         map[string]bool{"/etc/secure.key": true},
+			CmpOption:        cmp.AllowUnexported(),
+			ExpectedOuput:    nil,
+			ExpectedError:    fmt.Errorf("🔴 /dev/loop0: Can not open a device with an existing xfs file system as a LUKS device"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c := &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/loop0": {Fs: model.Xfs, Encryption: subtest.Encryption},
+				},
+			}
+			leb := backend.NewMockLinuxEncryptionBackend(subtest.BlockDevices, subtest.EncryptedDevices, subtest.KeyFiles)
+			oedl := NewOpenEncryptedDeviceLayer(leb)
+			actions, err := oedl.Modify(c)
+			utils.CheckError("oedl.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("oedl.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
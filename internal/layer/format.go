@@ -25,7 +25,8 @@ func (fdl *FormatDeviceLayer) From(c *config.Config) error {
 
 func (fdl *FormatDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if cd.Fs == model.Unformatted {
 			return nil, fmt.Errorf("🔴 %s: Can not erase the file system of a device", name)
 		}
@@ -37,22 +38,49 @@ func (fdl *FormatDeviceLayer) Modify(c *config.Config) ([]action.Action, error)
 		if bd.FileSystem == cd.Fs {
 			continue
 		}
+		mode := c.GetMode(name)
 		if bd.FileSystem != model.Unformatted {
-			return nil, fmt.Errorf("🔴 %s: Can not format a device with an existing %s file system", bd.Name, bd.FileSystem.String())
+			if cd.Reformat != model.ReformatOnMismatch {
+				return nil, fmt.Errorf("🔴 %s: Can not format a device with an existing %s file system", bd.Name, bd.FileSystem.String())
+			}
+			// The contents of an instance store volume are not expected to outlive
+			// the instance. However, the reformat policy is never trusted to identify
+			// an instance store volume by itself
+			if !fdl.deviceBackend.IsInstanceStore(bd) {
+				return nil, fmt.Errorf("🔴 %s: Can not reformat a device that is not an instance store volume", bd.Name)
+			}
+			if mode != model.Force {
+				return nil, fmt.Errorf("🔴 %s: Can not reformat a device with an existing %s file system unless in %s mode", bd.Name, bd.FileSystem.String(), model.Force)
+			}
+			as, err := fdl.deviceBackend.Reformat(bd, cd.Fs, c.GetFormatOptions(name))
+			if err != nil {
+				return nil, err
+			}
+			for _, a := range as {
+				actions = append(actions, a.SetMode(mode).SetDevice(name))
+			}
+			continue
 		}
 
-		mode := c.GetMode(name)
-		a, err := fdl.deviceBackend.Format(bd, cd.Fs)
+		wa, err := wipeSignatures(fdl.deviceBackend, bd, cd.AllowWipe, "format")
+		if err != nil {
+			return nil, err
+		}
+		if wa != nil {
+			actions = append(actions, wa.SetMode(mode).SetDevice(name))
+		}
+		a, err := fdl.deviceBackend.Format(bd, cd.Fs, c.GetFormatOptions(name))
 		if err != nil {
 			return nil, err
 		}
-		actions = append(actions, a.SetMode(mode))
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
 	}
 	return actions, nil
 }
 
 func (fdl *FormatDeviceLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		d, err := fdl.deviceBackend.GetBlockDevice(name)
 		if err != nil {
 			return err
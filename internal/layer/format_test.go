@@ -18,6 +18,8 @@ func TestFormatDeviceLayerModify(t *testing.T) {
 		Name           string
 		Config         *config.Config
 		Devices        map[string]*model.BlockDevice
+		Signatures     map[string][]*model.Signature
+		InstanceStores map[string]bool
 		CmpOption      cmp.Option
 		ExpectedOutput []action.Action
 		ExpectedError  error
@@ -42,7 +44,35 @@ func TestFormatDeviceLayerModify(t *testing.T) {
 				service.XfsService{},
 			),
 			ExpectedOutput: []action.Action{
-				action.NewFormatDeviceAction("/dev/xvdf", service.NewXfsService(nil)).SetMode(config.DefaultMode),
+				action.NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{}, service.NewXfsService(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Formatting Unformatted Block Device to ext4 with Format Options and Label",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:    model.Ext4,
+						Label: "stateful",
+						FormatOptions: &config.FormatOptions{
+							InodeRatio: 65536,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.FormatDeviceAction{},
+				service.Ext4Service{},
+			),
+			ExpectedOutput: []action.Action{
+				action.NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{InodeRatio: 65536, Label: "stateful"}, service.NewExt4Service(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -103,10 +133,159 @@ func TestFormatDeviceLayerModify(t *testing.T) {
 			ExpectedOutput: nil,
 			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: Can not format a device with an existing xfs file system"),
 		},
+		{
+			Name: "Attempting to Format a Block Device with a Partition Table",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Xfs,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			Signatures: map[string][]*model.Signature{
+				"/dev/xvdf": {
+					{Type: "PMBR", Offset: 0x1fe},
+					{Type: "gpt", Offset: 0x200},
+				},
+			},
+			CmpOption:      cmp.AllowUnexported(),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdf: Can not format a device with existing signatures: PMBR (offset 0x1fe), gpt (offset 0x200). Set allowWipe to erase them"),
+		},
+		{
+			Name: "Wiping a Block Device with a Partition Table before Formatting",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:        model.Xfs,
+						AllowWipe: true,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Unformatted,
+				},
+			},
+			Signatures: map[string][]*model.Signature{
+				"/dev/xvdf": {
+					{Type: "PMBR", Offset: 0x1fe},
+					{Type: "gpt", Offset: 0x200},
+				},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.WipeDeviceAction{},
+				action.FormatDeviceAction{},
+				service.XfsService{},
+			),
+			ExpectedOutput: []action.Action{
+				action.NewWipeDeviceAction("/dev/xvdf", []*model.Signature{
+					{Type: "PMBR", Offset: 0x1fe},
+					{Type: "gpt", Offset: 0x200},
+				}, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+				action.NewFormatDeviceAction("/dev/xvdf", &model.FormatOptions{}, service.NewXfsService(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Reformatting an Instance Store Volume",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {
+						Fs:       model.Xfs,
+						Reformat: model.ReformatOnMismatch,
+						Options: config.Options{
+							Mode: model.Force,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Ext4,
+				},
+			},
+			InstanceStores: map[string]bool{
+				"/dev/nvme1n1": true,
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.ReformatDeviceAction{},
+				service.XfsService{},
+			),
+			ExpectedOutput: []action.Action{
+				action.NewReformatDeviceAction("/dev/nvme1n1", model.Ext4, &model.FormatOptions{}, nil, service.NewXfsService(nil)).SetMode(model.Force).SetDevice("/dev/nvme1n1"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Attempting to Reformat an Instance Store Volume without Force Mode",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {
+						Fs:       model.Xfs,
+						Reformat: model.ReformatOnMismatch,
+						Options: config.Options{
+							Mode: model.Prompt,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Ext4,
+				},
+			},
+			InstanceStores: map[string]bool{
+				"/dev/nvme1n1": true,
+			},
+			CmpOption:      cmp.AllowUnexported(),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/nvme1n1: Can not reformat a device with an existing ext4 file system unless in force mode"),
+		},
+		{
+			Name: "Attempting to Reformat an EBS Volume",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {
+						Fs:       model.Xfs,
+						Reformat: model.ReformatOnMismatch,
+						Options: config.Options{
+							Mode: model.Force,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Ext4,
+				},
+			},
+			InstanceStores: map[string]bool{
+				"/dev/nvme1n1": false,
+			},
+			CmpOption:      cmp.AllowUnexported(),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/nvme1n1: Can not reformat a device that is not an instance store volume"),
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
-			ldb := backend.NewMockLinuxDeviceBackend(subtest.Devices)
+			var ldb *backend.LinuxDeviceBackend
+			if subtest.InstanceStores != nil {
+				ldb = backend.NewMockLinuxDeviceBackendWithInstanceStores(subtest.Devices, subtest.InstanceStores)
+			} else {
+				ldb = backend.NewMockLinuxDeviceBackendWithSignatures(subtest.Devices, subtest.Signatures)
+			}
 			ld := NewFormatDeviceLayer(ldb)
 			actions, err := ld.Modify(subtest.Config)
 			utils.CheckError("ld.Modify()", t, subtest.ExpectedError, err)
@@ -0,0 +1,105 @@
+package layer
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type CheckFileSystemLayer struct {
+	deviceBackend backend.DeviceBackend
+	checkBackend  backend.CheckBackend
+}
+
+func NewCheckFileSystemLayer(db backend.DeviceBackend, cb backend.CheckBackend) *CheckFileSystemLayer {
+	return &CheckFileSystemLayer{
+		deviceBackend: db,
+		checkBackend:  cb,
+	}
+}
+
+func (cfl *CheckFileSystemLayer) From(c *config.Config) error {
+	err := cfl.deviceBackend.From(c)
+	if err != nil {
+		return err
+	}
+	return cfl.checkBackend.From(c)
+}
+
+// A mounted file system is never checked. It is assumed to have been checked
+// when it was first mounted
+func (cfl *CheckFileSystemLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Fsck == model.FsckUnassigned {
+			continue
+		}
+
+		bd, err := cfl.deviceBackend.GetBlockDevice(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(bd.MountPoint) > 0 {
+			continue
+		}
+		result, err := cfl.checkBackend.GetCheckResult(name)
+		if err != nil {
+			return nil, err
+		}
+		if result != model.FsckUncorrected {
+			continue
+		}
+		if cd.Fsck != model.FsckRepair {
+			return nil, fmt.Errorf("🔴 %s: The %s file system has errors. Set fsck to %s, or repair the file system manually", name, bd.FileSystem.String(), model.FsckRepair)
+		}
+
+		mode := c.GetMode(name)
+		a, err := cfl.checkBackend.Repair(bd)
+		if err != nil {
+			return nil, err
+		}
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
+	}
+	return actions, nil
+}
+
+func (cfl *CheckFileSystemLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Fsck == model.FsckUnassigned {
+			continue
+		}
+		bd, err := cfl.deviceBackend.GetBlockDevice(name)
+		if err != nil {
+			return err
+		}
+		if len(bd.MountPoint) > 0 {
+			continue
+		}
+		result, err := cfl.checkBackend.GetCheckResult(name)
+		if err != nil {
+			return err
+		}
+		if result == model.FsckUncorrected {
+			return fmt.Errorf("🔴 %s: Failed fsck validation checks. Expected=%s, Actual=%s", name, model.FsckClean, result)
+		}
+	}
+	return nil
+}
+
+func (cfl *CheckFileSystemLayer) Warning() string {
+	return "Repairing larger file systems can take several minutes ⌛"
+}
+
+func (cfl *CheckFileSystemLayer) ShouldProcess(c *config.Config) bool {
+	for _, cd := range c.Devices {
+		if cd.Fsck != model.FsckUnassigned {
+			return true
+		}
+	}
+	return false
+}
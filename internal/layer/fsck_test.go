@@ -0,0 +1,272 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestCheckFileSystemLayerModify(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		Devices       map[string]*model.BlockDevice
+		Results       map[string]model.FsckResult
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name: "Repair File System With Errors",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Fsck: model.FsckRepair,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Results: map[string]model.FsckResult{
+				"/dev/xvdf": model.FsckUncorrected,
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.RepairFileSystemAction{},
+				service.Ext4Service{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewRepairFileSystemAction("/dev/xvdf", service.NewExt4Service(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Check File System With Errors",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Xfs,
+						Fsck: model.FsckCheck,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Xfs,
+				},
+			},
+			Results: map[string]model.FsckResult{
+				"/dev/xvdf": model.FsckUncorrected,
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: The xfs file system has errors. Set fsck to repair, or repair the file system manually"),
+		},
+		{
+			Name: "Clean File System",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Fsck: model.FsckRepair,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Results: map[string]model.FsckResult{
+				"/dev/xvdf": model.FsckClean,
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Skip Mounted Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Fsck: model.FsckRepair,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					MountPoint: "/mnt/foo",
+				},
+			},
+			Results:       map[string]model.FsckResult{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Skip File System Check",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Results:       map[string]model.FsckResult{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Result Could Not Be Found",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Fsck: model.FsckCheck,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Results:       map[string]model.FsckResult{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Could not find the result of a file system check"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackend(subtest.Devices)
+			lcb := backend.NewMockLinuxCheckBackend(subtest.Results)
+			layer := NewCheckFileSystemLayer(ldb, lcb)
+			actions, err := layer.Modify(subtest.Config)
+			utils.CheckError("CheckFileSystemLayer.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("CheckFileSystemLayer.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestCheckFileSystemLayerValidate(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		Devices       map[string]*model.BlockDevice
+		Results       map[string]model.FsckResult
+		ExpectedError error
+	}{
+		{
+			Name: "File System Is Clean",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4, Fsck: model.FsckRepair},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {Name: "/dev/xvdf", FileSystem: model.Ext4},
+			},
+			Results: map[string]model.FsckResult{
+				"/dev/xvdf": model.FsckClean,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Skip Mounted Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4, Fsck: model.FsckRepair},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {Name: "/dev/xvdf", FileSystem: model.Ext4, MountPoint: "/mnt/foo"},
+			},
+			Results:       map[string]model.FsckResult{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "File System Has Errors",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4, Fsck: model.FsckRepair},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {Name: "/dev/xvdf", FileSystem: model.Ext4},
+			},
+			Results: map[string]model.FsckResult{
+				"/dev/xvdf": model.FsckUncorrected,
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed fsck validation checks. Expected=clean, Actual=uncorrected"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackend(subtest.Devices)
+			lcb := backend.NewMockLinuxCheckBackend(subtest.Results)
+			cfl := NewCheckFileSystemLayer(ldb, lcb)
+			err := cfl.Validate(subtest.Config)
+			utils.CheckError("cfl.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestCheckFileSystemLayerShouldProcess(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		ExpectedValue bool
+	}{
+		{
+			Name: "At Least Once Device Has Fsck Specified",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdb": {Fs: model.Ext4, Fsck: model.FsckCheck},
+					"/dev/xvdf": {Fs: model.Ext4},
+				},
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "No Device Has Fsck Specified",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4},
+				},
+			},
+			ExpectedValue: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			cfl := NewCheckFileSystemLayer(nil, nil)
+			value := cfl.ShouldProcess(subtest.Config)
+			utils.CheckOutput("cfl.ShouldProcess()", t, subtest.ExpectedValue, value)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package layer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type UpdateFstabLayer struct {
+	deviceBackend backend.DeviceBackend
+	fileBackend   backend.FileBackend
+}
+
+func NewUpdateFstabLayer(db backend.DeviceBackend, fb backend.FileBackend) *UpdateFstabLayer {
+	return &UpdateFstabLayer{
+		deviceBackend: db,
+		fileBackend:   fb,
+	}
+}
+
+func (ufl *UpdateFstabLayer) From(c *config.Config) error {
+	err := ufl.deviceBackend.From(c)
+	if err != nil {
+		return err
+	}
+	return ufl.fileBackend.From(c)
+}
+
+func (ufl *UpdateFstabLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if !ufl.shouldManage(c, name, cd) {
+			continue
+		}
+		expected, err := ufl.entry(c, name, cd)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := ufl.fileBackend.GetFstabEntry(cd.MountPoint)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if actual != nil && *actual == *expected {
+			continue
+		}
+		mode := c.GetMode(name)
+		a := ufl.fileBackend.UpdateFstabEntry(expected).SetMode(mode).SetDevice(name)
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+func (ufl *UpdateFstabLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if !ufl.shouldManage(c, name, cd) {
+			continue
+		}
+		expected, err := ufl.entry(c, name, cd)
+		if err != nil {
+			return err
+		}
+		actual, err := ufl.fileBackend.GetFstabEntry(cd.MountPoint)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: Failed fstab validation checks. No entry found for %s", name, cd.MountPoint)
+		}
+		if *actual != *expected {
+			return fmt.Errorf("🔴 %s: Failed fstab validation checks. Expected='%s', Actual='%s'", name, expected, actual)
+		}
+	}
+	return nil
+}
+
+func (ufl *UpdateFstabLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (ufl *UpdateFstabLayer) ShouldProcess(c *config.Config) bool {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if ufl.shouldManage(c, name, cd) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ufl *UpdateFstabLayer) shouldManage(c *config.Config, name string, cd config.Device) bool {
+	return len(cd.MountPoint) > 0 && c.GetFstab(name)
+}
+
+// A device is referenced by its label, when one is configured, as it is stable
+// across reboots and remains readable to a human. Otherwise, the UUID of the
+// file system is used
+func (ufl *UpdateFstabLayer) entry(c *config.Config, name string, cd config.Device) (*model.FstabEntry, error) {
+	bd, err := ufl.deviceBackend.GetBlockDevice(name)
+	if err != nil {
+		return nil, err
+	}
+	var source string
+	if len(cd.Label) > 0 {
+		source = model.FstabLabelPrefix + cd.Label
+	} else {
+		if len(bd.UUID) == 0 {
+			return nil, fmt.Errorf("🔴 %s: Can not reference a file system with no UUID in /etc/fstab", name)
+		}
+		source = model.FstabUuidPrefix + bd.UUID
+	}
+	// RAID arrays and LUKS devices are assembled and opened by ebs-bootstrap, rather
+	// than during boot. Their absence must not fail the boot, and the mount of a
+	// RAID array must wait for each of its members
+	options := c.GetMountOptions(name)
+	if cd.Raid != nil || strings.HasPrefix(name, model.MapperDirectory+"/") {
+		options = options.With("nofail")
+	}
+	if cd.Raid != nil {
+		for _, member := range cd.Raid.Devices {
+			options = options.With("x-systemd.requires=" + member)
+		}
+	}
+	return &model.FstabEntry{
+		Source:     source,
+		MountPoint: cd.MountPoint,
+		FileSystem: cd.Fs,
+		Options:    options,
+		Dump:       0,
+		Pass:       c.GetFsckPass(name),
+	}, nil
+}
@@ -0,0 +1,372 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestUpdateFstabLayerModify(t *testing.T) {
+	pass := uint(0)
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		Devices       map[string]*model.BlockDevice
+		Fstab         []*model.FstabEntry
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name: "Add Missing Entry Referenced By Label",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						Label:      "stateful",
+						MountPoint: "/mnt/ebs",
+						Options: config.Options{
+							Fstab:        true,
+							MountOptions: "defaults,nofail",
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					Label:      "stateful",
+				},
+			},
+			Fstab:     []*model.FstabEntry{},
+			CmpOption: cmp.AllowUnexported(action.UpdateFstabEntryAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewUpdateFstabEntryAction(backend.FstabPath, &model.FstabEntry{
+					Source:     "LABEL=stateful",
+					MountPoint: "/mnt/ebs",
+					FileSystem: model.Ext4,
+					Options:    "defaults,nofail",
+					Pass:       config.DefaultFsckPass,
+				}, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Repair Drifted Entry Referenced By UUID",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Xfs,
+						MountPoint: "/mnt/ebs",
+						Options: config.Options{
+							Fstab:    true,
+							FsckPass: &pass,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Xfs,
+					UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				},
+			},
+			Fstab: []*model.FstabEntry{
+				{
+					Source:     "/dev/xvdf",
+					MountPoint: "/mnt/ebs",
+					FileSystem: model.Xfs,
+					Options:    "defaults",
+				},
+			},
+			CmpOption: cmp.AllowUnexported(action.UpdateFstabEntryAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewUpdateFstabEntryAction(backend.FstabPath, &model.FstabEntry{
+					Source:     "UUID=9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+					MountPoint: "/mnt/ebs",
+					FileSystem: model.Xfs,
+					Options:    "defaults",
+					Pass:       0,
+				}, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Entry Matches Configuration",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						Label:      "stateful",
+						MountPoint: "/mnt/ebs",
+						Options: config.Options{
+							Fstab: true,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					Label:      "stateful",
+				},
+			},
+			Fstab: []*model.FstabEntry{
+				{
+					Source:     "LABEL=stateful",
+					MountPoint: "/mnt/ebs",
+					FileSystem: model.Ext4,
+					Options:    "defaults",
+					Pass:       2,
+				},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Skip + Fstab Not Managed",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						MountPoint: "/mnt/ebs",
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Fstab:         []*model.FstabEntry{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "RAID Array Is Not Required During Boot",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/md/data": {
+						Fs:         model.Xfs,
+						Label:      "data",
+						MountPoint: "/mnt/data",
+						Raid: &config.Raid{
+							Level:   model.Raid0,
+							Devices: []string{"/dev/xvdf", "/dev/xvdg"},
+						},
+						Options: config.Options{
+							Fstab: true,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/md/data": {
+					Name:       "/dev/md/data",
+					FileSystem: model.Xfs,
+					Label:      "data",
+				},
+			},
+			Fstab:     []*model.FstabEntry{},
+			CmpOption: cmp.AllowUnexported(action.UpdateFstabEntryAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewUpdateFstabEntryAction(backend.FstabPath, &model.FstabEntry{
+					Source:     "LABEL=data",
+					MountPoint: "/mnt/data",
+					FileSystem: model.Xfs,
+					Options:    "defaults,nofail,x-systemd.requires=/dev/xvdf,x-systemd.requires=/dev/xvdg",
+					Pass:       config.DefaultFsckPass,
+				}, nil).SetMode(config.DefaultMode).SetDevice("/dev/md/data"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "LUKS Device Is Not Required During Boot",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/mapper/secure": {
+						Fs:         model.Ext4,
+						Label:      "secure",
+						MountPoint: "/mnt/secure",
+						Options: config.Options{
+							Fstab: true,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/mapper/secure": {
+					Name:       "/dev/mapper/secure",
+					FileSystem: model.Ext4,
+					Label:      "secure",
+				},
+			},
+			Fstab:     []*model.FstabEntry{},
+			CmpOption: cmp.AllowUnexported(action.UpdateFstabEntryAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewUpdateFstabEntryAction(backend.FstabPath, &model.FstabEntry{
+					Source:     "LABEL=secure",
+					MountPoint: "/mnt/secure",
+					FileSystem: model.Ext4,
+					Options:    "defaults,nofail",
+					Pass:       config.DefaultFsckPass,
+				}, nil).SetMode(config.DefaultMode).SetDevice("/dev/mapper/secure"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "File System Without UUID",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						MountPoint: "/mnt/ebs",
+						Options: config.Options{
+							Fstab: true,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name: "/dev/xvdf",
+				},
+			},
+			Fstab:         []*model.FstabEntry{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Can not reference a file system with no UUID in /etc/fstab"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackend(subtest.Devices)
+			lfb := backend.NewMockLinuxFileBackendWithFstab(nil, subtest.Fstab)
+			ufl := NewUpdateFstabLayer(ldb, lfb)
+			actions, err := ufl.Modify(subtest.Config)
+			utils.CheckError("ufl.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ufl.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestUpdateFstabLayerValidate(t *testing.T) {
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"/dev/xvdf": {
+				Fs:         model.Ext4,
+				Label:      "stateful",
+				MountPoint: "/mnt/ebs",
+				Options: config.Options{
+					Fstab: true,
+				},
+			},
+		},
+	}
+	devices := map[string]*model.BlockDevice{
+		"/dev/xvdf": {
+			Name:       "/dev/xvdf",
+			FileSystem: model.Ext4,
+			Label:      "stateful",
+		},
+	}
+	subtests := []struct {
+		Name          string
+		Fstab         []*model.FstabEntry
+		ExpectedError error
+	}{
+		{
+			Name: "Entry Matches Configuration",
+			Fstab: []*model.FstabEntry{
+				{Source: "LABEL=stateful", MountPoint: "/mnt/ebs", FileSystem: model.Ext4, Options: "defaults", Pass: 2},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Entry Does Not Match Configuration",
+			Fstab: []*model.FstabEntry{
+				{Source: "LABEL=stateful", MountPoint: "/mnt/ebs", FileSystem: model.Ext4, Options: "defaults", Pass: 1},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed fstab validation checks. Expected='LABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t2', Actual='LABEL=stateful\t/mnt/ebs\text4\tdefaults\t0\t1'"),
+		},
+		{
+			Name:          "Entry Does Not Exist",
+			Fstab:         []*model.FstabEntry{},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed fstab validation checks. No entry found for /mnt/ebs"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackend(devices)
+			lfb := backend.NewMockLinuxFileBackendWithFstab(nil, subtest.Fstab)
+			ufl := NewUpdateFstabLayer(ldb, lfb)
+			err := ufl.Validate(c)
+			utils.CheckError("ufl.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestUpdateFstabLayerShouldProcess(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		ExpectedValue bool
+	}{
+		{
+			Name: "Fstab Managed By Defaults",
+			Config: &config.Config{
+				Defaults: config.Options{
+					Fstab: true,
+				},
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/ebs",
+					},
+				},
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "Fstab Managed + No Mount Point",
+			Config: &config.Config{
+				Defaults: config.Options{
+					Fstab: true,
+				},
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {},
+				},
+			},
+			ExpectedValue: false,
+		},
+		{
+			Name: "Fstab Not Managed",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						MountPoint: "/mnt/ebs",
+					},
+				},
+			},
+			ExpectedValue: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ufl := NewUpdateFstabLayer(nil, nil)
+			value := ufl.ShouldProcess(subtest.Config)
+			utils.CheckOutput("ufl.ShouldProcess()", t, subtest.ExpectedValue, value)
+		})
+	}
+}
@@ -24,7 +24,8 @@ func (fdl *LabelDeviceLayer) From(c *config.Config) error {
 
 func (fdl *LabelDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Label) == 0 {
 			continue
 		}
@@ -43,14 +44,15 @@ func (fdl *LabelDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
 			return nil, err
 		}
 		for _, la := range las {
-			actions = append(actions, la.SetMode(mode))
+			actions = append(actions, la.SetMode(mode).SetDevice(name))
 		}
 	}
 	return actions, nil
 }
 
 func (fdl *LabelDeviceLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Label) == 0 {
 			continue
 		}
@@ -43,7 +43,7 @@ func TestLabelDeviceLayerModify(t *testing.T) {
 				service.Ext4Service{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewLabelDeviceAction("/dev/xvdf", "label", service.NewExt4Service(nil)).SetMode(config.DefaultMode),
+				action.NewLabelDeviceAction("/dev/xvdf", "label", service.NewExt4Service(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -71,8 +71,8 @@ func TestLabelDeviceLayerModify(t *testing.T) {
 				service.XfsService{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewUnmountDeviceAction("/dev/xvdf", "/mnt/foo", nil).SetMode(config.DefaultMode),
-				action.NewLabelDeviceAction("/dev/xvdf", "label", service.NewXfsService(nil)).SetMode(config.DefaultMode),
+				action.NewUnmountDeviceAction("/dev/xvdf", "/mnt/foo", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+				action.NewLabelDeviceAction("/dev/xvdf", "label", service.NewXfsService(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
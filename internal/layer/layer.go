@@ -1,13 +1,16 @@
 package layer
 
 import (
-	"log"
+	"fmt"
 	"math"
+	"reflect"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/reecetech/ebs-bootstrap/internal/action"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/report"
 )
 
 const (
@@ -26,9 +29,47 @@ type LayerExecutor interface {
 	Execute(layers []Layer) error
 }
 
+// The name of a layer is derived from its type (e.g. FormatDeviceLayer)
+func Name(layer Layer) string {
+	t := reflect.TypeOf(layer)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// Report each action that a layer proposes, prior to execution
+func reportActions(r report.Reporter, layer Layer, actions []action.Action) {
+	for _, a := range actions {
+		e := action.NewEvent(model.ActionProposed, a)
+		e.Layer = Name(layer)
+		r.Report(e)
+	}
+}
+
+type signatureBackend interface {
+	GetSignatures(bd *model.BlockDevice) []*model.Signature
+	Wipe(bd *model.BlockDevice, signatures []*model.Signature) action.Action
+}
+
+// A device without a recognised file system is not necessarily empty, as it can
+// still hold a partition table or the remnants of a structure that blkid does not
+// recognise. Any remaining signature is only erased when explicitly permitted
+func wipeSignatures(sb signatureBackend, bd *model.BlockDevice, allowWipe bool, operation string) (action.Action, error) {
+	signatures := sb.GetSignatures(bd)
+	if len(signatures) == 0 {
+		return nil, nil
+	}
+	if !allowWipe {
+		return nil, fmt.Errorf("🔴 %s: Can not %s a device with existing signatures: %s. Set allowWipe to erase them", bd.Name, operation, model.JoinSignatures(signatures))
+	}
+	return sb.Wipe(bd, signatures), nil
+}
+
 type ExponentialBackoffLayerExecutor struct {
 	backoff        backoff.BackOff
 	actionExecutor action.ActionExecutor
+	reporter       report.Reporter
 	config         *config.Config
 }
 
@@ -46,7 +87,7 @@ func DefaultExponentialBackoffParameters() *ExponentialBackoffParameters {
 	}
 }
 
-func NewExponentialBackoffLayerExecutor(c *config.Config, ae action.ActionExecutor, ebp *ExponentialBackoffParameters) *ExponentialBackoffLayerExecutor {
+func NewExponentialBackoffLayerExecutor(c *config.Config, ae action.ActionExecutor, r report.Reporter, ebp *ExponentialBackoffParameters) *ExponentialBackoffLayerExecutor {
 	// Cast Multiplier and MaxRetries to float64 for use in the backoff calculation
 	m := float64(ebp.Multiplier)
 	mr := float64(ebp.MaxRetries)
@@ -65,6 +106,7 @@ func NewExponentialBackoffLayerExecutor(c *config.Config, ae action.ActionExecut
 	return &ExponentialBackoffLayerExecutor{
 		backoff:        bo,
 		actionExecutor: ae,
+		reporter:       r,
 		config:         c,
 	}
 }
@@ -74,6 +116,7 @@ func (le *ExponentialBackoffLayerExecutor) Execute(layers []Layer) error {
 		if !layer.ShouldProcess(le.config) {
 			continue
 		}
+		le.reporter.Report(&model.Event{Kind: model.LayerStarted, Layer: Name(layer)})
 		err := layer.From(le.config)
 		if err != nil {
 			return err
@@ -85,8 +128,9 @@ func (le *ExponentialBackoffLayerExecutor) Execute(layers []Layer) error {
 		// Only print warning if actions are detected and a valid warning
 		// message is provided
 		if warning := layer.Warning(); len(actions) > 0 && warning != DisabledWarning {
-			log.Printf("🟠 %s", warning)
+			le.reporter.Report(&model.Event{Kind: model.LayerWarning, Layer: Name(layer), Message: warning})
 		}
+		reportActions(le.reporter, layer, actions)
 		err = le.actionExecutor.Execute(actions)
 		if err != nil {
 			return err
@@ -97,8 +141,10 @@ func (le *ExponentialBackoffLayerExecutor) Execute(layers []Layer) error {
 			return le.validate(layer)
 		}, le.backoff)
 		if err != nil {
+			le.reporter.Report(&model.Event{Kind: model.ValidationFailed, Layer: Name(layer), Error: err.Error()})
 			return err
 		}
+		le.reporter.Report(&model.Event{Kind: model.ValidationPassed, Layer: Name(layer)})
 	}
 	return nil
 }
@@ -114,3 +160,44 @@ func (le *ExponentialBackoffLayerExecutor) validate(layer Layer) error {
 	}
 	return layer.Validate(le.config)
 }
+
+// PlanLayerExecutor processes each layer once, without validating the outcome of
+// its actions. It is paired with an action executor that records actions, rather
+// than approving them, so that the complete set of changes can be presented
+// without failing at the first action
+type PlanLayerExecutor struct {
+	actionExecutor action.ActionExecutor
+	reporter       report.Reporter
+	config         *config.Config
+}
+
+func NewPlanLayerExecutor(c *config.Config, ae action.ActionExecutor, r report.Reporter) *PlanLayerExecutor {
+	return &PlanLayerExecutor{
+		actionExecutor: ae,
+		reporter:       r,
+		config:         c,
+	}
+}
+
+func (le *PlanLayerExecutor) Execute(layers []Layer) error {
+	for _, layer := range layers {
+		if !layer.ShouldProcess(le.config) {
+			continue
+		}
+		le.reporter.Report(&model.Event{Kind: model.LayerStarted, Layer: Name(layer)})
+		err := layer.From(le.config)
+		if err != nil {
+			return err
+		}
+		actions, err := layer.Modify(le.config)
+		if err != nil {
+			return err
+		}
+		reportActions(le.reporter, layer, actions)
+		err = le.actionExecutor.Execute(actions)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
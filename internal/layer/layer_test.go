@@ -7,6 +7,8 @@ import (
 
 	"github.com/reecetech/ebs-bootstrap/internal/action"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/report"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
 
@@ -46,7 +48,7 @@ func (ml *MockLayer) ShouldProcess(c *config.Config) bool {
 }
 
 func TestExponentialBackoffLayerExecutor(t *testing.T) {
-	mae := action.NewDefaultActionExecutor()
+	mae := action.NewDefaultActionExecutor(report.NewMockReporter())
 	// Lets generate ExponentialBackoffParameters with a custom
 	// InitialInterval of 10 ms. We do not want to slow down the test suite
 	// with an excessively long initial interval
@@ -116,7 +118,7 @@ func TestExponentialBackoffLayerExecutor(t *testing.T) {
 				validate:      subtest.Validate,
 				shouldProcess: true,
 			}
-			eb := NewExponentialBackoffLayerExecutor(nil, mae, ebp)
+			eb := NewExponentialBackoffLayerExecutor(nil, mae, report.NewMockReporter(), ebp)
 			err := eb.Execute([]Layer{ml})
 			utils.CheckError("eb.Execute()", t, subtest.ExpectedError, err)
 		})
@@ -134,7 +136,105 @@ func TestExponentialBackoffLayerExecutorShouldProcess(t *testing.T) {
 		shouldProcess: false,
 	}
 	debp := DefaultExponentialBackoffParameters()
-	eb := NewExponentialBackoffLayerExecutor(nil, nil, debp)
+	eb := NewExponentialBackoffLayerExecutor(nil, nil, report.NewMockReporter(), debp)
 	err := eb.Execute([]Layer{ml})
 	utils.CheckError("eb.Execute()", t, nil, err)
 }
+
+func TestExponentialBackoffLayerExecutorEvents(t *testing.T) {
+	ebp := &ExponentialBackoffParameters{
+		InitialInterval: 10 * time.Millisecond,
+		Multiplier:      2,
+		MaxRetries:      1,
+	}
+	subtests := []struct {
+		Name           string
+		Validate       *utils.MockIncrementError
+		ExpectedOutput []*model.Event
+	}{
+		{
+			Name:     "Validation Passed",
+			Validate: utils.NewMockIncrementError("Validate()", utils.SuccessUntilTrigger, MaxUint32),
+			ExpectedOutput: []*model.Event{
+				{Kind: model.LayerStarted, Layer: "MockLayer"},
+				{Kind: model.ValidationPassed, Layer: "MockLayer"},
+			},
+		},
+		{
+			Name:     "Validation Failed",
+			Validate: utils.NewMockIncrementError("Validate()", utils.SuccessUntilTrigger, 1),
+			ExpectedOutput: []*model.Event{
+				{Kind: model.LayerStarted, Layer: "MockLayer"},
+				{Kind: model.ValidationFailed, Layer: "MockLayer", Error: "🔴 Validate(): Type=SuccessUntilTrigger, Increment=1, Trigger=1"},
+			},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ml := &MockLayer{
+				from:          utils.NewMockIncrementError("From()", utils.SuccessUntilTrigger, MaxUint32),
+				modify:        utils.NewMockIncrementError("Modify()", utils.SuccessUntilTrigger, MaxUint32),
+				validate:      subtest.Validate,
+				shouldProcess: true,
+			}
+			mr := report.NewMockReporter()
+			eb := NewExponentialBackoffLayerExecutor(nil, action.NewDefaultActionExecutor(mr), mr, ebp)
+			_ = eb.Execute([]Layer{ml})
+			utils.CheckOutput("mr.Events", t, subtest.ExpectedOutput, mr.Events)
+		})
+	}
+}
+
+func TestPlanLayerExecutor(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		From          *utils.MockIncrementError
+		Modify        *utils.MockIncrementError
+		Validate      *utils.MockIncrementError
+		ExpectedError error
+	}{
+		{
+			Name:          "Success",
+			From:          utils.NewMockIncrementError("From()", utils.SuccessUntilTrigger, MaxUint32),
+			Modify:        utils.NewMockIncrementError("Modify()", utils.SuccessUntilTrigger, MaxUint32),
+			Validate:      utils.NewMockIncrementError("Validate()", utils.SuccessUntilTrigger, MaxUint32),
+			ExpectedError: nil,
+		},
+		{
+			Name:          "From() - Failure on First Call",
+			From:          utils.NewMockIncrementError("From()", utils.SuccessUntilTrigger, 1),
+			Modify:        utils.NewMockIncrementError("Modify()", utils.SuccessUntilTrigger, MaxUint32),
+			Validate:      utils.NewMockIncrementError("Validate()", utils.SuccessUntilTrigger, MaxUint32),
+			ExpectedError: fmt.Errorf("🔴 From(): Type=SuccessUntilTrigger, Increment=1, Trigger=1"),
+		},
+		{
+			Name:          "Modify() - Failure on First Call",
+			From:          utils.NewMockIncrementError("From()", utils.SuccessUntilTrigger, MaxUint32),
+			Modify:        utils.NewMockIncrementError("Modify()", utils.SuccessUntilTrigger, 1),
+			Validate:      utils.NewMockIncrementError("Validate()", utils.SuccessUntilTrigger, MaxUint32),
+			ExpectedError: fmt.Errorf("🔴 Modify(): Type=SuccessUntilTrigger, Increment=1, Trigger=1"),
+		},
+		// Validate() is never invoked by the plan executor, therefore a failing
+		// validation should not surface as an error
+		{
+			Name:          "Validate() - Never Invoked",
+			From:          utils.NewMockIncrementError("From()", utils.SuccessUntilTrigger, MaxUint32),
+			Modify:        utils.NewMockIncrementError("Modify()", utils.SuccessUntilTrigger, MaxUint32),
+			Validate:      utils.NewMockIncrementError("Validate()", utils.SuccessUntilTrigger, 1),
+			ExpectedError: nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ml := &MockLayer{
+				from:          subtest.From,
+				modify:        subtest.Modify,
+				validate:      subtest.Validate,
+				shouldProcess: true,
+			}
+			ple := NewPlanLayerExecutor(nil, action.NewPlanActionExecutor(), report.NewMockReporter())
+			err := ple.Execute([]Layer{ml})
+			utils.CheckError("ple.Execute()", t, subtest.ExpectedError, err)
+		})
+	}
+}
@@ -2,10 +2,12 @@ package layer
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/reecetech/ebs-bootstrap/internal/action"
 	"github.com/reecetech/ebs-bootstrap/internal/backend"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 )
 
 type CreateLogicalVolumeLayer struct {
@@ -20,49 +22,48 @@ func NewCreateLogicalVolumeLayer(lb backend.LvmBackend) *CreateLogicalVolumeLaye
 
 func (cvgl *CreateLogicalVolumeLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	// A logical volume that consumes the free space of its volume group must
+	// be created after every other logical volume of that volume group
+	deferred := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
+		lvName := c.GetLogicalVolume(name)
 
 		lvs, err := cvgl.lvmBackend.SearchLogicalVolumes(cd.Lvm)
 		if err != nil {
 			return nil, err
 		}
-		if len(lvs) == 1 {
-			if lvs[0].Name == cd.Lvm {
-				continue
-			}
-			return nil, fmt.Errorf("🔴 %s: Volume group %s already has logical volume %s associated", name, cd.Lvm, lvs[0].Name)
-		}
-		if len(lvs) > 1 {
-			return nil, fmt.Errorf("🔴 %s: Cannot manage volume group %s with more than one logical volume associated", name, cd.Lvm)
+		if slices.ContainsFunc(lvs, func(lv *model.LogicalVolume) bool { return lv.Name == lvName }) {
+			continue
 		}
 
 		mode := c.GetMode(name)
-		a := cvgl.lvmBackend.CreateLogicalVolume(cd.Lvm, cd.Lvm, c.GetLvmConsumption(name))
-		actions = append(actions, a.SetMode(mode))
+		size, err := c.GetLogicalVolumeSize(name)
+		if err != nil {
+			return nil, err
+		}
+		a := cvgl.lvmBackend.CreateLogicalVolume(lvName, cd.Lvm, size)
+		if size.Unit == model.FreePercent {
+			deferred = append(deferred, a.SetMode(mode).SetDevice(name))
+			continue
+		}
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
 	}
-	return actions, nil
+	return append(actions, deferred...), nil
 }
 
 func (cvgl *CreateLogicalVolumeLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
-		lvs, err := cvgl.lvmBackend.SearchLogicalVolumes(cd.Lvm)
-		if err != nil {
-			return err
-		}
-		if len(lvs) == 1 {
-			if lvs[0].Name == cd.Lvm {
-				continue
-			}
-			return fmt.Errorf("🔴 %s: Failed to validate logical volume. Expected=%s, Actual=%s", name, cd.Lvm, lvs[0].Name)
-		}
-		if len(lvs) > 1 {
-			return fmt.Errorf("🔴 %s: ailed to validate logical volume. #(Logical Volume) Expected=%d, Actual=%d", name, 1, len(lvs))
+		lvName := c.GetLogicalVolume(name)
+		if _, err := cvgl.lvmBackend.GetLogicalVolume(lvName, cd.Lvm); err != nil {
+			return fmt.Errorf("🔴 %s: Failed to validate logical volume. Logical volume %s does not exist in volume group %s", name, lvName, cd.Lvm)
 		}
 	}
 	return nil
@@ -21,12 +21,14 @@ func NewActivateLogicalVolumeLayer(lb backend.LvmBackend) *ActivateLogicalVolume
 
 func (cvgl *ActivateLogicalVolumeLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
+		lvName := c.GetLogicalVolume(name)
 
-		lv, err := cvgl.lvmBackend.GetLogicalVolume(cd.Lvm, cd.Lvm)
+		lv, err := cvgl.lvmBackend.GetLogicalVolume(lvName, cd.Lvm)
 		if err != nil {
 			return nil, err
 		}
@@ -40,8 +42,8 @@ func (cvgl *ActivateLogicalVolumeLayer) Modify(c *config.Config) ([]action.Actio
 		}
 
 		mode := c.GetMode(name)
-		a := cvgl.lvmBackend.ActivateLogicalVolume(cd.Lvm, cd.Lvm)
-		actions = append(actions, a.SetMode(mode))
+		a := cvgl.lvmBackend.ActivateLogicalVolume(lvName, cd.Lvm)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
 	}
 	return actions, nil
 }
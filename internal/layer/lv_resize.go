@@ -20,14 +20,20 @@ func NewResizeLogicalVolumeLayer(lb backend.LvmBackend) *ResizeLogicalVolumeLaye
 
 func (rpvl *ResizeLogicalVolumeLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
+		lvName := c.GetLogicalVolume(name)
 		if !c.GetResize(name) {
 			continue
 		}
-		shouldResize, err := rpvl.lvmBackend.ShouldResizeLogicalVolume(cd.Lvm, cd.Lvm, c.GetLvmConsumption(name))
+		size, err := c.GetLogicalVolumeSize(name)
+		if err != nil {
+			return nil, err
+		}
+		shouldResize, err := rpvl.lvmBackend.ShouldResizeLogicalVolume(lvName, cd.Lvm, size)
 		if err != nil {
 			return nil, err
 		}
@@ -35,26 +41,32 @@ func (rpvl *ResizeLogicalVolumeLayer) Modify(c *config.Config) ([]action.Action,
 			continue
 		}
 		mode := c.GetMode(name)
-		a := rpvl.lvmBackend.ResizeLogicalVolume(cd.Lvm, cd.Lvm, c.GetLvmConsumption(name))
-		actions = append(actions, a.SetMode(mode))
+		a := rpvl.lvmBackend.ResizeLogicalVolume(lvName, cd.Lvm, size)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
 	}
 	return actions, nil
 }
 
 func (rpvl *ResizeLogicalVolumeLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
+		lvName := c.GetLogicalVolume(name)
 		if !c.GetResize(name) {
 			continue
 		}
-		shouldResize, err := rpvl.lvmBackend.ShouldResizeLogicalVolume(cd.Lvm, cd.Lvm, c.GetLvmConsumption(name))
+		size, err := c.GetLogicalVolumeSize(name)
+		if err != nil {
+			return err
+		}
+		shouldResize, err := rpvl.lvmBackend.ShouldResizeLogicalVolume(lvName, cd.Lvm, size)
 		if err != nil {
 			return err
 		}
 		if shouldResize {
-			return fmt.Errorf("🔴 %s: Failed resize validation checks. Logical volume %s still needs to be resized", name, name)
+			return fmt.Errorf("🔴 %s: Failed resize validation checks. Logical volume %s still needs to be resized", name, lvName)
 		}
 	}
 	return nil
@@ -69,7 +81,8 @@ func (rpvl *ResizeLogicalVolumeLayer) From(c *config.Config) error {
 }
 
 func (rpvl *ResizeLogicalVolumeLayer) ShouldProcess(c *config.Config) bool {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) > 0 && c.GetResize(name) {
 			return true
 		}
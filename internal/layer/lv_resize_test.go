@@ -1,9 +1,13 @@
 package layer
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/datastructures"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
 
@@ -57,3 +61,25 @@ func TestResizeLogicalVolumeLayerShouldProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestResizeLogicalVolumeLayerValidate(t *testing.T) {
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"/dev/xvdf": {
+				Lvm: "ifmx-etc",
+				Options: config.Options{
+					Resize: true,
+				},
+			},
+		},
+	}
+	lg := datastructures.NewLvmGraph()
+	utils.ExpectErr("lg.AddDevice()", t, false, lg.AddDevice("/dev/xvdf", 100<<30))
+	utils.ExpectErr("lg.AddPhysicalVolume()", t, false, lg.AddPhysicalVolume("/dev/xvdf", 100<<30))
+	utils.ExpectErr("lg.AddVolumeGroup()", t, false, lg.AddVolumeGroup("ifmx-etc", "/dev/xvdf", 100<<30))
+	utils.ExpectErr("lg.AddLogicalVolume()", t, false, lg.AddLogicalVolume("ifmx-etc", "ifmx-etc", model.LogicalVolumeActive, 50<<30))
+
+	rlvl := NewResizeLogicalVolumeLayer(backend.NewMockLinuxLvmBackend(lg))
+	err := rlvl.Validate(c)
+	utils.CheckError("rlvl.Validate()", t, fmt.Errorf("🔴 /dev/xvdf: Failed resize validation checks. Logical volume ifmx-etc still needs to be resized"), err)
+}
@@ -3,7 +3,12 @@ package layer
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/datastructures"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
 
@@ -43,3 +48,39 @@ func TestCreateLogicalVolumeLayerShouldProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateLogicalVolumeLayerModify(t *testing.T) {
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"data": {
+				Lvm:  "db",
+				Size: "100%FREE",
+			},
+			"logs": {
+				Lvm:  "db",
+				Size: "20GiB",
+			},
+			"wal": {
+				Lvm:  "db",
+				Size: "10%VG",
+			},
+		},
+		VolumeGroups: map[string]config.VolumeGroup{
+			"db": {Devices: []string{"/dev/xvdf"}},
+		},
+	}
+	lg := datastructures.NewLvmGraph()
+	utils.ExpectErr("lg.AddDevice()", t, false, lg.AddDevice("/dev/xvdf", 100<<30))
+	utils.ExpectErr("lg.AddPhysicalVolume()", t, false, lg.AddPhysicalVolume("/dev/xvdf", 100<<30))
+	utils.ExpectErr("lg.AddVolumeGroup()", t, false, lg.AddVolumeGroup("db", "/dev/xvdf", 100<<30))
+	utils.ExpectErr("lg.AddLogicalVolume()", t, false, lg.AddLogicalVolume("wal", "db", model.LogicalVolumeActive, 10<<30))
+
+	clvl := NewCreateLogicalVolumeLayer(backend.NewMockLinuxLvmBackend(lg))
+	actions, err := clvl.Modify(c)
+	utils.CheckError("clvl.Modify()", t, nil, err)
+	// The logical volume that consumes the remaining free space is created last
+	utils.CheckOutput("clvl.Modify()", t, []action.Action{
+		action.NewCreateLogicalVolumeAction("logs", model.LvmSize{Value: 20 << 30, Unit: model.Bytes}, "db", nil).SetMode(config.DefaultMode).SetDevice("logs"),
+		action.NewCreateLogicalVolumeAction("data", model.LvmSize{Value: 100, Unit: model.FreePercent}, "db", nil).SetMode(config.DefaultMode).SetDevice("data"),
+	}, actions, cmp.AllowUnexported(action.CreateLogicalVolumeAction{}))
+}
@@ -2,6 +2,7 @@ package layer
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/reecetech/ebs-bootstrap/internal/action"
 	"github.com/reecetech/ebs-bootstrap/internal/backend"
@@ -29,9 +30,17 @@ func (fdl *MountDeviceLayer) From(c *config.Config) error {
 	return fdl.fileBackend.From(c)
 }
 
+// Devices are mounted in the order of the depth of their mount points, so that a
+// device whose mount point is nested within that of another device is mounted
+// after it. The mount of a parent hides the contents of its mount point, so any
+// of its children are unmounted beforehand and mounted again afterwards
 func (fdl *MountDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
-	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	umounts := make([]*umountAction, 0)
+	mounts := make([]action.Action, 0)
+	// The mount points that are mounted in the current pass
+	mounted := make([]string, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
@@ -44,17 +53,32 @@ func (fdl *MountDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
 			return nil, fmt.Errorf("🔴 %s: Can not mount a device with no file system", bd.Name)
 		}
 
+		mode := c.GetMode(name)
+		mo := c.GetMountOptions(name)
+		if fdl.hasMountedAncestor(cd.MountPoint, mounted) {
+			// The mount point only exists on the file system of the parent once it
+			// has been mounted. Therefore, it is created again before the mount
+			if len(bd.MountPoint) > 0 {
+				a := fdl.deviceBackend.Umount(bd).SetMode(mode).SetDevice(name)
+				umounts = append(umounts, &umountAction{mountPoint: bd.MountPoint, action: a})
+			}
+			mounts = append(mounts,
+				fdl.fileBackend.CreateDirectory(cd.MountPoint).SetMode(mode).SetDevice(name),
+				fdl.deviceBackend.Mount(bd, cd.MountPoint, mo).SetMode(mode).SetDevice(name),
+			)
+			mounted = append(mounted, cd.MountPoint)
+			continue
+		}
+
 		d, err := fdl.fileBackend.GetDirectory(cd.MountPoint)
 		if err != nil {
 			return nil, fmt.Errorf("🔴 %s: %s must exist as a directory before it can be mounted", name, cd.MountPoint)
 		}
 
-		mode := c.GetMode(name)
-		mo := c.GetMountOptions(name)
 		if bd.MountPoint == d.Path {
 			if c.GetRemount(name) {
-				a := fdl.deviceBackend.Remount(bd, cd.MountPoint, mo).SetMode(mode)
-				actions = append(actions, a)
+				a := fdl.deviceBackend.Remount(bd, cd.MountPoint, mo).SetMode(mode).SetDevice(name)
+				mounts = append(mounts, a)
 			}
 		} else {
 			if fdl.fileBackend.IsMount(cd.MountPoint) {
@@ -62,18 +86,43 @@ func (fdl *MountDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
 			}
 			// If mount point already exists, then lets unmount it first
 			if len(bd.MountPoint) > 0 {
-				a := fdl.deviceBackend.Umount(bd).SetMode(mode)
-				actions = append(actions, a)
+				a := fdl.deviceBackend.Umount(bd).SetMode(mode).SetDevice(name)
+				umounts = append(umounts, &umountAction{mountPoint: bd.MountPoint, action: a})
 			}
-			a := fdl.deviceBackend.Mount(bd, cd.MountPoint, mo).SetMode(mode)
-			actions = append(actions, a)
+			a := fdl.deviceBackend.Mount(bd, cd.MountPoint, mo).SetMode(mode).SetDevice(name)
+			mounts = append(mounts, a)
+			mounted = append(mounted, cd.MountPoint)
 		}
 	}
-	return actions, nil
+	// Devices are unmounted in the reverse depth order of their current mount points,
+	// so that a device is never unmounted while another device is mounted within it
+	slices.SortStableFunc(umounts, func(a, b *umountAction) int {
+		return model.PathDepth(b.mountPoint) - model.PathDepth(a.mountPoint)
+	})
+	actions := make([]action.Action, 0, len(umounts)+len(mounts))
+	for _, u := range umounts {
+		actions = append(actions, u.action)
+	}
+	return append(actions, mounts...), nil
+}
+
+type umountAction struct {
+	mountPoint string
+	action     action.Action
+}
+
+func (fdl *MountDeviceLayer) hasMountedAncestor(mountPoint string, mounted []string) bool {
+	for _, m := range mounted {
+		if model.IsNestedPath(mountPoint, m) {
+			return true
+		}
+	}
+	return false
 }
 
 func (fdl *MountDeviceLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
@@ -61,7 +61,7 @@ func TestMountDeviceLayerModify(t *testing.T) {
 				service.LinuxDeviceService{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewMountDeviceAction("/dev/xvdf", "/mnt/foo", model.Ext4, "nouuid", nil).SetMode(config.DefaultMode),
+				action.NewMountDeviceAction("/dev/xvdf", "/mnt/foo", model.Ext4, "nouuid", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -143,8 +143,113 @@ func TestMountDeviceLayerModify(t *testing.T) {
 				service.LinuxDeviceService{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewUnmountDeviceAction("/dev/xvdf", "/mnt/bar", nil).SetMode(config.DefaultMode),
-				action.NewMountDeviceAction("/dev/xvdf", "/mnt/foo", model.Ext4, "nouuid", nil).SetMode(config.DefaultMode),
+				action.NewUnmountDeviceAction("/dev/xvdf", "/mnt/bar", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+				action.NewMountDeviceAction("/dev/xvdf", "/mnt/foo", model.Ext4, "nouuid", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Mount Nested Block Devices in Order of Depth",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvda": {
+						Fs:         model.Ext4,
+						MountPoint: "/data/logs",
+					},
+					"/dev/xvdb": {
+						Fs:         model.Ext4,
+						MountPoint: "/data",
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvda": {
+					Name:       "/dev/xvda",
+					FileSystem: model.Ext4,
+				},
+				"/dev/xvdb": {
+					Name:       "/dev/xvdb",
+					FileSystem: model.Ext4,
+				},
+			},
+			Files: map[string]*model.File{
+				"/data/logs": {
+					Path:     "/data/logs",
+					Type:     model.Directory,
+					DeviceId: 1000,
+					InodeNo:  2000,
+				},
+				"/data": {
+					Path:     "/data",
+					Type:     model.Directory,
+					DeviceId: 1000,
+					InodeNo:  2500,
+				},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.CreateDirectoryAction{},
+				action.MountDeviceAction{},
+				service.LinuxDeviceService{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewMountDeviceAction("/dev/xvdb", "/data", model.Ext4, config.DefaultMountOptions, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdb"),
+				action.NewCreateDirectoryAction("/data/logs", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvda"),
+				action.NewMountDeviceAction("/dev/xvda", "/data/logs", model.Ext4, config.DefaultMountOptions, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvda"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Remount Nested Block Device After Parent is Mounted",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvda": {
+						Fs:         model.Ext4,
+						MountPoint: "/data/logs",
+					},
+					"/dev/xvdb": {
+						Fs:         model.Ext4,
+						MountPoint: "/data",
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvda": {
+					Name:       "/dev/xvda",
+					FileSystem: model.Ext4,
+					MountPoint: "/data/logs",
+				},
+				"/dev/xvdb": {
+					Name:       "/dev/xvdb",
+					FileSystem: model.Ext4,
+					MountPoint: "/mnt",
+				},
+			},
+			Files: map[string]*model.File{
+				"/data/logs": {
+					Path:     "/data/logs",
+					Type:     model.Directory,
+					DeviceId: 3000,
+					InodeNo:  2,
+				},
+				"/data": {
+					Path:     "/data",
+					Type:     model.Directory,
+					DeviceId: 1000,
+					InodeNo:  2500,
+				},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.CreateDirectoryAction{},
+				action.UnmountDeviceAction{},
+				action.MountDeviceAction{},
+				service.LinuxDeviceService{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewUnmountDeviceAction("/dev/xvda", "/data/logs", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvda"),
+				action.NewUnmountDeviceAction("/dev/xvdb", "/mnt", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdb"),
+				action.NewMountDeviceAction("/dev/xvdb", "/data", model.Ext4, config.DefaultMountOptions, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdb"),
+				action.NewCreateDirectoryAction("/data/logs", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvda"),
+				action.NewMountDeviceAction("/dev/xvda", "/data/logs", model.Ext4, config.DefaultMountOptions, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvda"),
 			},
 			ExpectedError: nil,
 		},
@@ -188,7 +293,7 @@ func TestMountDeviceLayerModify(t *testing.T) {
 				service.LinuxDeviceService{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewMountDeviceAction("/dev/xvdf", "/mnt/foo", model.Ext4, "nouuid,remount", nil).SetMode(config.DefaultMode),
+				action.NewMountDeviceAction("/dev/xvdf", "/mnt/foo", model.Ext4, "nouuid,remount", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -230,7 +335,7 @@ func TestMountDeviceLayerModify(t *testing.T) {
 				service.LinuxDeviceService{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewMountDeviceAction("/dev/xvdf", "/mnt/bar", model.Ext4, "nouuid", nil).SetMode(config.DefaultMode),
+				action.NewMountDeviceAction("/dev/xvdf", "/mnt/bar", model.Ext4, "nouuid", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -30,7 +30,8 @@ func (fdl *ChangeOwnerLayer) From(c *config.Config) error {
 
 func (fdl *ChangeOwnerLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
@@ -64,14 +65,15 @@ func (fdl *ChangeOwnerLayer) Modify(c *config.Config) ([]action.Action, error) {
 		}
 
 		mode := c.GetMode(name)
-		a := fdl.fileBackend.ChangeOwner(cd.MountPoint, uid, gid).SetMode(mode)
+		a := fdl.fileBackend.ChangeOwner(cd.MountPoint, uid, gid).SetMode(mode).SetDevice(name)
 		actions = append(actions, a)
 	}
 	return actions, nil
 }
 
 func (fdl *ChangeOwnerLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
@@ -57,7 +57,7 @@ func TestChangeOwnerLayerModify(t *testing.T) {
 			},
 			CmpOption: cmp.AllowUnexported(action.ChangeOwnerAction{}),
 			ExpectedOutput: []action.Action{
-				action.NewChangeOwnerAction("/mnt/foo", model.UserId(1500), model.GroupId(2000), nil).SetMode(config.DefaultMode),
+				action.NewChangeOwnerAction("/mnt/foo", model.UserId(1500), model.GroupId(2000), nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 		},
 		{
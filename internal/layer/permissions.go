@@ -24,7 +24,8 @@ func (fdl *ChangePermissionsLayer) From(c *config.Config) error {
 
 func (fdl *ChangePermissionsLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
@@ -42,14 +43,15 @@ func (fdl *ChangePermissionsLayer) Modify(c *config.Config) ([]action.Action, er
 		}
 
 		mode := c.GetMode(name)
-		a := fdl.fileBackend.ChangePermissions(cd.MountPoint, cd.Permissions).SetMode(mode)
+		a := fdl.fileBackend.ChangePermissions(cd.MountPoint, cd.Permissions).SetMode(mode).SetDevice(name)
 		actions = append(actions, a)
 	}
 	return actions, nil
 }
 
 func (fdl *ChangePermissionsLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.MountPoint) == 0 {
 			continue
 		}
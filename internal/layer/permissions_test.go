@@ -40,7 +40,7 @@ func TestChangePermissionsLayerModify(t *testing.T) {
 			},
 			CmpOption: cmp.AllowUnexported(action.ChangePermissionsAction{}),
 			ExpectedOutput: []action.Action{
-				action.NewChangePermissionsAction("/mnt/foo", model.FilePermissions(0755), nil).SetMode(config.DefaultMode),
+				action.NewChangePermissionsAction("/mnt/foo", model.FilePermissions(0755), nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -23,38 +23,47 @@ func NewCreatePhysicalVolumeLayer(db backend.DeviceBackend, lb backend.LvmBacken
 
 func (cpvl *CreatePhysicalVolumeLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
-		if len(cd.Lvm) == 0 {
+	// The logical volumes of a volume group share its physical volumes
+	visited := map[string]bool{}
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if len(cd.Lvm) == 0 || visited[cd.Lvm] {
 			continue
 		}
-		bd, err := cpvl.deviceBackend.GetBlockDevice(name)
-		if err != nil {
-			return nil, err
-		}
-		if bd.FileSystem == model.Lvm {
-			continue
+		visited[cd.Lvm] = true
+		for _, pv := range c.GetPhysicalVolumes(name) {
+			bd, err := cpvl.deviceBackend.GetBlockDevice(pv)
+			if err != nil {
+				return nil, err
+			}
+			if bd.FileSystem == model.Lvm {
+				continue
+			}
+			if bd.FileSystem != model.Unformatted {
+				return nil, fmt.Errorf("🔴 %s: Can not create a physical volume on a device with an existing %s file system", bd.Name, bd.FileSystem.String())
+			}
+			mode := c.GetMode(name)
+			a := cpvl.lvmBackend.CreatePhysicalVolume(bd.Name)
+			actions = append(actions, a.SetMode(mode).SetDevice(name))
 		}
-		if bd.FileSystem != model.Unformatted {
-			return nil, fmt.Errorf("🔴 %s: Can not create a physical volume on a device with an existing %s file system", bd.Name, bd.FileSystem.String())
-		}
-		mode := c.GetMode(name)
-		a := cpvl.lvmBackend.CreatePhysicalVolume(bd.Name)
-		actions = append(actions, a.SetMode(mode))
 	}
 	return actions, nil
 }
 
 func (cpvl *CreatePhysicalVolumeLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
-		bd, err := cpvl.deviceBackend.GetBlockDevice(name)
-		if err != nil {
-			return err
-		}
-		if bd.FileSystem != model.Lvm {
-			return fmt.Errorf("🔴 %s: Failed physical volume validation checks. Expected=%s, Actual=%s", bd.Name, model.Lvm, bd.FileSystem)
+		for _, pv := range c.GetPhysicalVolumes(name) {
+			bd, err := cpvl.deviceBackend.GetBlockDevice(pv)
+			if err != nil {
+				return err
+			}
+			if bd.FileSystem != model.Lvm {
+				return fmt.Errorf("🔴 %s: Failed physical volume validation checks. Expected=%s, Actual=%s", bd.Name, model.Lvm, bd.FileSystem)
+			}
 		}
 	}
 	return nil
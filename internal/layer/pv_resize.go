@@ -20,41 +20,50 @@ func NewResizePhysicalVolumeLayer(lb backend.LvmBackend) *ResizePhysicalVolumeLa
 
 func (rpvl *ResizePhysicalVolumeLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
-		if len(cd.Lvm) == 0 {
+	// The logical volumes of a volume group share its physical volumes
+	visited := map[string]bool{}
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if len(cd.Lvm) == 0 || visited[cd.Lvm] {
 			continue
 		}
 		if !c.GetResize(name) {
 			continue
 		}
-		shouldResize, err := rpvl.lvmBackend.ShouldResizePhysicalVolume(name)
-		if err != nil {
-			return nil, err
-		}
-		if !shouldResize {
-			continue
+		visited[cd.Lvm] = true
+		for _, pv := range c.GetPhysicalVolumes(name) {
+			shouldResize, err := rpvl.lvmBackend.ShouldResizePhysicalVolume(pv)
+			if err != nil {
+				return nil, err
+			}
+			if !shouldResize {
+				continue
+			}
+			mode := c.GetMode(name)
+			a := rpvl.lvmBackend.ResizePhysicalVolume(pv)
+			actions = append(actions, a.SetMode(mode).SetDevice(name))
 		}
-		mode := c.GetMode(name)
-		a := rpvl.lvmBackend.ResizePhysicalVolume(name)
-		actions = append(actions, a.SetMode(mode))
 	}
 	return actions, nil
 }
 
 func (rpvl *ResizePhysicalVolumeLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
 		if !c.GetResize(name) {
 			continue
 		}
-		shouldResize, err := rpvl.lvmBackend.ShouldResizePhysicalVolume(name)
-		if err != nil {
-			return err
-		}
-		if shouldResize {
-			return fmt.Errorf("🔴 %s: Failed resize validation checks. Physical volume %s still needs to be resized", name, name)
+		for _, pv := range c.GetPhysicalVolumes(name) {
+			shouldResize, err := rpvl.lvmBackend.ShouldResizePhysicalVolume(pv)
+			if err != nil {
+				return err
+			}
+			if shouldResize {
+				return fmt.Errorf("🔴 %s: Failed resize validation checks. Physical volume %s still needs to be resized", name, pv)
+			}
 		}
 	}
 	return nil
@@ -69,7 +78,8 @@ func (rpvl *ResizePhysicalVolumeLayer) From(c *config.Config) error {
 }
 
 func (rpvl *ResizePhysicalVolumeLayer) ShouldProcess(c *config.Config) bool {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) > 0 && c.GetResize(name) {
 			return true
 		}
@@ -0,0 +1,208 @@
+package layer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type CreateRaidArrayLayer struct {
+	raidBackend backend.RaidBackend
+}
+
+func NewCreateRaidArrayLayer(rb backend.RaidBackend) *CreateRaidArrayLayer {
+	return &CreateRaidArrayLayer{
+		raidBackend: rb,
+	}
+}
+
+func (cral *CreateRaidArrayLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Raid == nil {
+			continue
+		}
+		ra, err := cral.raidBackend.GetArray(name)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if ra != nil {
+			if err := validateRaidArray(name, cd.Raid, ra); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		members := 0
+		for _, device := range cd.Raid.Devices {
+			bd, err := cral.raidBackend.GetMember(device)
+			if err != nil {
+				return nil, err
+			}
+			if bd.FileSystem == model.RaidMember {
+				members++
+				continue
+			}
+			if bd.FileSystem != model.Unformatted {
+				return nil, fmt.Errorf("🔴 %s: Can not create a RAID array on a device with an existing %s file system", bd.Name, bd.FileSystem.String())
+			}
+		}
+		// An inactive array, whose members all carry a RAID superblock, is assembled
+		// rather than created. Creating it would destroy the data on the array
+		if members == len(cd.Raid.Devices) {
+			continue
+		}
+		if members > 0 {
+			return nil, fmt.Errorf("🔴 %s: Can not create a RAID array when only some of its devices are existing RAID members", name)
+		}
+		mode := c.GetMode(name)
+		for _, device := range cd.Raid.Devices {
+			bd, err := cral.raidBackend.GetMember(device)
+			if err != nil {
+				return nil, err
+			}
+			wa, err := wipeSignatures(cral.raidBackend, bd, cd.AllowWipe, "create a RAID array on")
+			if err != nil {
+				return nil, err
+			}
+			if wa != nil {
+				actions = append(actions, wa.SetMode(mode).SetDevice(name))
+			}
+		}
+		a := cral.raidBackend.CreateArray(name, cd.Raid.Level, cd.Raid.ChunkSize, cd.Raid.Devices)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
+	}
+	return actions, nil
+}
+
+func (cral *CreateRaidArrayLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Raid == nil {
+			continue
+		}
+		for _, device := range cd.Raid.Devices {
+			bd, err := cral.raidBackend.GetMember(device)
+			if err != nil {
+				return err
+			}
+			if bd.FileSystem != model.RaidMember {
+				return fmt.Errorf("🔴 %s: Failed RAID member validation checks. Expected=%s, Actual=%s", name, model.RaidMember, bd.FileSystem)
+			}
+		}
+	}
+	return nil
+}
+
+func (cral *CreateRaidArrayLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (cral *CreateRaidArrayLayer) From(c *config.Config) error {
+	return cral.raidBackend.From(c)
+}
+
+func (cral *CreateRaidArrayLayer) ShouldProcess(c *config.Config) bool {
+	return shouldProcessRaid(c)
+}
+
+type AssembleRaidArrayLayer struct {
+	raidBackend backend.RaidBackend
+}
+
+func NewAssembleRaidArrayLayer(rb backend.RaidBackend) *AssembleRaidArrayLayer {
+	return &AssembleRaidArrayLayer{
+		raidBackend: rb,
+	}
+}
+
+func (aral *AssembleRaidArrayLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Raid == nil {
+			continue
+		}
+		_, err := aral.raidBackend.GetArray(name)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		for _, device := range cd.Raid.Devices {
+			bd, err := aral.raidBackend.GetMember(device)
+			if err != nil {
+				return nil, err
+			}
+			if bd.FileSystem != model.RaidMember {
+				return nil, fmt.Errorf("🔴 %s: Can not assemble a RAID array from %s as it is not a RAID member", name, bd.Name)
+			}
+		}
+		mode := c.GetMode(name)
+		a := aral.raidBackend.AssembleArray(name, cd.Raid.Devices)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
+	}
+	return actions, nil
+}
+
+func (aral *AssembleRaidArrayLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if cd.Raid == nil {
+			continue
+		}
+		ra, err := aral.raidBackend.GetArray(name)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: Failed RAID array validation checks. Array is not active", name)
+		}
+		if err := validateRaidArray(name, cd.Raid, ra); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (aral *AssembleRaidArrayLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (aral *AssembleRaidArrayLayer) From(c *config.Config) error {
+	return aral.raidBackend.From(c)
+}
+
+func (aral *AssembleRaidArrayLayer) ShouldProcess(c *config.Config) bool {
+	return shouldProcessRaid(c)
+}
+
+func shouldProcessRaid(c *config.Config) bool {
+	for _, cd := range c.Devices {
+		if cd.Raid != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRaidArray ensures that an existing array has the level and members of its
+// configuration. ebs-bootstrap does not reshape arrays, so any deviation is an error
+func validateRaidArray(name string, r *config.Raid, ra *model.RaidArray) error {
+	if ra.Level != r.Level {
+		return fmt.Errorf("🔴 %s: RAID array has level %s, but %s was configured", name, ra.Level, r.Level)
+	}
+	expected := slices.Clone(r.Devices)
+	actual := slices.Clone(ra.Devices)
+	slices.Sort(expected)
+	slices.Sort(actual)
+	if !slices.Equal(expected, actual) {
+		return fmt.Errorf("🔴 %s: RAID array has devices [%s], but [%s] were configured", name, strings.Join(actual, ", "), strings.Join(expected, ", "))
+	}
+	return nil
+}
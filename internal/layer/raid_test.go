@@ -0,0 +1,220 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestCreateRaidArrayLayerModify(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Arrays        map[string]*model.RaidArray
+		Members       map[string]*model.BlockDevice
+		Signatures    map[string][]*model.Signature
+		AllowWipe     bool
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name:   "Create Array From Unformatted Devices",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.Unformatted},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.Unformatted},
+			},
+			CmpOption: cmp.AllowUnexported(action.CreateRaidArrayAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewCreateRaidArrayAction("scratch", model.Raid0, 0, []string{"/dev/nvme1n1", "/dev/nvme2n1"}, nil).SetMode(config.DefaultMode).SetDevice("scratch"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:   "Inactive Array Is Left For Assembly",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.RaidMember},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.RaidMember},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Active Array Matches Configuration",
+			Arrays: map[string]*model.RaidArray{
+				"scratch": {Name: "scratch", Level: model.Raid0, Devices: []string{"/dev/nvme2n1", "/dev/nvme1n1"}},
+			},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.RaidMember},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.RaidMember},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Active Array With Different Level",
+			Arrays: map[string]*model.RaidArray{
+				"scratch": {Name: "scratch", Level: model.Raid1, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}},
+			},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.RaidMember},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.RaidMember},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 scratch: RAID array has level raid1, but raid0 was configured"),
+		},
+		{
+			Name:   "Device With Existing File System",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.Unformatted},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.Ext4},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme2n1: Can not create a RAID array on a device with an existing ext4 file system"),
+		},
+		{
+			Name:   "Partial RAID Membership",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.RaidMember},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.Unformatted},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 scratch: Can not create a RAID array when only some of its devices are existing RAID members"),
+		},
+		{
+			Name:   "Device With Existing Signatures",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.Unformatted},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.Unformatted},
+			},
+			Signatures: map[string][]*model.Signature{
+				"/dev/nvme2n1": {{Type: "gpt", Offset: 0x200}},
+			},
+			AllowWipe:     false,
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme2n1: Can not create a RAID array on a device with existing signatures: gpt (offset 0x200). Set allowWipe to erase them"),
+		},
+		{
+			Name:   "Wipe Device With Existing Signatures",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.Unformatted},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.Unformatted},
+			},
+			Signatures: map[string][]*model.Signature{
+				"/dev/nvme2n1": {{Type: "gpt", Offset: 0x200}},
+			},
+			AllowWipe: true,
+			CmpOption: cmp.AllowUnexported(action.WipeDeviceAction{}, action.CreateRaidArrayAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewWipeDeviceAction("/dev/nvme2n1", []*model.Signature{{Type: "gpt", Offset: 0x200}}, nil).SetMode(config.DefaultMode).SetDevice("scratch"),
+				action.NewCreateRaidArrayAction("scratch", model.Raid0, 0, []string{"/dev/nvme1n1", "/dev/nvme2n1"}, nil).SetMode(config.DefaultMode).SetDevice("scratch"),
+			},
+			ExpectedError: nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			c := &config.Config{
+				Devices: map[string]config.Device{
+					"scratch": {
+						Raid: &config.Raid{
+							Level:   model.Raid0,
+							Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+						},
+						AllowWipe: subtest.AllowWipe,
+					},
+				},
+			}
+			lrb := backend.NewMockLinuxRaidBackendWithSignatures(subtest.Arrays, subtest.Members, subtest.Signatures)
+			cral := NewCreateRaidArrayLayer(lrb)
+			actions, err := cral.Modify(c)
+			utils.CheckError("cral.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("cral.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestAssembleRaidArrayLayerModify(t *testing.T) {
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"scratch": {
+				Raid: &config.Raid{
+					Level:   model.Raid1,
+					Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+				},
+			},
+		},
+	}
+	subtests := []struct {
+		Name          string
+		Arrays        map[string]*model.RaidArray
+		Members       map[string]*model.BlockDevice
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name:   "Assemble Inactive Array",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.RaidMember},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.RaidMember},
+			},
+			CmpOption: cmp.AllowUnexported(action.AssembleRaidArrayAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewAssembleRaidArrayAction("scratch", []string{"/dev/nvme1n1", "/dev/nvme2n1"}, nil).SetMode(config.DefaultMode).SetDevice("scratch"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Array Already Active",
+			Arrays: map[string]*model.RaidArray{
+				"scratch": {Name: "scratch", Level: model.Raid1, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}},
+			},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.RaidMember},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.RaidMember},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name:   "Device Is Not A RAID Member",
+			Arrays: map[string]*model.RaidArray{},
+			Members: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.RaidMember},
+				"/dev/nvme2n1": {Name: "/dev/nvme2n1", FileSystem: model.Unformatted},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 scratch: Can not assemble a RAID array from /dev/nvme2n1 as it is not a RAID member"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lrb := backend.NewMockLinuxRaidBackend(subtest.Arrays, subtest.Members)
+			aral := NewAssembleRaidArrayLayer(lrb)
+			actions, err := aral.Modify(c)
+			utils.CheckError("aral.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("aral.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
@@ -30,7 +30,7 @@ func (fdl *ResizeDeviceLayer) From(c *config.Config) error {
 
 func (fdl *ResizeDeviceLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name := range c.Devices {
+	for _, name := range c.GetDevices() {
 		if !c.GetResize(name) {
 			continue
 		}
@@ -52,14 +52,14 @@ func (fdl *ResizeDeviceLayer) Modify(c *config.Config) ([]action.Action, error)
 		if err != nil {
 			return nil, err
 		}
-		a = a.SetMode(mode)
+		a = a.SetMode(mode).SetDevice(name)
 		actions = append(actions, a)
 	}
 	return actions, nil
 }
 
 func (fdl *ResizeDeviceLayer) Validate(c *config.Config) error {
-	for name := range c.Devices {
+	for _, name := range c.GetDevices() {
 		if !c.GetResize(name) {
 			continue
 		}
@@ -79,7 +79,7 @@ func (fdl *ResizeDeviceLayer) Warning() string {
 }
 
 func (fdl *ResizeDeviceLayer) ShouldProcess(c *config.Config) bool {
-	for name := range c.Devices {
+	for _, name := range c.GetDevices() {
 		if c.GetResize(name) {
 			return true
 		}
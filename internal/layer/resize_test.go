@@ -80,7 +80,7 @@ func TestResizeDeviceLayerModify(t *testing.T) {
 				service.Ext4Service{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewResizeDeviceAction("/dev/xvdf", "/dev/xvdf", service.NewExt4Service(nil)).SetMode(config.DefaultMode),
+				action.NewResizeDeviceAction("/dev/xvdf", "/dev/xvdf", service.NewExt4Service(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -118,7 +118,7 @@ func TestResizeDeviceLayerModify(t *testing.T) {
 				service.XfsService{},
 			),
 			ExpectedOuput: []action.Action{
-				action.NewResizeDeviceAction("/dev/xvdf", "/mnt/foo", service.NewXfsService(nil)).SetMode(config.DefaultMode),
+				action.NewResizeDeviceAction("/dev/xvdf", "/mnt/foo", service.NewXfsService(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
 			},
 			ExpectedError: nil,
 		},
@@ -0,0 +1,88 @@
+package layer
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type ActivateSwapLayer struct {
+	swapBackend backend.SwapBackend
+}
+
+func NewActivateSwapLayer(sb backend.SwapBackend) *ActivateSwapLayer {
+	return &ActivateSwapLayer{
+		swapBackend: sb,
+	}
+}
+
+func (asl *ActivateSwapLayer) From(c *config.Config) error {
+	return asl.swapBackend.From(c)
+}
+
+// The priority of an active swap area can not be changed in place. A swap area
+// with a different priority is deactivated, and then activated again
+func (asl *ActivateSwapLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range asl.getSwapAreas(c) {
+		mode := c.GetMode(name)
+		if _, isSwapFile := c.SwapFiles[name]; isSwapFile {
+			mode = c.GetSwapFileMode(name)
+		}
+		priority := c.GetSwapPriority(name)
+
+		sa, err := asl.swapBackend.GetSwapArea(name)
+		if err != nil {
+			return nil, err
+		}
+		if sa != nil {
+			if priority == nil || sa.Priority == *priority {
+				continue
+			}
+			a := asl.swapBackend.Deactivate(name)
+			actions = append(actions, a.SetMode(mode).SetDevice(name))
+		}
+		a := asl.swapBackend.Activate(name, priority)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
+	}
+	return actions, nil
+}
+
+func (asl *ActivateSwapLayer) Validate(c *config.Config) error {
+	for _, name := range asl.getSwapAreas(c) {
+		sa, err := asl.swapBackend.GetSwapArea(name)
+		if err != nil {
+			return err
+		}
+		if sa == nil {
+			return fmt.Errorf("🔴 %s: Failed swap validation checks. Swap area is not active", name)
+		}
+		if priority := c.GetSwapPriority(name); priority != nil && sa.Priority != *priority {
+			return fmt.Errorf("🔴 %s: Failed swap priority validation checks. Expected=%d, Actual=%d", name, *priority, sa.Priority)
+		}
+	}
+	return nil
+}
+
+func (asl *ActivateSwapLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (asl *ActivateSwapLayer) ShouldProcess(c *config.Config) bool {
+	return len(asl.getSwapAreas(c)) > 0
+}
+
+// getSwapAreas returns the names of the swap devices, followed by the swap files,
+// in a deterministic order
+func (asl *ActivateSwapLayer) getSwapAreas(c *config.Config) []string {
+	names := make([]string, 0)
+	for _, name := range c.GetDevices() {
+		if c.Devices[name].Fs == model.Swap {
+			names = append(names, name)
+		}
+	}
+	return append(names, c.GetSwapFiles()...)
+}
@@ -0,0 +1,204 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestActivateSwapLayerModify(t *testing.T) {
+	priority := 10
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		SwapAreas     map[string]*model.SwapArea
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name: "Activate Swap Device and Swap File",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap, Swap: &config.Swap{Priority: &priority}},
+					"/dev/xvdf":    {Fs: model.Ext4, MountPoint: "/mnt/scratch"},
+				},
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB", Mode: model.Force},
+				},
+			},
+			SwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1":          nil,
+				"/mnt/scratch/swapfile": nil,
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.ActivateSwapAction{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewActivateSwapAction("/dev/nvme1n1", &priority, nil).SetMode(config.DefaultMode).SetDevice("/dev/nvme1n1"),
+				action.NewActivateSwapAction("/mnt/scratch/swapfile", nil, nil).SetMode(model.Force).SetDevice("/mnt/scratch/swapfile"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Active Swap Area With Different Priority",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap, Swap: &config.Swap{Priority: &priority}},
+				},
+			},
+			SwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", Size: 8589930496, Priority: -2},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.DeactivateSwapAction{},
+				action.ActivateSwapAction{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewDeactivateSwapAction("/dev/nvme1n1", nil).SetMode(config.DefaultMode).SetDevice("/dev/nvme1n1"),
+				action.NewActivateSwapAction("/dev/nvme1n1", &priority, nil).SetMode(config.DefaultMode).SetDevice("/dev/nvme1n1"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Active Swap Area Without Priority",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap},
+				},
+			},
+			SwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", Size: 8589930496, Priority: -2},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Swap Area Could Not Be Found",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap},
+				},
+			},
+			SwapAreas:     map[string]*model.SwapArea{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Could not find the state of a swap area"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lsb := backend.NewMockLinuxSwapBackend(subtest.SwapAreas)
+			asl := NewActivateSwapLayer(lsb)
+			actions, err := asl.Modify(subtest.Config)
+			utils.CheckError("asl.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("asl.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestActivateSwapLayerValidate(t *testing.T) {
+	priority := 10
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		SwapAreas     map[string]*model.SwapArea
+		ExpectedError error
+	}{
+		{
+			Name: "Active Swap Area",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap, Swap: &config.Swap{Priority: &priority}},
+				},
+			},
+			SwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", Size: 8589930496, Priority: 10},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Inactive Swap Area",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			SwapAreas: map[string]*model.SwapArea{
+				"/mnt/scratch/swapfile": nil,
+			},
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: Failed swap validation checks. Swap area is not active"),
+		},
+		{
+			Name: "Different Priority",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap, Swap: &config.Swap{Priority: &priority}},
+				},
+			},
+			SwapAreas: map[string]*model.SwapArea{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", Size: 8589930496, Priority: -2},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Failed swap priority validation checks. Expected=10, Actual=-2"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lsb := backend.NewMockLinuxSwapBackend(subtest.SwapAreas)
+			asl := NewActivateSwapLayer(lsb)
+			err := asl.Validate(subtest.Config)
+			utils.CheckError("asl.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestActivateSwapLayerShouldProcess(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		ExpectedValue bool
+	}{
+		{
+			Name: "Swap Device Configured",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap},
+					"/dev/xvdf":    {Fs: model.Ext4},
+				},
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "Swap File Configured",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "No Swap Configured",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4},
+				},
+			},
+			ExpectedValue: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			asl := NewActivateSwapLayer(nil)
+			value := asl.ShouldProcess(subtest.Config)
+			utils.CheckOutput("asl.ShouldProcess()", t, subtest.ExpectedValue, value)
+		})
+	}
+}
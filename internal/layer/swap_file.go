@@ -0,0 +1,70 @@
+package layer
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type CreateSwapFileLayer struct {
+	swapBackend backend.SwapBackend
+}
+
+func NewCreateSwapFileLayer(sb backend.SwapBackend) *CreateSwapFileLayer {
+	return &CreateSwapFileLayer{
+		swapBackend: sb,
+	}
+}
+
+func (csfl *CreateSwapFileLayer) From(c *config.Config) error {
+	return csfl.swapBackend.From(c)
+}
+
+// An existing swap file is never resized or reformatted. However, swapon(8) refuses
+// a swap file that is readable by other users, so its permissions are corrected
+func (csfl *CreateSwapFileLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetSwapFiles() {
+		mode := c.GetSwapFileMode(name)
+		f, err := csfl.swapBackend.GetSwapFile(name)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			a := csfl.swapBackend.CreateSwapFile(name, c.GetSwapFileSize(name))
+			actions = append(actions, a.SetMode(mode).SetDevice(name))
+			continue
+		}
+		if f.Permissions == model.SwapFilePermissions {
+			continue
+		}
+		a := csfl.swapBackend.ChangePermissions(name, model.SwapFilePermissions)
+		actions = append(actions, a.SetMode(mode).SetDevice(name))
+	}
+	return actions, nil
+}
+
+func (csfl *CreateSwapFileLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetSwapFiles() {
+		f, err := csfl.swapBackend.GetSwapFile(name)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: Failed swap file validation checks. Swap file is either not a regular file or does not exist", name)
+		}
+		if f.Permissions != model.SwapFilePermissions {
+			return fmt.Errorf("🔴 %s: Failed permissions validation checks. Expected=%#o, Actual=%#o", name, model.SwapFilePermissions, f.Permissions)
+		}
+	}
+	return nil
+}
+
+func (csfl *CreateSwapFileLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (csfl *CreateSwapFileLayer) ShouldProcess(c *config.Config) bool {
+	return len(c.SwapFiles) > 0
+}
@@ -0,0 +1,182 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestCreateSwapFileLayerModify(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		SwapFiles     map[string]*model.File
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name: "Create Swap File",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB", Mode: model.Force},
+				},
+			},
+			SwapFiles: map[string]*model.File{},
+			CmpOption: cmp.AllowUnexported(
+				action.CreateSwapFileAction{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewCreateSwapFileAction("/mnt/scratch/swapfile", 4294967296, nil).SetMode(model.Force).SetDevice("/mnt/scratch/swapfile"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Swap File Readable By Other Users",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			SwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.RegularFile, Permissions: 0644},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.ChangePermissionsAction{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewChangePermissionsAction("/mnt/scratch/swapfile", model.SwapFilePermissions, nil).SetMode(config.DefaultMode).SetDevice("/mnt/scratch/swapfile"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Existing Swap File",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			SwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.RegularFile, Permissions: 0600},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Swap File Is a Directory",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			SwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.Directory, Permissions: 0755},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: Swap file is not a regular file"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lsb := backend.NewMockLinuxSwapBackendWithFiles(map[string]*model.SwapArea{}, subtest.SwapFiles)
+			csfl := NewCreateSwapFileLayer(lsb)
+			actions, err := csfl.Modify(subtest.Config)
+			utils.CheckError("csfl.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("csfl.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestCreateSwapFileLayerValidate(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		SwapFiles     map[string]*model.File
+		ExpectedError error
+	}{
+		{
+			Name: "Valid Swap File",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			SwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.RegularFile, Permissions: 0600},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Missing Swap File",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			SwapFiles:     map[string]*model.File{},
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: Failed swap file validation checks. Swap file is either not a regular file or does not exist"),
+		},
+		{
+			Name: "Invalid Permissions",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			SwapFiles: map[string]*model.File{
+				"/mnt/scratch/swapfile": {Path: "/mnt/scratch/swapfile", Type: model.RegularFile, Permissions: 0644},
+			},
+			ExpectedError: fmt.Errorf("🔴 /mnt/scratch/swapfile: Failed permissions validation checks. Expected=0600, Actual=0644"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lsb := backend.NewMockLinuxSwapBackendWithFiles(map[string]*model.SwapArea{}, subtest.SwapFiles)
+			csfl := NewCreateSwapFileLayer(lsb)
+			err := csfl.Validate(subtest.Config)
+			utils.CheckError("csfl.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestCreateSwapFileLayerShouldProcess(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		ExpectedValue bool
+	}{
+		{
+			Name: "Swap File Configured",
+			Config: &config.Config{
+				SwapFiles: map[string]config.SwapFile{
+					"/mnt/scratch/swapfile": {Size: "4GiB"},
+				},
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "No Swap File Configured",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Swap},
+				},
+			},
+			ExpectedValue: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			csfl := NewCreateSwapFileLayer(nil)
+			value := csfl.ShouldProcess(subtest.Config)
+			utils.CheckOutput("csfl.ShouldProcess()", t, subtest.ExpectedValue, value)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package layer
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type UpdateMountUnitLayer struct {
+	deviceBackend  backend.DeviceBackend
+	systemdBackend backend.SystemdBackend
+}
+
+func NewUpdateMountUnitLayer(db backend.DeviceBackend, sb backend.SystemdBackend) *UpdateMountUnitLayer {
+	return &UpdateMountUnitLayer{
+		deviceBackend:  db,
+		systemdBackend: sb,
+	}
+}
+
+func (umul *UpdateMountUnitLayer) From(c *config.Config) error {
+	err := umul.deviceBackend.From(c)
+	if err != nil {
+		return err
+	}
+	return umul.systemdBackend.From(c)
+}
+
+func (umul *UpdateMountUnitLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if !umul.shouldManage(c, name, cd) {
+			continue
+		}
+		expected, err := umul.unit(c, name, cd)
+		if err != nil {
+			return nil, err
+		}
+		actual, err := umul.systemdBackend.GetMountUnit(cd.MountPoint)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if actual == expected.String() {
+			continue
+		}
+		mode := c.GetMode(name)
+		a := umul.systemdBackend.UpdateMountUnit(expected).SetMode(mode).SetDevice(name)
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+func (umul *UpdateMountUnitLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if !umul.shouldManage(c, name, cd) {
+			continue
+		}
+		expected, err := umul.unit(c, name, cd)
+		if err != nil {
+			return err
+		}
+		actual, err := umul.systemdBackend.GetMountUnit(cd.MountPoint)
+		if err != nil {
+			return fmt.Errorf("🔴 %s: Failed mount unit validation checks. No mount unit %s found for %s", name, expected.Name(), cd.MountPoint)
+		}
+		if actual != expected.String() {
+			return fmt.Errorf("🔴 %s: Failed mount unit validation checks. Mount unit %s does not match the expected configuration", name, expected.Name())
+		}
+	}
+	return nil
+}
+
+func (umul *UpdateMountUnitLayer) Warning() string {
+	return DisabledWarning
+}
+
+func (umul *UpdateMountUnitLayer) ShouldProcess(c *config.Config) bool {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if umul.shouldManage(c, name, cd) {
+			return true
+		}
+	}
+	return false
+}
+
+func (umul *UpdateMountUnitLayer) shouldManage(c *config.Config, name string, cd config.Device) bool {
+	return len(cd.MountPoint) > 0 && c.GetSystemdMount(name)
+}
+
+// Similar to an /etc/fstab entry, a device is referenced by the udev symbolic
+// link of its label, when one is configured. Otherwise, the symbolic link of the
+// UUID of the file system is used
+func (umul *UpdateMountUnitLayer) unit(c *config.Config, name string, cd config.Device) (*model.MountUnit, error) {
+	bd, err := umul.deviceBackend.GetBlockDevice(name)
+	if err != nil {
+		return nil, err
+	}
+	var what string
+	if len(cd.Label) > 0 {
+		what = path.Join(model.DiskByLabelPath, cd.Label)
+	} else {
+		if len(bd.UUID) == 0 {
+			return nil, fmt.Errorf("🔴 %s: Can not reference a file system with no UUID in a systemd mount unit", name)
+		}
+		what = path.Join(model.DiskByUuidPath, bd.UUID)
+	}
+	return &model.MountUnit{
+		What:       what,
+		Where:      cd.MountPoint,
+		Type:       cd.Fs,
+		Options:    c.GetMountOptions(name),
+		RequiredBy: cd.RequiredBy,
+	}, nil
+}
@@ -0,0 +1,221 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestUpdateMountUnitLayerModify(t *testing.T) {
+	labelled := &model.MountUnit{
+		What:       "/dev/disk/by-label/stateful",
+		Where:      "/var/lib/postgresql",
+		Type:       model.Ext4,
+		Options:    "defaults",
+		RequiredBy: []string{"postgresql.service"},
+	}
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		Devices       map[string]*model.BlockDevice
+		Units         map[string]string
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name: "Write Missing Unit Referenced By Label",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						Label:      "stateful",
+						MountPoint: "/var/lib/postgresql",
+						RequiredBy: []string{"postgresql.service"},
+						Options: config.Options{
+							SystemdMount: true,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					Label:      "stateful",
+				},
+			},
+			Units:     map[string]string{},
+			CmpOption: cmp.AllowUnexported(action.UpdateMountUnitAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewUpdateMountUnitAction("/etc/systemd/system/var-lib-postgresql.mount", labelled, nil, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Repair Drifted Unit Referenced By UUID",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Xfs,
+						MountPoint: "/mnt/ebs",
+						Options: config.Options{
+							SystemdMount: true,
+							MountOptions: "defaults,noatime",
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Xfs,
+					UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				},
+			},
+			Units: map[string]string{
+				"/mnt/ebs": "[Mount]\nWhat=/dev/xvdf\nWhere=/mnt/ebs\n",
+			},
+			CmpOption: cmp.AllowUnexported(action.UpdateMountUnitAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewUpdateMountUnitAction("/etc/systemd/system/mnt-ebs.mount", &model.MountUnit{
+					What:    "/dev/disk/by-uuid/9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+					Where:   "/mnt/ebs",
+					Type:    model.Xfs,
+					Options: "defaults,noatime",
+				}, nil, nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Unit Matches Configuration",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						Label:      "stateful",
+						MountPoint: "/var/lib/postgresql",
+						RequiredBy: []string{"postgresql.service"},
+						Options: config.Options{
+							SystemdMount: true,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					Label:      "stateful",
+				},
+			},
+			Units: map[string]string{
+				"/var/lib/postgresql": labelled.String(),
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "File System Without UUID",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Ext4,
+						MountPoint: "/mnt/ebs",
+						Options: config.Options{
+							SystemdMount: true,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name: "/dev/xvdf",
+				},
+			},
+			Units:         map[string]string{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Can not reference a file system with no UUID in a systemd mount unit"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackend(subtest.Devices)
+			lsb := backend.NewMockLinuxSystemdBackend(subtest.Units)
+			umul := NewUpdateMountUnitLayer(ldb, lsb)
+			actions, err := umul.Modify(subtest.Config)
+			utils.CheckError("umul.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("umul.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestUpdateMountUnitLayerValidate(t *testing.T) {
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"/dev/xvdf": {
+				Fs:         model.Ext4,
+				Label:      "stateful",
+				MountPoint: "/mnt/ebs",
+				Options: config.Options{
+					SystemdMount: true,
+				},
+			},
+		},
+	}
+	devices := map[string]*model.BlockDevice{
+		"/dev/xvdf": {
+			Name:       "/dev/xvdf",
+			FileSystem: model.Ext4,
+			Label:      "stateful",
+		},
+	}
+	expected := &model.MountUnit{
+		What:    "/dev/disk/by-label/stateful",
+		Where:   "/mnt/ebs",
+		Type:    model.Ext4,
+		Options: "defaults",
+	}
+	subtests := []struct {
+		Name          string
+		Units         map[string]string
+		ExpectedError error
+	}{
+		{
+			Name: "Unit Matches Configuration",
+			Units: map[string]string{
+				"/mnt/ebs": expected.String(),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Unit Does Not Match Configuration",
+			Units: map[string]string{
+				"/mnt/ebs": "[Mount]\n",
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed mount unit validation checks. Mount unit mnt-ebs.mount does not match the expected configuration"),
+		},
+		{
+			Name:          "Unit Does Not Exist",
+			Units:         map[string]string{},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed mount unit validation checks. No mount unit mnt-ebs.mount found for /mnt/ebs"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackend(devices)
+			lsb := backend.NewMockLinuxSystemdBackend(subtest.Units)
+			umul := NewUpdateMountUnitLayer(ldb, lsb)
+			err := umul.Validate(c)
+			utils.CheckError("umul.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
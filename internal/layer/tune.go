@@ -0,0 +1,92 @@
+package layer
+
+import (
+	"fmt"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+)
+
+type TuneFileSystemLayer struct {
+	deviceBackend backend.DeviceBackend
+	tuneBackend   backend.TuneBackend
+}
+
+func NewTuneFileSystemLayer(db backend.DeviceBackend, tb backend.TuneBackend) *TuneFileSystemLayer {
+	return &TuneFileSystemLayer{
+		deviceBackend: db,
+		tuneBackend:   tb,
+	}
+}
+
+func (tfl *TuneFileSystemLayer) From(c *config.Config) error {
+	err := tfl.deviceBackend.From(c)
+	if err != nil {
+		return err
+	}
+	return tfl.tuneBackend.From(c)
+}
+
+func (tfl *TuneFileSystemLayer) Modify(c *config.Config) ([]action.Action, error) {
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		desired := c.GetTuneProperties(name)
+		if desired == nil {
+			continue
+		}
+
+		bd, err := tfl.deviceBackend.GetBlockDevice(name)
+		if err != nil {
+			return nil, err
+		}
+		current, err := tfl.tuneBackend.GetProperties(name)
+		if err != nil {
+			return nil, err
+		}
+		diff := desired.Diff(current)
+		if diff == nil {
+			continue
+		}
+
+		mode := c.GetMode(name)
+		tas, err := tfl.tuneBackend.Tune(bd, diff)
+		if err != nil {
+			return nil, err
+		}
+		for _, ta := range tas {
+			actions = append(actions, ta.SetMode(mode).SetDevice(name))
+		}
+	}
+	return actions, nil
+}
+
+func (tfl *TuneFileSystemLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		desired := c.GetTuneProperties(name)
+		if desired == nil {
+			continue
+		}
+		current, err := tfl.tuneBackend.GetProperties(name)
+		if err != nil {
+			return err
+		}
+		if diff := desired.Diff(current); diff != nil {
+			return fmt.Errorf("🔴 %s: Failed tune validation checks. Expected=%s, Actual=%s", name, diff, current)
+		}
+	}
+	return nil
+}
+
+func (tfl *TuneFileSystemLayer) Warning() string {
+	return "Certain file systems require that devices be unmounted prior to tuning"
+}
+
+func (tfl *TuneFileSystemLayer) ShouldProcess(c *config.Config) bool {
+	for _, cd := range c.Devices {
+		if cd.Tune != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestTuneFileSystemLayerModify(t *testing.T) {
+	zero := uint64(0)
+	five := uint64(5)
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		Devices       map[string]*model.BlockDevice
+		Properties    map[string]*model.FileSystemProperties
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name: "Tune Properties That Differ",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+						Tune: &config.Tune{
+							ReservedBlocksPercent: &zero,
+							Errors:                model.ErrorsContinue,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					MountPoint: "/mnt/foo",
+				},
+			},
+			Properties: map[string]*model.FileSystemProperties{
+				"/dev/xvdf": {
+					ReservedBlocksPercent: &five,
+					Errors:                model.ErrorsContinue,
+				},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.TuneFileSystemAction{},
+				service.Ext4Service{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewTuneFileSystemAction("/dev/xvdf", &model.FileSystemProperties{ReservedBlocksPercent: &zero}, service.NewExt4Service(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Tune File System That Requires Unmounting",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:         model.Xfs,
+						MountPoint: "/mnt/foo",
+						Tune: &config.Tune{
+							Features: []string{"bigtime", "reflink"},
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Xfs,
+					MountPoint: "/mnt/foo",
+				},
+			},
+			Properties: map[string]*model.FileSystemProperties{
+				"/dev/xvdf": {
+					Features: []string{"crc", "reflink"},
+				},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.UnmountDeviceAction{},
+				action.TuneFileSystemAction{},
+				service.XfsService{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewUnmountDeviceAction("/dev/xvdf", "/mnt/foo", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+				action.NewTuneFileSystemAction("/dev/xvdf", &model.FileSystemProperties{Features: []string{"bigtime"}}, service.NewXfsService(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Properties Match Requested Properties",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+						Tune: &config.Tune{
+							ReservedBlocksPercent: &zero,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Properties: map[string]*model.FileSystemProperties{
+				"/dev/xvdf": {
+					ReservedBlocksPercent: &zero,
+					Errors:                model.ErrorsContinue,
+				},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Skip Tuning",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Properties:    map[string]*model.FileSystemProperties{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Properties Could Not Be Read",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+						Tune: &config.Tune{
+							ReservedBlocksPercent: &zero,
+						},
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+				},
+			},
+			Properties:    map[string]*model.FileSystemProperties{},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Could not find file system properties"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackend(subtest.Devices)
+			ltb := backend.NewMockLinuxTuneBackend(subtest.Properties)
+			layer := NewTuneFileSystemLayer(ldb, ltb)
+			actions, err := layer.Modify(subtest.Config)
+			utils.CheckError("TuneFileSystemLayer.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("TuneFileSystemLayer.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
+
+func TestTuneFileSystemLayerValidate(t *testing.T) {
+	zero := uint64(0)
+	five := uint64(5)
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		Properties    map[string]*model.FileSystemProperties
+		ExpectedError error
+	}{
+		{
+			Name: "Properties Match Requested Properties",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Tune: &config.Tune{ReservedBlocksPercent: &zero},
+					},
+				},
+			},
+			Properties: map[string]*model.FileSystemProperties{
+				"/dev/xvdf": {ReservedBlocksPercent: &zero},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Skipping Validation",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+					},
+				},
+			},
+			Properties:    map[string]*model.FileSystemProperties{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Properties Do Not Match Requested Properties",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Tune: &config.Tune{ReservedBlocksPercent: &zero},
+					},
+				},
+			},
+			Properties: map[string]*model.FileSystemProperties{
+				"/dev/xvdf": {ReservedBlocksPercent: &five},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed tune validation checks. Expected=reservedBlocksPercent=0, Actual=reservedBlocksPercent=5"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ltb := backend.NewMockLinuxTuneBackend(subtest.Properties)
+			tfl := NewTuneFileSystemLayer(nil, ltb)
+			err := tfl.Validate(subtest.Config)
+			utils.CheckError("tfl.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestTuneFileSystemLayerShouldProcess(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		ExpectedValue bool
+	}{
+		{
+			Name: "At Least Once Device Has Tune Specified",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdb": {
+						Fs:   model.Ext4,
+						Tune: &config.Tune{Errors: model.ErrorsRemountRo},
+					},
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+					},
+				},
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "No Device Has Tune Specified",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs: model.Ext4,
+					},
+				},
+			},
+			ExpectedValue: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			tfl := NewTuneFileSystemLayer(nil, nil)
+			value := tfl.ShouldProcess(subtest.Config)
+			utils.CheckOutput("tfl.ShouldProcess()", t, subtest.ExpectedValue, value)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package layer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type ChangeUuidLayer struct {
+	deviceBackend backend.DeviceBackend
+	// The devices that were found to share a UUID during the last modification
+	clones []string
+}
+
+func NewChangeUuidLayer(db backend.DeviceBackend) *ChangeUuidLayer {
+	return &ChangeUuidLayer{
+		deviceBackend: db,
+	}
+}
+
+func (cul *ChangeUuidLayer) From(c *config.Config) error {
+	return cul.deviceBackend.From(c)
+}
+
+func (cul *ChangeUuidLayer) Modify(c *config.Config) ([]action.Action, error) {
+	cul.clones = nil
+	actions := make([]action.Action, 0)
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if len(cd.Uuid) == 0 {
+			continue
+		}
+
+		bd, err := cul.deviceBackend.GetBlockDevice(name)
+		if err != nil {
+			return nil, err
+		}
+		if cd.Uuid == model.RandomUuid {
+			// A random UUID is only assigned to a file system that shares its UUID
+			// with another attached device. Otherwise, the UUID is left unchanged
+			clones := cul.deviceBackend.GetClones(bd)
+			if len(clones) == 0 {
+				continue
+			}
+			for _, clone := range clones {
+				if !slices.Contains(cul.clones, clone) {
+					cul.clones = append(cul.clones, clone)
+				}
+			}
+		} else if strings.EqualFold(bd.UUID, cd.Uuid) {
+			continue
+		}
+
+		mode := c.GetMode(name)
+		uas, err := cul.deviceBackend.SetUuid(bd, cd.Uuid)
+		if err != nil {
+			return nil, err
+		}
+		for _, ua := range uas {
+			actions = append(actions, ua.SetMode(mode).SetDevice(name))
+		}
+	}
+	return actions, nil
+}
+
+func (cul *ChangeUuidLayer) Validate(c *config.Config) error {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if len(cd.Uuid) == 0 {
+			continue
+		}
+		bd, err := cul.deviceBackend.GetBlockDevice(name)
+		if err != nil {
+			return err
+		}
+		if cd.Uuid == model.RandomUuid {
+			if clones := cul.deviceBackend.GetClones(bd); len(clones) > 0 {
+				return fmt.Errorf("🔴 %s: Failed UUID validation checks. UUID %s is shared by %s", name, bd.UUID, strings.Join(clones, ", "))
+			}
+			continue
+		}
+		if !strings.EqualFold(bd.UUID, cd.Uuid) {
+			return fmt.Errorf("🔴 %s: Failed UUID validation checks. Expected=%s, Actual=%s", name, cd.Uuid, bd.UUID)
+		}
+	}
+	return nil
+}
+
+// Devices that share a UUID were most likely restored from the same snapshot
+func (cul *ChangeUuidLayer) Warning() string {
+	warning := "Devices must be unmounted prior to changing the UUID of their file system"
+	if len(cul.clones) > 0 {
+		warning = fmt.Sprintf("Detected file systems that share a UUID, which is typical of volumes that were restored from the same snapshot (%s). %s", strings.Join(cul.clones, ", "), warning)
+	}
+	return warning
+}
+
+func (cul *ChangeUuidLayer) ShouldProcess(c *config.Config) bool {
+	for _, cd := range c.Devices {
+		if len(cd.Uuid) > 0 {
+			return true
+		}
+	}
+	return false
+}
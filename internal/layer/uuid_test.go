@@ -0,0 +1,261 @@
+package layer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
+	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/service"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestChangeUuidLayerModify(t *testing.T) {
+	subtests := []struct {
+		Name            string
+		Config          *config.Config
+		Devices         map[string]*model.BlockDevice
+		Clones          map[string][]string
+		CmpOption       cmp.Option
+		ExpectedOuput   []action.Action
+		ExpectedWarning string
+		ExpectedError   error
+	}{
+		{
+			Name: "Random UUID for Cloned Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {
+						Fs:   model.Xfs,
+						Uuid: model.RandomUuid,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Xfs,
+					UUID:       "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+				},
+			},
+			Clones: map[string][]string{
+				"/dev/nvme1n1": {"/dev/nvme0n1", "/dev/nvme1n1"},
+			},
+			CmpOption: cmp.AllowUnexported(
+				action.ChangeUuidAction{},
+				service.XfsService{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewChangeUuidAction("/dev/nvme1n1", model.RandomUuid, service.NewXfsService(nil)).SetMode(config.DefaultMode).SetDevice("/dev/nvme1n1"),
+			},
+			ExpectedWarning: "Detected file systems that share a UUID, which is typical of volumes that were restored from the same snapshot (/dev/nvme0n1, /dev/nvme1n1). Devices must be unmounted prior to changing the UUID of their file system",
+			ExpectedError:   nil,
+		},
+		{
+			Name: "Random UUID for Unique Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {
+						Fs:   model.Xfs,
+						Uuid: model.RandomUuid,
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {
+					Name:       "/dev/nvme1n1",
+					FileSystem: model.Xfs,
+					UUID:       "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+				},
+			},
+			Clones:          map[string][]string{},
+			CmpOption:       cmp.AllowUnexported(),
+			ExpectedOuput:   []action.Action{},
+			ExpectedWarning: "Devices must be unmounted prior to changing the UUID of their file system",
+			ExpectedError:   nil,
+		},
+		{
+			Name: "Fixed UUID for Mounted Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Uuid: "6f3b1a4e-8d6c-4c2e-9f0a-1b2c3d4e5f60",
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					UUID:       "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+					MountPoint: "/mnt/app",
+				},
+			},
+			Clones: map[string][]string{},
+			CmpOption: cmp.AllowUnexported(
+				action.UnmountDeviceAction{},
+				action.ChangeUuidAction{},
+				service.Ext4Service{},
+			),
+			ExpectedOuput: []action.Action{
+				action.NewUnmountDeviceAction("/dev/xvdf", "/mnt/app", nil).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+				action.NewChangeUuidAction("/dev/xvdf", "6f3b1a4e-8d6c-4c2e-9f0a-1b2c3d4e5f60", service.NewExt4Service(nil)).SetMode(config.DefaultMode).SetDevice("/dev/xvdf"),
+			},
+			ExpectedWarning: "Devices must be unmounted prior to changing the UUID of their file system",
+			ExpectedError:   nil,
+		},
+		{
+			Name: "Fixed UUID Matches",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Uuid: "31CF3150-F4B1-409A-9887-7F9DA1FDD99C",
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name:       "/dev/xvdf",
+					FileSystem: model.Ext4,
+					UUID:       "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+				},
+			},
+			Clones:          map[string][]string{},
+			CmpOption:       cmp.AllowUnexported(),
+			ExpectedOuput:   []action.Action{},
+			ExpectedWarning: "Devices must be unmounted prior to changing the UUID of their file system",
+			ExpectedError:   nil,
+		},
+		{
+			Name: "Unformatted Device",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {
+						Fs:   model.Ext4,
+						Uuid: "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+					},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {
+					Name: "/dev/xvdf",
+				},
+			},
+			Clones:          map[string][]string{},
+			CmpOption:       cmp.AllowUnexported(),
+			ExpectedOuput:   nil,
+			ExpectedWarning: "Devices must be unmounted prior to changing the UUID of their file system",
+			ExpectedError:   fmt.Errorf("🔴 /dev/xvdf: An unformatted file system can not be queried/modified"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackendWithClones(subtest.Devices, subtest.Clones)
+			layer := NewChangeUuidLayer(ldb)
+			actions, err := layer.Modify(subtest.Config)
+			utils.CheckError("ChangeUuidLayer.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ChangeUuidLayer.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+			utils.CheckOutput("ChangeUuidLayer.Warning()", t, subtest.ExpectedWarning, layer.Warning())
+		})
+	}
+}
+
+func TestChangeUuidLayerValidate(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		Devices       map[string]*model.BlockDevice
+		Clones        map[string][]string
+		ExpectedError error
+	}{
+		{
+			Name: "Fixed UUID Matches",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4, Uuid: "31cf3150-f4b1-409a-9887-7f9da1fdd99c"},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {Name: "/dev/xvdf", FileSystem: model.Ext4, UUID: "31cf3150-f4b1-409a-9887-7f9da1fdd99c"},
+			},
+			Clones:        map[string][]string{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Fixed UUID Does Not Match",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4, Uuid: "6f3b1a4e-8d6c-4c2e-9f0a-1b2c3d4e5f60"},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/xvdf": {Name: "/dev/xvdf", FileSystem: model.Ext4, UUID: "31cf3150-f4b1-409a-9887-7f9da1fdd99c"},
+			},
+			Clones:        map[string][]string{},
+			ExpectedError: fmt.Errorf("🔴 /dev/xvdf: Failed UUID validation checks. Expected=6f3b1a4e-8d6c-4c2e-9f0a-1b2c3d4e5f60, Actual=31cf3150-f4b1-409a-9887-7f9da1fdd99c"),
+		},
+		{
+			Name: "Random UUID Is Still Shared",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/nvme1n1": {Fs: model.Xfs, Uuid: model.RandomUuid},
+				},
+			},
+			Devices: map[string]*model.BlockDevice{
+				"/dev/nvme1n1": {Name: "/dev/nvme1n1", FileSystem: model.Xfs, UUID: "31cf3150-f4b1-409a-9887-7f9da1fdd99c"},
+			},
+			Clones: map[string][]string{
+				"/dev/nvme1n1": {"/dev/nvme0n1", "/dev/nvme1n1"},
+			},
+			ExpectedError: fmt.Errorf("🔴 /dev/nvme1n1: Failed UUID validation checks. UUID 31cf3150-f4b1-409a-9887-7f9da1fdd99c is shared by /dev/nvme0n1, /dev/nvme1n1"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ldb := backend.NewMockLinuxDeviceBackendWithClones(subtest.Devices, subtest.Clones)
+			cul := NewChangeUuidLayer(ldb)
+			err := cul.Validate(subtest.Config)
+			utils.CheckError("cul.Validate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestChangeUuidLayerShouldProcess(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Config        *config.Config
+		ExpectedValue bool
+	}{
+		{
+			Name: "At Least Once Device Has UUID Specified",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdb": {Fs: model.Xfs, Uuid: model.RandomUuid},
+					"/dev/xvdf": {Fs: model.Ext4},
+				},
+			},
+			ExpectedValue: true,
+		},
+		{
+			Name: "No Device Has UUID Specified",
+			Config: &config.Config{
+				Devices: map[string]config.Device{
+					"/dev/xvdf": {Fs: model.Ext4},
+				},
+			},
+			ExpectedValue: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			cul := NewChangeUuidLayer(nil)
+			value := cul.ShouldProcess(subtest.Config)
+			utils.CheckOutput("cul.ShouldProcess()", t, subtest.ExpectedValue, value)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package layer
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/reecetech/ebs-bootstrap/internal/action"
 	"github.com/reecetech/ebs-bootstrap/internal/backend"
@@ -20,52 +21,70 @@ func NewCreateVolumeGroupLayer(lb backend.LvmBackend) *CreateVolumeGroupLayer {
 
 func (cvgl *CreateVolumeGroupLayer) Modify(c *config.Config) ([]action.Action, error) {
 	actions := make([]action.Action, 0)
-	for name, cd := range c.Devices {
-		if len(cd.Lvm) == 0 {
+	// The logical volumes of a volume group share its physical volumes
+	visited := map[string]bool{}
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
+		if len(cd.Lvm) == 0 || visited[cd.Lvm] {
 			continue
 		}
-		vg, err := cvgl.lvmBackend.SearchVolumeGroup(name)
-		if err != nil {
-			return nil, err
-		}
-		if vg != nil && vg.Name != cd.Lvm {
-			return nil, fmt.Errorf("🔴 %s: Physical volume %s already has volume group %s associated", name, name, vg.Name)
+		visited[cd.Lvm] = true
+		pvs := c.GetPhysicalVolumes(name)
+		for _, pv := range pvs {
+			vg, err := cvgl.lvmBackend.SearchVolumeGroup(pv)
+			if err != nil {
+				return nil, err
+			}
+			if vg != nil && vg.Name != cd.Lvm {
+				return nil, fmt.Errorf("🔴 %s: Physical volume %s already has volume group %s associated", name, pv, vg.Name)
+			}
 		}
 
-		vgs := cvgl.lvmBackend.GetVolumeGroups(cd.Lvm)
-		if len(vgs) == 1 {
-			if vgs[0].PhysicalVolume == name {
-				continue
+		members := map[string]bool{}
+		for _, vg := range cvgl.lvmBackend.GetVolumeGroups(cd.Lvm) {
+			if !slices.Contains(pvs, vg.PhysicalVolume) {
+				return nil, fmt.Errorf("🔴 %s: Volume group %s already exists and belongs to physical volume %s", name, cd.Lvm, vg.PhysicalVolume)
 			}
-			return nil, fmt.Errorf("🔴 %s: Volume group %s already exists and belongs to physical volume %s", name, cd.Lvm, vgs[0].PhysicalVolume)
-		}
-		if len(vgs) > 1 {
-			return nil, fmt.Errorf("🔴 %s: Cannot manage volume group %s because it is associated with more than one physical volume", name, cd.Lvm)
+			members[vg.PhysicalVolume] = true
 		}
 
+		// A volume group is created on the first of its physical volumes and is then
+		// extended onto the rest. An existing volume group is extended onto any
+		// physical volume that has since been added to the configuration
 		mode := c.GetMode(name)
-		a := cvgl.lvmBackend.CreateVolumeGroup(cd.Lvm, name)
-		actions = append(actions, a.SetMode(mode))
+		for _, pv := range pvs {
+			if members[pv] {
+				continue
+			}
+			var a action.Action
+			if len(members) == 0 {
+				a = cvgl.lvmBackend.CreateVolumeGroup(cd.Lvm, pv)
+			} else {
+				a = cvgl.lvmBackend.ExtendVolumeGroup(cd.Lvm, pv)
+			}
+			members[pv] = true
+			actions = append(actions, a.SetMode(mode).SetDevice(name))
+		}
 	}
 	return actions, nil
 }
 
 func (cvgl *CreateVolumeGroupLayer) Validate(c *config.Config) error {
-	for name, cd := range c.Devices {
+	for _, name := range c.GetDevices() {
+		cd := c.Devices[name]
 		if len(cd.Lvm) == 0 {
 			continue
 		}
+		pvs := c.GetPhysicalVolumes(name)
 		vgs := cvgl.lvmBackend.GetVolumeGroups(cd.Lvm)
-		if len(vgs) == 1 {
-			if vgs[0].PhysicalVolume == name {
-				return nil
-			}
-			return fmt.Errorf("🔴 %s: Failed to validate volume group. Expected=%s, Actual=%s", name, name, vgs[0].PhysicalVolume)
+		if len(vgs) != len(pvs) {
+			return fmt.Errorf("🔴 %s: Failed to validate volume group. #(Physical volume) Expected=%d, Actual=%d", name, len(pvs), len(vgs))
 		}
-		if len(vgs) > 1 {
-			return fmt.Errorf("🔴 %s: Failed to validate volume group. #(Physical volume) Expected=%d, Actual=%d", name, 1, len(vgs))
+		for _, vg := range vgs {
+			if !slices.Contains(pvs, vg.PhysicalVolume) {
+				return fmt.Errorf("🔴 %s: Failed to validate volume group. Physical volume %s is not configured", name, vg.PhysicalVolume)
+			}
 		}
-
 	}
 	return nil
 }
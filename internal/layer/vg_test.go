@@ -1,9 +1,15 @@
 package layer
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/reecetech/ebs-bootstrap/internal/action"
+	"github.com/reecetech/ebs-bootstrap/internal/backend"
 	"github.com/reecetech/ebs-bootstrap/internal/config"
+	"github.com/reecetech/ebs-bootstrap/internal/datastructures"
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
 
@@ -43,3 +49,90 @@ func TestCreateVolumeGroupLayerShouldProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateVolumeGroupLayerModify(t *testing.T) {
+	c := &config.Config{
+		Devices: map[string]config.Device{
+			"data": {
+				Lvm: "db",
+			},
+		},
+		VolumeGroups: map[string]config.VolumeGroup{
+			"db": {Devices: []string{"/dev/xvdf", "/dev/xvdg"}},
+		},
+	}
+	subtests := []struct {
+		Name          string
+		VolumeGroups  []*model.VolumeGroup
+		CmpOption     cmp.Option
+		ExpectedOuput []action.Action
+		ExpectedError error
+	}{
+		{
+			Name:         "Create Volume Group Across Physical Volumes",
+			VolumeGroups: []*model.VolumeGroup{},
+			CmpOption:    cmp.AllowUnexported(action.CreateVolumeGroupAction{}, action.ExtendVolumeGroupAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewCreateVolumeGroupAction("db", "/dev/xvdf", nil).SetMode(config.DefaultMode).SetDevice("data"),
+				action.NewExtendVolumeGroupAction("db", "/dev/xvdg", nil).SetMode(config.DefaultMode).SetDevice("data"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Extend Volume Group With New Physical Volume",
+			VolumeGroups: []*model.VolumeGroup{
+				{Name: "db", PhysicalVolume: "/dev/xvdf", Size: 1000},
+			},
+			CmpOption: cmp.AllowUnexported(action.ExtendVolumeGroupAction{}),
+			ExpectedOuput: []action.Action{
+				action.NewExtendVolumeGroupAction("db", "/dev/xvdg", nil).SetMode(config.DefaultMode).SetDevice("data"),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Volume Group Spans All Physical Volumes",
+			VolumeGroups: []*model.VolumeGroup{
+				{Name: "db", PhysicalVolume: "/dev/xvdf", Size: 2000},
+				{Name: "db", PhysicalVolume: "/dev/xvdg", Size: 2000},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: []action.Action{},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Volume Group Has Unconfigured Physical Volume",
+			VolumeGroups: []*model.VolumeGroup{
+				{Name: "db", PhysicalVolume: "/dev/xvdh", Size: 1000},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 data: Volume group db already exists and belongs to physical volume /dev/xvdh"),
+		},
+		{
+			Name: "Physical Volume Belongs To Another Volume Group",
+			VolumeGroups: []*model.VolumeGroup{
+				{Name: "logs", PhysicalVolume: "/dev/xvdg", Size: 1000},
+			},
+			CmpOption:     cmp.AllowUnexported(),
+			ExpectedOuput: nil,
+			ExpectedError: fmt.Errorf("🔴 data: Physical volume /dev/xvdg already has volume group logs associated"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lg := datastructures.NewLvmGraph()
+			for _, pv := range []string{"/dev/xvdf", "/dev/xvdg", "/dev/xvdh"} {
+				utils.ExpectErr("lg.AddDevice()", t, false, lg.AddDevice(pv, 1000))
+				utils.ExpectErr("lg.AddPhysicalVolume()", t, false, lg.AddPhysicalVolume(pv, 1000))
+			}
+			for _, vg := range subtest.VolumeGroups {
+				utils.ExpectErr("lg.AddVolumeGroup()", t, false, lg.AddVolumeGroup(vg.Name, vg.PhysicalVolume, vg.Size))
+			}
+			lb := backend.NewMockLinuxLvmBackend(lg)
+			cvgl := NewCreateVolumeGroupLayer(lb)
+			actions, err := cvgl.Modify(c)
+			utils.CheckError("cvgl.Modify()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("cvgl.Modify()", t, subtest.ExpectedOuput, actions, subtest.CmpOption)
+		})
+	}
+}
@@ -22,3 +22,64 @@ func ParseMode(s string) (Mode, error) {
 		return m, fmt.Errorf("🔴 Mode '%s' is not supported", s)
 	}
 }
+
+// PromptStyle determines how approval is sought for the actions of prompt mode.
+// Either each action is approved individually, just before it is executed, or
+// the actions handed to an executor are approved together, before any of them
+// are executed
+type PromptStyle string
+
+const (
+	IndividualPrompt PromptStyle = "individual"
+	BatchPrompt      PromptStyle = "batch"
+)
+
+func ParsePromptStyle(s string) (PromptStyle, error) {
+	// Each action is approved individually by default
+	if len(s) == 0 {
+		return IndividualPrompt, nil
+	}
+	ps := PromptStyle(s)
+	switch ps {
+	case IndividualPrompt, BatchPrompt:
+		return ps, nil
+	default:
+		return ps, fmt.Errorf("🔴 Prompt style '%s' is not supported", s)
+	}
+}
+
+// ActionKind provides a stable, machine-readable identifier for each type of action
+type ActionKind string
+
+const (
+	CreateDirectoryAction         ActionKind = "create-directory"
+	ChangeOwnerAction             ActionKind = "change-owner"
+	ChangePermissionsAction       ActionKind = "change-permissions"
+	FormatAction                  ActionKind = "format"
+	WipeAction                    ActionKind = "wipe"
+	ReformatAction                ActionKind = "reformat"
+	LabelAction                   ActionKind = "label"
+	TuneAction                    ActionKind = "tune"
+	ChangeUuidAction              ActionKind = "change-uuid"
+	RepairFileSystemAction        ActionKind = "repair-file-system"
+	MountAction                   ActionKind = "mount"
+	UnmountAction                 ActionKind = "unmount"
+	ResizeAction                  ActionKind = "resize"
+	CreatePhysicalVolumeAction    ActionKind = "create-physical-volume"
+	CreateVolumeGroupAction       ActionKind = "create-volume-group"
+	ExtendVolumeGroupAction       ActionKind = "extend-volume-group"
+	CreateLogicalVolumeAction     ActionKind = "create-logical-volume"
+	ActivateLogicalVolumeAction   ActionKind = "activate-logical-volume"
+	ResizePhysicalVolumeAction    ActionKind = "resize-physical-volume"
+	ResizeLogicalVolumeAction     ActionKind = "resize-logical-volume"
+	UpdateFstabEntryAction        ActionKind = "update-fstab-entry"
+	UpdateMountUnitAction         ActionKind = "update-mount-unit"
+	CreateRaidArrayAction         ActionKind = "create-raid-array"
+	AssembleRaidArrayAction       ActionKind = "assemble-raid-array"
+	FormatEncryptedDeviceAction   ActionKind = "format-encrypted-device"
+	ReformatEncryptedDeviceAction ActionKind = "reformat-encrypted-device"
+	OpenEncryptedDeviceAction     ActionKind = "open-encrypted-device"
+	CreateSwapFileAction          ActionKind = "create-swap-file"
+	ActivateSwapAction            ActionKind = "activate-swap"
+	DeactivateSwapAction          ActionKind = "deactivate-swap"
+)
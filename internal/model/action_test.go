@@ -47,3 +47,44 @@ func TestParseMode(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePromptStyle(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		PromptStyle    string
+		ExpectedOutput PromptStyle
+		ExpectedError  error
+	}{
+		{
+			Name:           "Default",
+			PromptStyle:    "",
+			ExpectedOutput: IndividualPrompt,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Individual",
+			PromptStyle:    "individual",
+			ExpectedOutput: IndividualPrompt,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Batch",
+			PromptStyle:    "batch",
+			ExpectedOutput: BatchPrompt,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Invalid",
+			PromptStyle:    "invalid",
+			ExpectedOutput: PromptStyle("invalid"),
+			ExpectedError:  fmt.Errorf("🔴 Prompt style 'invalid' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ps, err := ParsePromptStyle(subtest.PromptStyle)
+			utils.CheckError("ParsePromptStyle()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParsePromptStyle()", t, subtest.ExpectedOutput, ps)
+		})
+	}
+}
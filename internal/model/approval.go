@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// A fingerprint identifies an action by its kind, the device that it was generated
+// for and its parameters. The fields match those of an action event, so that an
+// event emitted with -output=json is also a fingerprint
+type Fingerprint struct {
+	Action     ActionKind        `json:"action"`
+	Device     string            `json:"device"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// String encodes a fingerprint as JSON. The parameters are encoded in the order of
+// their keys, so that equivalent fingerprints are always encoded identically
+func (f Fingerprint) String() string {
+	if f.Parameters == nil {
+		f.Parameters = map[string]string{}
+	}
+	b, _ := json.Marshal(f)
+	return string(b)
+}
+
+// Approvals are the fingerprints of the actions that were approved in advance
+type Approvals struct {
+	fingerprints map[string]bool
+}
+
+func NewApprovals(fingerprints ...Fingerprint) *Approvals {
+	a := &Approvals{
+		fingerprints: map[string]bool{},
+	}
+	for _, f := range fingerprints {
+		a.fingerprints[f.String()] = true
+	}
+	return a
+}
+
+// ParseApprovals decodes a file of approvals, where each line is the fingerprint
+// of an approved action. Attributes of an event that are not part of a fingerprint
+// (e.g. its mode and error) are ignored. Blank lines and comments are skipped
+func ParseApprovals(data string) (*Approvals, error) {
+	fingerprints := []Fingerprint{}
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var f Fingerprint
+		if err := json.Unmarshal([]byte(line), &f); err != nil || len(f.Action) == 0 {
+			return nil, fmt.Errorf("Line %d is not a valid action fingerprint", i+1)
+		}
+		fingerprints = append(fingerprints, f)
+	}
+	return NewApprovals(fingerprints...), nil
+}
+
+func (a *Approvals) IsApproved(f Fingerprint) bool {
+	return a.fingerprints[f.String()]
+}
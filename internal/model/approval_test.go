@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseApprovals(t *testing.T) {
+	format := Fingerprint{
+		Action:     "format",
+		Device:     "/dev/xvdf",
+		Parameters: map[string]string{"device": "/dev/xvdf", "fileSystem": "ext4"},
+	}
+	subtests := []struct {
+		Name           string
+		Data           string
+		Fingerprint    Fingerprint
+		ExpectedOutput bool
+		ExpectedError  error
+	}{
+		{
+			Name: "Refused Event",
+			Data: `# Approved by the platform team
+{"event":"action.refused","device":"/dev/xvdf","action":"format","parameters":{"device":"/dev/xvdf","fileSystem":"ext4"},"mode":"healthcheck","error":"🔴 Healthcheck mode enabled. Refused to format /dev/xvdf to ext4"}
+`,
+			Fingerprint:    format,
+			ExpectedOutput: true,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Parameters In Any Order",
+			Data:           `{"action":"format","device":"/dev/xvdf","parameters":{"fileSystem":"ext4","device":"/dev/xvdf"}}`,
+			Fingerprint:    format,
+			ExpectedOutput: true,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Different Parameters",
+			Data:           `{"action":"format","device":"/dev/xvdf","parameters":{"device":"/dev/xvdf","fileSystem":"xfs"}}`,
+			Fingerprint:    format,
+			ExpectedOutput: false,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Different Device",
+			Data:           `{"action":"format","device":"/dev/xvdg","parameters":{"device":"/dev/xvdf","fileSystem":"ext4"}}`,
+			Fingerprint:    format,
+			ExpectedOutput: false,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Empty File",
+			Data:           "",
+			Fingerprint:    format,
+			ExpectedOutput: false,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Malformed Fingerprint",
+			Data:           "\n{\"action\":\"format\"",
+			Fingerprint:    format,
+			ExpectedOutput: false,
+			ExpectedError:  fmt.Errorf("Line 2 is not a valid action fingerprint"),
+		},
+		{
+			Name:           "Fingerprint Without Action",
+			Data:           `{"event":"run.failed","error":"🔴 Healthcheck mode enabled. Refused to format /dev/xvdf to ext4"}`,
+			Fingerprint:    format,
+			ExpectedOutput: false,
+			ExpectedError:  fmt.Errorf("Line 1 is not a valid action fingerprint"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			a, err := ParseApprovals(subtest.Data)
+			utils.CheckError("ParseApprovals()", t, subtest.ExpectedError, err)
+			if err != nil {
+				return
+			}
+			utils.CheckOutput("a.IsApproved()", t, subtest.ExpectedOutput, a.IsApproved(subtest.Fingerprint))
+		})
+	}
+}
+
+func TestFingerprintString(t *testing.T) {
+	f := Fingerprint{Action: "mock", Device: "/dev/xvdf"}
+	utils.CheckOutput("f.String()", t, `{"action":"mock","device":"/dev/xvdf","parameters":{}}`, f.String())
+}
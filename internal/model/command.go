@@ -0,0 +1,23 @@
+package model
+
+import (
+	"fmt"
+)
+
+type Command string
+
+const (
+	Bootstrap Command = ""
+	Plan      Command = "plan"
+	Apply     Command = "apply"
+)
+
+func ParseCommand(s string) (Command, error) {
+	c := Command(s)
+	switch c {
+	case Bootstrap, Plan, Apply:
+		return c, nil
+	default:
+		return c, fmt.Errorf("🔴 Command '%s' is not supported", s)
+	}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseCommand(t *testing.T) {
+	subtests := []struct {
+		Command        string
+		ExpectedOutput Command
+		ExpectedError  error
+	}{
+		{
+			Command:        "",
+			ExpectedOutput: Bootstrap,
+			ExpectedError:  nil,
+		},
+		{
+			Command:        "plan",
+			ExpectedOutput: Plan,
+			ExpectedError:  nil,
+		},
+		{
+			Command:        "apply",
+			ExpectedOutput: Apply,
+			ExpectedError:  nil,
+		},
+		{
+			Command:        "invalid",
+			ExpectedOutput: Command("invalid"),
+			ExpectedError:  fmt.Errorf("🔴 Command 'invalid' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Command, func(t *testing.T) {
+			c, err := ParseCommand(subtest.Command)
+			utils.CheckError("ParseCommand()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseCommand()", t, subtest.ExpectedOutput, c)
+		})
+	}
+}
@@ -1,15 +1,104 @@
 package model
 
 import (
+	"fmt"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 )
 
+// The id of an EBS volume has either 8 or 17 hexadecimal characters (e.g. vol-0123456789abcdef0)
+var volumeIdRegex = regexp.MustCompile(`^vol-([0-9a-f]{8}|[0-9a-f]{17})$`)
+
+func IsVolumeId(s string) bool {
+	return volumeIdRegex.MatchString(s)
+}
+
+// A UUID of random requests that a new UUID be generated for a file system
+const RandomUuid = "random"
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func IsUuid(s string) bool {
+	return uuidRegex.MatchString(s)
+}
+
 type BlockDevice struct {
 	Name       string
 	MountPoint string
 	FileSystem FileSystem
 	Label      string
+	UUID       string
+	// The unique id of a partition, as recorded by its partition table
+	PartUUID   string
+	Size       uint64 // bytes
+	Rotational bool
+	ReadOnly   bool
+	// The devices that are built upon the block device (e.g. /dev/dm-0)
+	Holders []string
+}
+
+// A reformat policy determines whether a device with an existing file system,
+// other than the one that was requested, can be reformatted
+type ReformatPolicy string
+
+const (
+	ReformatNever ReformatPolicy = "never"
+	// Only applicable to instance store volumes, whose contents are not expected
+	// to outlive the instance
+	ReformatOnMismatch ReformatPolicy = "onMismatch"
+)
+
+func ParseReformatPolicy(s string) (ReformatPolicy, error) {
+	rp := ReformatPolicy(s)
+	switch rp {
+	case ReformatNever, ReformatOnMismatch:
+		return rp, nil
+	default:
+		return rp, fmt.Errorf("Reformat policy '%s' is not supported", s)
+	}
+}
+
+// A signature is the magic string that identifies a file system, partition table
+// or any other on-disk structure (e.g. a LUKS header) at an offset of a device
+type Signature struct {
+	Type   string
+	Offset uint64
+}
+
+func (s *Signature) String() string {
+	return fmt.Sprintf("%s (offset %#x)", s.Type, s.Offset)
+}
+
+func JoinSignatures(signatures []*Signature) string {
+	s := make([]string, len(signatures))
+	for i, signature := range signatures {
+		s[i] = signature.String()
+	}
+	return strings.Join(s, ", ")
+}
+
+var signatureRegex = regexp.MustCompile(`^(.+) \(offset (0x[0-9a-f]+)\)$`)
+
+// ParseSignatures decodes signatures that were described by JoinSignatures
+func ParseSignatures(s string) ([]*Signature, error) {
+	signatures := []*Signature{}
+	if len(s) == 0 {
+		return signatures, nil
+	}
+	for _, signature := range strings.Split(s, ", ") {
+		m := signatureRegex.FindStringSubmatch(signature)
+		if m == nil {
+			return nil, fmt.Errorf("Signature '%s' is not supported", signature)
+		}
+		offset, err := strconv.ParseUint(m[2], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Signature '%s' is not supported", signature)
+		}
+		signatures = append(signatures, &Signature{Type: m[1], Offset: offset})
+	}
+	return signatures, nil
 }
 
 type MountOptions string
@@ -28,6 +117,20 @@ func (mop MountOptions) Remount() MountOptions {
 	return MountOptions(strings.Join(mops, ","))
 }
 
+// With appends each of the options that is not already present
+func (mop MountOptions) With(options ...string) MountOptions {
+	mops := []string{}
+	if len(mop) > 0 {
+		mops = strings.Split(string(mop), ",")
+	}
+	for _, o := range options {
+		if !slices.Contains(mops, o) {
+			mops = append(mops, o)
+		}
+	}
+	return MountOptions(strings.Join(mops, ","))
+}
+
 type BlockDeviceMetrics struct {
 	FileSystemSize  uint64
 	BlockDeviceSize uint64
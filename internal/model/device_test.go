@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
@@ -33,3 +34,181 @@ func TestRemount(t *testing.T) {
 		utils.CheckOutput("ParseRemount()", t, subtest.ExpectedOutput, mo)
 	}
 }
+
+func TestMountOptionsWith(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		MountOptions   MountOptions
+		Options        []string
+		ExpectedOutput MountOptions
+	}{
+		{
+			Name:           "Empty",
+			MountOptions:   MountOptions(""),
+			Options:        []string{"nofail"},
+			ExpectedOutput: MountOptions("nofail"),
+		},
+		{
+			Name:           "Options Not Present",
+			MountOptions:   MountOptions("defaults"),
+			Options:        []string{"nofail", "x-systemd.requires=/dev/xvdf"},
+			ExpectedOutput: MountOptions("defaults,nofail,x-systemd.requires=/dev/xvdf"),
+		},
+		{
+			Name:           "Option Already Present",
+			MountOptions:   MountOptions("defaults,nofail"),
+			Options:        []string{"nofail"},
+			ExpectedOutput: MountOptions("defaults,nofail"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput("mop.With()", t, subtest.ExpectedOutput, subtest.MountOptions.With(subtest.Options...))
+		})
+	}
+}
+
+func TestIsVolumeId(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		VolumeId       string
+		ExpectedOutput bool
+	}{
+		{
+			Name:           "Volume Id",
+			VolumeId:       "vol-0123456789abcdef0",
+			ExpectedOutput: true,
+		},
+		{
+			Name:           "Legacy Volume Id",
+			VolumeId:       "vol-01234567",
+			ExpectedOutput: true,
+		},
+		{
+			Name:           "Volume Id Without Dash",
+			VolumeId:       "vol0123456789abcdef0",
+			ExpectedOutput: false,
+		},
+		{
+			Name:           "Uppercase Volume Id",
+			VolumeId:       "vol-0123456789ABCDEF0",
+			ExpectedOutput: false,
+		},
+		{
+			Name:           "Device",
+			VolumeId:       "/dev/sdb",
+			ExpectedOutput: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput("IsVolumeId()", t, subtest.ExpectedOutput, IsVolumeId(subtest.VolumeId))
+		})
+	}
+}
+
+func TestIsUuid(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Uuid           string
+		ExpectedOutput bool
+	}{
+		{
+			Name:           "Uuid",
+			Uuid:           "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+			ExpectedOutput: true,
+		},
+		{
+			Name:           "Uppercase Uuid",
+			Uuid:           "31CF3150-F4B1-409A-9887-7F9DA1FDD99C",
+			ExpectedOutput: true,
+		},
+		{
+			Name:           "Uuid Without Dashes",
+			Uuid:           "31cf3150f4b1409a98877f9da1fdd99c",
+			ExpectedOutput: false,
+		},
+		{
+			Name:           "Random",
+			Uuid:           RandomUuid,
+			ExpectedOutput: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput("IsUuid()", t, subtest.ExpectedOutput, IsUuid(subtest.Uuid))
+		})
+	}
+}
+
+func TestParseReformatPolicy(t *testing.T) {
+	subtests := []struct {
+		ReformatPolicy string
+		ExpectedOutput ReformatPolicy
+		ExpectedError  error
+	}{
+		{
+			ReformatPolicy: "never",
+			ExpectedOutput: ReformatNever,
+			ExpectedError:  nil,
+		},
+		{
+			ReformatPolicy: "onMismatch",
+			ExpectedOutput: ReformatOnMismatch,
+			ExpectedError:  nil,
+		},
+		{
+			ReformatPolicy: "always",
+			ExpectedOutput: ReformatPolicy("always"),
+			ExpectedError:  fmt.Errorf("Reformat policy 'always' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.ReformatPolicy, func(t *testing.T) {
+			rp, err := ParseReformatPolicy(subtest.ReformatPolicy)
+			utils.CheckError("ParseReformatPolicy()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseReformatPolicy()", t, subtest.ExpectedOutput, rp)
+		})
+	}
+}
+
+func TestParseSignatures(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Signatures     string
+		ExpectedOutput []*Signature
+		ExpectedError  error
+	}{
+		{
+			Name:           "No Signatures",
+			Signatures:     "",
+			ExpectedOutput: []*Signature{},
+			ExpectedError:  nil,
+		},
+		{
+			Name:       "Multiple Signatures",
+			Signatures: "gpt (offset 0x200), PMBR (offset 0x1fe)",
+			ExpectedOutput: []*Signature{
+				{Type: "gpt", Offset: 0x200},
+				{Type: "PMBR", Offset: 0x1fe},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "Malformed Signature",
+			Signatures:     "gpt",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Signature 'gpt' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			signatures, err := ParseSignatures(subtest.Signatures)
+			utils.CheckError("ParseSignatures()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseSignatures()", t, subtest.ExpectedOutput, signatures)
+			if err == nil {
+				utils.CheckOutput("JoinSignatures()", t, subtest.Signatures, JoinSignatures(signatures))
+			}
+		})
+	}
+}
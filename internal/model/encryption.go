@@ -0,0 +1,17 @@
+package model
+
+const (
+	// An open LUKS device is exposed to the host as /dev/mapper/<name>
+	MapperDirectory = "/dev/mapper"
+	// Ephemeral keys are written to a tmpfs so that they never outlive the host
+	EphemeralKeyDirectory = "/run/ebs-bootstrap/keys"
+	// The size (bytes) of a randomly generated ephemeral key
+	EphemeralKeySize = 64
+)
+
+// EncryptedDevice is an active mapping of a LUKS device (e.g. /dev/nvme1n1) to the
+// block device that exposes its decrypted contents (e.g. /dev/mapper/scratch)
+type EncryptedDevice struct {
+	Name   string
+	Device string
+}
@@ -0,0 +1,34 @@
+package model
+
+type EventKind string
+
+const (
+	LayerStarted     EventKind = "layer.started"
+	LayerWarning     EventKind = "layer.warning"
+	ActionProposed   EventKind = "action.proposed"
+	ActionPlanned    EventKind = "action.planned"
+	ActionExecuted   EventKind = "action.executed"
+	ActionRefused    EventKind = "action.refused"
+	ActionFailed     EventKind = "action.failed"
+	ValidationPassed EventKind = "validation.passed"
+	ValidationFailed EventKind = "validation.failed"
+	PlanCreated      EventKind = "plan.created"
+	PlanSaved        EventKind = "plan.saved"
+	PlanApplied      EventKind = "plan.applied"
+	RunFailed        EventKind = "run.failed"
+)
+
+// Event is a structured record of a notable occurrence during a run. Fields
+// that are not relevant to a particular kind of event are left empty
+type Event struct {
+	Kind       EventKind         `json:"event"`
+	Device     string            `json:"device,omitempty"`
+	Layer      string            `json:"layer,omitempty"`
+	Action     ActionKind        `json:"action,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Mode       Mode              `json:"mode,omitempty"`
+	// The position of a planned action in the overall order of execution
+	Position int    `json:"position,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
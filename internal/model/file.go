@@ -3,7 +3,9 @@ package model
 import (
 	"fmt"
 	"io/fs"
+	"path"
 	"strconv"
+	"strings"
 )
 
 type FileType uint32
@@ -58,3 +60,24 @@ func (p *FilePermissions) UnmarshalYAML(unmarshal func(interface{}) error) error
 	*p = FilePermissions(mode)
 	return nil
 }
+
+// PathDepth returns the number of components of a normalised absolute path
+// (e.g. /mnt/app/logs -> 3). The root directory has a depth of 0
+func PathDepth(p string) int {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if len(p) == 0 {
+		return 0
+	}
+	return strings.Count(p, "/") + 1
+}
+
+// IsNestedPath reports whether a path is a descendant of another path. A path
+// is not considered to be nested within itself
+func IsNestedPath(p string, ancestor string) bool {
+	p = path.Clean(p)
+	ancestor = path.Clean(ancestor)
+	if p == ancestor {
+		return false
+	}
+	return strings.HasPrefix(p, strings.TrimSuffix(ancestor, "/")+"/")
+}
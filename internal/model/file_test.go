@@ -75,3 +75,71 @@ func TestPerm(t *testing.T) {
 		})
 	}
 }
+
+func TestPathDepth(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Path           string
+		ExpectedOutput int
+	}{
+		{
+			Name:           "Root Directory",
+			Path:           "/",
+			ExpectedOutput: 0,
+		},
+		{
+			Name:           "Nested Directory",
+			Path:           "/mnt/app/logs",
+			ExpectedOutput: 3,
+		},
+		{
+			Name:           "Redundant Slashes",
+			Path:           "//mnt//app/",
+			ExpectedOutput: 2,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput("PathDepth()", t, subtest.ExpectedOutput, PathDepth(subtest.Path))
+		})
+	}
+}
+
+func TestIsNestedPath(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Path           string
+		Ancestor       string
+		ExpectedOutput bool
+	}{
+		{
+			Name:           "Nested",
+			Path:           "/mnt/app/logs",
+			Ancestor:       "/mnt/app",
+			ExpectedOutput: true,
+		},
+		{
+			Name:           "Nested Within Root Directory",
+			Path:           "/mnt",
+			Ancestor:       "/",
+			ExpectedOutput: true,
+		},
+		{
+			Name:           "Same Path",
+			Path:           "/mnt/app/",
+			Ancestor:       "/mnt/app",
+			ExpectedOutput: false,
+		},
+		{
+			Name:           "Common Prefix",
+			Path:           "/mnt/application",
+			Ancestor:       "/mnt/app",
+			ExpectedOutput: false,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput("IsNestedPath()", t, subtest.ExpectedOutput, IsNestedPath(subtest.Path, subtest.Ancestor))
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
 
 type FileSystem string
 
@@ -8,7 +13,14 @@ const (
 	Unformatted FileSystem = ""
 	Ext4        FileSystem = "ext4"
 	Xfs         FileSystem = "xfs"
+	Btrfs       FileSystem = "btrfs"
 	Lvm         FileSystem = "LVM2_member"
+	RaidMember  FileSystem = "linux_raid_member"
+	Luks        FileSystem = "crypto_LUKS"
+	Swap        FileSystem = "swap"
+	// A file system that is recognised, but not supported (e.g. ext3). It can
+	// never be configured for a device
+	Unknown FileSystem = "unknown"
 )
 
 func (fs FileSystem) String() string {
@@ -21,9 +33,256 @@ func (fs FileSystem) String() string {
 func ParseFileSystem(s string) (FileSystem, error) {
 	fst := FileSystem(s)
 	switch fst {
-	case Unformatted, Ext4, Xfs, Lvm:
+	case Unformatted, Ext4, Xfs, Btrfs, Lvm, RaidMember, Luks, Swap, Unknown:
 		return fst, nil
 	default:
 		return fst, fmt.Errorf("File system '%s' is not supported", fst.String())
 	}
 }
+
+// FormatOptions tune a file system when it is created. Not every option is
+// supported by every file system. An option that is omitted is left to the
+// discretion of mkfs
+type FormatOptions struct {
+	BlockSize             uint64 // bytes
+	InodeRatio            uint64 // bytes per inode
+	ReservedBlocksPercent *uint64
+	LazyItableInit        *bool
+	Reflink               *bool
+	// The stripe unit (KiB) and stripe width (number of data devices) of the
+	// RAID array that the file system is created upon
+	StripeUnit  uint64
+	StripeWidth uint64
+	// The label is not a user-provided option. It is assigned at format time by
+	// a file system that supports it, which saves a separate labelling pass
+	Label string
+}
+
+// String describes the user-provided options in a stable order
+func (fo *FormatOptions) String() string {
+	if fo == nil {
+		return ""
+	}
+	options := []string{}
+	if fo.BlockSize > 0 {
+		options = append(options, fmt.Sprintf("blockSize=%d", fo.BlockSize))
+	}
+	if fo.InodeRatio > 0 {
+		options = append(options, fmt.Sprintf("inodeRatio=%d", fo.InodeRatio))
+	}
+	if fo.ReservedBlocksPercent != nil {
+		options = append(options, fmt.Sprintf("reservedBlocksPercent=%d", *fo.ReservedBlocksPercent))
+	}
+	if fo.LazyItableInit != nil {
+		options = append(options, fmt.Sprintf("lazyItableInit=%t", *fo.LazyItableInit))
+	}
+	if fo.Reflink != nil {
+		options = append(options, fmt.Sprintf("reflink=%t", *fo.Reflink))
+	}
+	if fo.StripeUnit > 0 {
+		options = append(options, fmt.Sprintf("stripeUnit=%d", fo.StripeUnit))
+	}
+	if fo.StripeWidth > 0 {
+		options = append(options, fmt.Sprintf("stripeWidth=%d", fo.StripeWidth))
+	}
+	return strings.Join(options, ",")
+}
+
+// ParseFormatOptions decodes format options that were described by String. The
+// label is not described, and therefore, it is never decoded
+func ParseFormatOptions(s string) (*FormatOptions, error) {
+	fo := &FormatOptions{}
+	for key, value := range splitOptions(s) {
+		var err error
+		switch key {
+		case "blockSize":
+			fo.BlockSize, err = strconv.ParseUint(value, 10, 64)
+		case "inodeRatio":
+			fo.InodeRatio, err = strconv.ParseUint(value, 10, 64)
+		case "reservedBlocksPercent":
+			var rbp uint64
+			rbp, err = strconv.ParseUint(value, 10, 64)
+			fo.ReservedBlocksPercent = &rbp
+		case "lazyItableInit":
+			var lii bool
+			lii, err = strconv.ParseBool(value)
+			fo.LazyItableInit = &lii
+		case "reflink":
+			var rl bool
+			rl, err = strconv.ParseBool(value)
+			fo.Reflink = &rl
+		case "stripeUnit":
+			fo.StripeUnit, err = strconv.ParseUint(value, 10, 64)
+		case "stripeWidth":
+			fo.StripeWidth, err = strconv.ParseUint(value, 10, 64)
+		default:
+			return nil, fmt.Errorf("Format option '%s' is not supported", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Format option '%s' has an invalid value '%s'", key, value)
+		}
+	}
+	return fo, nil
+}
+
+// splitOptions splits a comma-separated list of key=value pairs
+func splitOptions(s string) map[string]string {
+	options := map[string]string{}
+	if len(s) == 0 {
+		return options
+	}
+	for _, option := range strings.Split(s, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		options[key] = value
+	}
+	return options
+}
+
+type ErrorBehaviour string
+
+const (
+	ErrorsContinue   ErrorBehaviour = "continue"
+	ErrorsRemountRo  ErrorBehaviour = "remount-ro"
+	ErrorsPanic      ErrorBehaviour = "panic"
+	ErrorsUnassigned ErrorBehaviour = ""
+)
+
+func ParseErrorBehaviour(s string) (ErrorBehaviour, error) {
+	eb := ErrorBehaviour(s)
+	switch eb {
+	case ErrorsUnassigned, ErrorsContinue, ErrorsRemountRo, ErrorsPanic:
+		return eb, nil
+	default:
+		return eb, fmt.Errorf("Error behaviour '%s' is not supported", s)
+	}
+}
+
+// FileSystemProperties are the properties of an existing file system that can be
+// tuned in place. A property that is omitted is left unchanged
+type FileSystemProperties struct {
+	ReservedBlocksPercent *uint64
+	// A maximum mount count of -1 disables the mount count dependent file system check
+	MaxMountCount *int64
+	Errors        ErrorBehaviour
+	// Features can only be enabled. A feature that is not listed is left unchanged
+	Features []string
+}
+
+// String describes the provided properties in a stable order
+func (fp *FileSystemProperties) String() string {
+	if fp == nil {
+		return ""
+	}
+	properties := []string{}
+	if fp.ReservedBlocksPercent != nil {
+		properties = append(properties, fmt.Sprintf("reservedBlocksPercent=%d", *fp.ReservedBlocksPercent))
+	}
+	if fp.MaxMountCount != nil {
+		properties = append(properties, fmt.Sprintf("maxMountCount=%d", *fp.MaxMountCount))
+	}
+	if len(fp.Errors) > 0 {
+		properties = append(properties, fmt.Sprintf("errors=%s", fp.Errors))
+	}
+	if len(fp.Features) > 0 {
+		properties = append(properties, fmt.Sprintf("features=%s", strings.Join(fp.Features, "+")))
+	}
+	return strings.Join(properties, ",")
+}
+
+// ParseFileSystemProperties decodes file system properties that were described by String
+func ParseFileSystemProperties(s string) (*FileSystemProperties, error) {
+	fp := &FileSystemProperties{}
+	for key, value := range splitOptions(s) {
+		var err error
+		switch key {
+		case "reservedBlocksPercent":
+			var rbp uint64
+			rbp, err = strconv.ParseUint(value, 10, 64)
+			fp.ReservedBlocksPercent = &rbp
+		case "maxMountCount":
+			var mmc int64
+			mmc, err = strconv.ParseInt(value, 10, 64)
+			fp.MaxMountCount = &mmc
+		case "errors":
+			fp.Errors, err = ParseErrorBehaviour(value)
+		case "features":
+			fp.Features = strings.Split(value, "+")
+		default:
+			return nil, fmt.Errorf("File system property '%s' is not supported", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("File system property '%s' has an invalid value '%s'", key, value)
+		}
+	}
+	return fp, nil
+}
+
+// Diff returns the desired properties that differ from the current properties of
+// a file system, or nil when there are none. A property that could not be read
+// from the file system is always considered to differ
+func (fp *FileSystemProperties) Diff(current *FileSystemProperties) *FileSystemProperties {
+	if fp == nil {
+		return nil
+	}
+	if current == nil {
+		current = &FileSystemProperties{}
+	}
+	diff := &FileSystemProperties{}
+	changed := false
+	if d := fp.ReservedBlocksPercent; d != nil {
+		if c := current.ReservedBlocksPercent; c == nil || *c != *d {
+			diff.ReservedBlocksPercent = d
+			changed = true
+		}
+	}
+	if d := fp.MaxMountCount; d != nil {
+		if c := current.MaxMountCount; c == nil || *c != *d {
+			diff.MaxMountCount = d
+			changed = true
+		}
+	}
+	if len(fp.Errors) > 0 && fp.Errors != current.Errors {
+		diff.Errors = fp.Errors
+		changed = true
+	}
+	for _, f := range fp.Features {
+		if !slices.Contains(current.Features, f) {
+			diff.Features = append(diff.Features, f)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return diff
+}
+
+// FsckMode determines how a file system is checked before it is mounted. A file
+// system is either only checked, or repaired when errors are found
+type FsckMode string
+
+const (
+	FsckUnassigned FsckMode = ""
+	FsckCheck      FsckMode = "check"
+	FsckRepair     FsckMode = "repair"
+)
+
+func ParseFsckMode(s string) (FsckMode, error) {
+	fm := FsckMode(s)
+	switch fm {
+	case FsckUnassigned, FsckCheck, FsckRepair:
+		return fm, nil
+	default:
+		return fm, fmt.Errorf("File system check '%s' is not supported", s)
+	}
+}
+
+// FsckResult is the outcome of a file system check, as interpreted from the exit
+// code of the tool that performed it
+type FsckResult string
+
+const (
+	FsckClean       FsckResult = "clean"
+	FsckCorrected   FsckResult = "corrected"
+	FsckUncorrected FsckResult = "uncorrected"
+)
@@ -29,6 +29,31 @@ func TestParseFileSystem(t *testing.T) {
 			ExpectedOutput: Ext4,
 			ExpectedError:  nil,
 		},
+		{
+			FileSystem:     "btrfs",
+			ExpectedOutput: Btrfs,
+			ExpectedError:  nil,
+		},
+		{
+			FileSystem:     "linux_raid_member",
+			ExpectedOutput: RaidMember,
+			ExpectedError:  nil,
+		},
+		{
+			FileSystem:     "crypto_LUKS",
+			ExpectedOutput: Luks,
+			ExpectedError:  nil,
+		},
+		{
+			FileSystem:     "swap",
+			ExpectedOutput: Swap,
+			ExpectedError:  nil,
+		},
+		{
+			FileSystem:     "unknown",
+			ExpectedOutput: Unknown,
+			ExpectedError:  nil,
+		},
 		{
 			FileSystem:     "jfs",
 			ExpectedOutput: FileSystem("jfs"),
@@ -69,3 +94,255 @@ func TestFileSystemString(t *testing.T) {
 		})
 	}
 }
+
+func TestParseErrorBehaviour(t *testing.T) {
+	subtests := []struct {
+		ErrorBehaviour string
+		ExpectedOutput ErrorBehaviour
+		ExpectedError  error
+	}{
+		{
+			ErrorBehaviour: "",
+			ExpectedOutput: ErrorsUnassigned,
+			ExpectedError:  nil,
+		},
+		{
+			ErrorBehaviour: "remount-ro",
+			ExpectedOutput: ErrorsRemountRo,
+			ExpectedError:  nil,
+		},
+		{
+			ErrorBehaviour: "halt",
+			ExpectedOutput: ErrorBehaviour("halt"),
+			ExpectedError:  fmt.Errorf("Error behaviour 'halt' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.ErrorBehaviour, func(t *testing.T) {
+			eb, err := ParseErrorBehaviour(subtest.ErrorBehaviour)
+			utils.CheckError("ParseErrorBehaviour()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseErrorBehaviour()", t, subtest.ExpectedOutput, eb)
+		})
+	}
+}
+
+func TestFileSystemPropertiesDiff(t *testing.T) {
+	zero := uint64(0)
+	five := uint64(5)
+	disabled := int64(-1)
+	subtests := []struct {
+		Name           string
+		Desired        *FileSystemProperties
+		Current        *FileSystemProperties
+		ExpectedOutput *FileSystemProperties
+	}{
+		{
+			Name: "Identical Properties",
+			Desired: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+				MaxMountCount:         &disabled,
+				Errors:                ErrorsRemountRo,
+				Features:              []string{"metadata_csum"},
+			},
+			Current: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+				MaxMountCount:         &disabled,
+				Errors:                ErrorsRemountRo,
+				Features:              []string{"has_journal", "metadata_csum"},
+			},
+			ExpectedOutput: nil,
+		},
+		{
+			Name: "Omitted Properties Are Left Unchanged",
+			Desired: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+			},
+			Current: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+				Errors:                ErrorsContinue,
+			},
+			ExpectedOutput: nil,
+		},
+		{
+			Name: "Differing Properties",
+			Desired: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+				MaxMountCount:         &disabled,
+				Errors:                ErrorsRemountRo,
+				Features:              []string{"metadata_csum", "fast_commit"},
+			},
+			Current: &FileSystemProperties{
+				ReservedBlocksPercent: &five,
+				MaxMountCount:         &disabled,
+				Errors:                ErrorsContinue,
+				Features:              []string{"metadata_csum"},
+			},
+			ExpectedOutput: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+				Errors:                ErrorsRemountRo,
+				Features:              []string{"fast_commit"},
+			},
+		},
+		{
+			Name: "Unknown Properties",
+			Desired: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+			},
+			Current: &FileSystemProperties{},
+			ExpectedOutput: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+			},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			diff := subtest.Desired.Diff(subtest.Current)
+			utils.CheckOutput("FileSystemProperties.Diff()", t, subtest.ExpectedOutput, diff)
+		})
+	}
+}
+
+func TestFileSystemPropertiesString(t *testing.T) {
+	zero := uint64(0)
+	disabled := int64(-1)
+	fp := &FileSystemProperties{
+		ReservedBlocksPercent: &zero,
+		MaxMountCount:         &disabled,
+		Errors:                ErrorsRemountRo,
+		Features:              []string{"metadata_csum", "fast_commit"},
+	}
+	expected := "reservedBlocksPercent=0,maxMountCount=-1,errors=remount-ro,features=metadata_csum+fast_commit"
+	utils.CheckOutput("FileSystemProperties.String()", t, expected, fp.String())
+}
+
+func TestParseFileSystemProperties(t *testing.T) {
+	zero := uint64(0)
+	disabled := int64(-1)
+	subtests := []struct {
+		Name           string
+		Properties     string
+		ExpectedOutput *FileSystemProperties
+		ExpectedError  error
+	}{
+		{
+			Name:       "Valid Properties",
+			Properties: "reservedBlocksPercent=0,maxMountCount=-1,errors=remount-ro,features=metadata_csum+fast_commit",
+			ExpectedOutput: &FileSystemProperties{
+				ReservedBlocksPercent: &zero,
+				MaxMountCount:         &disabled,
+				Errors:                ErrorsRemountRo,
+				Features:              []string{"metadata_csum", "fast_commit"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "Unsupported Property",
+			Properties:     "journal=true",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("File system property 'journal' is not supported"),
+		},
+		{
+			Name:           "Invalid Error Behaviour",
+			Properties:     "errors=ignore",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("File system property 'errors' has an invalid value 'ignore'"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			fp, err := ParseFileSystemProperties(subtest.Properties)
+			utils.CheckError("ParseFileSystemProperties()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseFileSystemProperties()", t, subtest.ExpectedOutput, fp)
+		})
+	}
+}
+
+func TestParseFormatOptions(t *testing.T) {
+	zero := uint64(0)
+	enabled := true
+	subtests := []struct {
+		Name           string
+		Options        string
+		ExpectedOutput *FormatOptions
+		ExpectedError  error
+	}{
+		{
+			Name:           "No Options",
+			Options:        "",
+			ExpectedOutput: &FormatOptions{},
+			ExpectedError:  nil,
+		},
+		{
+			Name:    "Valid Options",
+			Options: "blockSize=4096,inodeRatio=16384,reservedBlocksPercent=0,lazyItableInit=true,reflink=true,stripeUnit=64,stripeWidth=2",
+			ExpectedOutput: &FormatOptions{
+				BlockSize:             4096,
+				InodeRatio:            16384,
+				ReservedBlocksPercent: &zero,
+				LazyItableInit:        &enabled,
+				Reflink:               &enabled,
+				StripeUnit:            64,
+				StripeWidth:           2,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "Unsupported Option",
+			Options:        "label=stateful",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Format option 'label' is not supported"),
+		},
+		{
+			Name:           "Invalid Value",
+			Options:        "reflink=maybe",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Format option 'reflink' has an invalid value 'maybe'"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			fo, err := ParseFormatOptions(subtest.Options)
+			utils.CheckError("ParseFormatOptions()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseFormatOptions()", t, subtest.ExpectedOutput, fo)
+			if err == nil {
+				utils.CheckOutput("FormatOptions.String()", t, subtest.Options, fo.String())
+			}
+		})
+	}
+}
+
+func TestParseFsckMode(t *testing.T) {
+	subtests := []struct {
+		FsckMode       string
+		ExpectedOutput FsckMode
+		ExpectedError  error
+	}{
+		{
+			FsckMode:       "",
+			ExpectedOutput: FsckUnassigned,
+			ExpectedError:  nil,
+		},
+		{
+			FsckMode:       "check",
+			ExpectedOutput: FsckCheck,
+			ExpectedError:  nil,
+		},
+		{
+			FsckMode:       "repair",
+			ExpectedOutput: FsckRepair,
+			ExpectedError:  nil,
+		},
+		{
+			FsckMode:       "force",
+			ExpectedOutput: FsckMode("force"),
+			ExpectedError:  fmt.Errorf("File system check 'force' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.FsckMode, func(t *testing.T) {
+			fm, err := ParseFsckMode(subtest.FsckMode)
+			utils.CheckError("ParseFsckMode()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseFsckMode()", t, subtest.ExpectedOutput, fm)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	FstabLabelPrefix = "LABEL="
+	FstabUuidPrefix  = "UUID="
+)
+
+// Whitespace is used to separate the fields of an fstab entry. Therefore, any
+// whitespace within a field must be escaped as an octal sequence
+var fstabEscaper = strings.NewReplacer(" ", `\040`, "\t", `\011`, "\n", `\012`, `\`, `\134`)
+var fstabUnescaper = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+type FstabEntry struct {
+	Source     string
+	MountPoint string
+	FileSystem FileSystem
+	Options    MountOptions
+	Dump       uint
+	Pass       uint
+}
+
+func (fe *FstabEntry) String() string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t%d\t%d",
+		fstabEscaper.Replace(fe.Source),
+		fstabEscaper.Replace(fe.MountPoint),
+		fe.FileSystem,
+		fe.Options,
+		fe.Dump,
+		fe.Pass,
+	)
+}
+
+// ParseFstabEntry decodes a single line of an fstab file. Comments and blank lines
+// are not entries and are reported as (nil, nil). The dump and pass fields are
+// optional and default to 0, as documented in fstab(5)
+func ParseFstabEntry(line string) (*FstabEntry, error) {
+	trimmed := strings.TrimSpace(line)
+	if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+		return nil, nil
+	}
+	fields := strings.Fields(trimmed)
+	if len(fields) < 4 || len(fields) > 6 {
+		return nil, fmt.Errorf("🔴 Failed to decode fstab entry '%s'", line)
+	}
+	numbers := []uint{0, 0}
+	for i, field := range fields[4:] {
+		n, err := strconv.ParseUint(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("🔴 Failed to decode fstab entry '%s'", line)
+		}
+		numbers[i] = uint(n)
+	}
+	return &FstabEntry{
+		Source:     fstabUnescaper.Replace(fields[0]),
+		MountPoint: fstabUnescaper.Replace(fields[1]),
+		FileSystem: FileSystem(fields[2]),
+		Options:    MountOptions(fields[3]),
+		Dump:       numbers[0],
+		Pass:       numbers[1],
+	}, nil
+}
+
+// ParseFstab decodes every entry of an fstab file. Lines that can not be decoded
+// are skipped, as they are not managed by ebs-bootstrap
+func ParseFstab(content string) []*FstabEntry {
+	entries := []*FstabEntry{}
+	for _, line := range strings.Split(content, "\n") {
+		fe, err := ParseFstabEntry(line)
+		if err != nil || fe == nil {
+			continue
+		}
+		entries = append(entries, fe)
+	}
+	return entries
+}
+
+// UpdateFstab replaces the entry for the mount point of the provided entry. The
+// first matching line is replaced in-place and any duplicates are removed. If
+// there is no matching line, the entry is appended. Every other line, including
+// comments, is left untouched
+func UpdateFstab(content string, entry *FstabEntry) string {
+	lines := []string{}
+	if len(content) > 0 {
+		lines = strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	}
+	updated := []string{}
+	found := false
+	for _, line := range lines {
+		fe, err := ParseFstabEntry(line)
+		if err != nil || fe == nil || fe.MountPoint != entry.MountPoint {
+			updated = append(updated, line)
+			continue
+		}
+		if !found {
+			updated = append(updated, entry.String())
+			found = true
+		}
+	}
+	if !found {
+		updated = append(updated, entry.String())
+	}
+	return strings.Join(updated, "\n") + "\n"
+}
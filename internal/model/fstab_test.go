@@ -0,0 +1,128 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseFstabEntry(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Line           string
+		ExpectedOutput *FstabEntry
+		ExpectedError  error
+	}{
+		{
+			Name: "Complete Entry",
+			Line: "LABEL=stateful\t/mnt/ebs\text4\tdefaults,nofail\t0\t2",
+			ExpectedOutput: &FstabEntry{
+				Source:     "LABEL=stateful",
+				MountPoint: "/mnt/ebs",
+				FileSystem: Ext4,
+				Options:    "defaults,nofail",
+				Dump:       0,
+				Pass:       2,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Omitted Dump and Pass",
+			Line: "UUID=9a9e3d3c /mnt/app xfs defaults",
+			ExpectedOutput: &FstabEntry{
+				Source:     "UUID=9a9e3d3c",
+				MountPoint: "/mnt/app",
+				FileSystem: Xfs,
+				Options:    "defaults",
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Escaped Mount Point",
+			Line: `LABEL=data /mnt/my\040data ext4 defaults 0 2`,
+			ExpectedOutput: &FstabEntry{
+				Source:     "LABEL=data",
+				MountPoint: "/mnt/my data",
+				FileSystem: Ext4,
+				Options:    "defaults",
+				Pass:       2,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "Comment",
+			Line:           "# /etc/fstab: static file system information.",
+			ExpectedOutput: nil,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Blank Line",
+			Line:           "   ",
+			ExpectedOutput: nil,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Malformed Entry",
+			Line:           "LABEL=data /mnt/data",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Failed to decode fstab entry 'LABEL=data /mnt/data'"),
+		},
+		{
+			Name:           "Non-Numeric Pass",
+			Line:           "LABEL=data /mnt/data ext4 defaults 0 x",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Failed to decode fstab entry 'LABEL=data /mnt/data ext4 defaults 0 x'"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			fe, err := ParseFstabEntry(subtest.Line)
+			utils.CheckError("ParseFstabEntry()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseFstabEntry()", t, subtest.ExpectedOutput, fe)
+		})
+	}
+}
+
+func TestUpdateFstab(t *testing.T) {
+	entry := &FstabEntry{
+		Source:     "LABEL=stateful",
+		MountPoint: "/mnt/ebs",
+		FileSystem: Ext4,
+		Options:    "defaults,nofail",
+		Dump:       0,
+		Pass:       2,
+	}
+	subtests := []struct {
+		Name           string
+		Content        string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Empty File",
+			Content:        "",
+			ExpectedOutput: "LABEL=stateful\t/mnt/ebs\text4\tdefaults,nofail\t0\t2\n",
+		},
+		{
+			Name:           "Append Entry",
+			Content:        "# static file system information\nLABEL=cloudimg-rootfs\t/\text4\tdefaults\t0 1",
+			ExpectedOutput: "# static file system information\nLABEL=cloudimg-rootfs\t/\text4\tdefaults\t0 1\nLABEL=stateful\t/mnt/ebs\text4\tdefaults,nofail\t0\t2\n",
+		},
+		{
+			Name:           "Replace Entry In-Place",
+			Content:        "LABEL=cloudimg-rootfs / ext4 defaults 0 1\nLABEL=stateful /mnt/ebs ext4 defaults,comment=cloudconfig 0 2\n# trailing comment\n",
+			ExpectedOutput: "LABEL=cloudimg-rootfs / ext4 defaults 0 1\nLABEL=stateful\t/mnt/ebs\text4\tdefaults,nofail\t0\t2\n# trailing comment\n",
+		},
+		{
+			Name:           "Remove Duplicate Entries",
+			Content:        "/dev/xvdf /mnt/ebs ext4 defaults 0 0\nmalformed\n/dev/nvme1n1 /mnt/ebs ext4 defaults 0 0\n",
+			ExpectedOutput: "LABEL=stateful\t/mnt/ebs\text4\tdefaults,nofail\t0\t2\nmalformed\n",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			content := UpdateFstab(subtest.Content, entry)
+			utils.CheckOutput("UpdateFstab()", t, subtest.ExpectedOutput, content)
+		})
+	}
+}
@@ -1,5 +1,11 @@
 package model
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type LvmState int32
 type LvmKind int32
 
@@ -26,20 +32,60 @@ type Device struct {
 }
 
 type PhysicalVolume struct {
-	Name string
-	Size uint64
+	Name string `json:"name"`
+	Size uint64 `json:"size"`
 }
 
 type VolumeGroup struct {
-	Name           string
-	PhysicalVolume string
-	Size           uint64
-	State          LvmState
+	Name           string   `json:"name"`
+	PhysicalVolume string   `json:"physicalVolume"`
+	Size           uint64   `json:"size"`
+	State          LvmState `json:"state"`
 }
 
 type LogicalVolume struct {
-	Name        string
-	VolumeGroup string
-	State       LvmState
-	Size        uint64
+	Name        string   `json:"name"`
+	VolumeGroup string   `json:"volumeGroup"`
+	State       LvmState `json:"state"`
+	Size        uint64   `json:"size"`
+}
+
+type LvmSizeUnit string
+
+const (
+	VolumeGroupPercent LvmSizeUnit = "%VG"
+	FreePercent        LvmSizeUnit = "%FREE"
+	Bytes              LvmSizeUnit = "B"
+)
+
+// LvmSize is the size of a logical volume. It is either a percentage of the
+// size (%VG) or the free space (%FREE) of its volume group, or an absolute
+// number of bytes
+type LvmSize struct {
+	Value uint64
+	Unit  LvmSizeUnit
+}
+
+func (ls LvmSize) String() string {
+	if ls.Unit != Bytes {
+		return fmt.Sprintf("%d%s", ls.Value, ls.Unit)
+	}
+	return FormatByteSize(ls.Value)
+}
+
+func ParseLvmSize(s string) (*LvmSize, error) {
+	for _, unit := range []LvmSizeUnit{VolumeGroupPercent, FreePercent} {
+		if !strings.HasSuffix(s, string(unit)) {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSuffix(s, string(unit)), 10, 64)
+		if err != nil || value == 0 || value > 100 {
+			return nil, fmt.Errorf("Logical volume size '%s' must be a percentage between 1 and 100 (inclusive)", s)
+		}
+		return &LvmSize{Value: value, Unit: unit}, nil
+	}
+	if value, err := ParseByteSize(s); err == nil {
+		return &LvmSize{Value: value, Unit: Bytes}, nil
+	}
+	return nil, fmt.Errorf("Logical volume size '%s' is not supported", s)
 }
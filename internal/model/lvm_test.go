@@ -0,0 +1,73 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseLvmSize(t *testing.T) {
+	subtests := []struct {
+		LvmSize        string
+		ExpectedOutput *LvmSize
+		ExpectedString string
+		ExpectedError  error
+	}{
+		{
+			LvmSize:        "80%VG",
+			ExpectedOutput: &LvmSize{Value: 80, Unit: VolumeGroupPercent},
+			ExpectedString: "80%VG",
+			ExpectedError:  nil,
+		},
+		{
+			LvmSize:        "100%FREE",
+			ExpectedOutput: &LvmSize{Value: 100, Unit: FreePercent},
+			ExpectedString: "100%FREE",
+			ExpectedError:  nil,
+		},
+		{
+			LvmSize:        "20GiB",
+			ExpectedOutput: &LvmSize{Value: 20 << 30, Unit: Bytes},
+			ExpectedString: "20GiB",
+			ExpectedError:  nil,
+		},
+		{
+			LvmSize:        "3072MiB",
+			ExpectedOutput: &LvmSize{Value: 3072 << 20, Unit: Bytes},
+			ExpectedString: "3GiB",
+			ExpectedError:  nil,
+		},
+		{
+			LvmSize:        "512B",
+			ExpectedOutput: &LvmSize{Value: 512, Unit: Bytes},
+			ExpectedString: "512B",
+			ExpectedError:  nil,
+		},
+		{
+			LvmSize:        "120%VG",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Logical volume size '120%%VG' must be a percentage between 1 and 100 (inclusive)"),
+		},
+		{
+			LvmSize:        "0GiB",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Logical volume size '0GiB' is not supported"),
+		},
+		{
+			LvmSize:        "20GB",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Logical volume size '20GB' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.LvmSize, func(t *testing.T) {
+			ls, err := ParseLvmSize(subtest.LvmSize)
+			utils.CheckError("ParseLvmSize()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseLvmSize()", t, subtest.ExpectedOutput, ls)
+			if ls != nil {
+				utils.CheckOutput("ls.String()", t, subtest.ExpectedString, ls.String())
+			}
+		})
+	}
+}
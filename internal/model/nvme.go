@@ -0,0 +1,12 @@
+package model
+
+// NVMeController is the subset of the Identify Controller data of an NVMe device
+// that can be used to select the device
+type NVMeController struct {
+	Name   string
+	Model  string
+	Serial string
+	// The virtual name of an instance store volume (e.g. ephemeral0). The virtual
+	// name of any other device is empty
+	VirtualName string
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"fmt"
+)
+
+type Output string
+
+const (
+	TextOutput Output = "text"
+	JsonOutput Output = "json"
+)
+
+func ParseOutput(s string) (Output, error) {
+	// Text is the default output format
+	if len(s) == 0 {
+		return TextOutput, nil
+	}
+	o := Output(s)
+	switch o {
+	case TextOutput, JsonOutput:
+		return o, nil
+	default:
+		return o, fmt.Errorf("🔴 Output '%s' is not supported", s)
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseOutput(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Output         string
+		ExpectedOutput Output
+		ExpectedError  error
+	}{
+		{
+			Name:           "Default",
+			Output:         "",
+			ExpectedOutput: TextOutput,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Text",
+			Output:         "text",
+			ExpectedOutput: TextOutput,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "JSON",
+			Output:         "json",
+			ExpectedOutput: JsonOutput,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Unsupported",
+			Output:         "yaml",
+			ExpectedOutput: Output("yaml"),
+			ExpectedError:  fmt.Errorf("🔴 Output 'yaml' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			o, err := ParseOutput(subtest.Output)
+			utils.CheckError("ParseOutput()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseOutput()", t, subtest.ExpectedOutput, o)
+		})
+	}
+}
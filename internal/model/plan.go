@@ -0,0 +1,123 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// A SavedPlan is the serialised form of a plan. It records every planned action,
+// and the state of the devices that the actions were planned against. A saved
+// plan can only be applied while the observed state remains unchanged
+type SavedPlan struct {
+	State   *ObservedState   `json:"state"`
+	Actions []*PlannedAction `json:"actions"`
+}
+
+// A PlannedAction is the serialised form of an action. The parameters of an
+// action are sufficient to rebuild it
+type PlannedAction struct {
+	Action     ActionKind        `json:"action"`
+	Device     string            `json:"device"`
+	Mode       Mode              `json:"mode"`
+	Parameters map[string]string `json:"parameters"`
+}
+
+// ObservedState is a fingerprint of the configured devices and the LVM graph, as
+// they were observed before any action was planned
+type ObservedState struct {
+	Devices []*ObservedDevice `json:"devices"`
+	// The LVM graph is only observed when LVM is configured for a device
+	Lvm *ObservedLvm `json:"lvm,omitempty"`
+}
+
+// An ObservedDevice records the state of a device. A device that is yet to be
+// created (e.g. a RAID array) is not present
+type ObservedDevice struct {
+	Name       string     `json:"name"`
+	Present    bool       `json:"present"`
+	FileSystem FileSystem `json:"fileSystem"`
+	Label      string     `json:"label"`
+	UUID       string     `json:"uuid"`
+	MountPoint string     `json:"mountPoint"`
+	Size       uint64     `json:"size"`
+}
+
+type ObservedLvm struct {
+	PhysicalVolumes []*PhysicalVolume `json:"physicalVolumes"`
+	VolumeGroups    []*VolumeGroup    `json:"volumeGroups"`
+	LogicalVolumes  []*LogicalVolume  `json:"logicalVolumes"`
+}
+
+// ParseSavedPlan decodes a plan that was saved with -out
+func ParseSavedPlan(data []byte) (*SavedPlan, error) {
+	sp := &SavedPlan{}
+	if err := json.Unmarshal(data, sp); err != nil {
+		return nil, fmt.Errorf("Plan is not valid JSON")
+	}
+	if sp.State == nil {
+		return nil, fmt.Errorf("Plan does not record the observed state of its devices")
+	}
+	for i, pa := range sp.Actions {
+		if pa == nil || len(pa.Action) == 0 {
+			return nil, fmt.Errorf("Action %d of the plan does not have a kind", i+1)
+		}
+	}
+	return sp, nil
+}
+
+// Drift reports the first difference between the state that a plan was created
+// from and the live state of the same devices, or nil when there is none
+func (s *ObservedState) Drift(live *ObservedState) error {
+	devices := map[string]*ObservedDevice{}
+	for _, d := range live.Devices {
+		devices[d.Name] = d
+	}
+	for _, expected := range s.Devices {
+		actual, found := devices[expected.Name]
+		if !found {
+			return fmt.Errorf("%s: Device was not observed", expected.Name)
+		}
+		if expected.Present != actual.Present {
+			if expected.Present {
+				return fmt.Errorf("%s: Device is no longer present", expected.Name)
+			}
+			return fmt.Errorf("%s: Device is now present", expected.Name)
+		}
+		if expected.FileSystem != actual.FileSystem {
+			return fmt.Errorf("%s: File system drifted. Expected=%s, Actual=%s", expected.Name, expected.FileSystem.String(), actual.FileSystem.String())
+		}
+		if expected.Label != actual.Label {
+			return fmt.Errorf("%s: Label drifted. Expected='%s', Actual='%s'", expected.Name, expected.Label, actual.Label)
+		}
+		if expected.UUID != actual.UUID {
+			return fmt.Errorf("%s: UUID drifted. Expected='%s', Actual='%s'", expected.Name, expected.UUID, actual.UUID)
+		}
+		if expected.MountPoint != actual.MountPoint {
+			return fmt.Errorf("%s: Mount point drifted. Expected='%s', Actual='%s'", expected.Name, expected.MountPoint, actual.MountPoint)
+		}
+		if expected.Size != actual.Size {
+			return fmt.Errorf("%s: Size drifted. Expected=%d, Actual=%d", expected.Name, expected.Size, actual.Size)
+		}
+	}
+	if (s.Lvm == nil) != (live.Lvm == nil) {
+		return fmt.Errorf("LVM graph was not observed consistently")
+	}
+	if s.Lvm == nil {
+		return nil
+	}
+	if !slices.EqualFunc(s.Lvm.PhysicalVolumes, live.Lvm.PhysicalVolumes, equal[PhysicalVolume]) {
+		return fmt.Errorf("Physical volumes of the LVM graph drifted")
+	}
+	if !slices.EqualFunc(s.Lvm.VolumeGroups, live.Lvm.VolumeGroups, equal[VolumeGroup]) {
+		return fmt.Errorf("Volume groups of the LVM graph drifted")
+	}
+	if !slices.EqualFunc(s.Lvm.LogicalVolumes, live.Lvm.LogicalVolumes, equal[LogicalVolume]) {
+		return fmt.Errorf("Logical volumes of the LVM graph drifted")
+	}
+	return nil
+}
+
+func equal[T comparable](a *T, b *T) bool {
+	return *a == *b
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseSavedPlan(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Data           string
+		ExpectedOutput *SavedPlan
+		ExpectedError  error
+	}{
+		{
+			Name: "Valid Plan",
+			Data: `{
+				"state": {"devices": [{"name": "/dev/xvdf", "present": true, "fileSystem": "", "size": 1073741824}]},
+				"actions": [{"action": "format", "device": "/dev/xvdf", "mode": "force", "parameters": {"device": "/dev/xvdf", "fileSystem": "ext4", "options": ""}}]
+			}`,
+			ExpectedOutput: &SavedPlan{
+				State: &ObservedState{
+					Devices: []*ObservedDevice{
+						{Name: "/dev/xvdf", Present: true, FileSystem: Unformatted, Size: 1073741824},
+					},
+				},
+				Actions: []*PlannedAction{
+					{
+						Action:     FormatAction,
+						Device:     "/dev/xvdf",
+						Mode:       Force,
+						Parameters: map[string]string{"device": "/dev/xvdf", "fileSystem": "ext4", "options": ""},
+					},
+				},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "Malformed Plan",
+			Data:           `format /dev/xvdf`,
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Plan is not valid JSON"),
+		},
+		{
+			Name:           "Missing State",
+			Data:           `{"actions": []}`,
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Plan does not record the observed state of its devices"),
+		},
+		{
+			Name:           "Missing Action Kind",
+			Data:           `{"state": {"devices": []}, "actions": [{"device": "/dev/xvdf"}]}`,
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("Action 1 of the plan does not have a kind"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sp, err := ParseSavedPlan([]byte(subtest.Data))
+			utils.CheckError("ParseSavedPlan()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseSavedPlan()", t, subtest.ExpectedOutput, sp)
+		})
+	}
+}
+
+func TestObservedStateDrift(t *testing.T) {
+	device := func(modify func(od *ObservedDevice)) *ObservedState {
+		od := &ObservedDevice{
+			Name:       "/dev/xvdf",
+			Present:    true,
+			FileSystem: Ext4,
+			Label:      "stateful",
+			UUID:       "5ac8c5b4-4d5b-4e6e-9ea0-0c6e84b7a3d5",
+			MountPoint: "/mnt/app",
+			Size:       1073741824,
+		}
+		modify(od)
+		return &ObservedState{Devices: []*ObservedDevice{od}}
+	}
+	lvm := func(size uint64) *ObservedState {
+		return &ObservedState{
+			Devices: []*ObservedDevice{},
+			Lvm: &ObservedLvm{
+				PhysicalVolumes: []*PhysicalVolume{{Name: "/dev/xvdf", Size: 1069547520}},
+				VolumeGroups:    []*VolumeGroup{{Name: "vg", PhysicalVolume: "/dev/xvdf", Size: 1069547520, State: VolumeGroupActive}},
+				LogicalVolumes:  []*LogicalVolume{{Name: "lv", VolumeGroup: "vg", Size: size, State: LogicalVolumeActive}},
+			},
+		}
+	}
+	subtests := []struct {
+		Name          string
+		Expected      *ObservedState
+		Actual        *ObservedState
+		ExpectedError error
+	}{
+		{
+			Name:          "No Drift",
+			Expected:      device(func(od *ObservedDevice) {}),
+			Actual:        device(func(od *ObservedDevice) {}),
+			ExpectedError: nil,
+		},
+		{
+			Name:          "Device No Longer Present",
+			Expected:      device(func(od *ObservedDevice) {}),
+			Actual:        device(func(od *ObservedDevice) { *od = ObservedDevice{Name: od.Name} }),
+			ExpectedError: fmt.Errorf("/dev/xvdf: Device is no longer present"),
+		},
+		{
+			Name:          "File System Drift",
+			Expected:      device(func(od *ObservedDevice) { od.FileSystem = Unformatted }),
+			Actual:        device(func(od *ObservedDevice) {}),
+			ExpectedError: fmt.Errorf("/dev/xvdf: File system drifted. Expected=unformatted, Actual=ext4"),
+		},
+		{
+			Name:          "Label Drift",
+			Expected:      device(func(od *ObservedDevice) {}),
+			Actual:        device(func(od *ObservedDevice) { od.Label = "" }),
+			ExpectedError: fmt.Errorf("/dev/xvdf: Label drifted. Expected='stateful', Actual=''"),
+		},
+		{
+			Name:          "UUID Drift",
+			Expected:      device(func(od *ObservedDevice) {}),
+			Actual:        device(func(od *ObservedDevice) { od.UUID = "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a" }),
+			ExpectedError: fmt.Errorf("/dev/xvdf: UUID drifted. Expected='5ac8c5b4-4d5b-4e6e-9ea0-0c6e84b7a3d5', Actual='9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a'"),
+		},
+		{
+			Name:          "Mount Point Drift",
+			Expected:      device(func(od *ObservedDevice) {}),
+			Actual:        device(func(od *ObservedDevice) { od.MountPoint = "/mnt/other" }),
+			ExpectedError: fmt.Errorf("/dev/xvdf: Mount point drifted. Expected='/mnt/app', Actual='/mnt/other'"),
+		},
+		{
+			Name:          "Size Drift",
+			Expected:      device(func(od *ObservedDevice) {}),
+			Actual:        device(func(od *ObservedDevice) { od.Size = 2147483648 }),
+			ExpectedError: fmt.Errorf("/dev/xvdf: Size drifted. Expected=1073741824, Actual=2147483648"),
+		},
+		{
+			Name:          "LVM Graph Unchanged",
+			Expected:      lvm(1069547520),
+			Actual:        lvm(1069547520),
+			ExpectedError: nil,
+		},
+		{
+			Name:          "LVM Graph Drift",
+			Expected:      lvm(1069547520),
+			Actual:        lvm(536870912),
+			ExpectedError: fmt.Errorf("Logical volumes of the LVM graph drifted"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			err := subtest.Expected.Drift(subtest.Actual)
+			utils.CheckError("ObservedState.Drift()", t, subtest.ExpectedError, err)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import "fmt"
+
+const (
+	// Arrays are referenced by the stable symbolic links that mdadm maintains
+	// in this directory (e.g. /dev/md/scratch), rather than /dev/md[0-9]+
+	RaidDirectory = "/dev/md"
+)
+
+type RaidLevel string
+
+const (
+	Raid0 RaidLevel = "raid0"
+	Raid1 RaidLevel = "raid1"
+)
+
+func ParseRaidLevel(s string) (RaidLevel, error) {
+	rl := RaidLevel(s)
+	switch rl {
+	case Raid0, Raid1:
+		return rl, nil
+	default:
+		return rl, fmt.Errorf("RAID level '%s' is not supported", s)
+	}
+}
+
+type RaidArray struct {
+	Name    string
+	Level   RaidLevel
+	Devices []string
+}
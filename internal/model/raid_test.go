@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseRaidLevel(t *testing.T) {
+	subtests := []struct {
+		RaidLevel      string
+		ExpectedOutput RaidLevel
+		ExpectedError  error
+	}{
+		{
+			RaidLevel:      "raid0",
+			ExpectedOutput: Raid0,
+			ExpectedError:  nil,
+		},
+		{
+			RaidLevel:      "raid1",
+			ExpectedOutput: Raid1,
+			ExpectedError:  nil,
+		},
+		{
+			RaidLevel:      "raid5",
+			ExpectedOutput: RaidLevel("raid5"),
+			ExpectedError:  fmt.Errorf("RAID level 'raid5' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.RaidLevel, func(t *testing.T) {
+			rl, err := ParseRaidLevel(subtest.RaidLevel)
+			utils.CheckError("ParseRaidLevel()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseRaidLevel()", t, subtest.ExpectedOutput, rl)
+		})
+	}
+}
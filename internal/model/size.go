@@ -0,0 +1,45 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The binary units that a size can be expressed in, ordered from largest to smallest
+var byteSizeMultipliers = []struct {
+	suffix     string
+	multiplier uint64
+}{
+	{"TiB", 1 << 40},
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+	{"B", 1},
+}
+
+// FormatByteSize expresses a number of bytes in the largest binary unit that
+// represents it exactly (e.g. 21474836480 -> 20GiB)
+func FormatByteSize(b uint64) string {
+	for _, m := range byteSizeMultipliers {
+		if b > 0 && b%m.multiplier == 0 {
+			return fmt.Sprintf("%d%s", b/m.multiplier, m.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", b)
+}
+
+// ParseByteSize decodes a non-zero size that is expressed in a binary unit (e.g. 20GiB)
+func ParseByteSize(s string) (uint64, error) {
+	for _, m := range byteSizeMultipliers {
+		if !strings.HasSuffix(s, m.suffix) {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSuffix(s, m.suffix), 10, 64)
+		if err != nil || value == 0 {
+			break
+		}
+		return value * m.multiplier, nil
+	}
+	return 0, fmt.Errorf("Size '%s' is not supported", s)
+}
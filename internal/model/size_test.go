@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestParseByteSize(t *testing.T) {
+	subtests := []struct {
+		Size           string
+		ExpectedOutput uint64
+		ExpectedError  error
+	}{
+		{
+			Size:           "100GiB",
+			ExpectedOutput: 100 << 30,
+			ExpectedError:  nil,
+		},
+		{
+			Size:           "512B",
+			ExpectedOutput: 512,
+			ExpectedError:  nil,
+		},
+		{
+			Size:           "0GiB",
+			ExpectedOutput: 0,
+			ExpectedError:  fmt.Errorf("Size '0GiB' is not supported"),
+		},
+		{
+			Size:           "100G",
+			ExpectedOutput: 0,
+			ExpectedError:  fmt.Errorf("Size '100G' is not supported"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Size, func(t *testing.T) {
+			size, err := ParseByteSize(subtest.Size)
+			utils.CheckError("ParseByteSize()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ParseByteSize()", t, subtest.ExpectedOutput, size)
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	subtests := []struct {
+		Size           uint64
+		ExpectedOutput string
+	}{
+		{
+			Size:           20 << 30,
+			ExpectedOutput: "20GiB",
+		},
+		{
+			Size:           1536 << 20,
+			ExpectedOutput: "1536MiB",
+		},
+		{
+			Size:           1000,
+			ExpectedOutput: "1000B",
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.ExpectedOutput, func(t *testing.T) {
+			utils.CheckOutput("FormatByteSize()", t, subtest.ExpectedOutput, FormatByteSize(subtest.Size))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+const (
+	// The mount point that lsblk reports for a device that is an active swap area
+	SwapMountPoint = "[SWAP]"
+	// A swap file that can be read by other users would expose the memory of
+	// every process that was swapped out to it
+	SwapFilePermissions = FilePermissions(0600)
+	// The range of priorities that can be assigned to a swap area by swapon
+	MinimumSwapPriority = -1
+	MaximumSwapPriority = 32767
+)
+
+// SwapArea describes a device or file that is an active swap area, as reported
+// by /proc/swaps
+type SwapArea struct {
+	Name     string
+	Size     uint64 // bytes
+	Priority int
+}
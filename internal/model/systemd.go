@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+const (
+	MountUnitSuffix  = ".mount"
+	DiskByLabelPath  = "/dev/disk/by-label"
+	DiskByUuidPath   = "/dev/disk/by-uuid"
+	LocalFsTarget    = "local-fs.target"
+	mountUnitHeading = "# This file is managed by ebs-bootstrap. Manual changes will be overwritten"
+)
+
+type MountUnit struct {
+	What    string
+	Where   string
+	Type    FileSystem
+	Options MountOptions
+	// Units that require the mount to be present before they can start
+	RequiredBy []string
+}
+
+// The name of a mount unit must be the escaped path of its mount point, as
+// mandated by systemd.mount(5) (e.g. /mnt/ebs -> mnt-ebs.mount)
+func (mu *MountUnit) Name() string {
+	return SystemdEscapePath(mu.Where) + MountUnitSuffix
+}
+
+func (mu *MountUnit) String() string {
+	var sb strings.Builder
+	sb.WriteString(mountUnitHeading + "\n")
+	sb.WriteString("[Unit]\n")
+	fmt.Fprintf(&sb, "Description=Mount %s to %s\n", mu.What, mu.Where)
+	if len(mu.RequiredBy) > 0 {
+		fmt.Fprintf(&sb, "Before=%s\n", strings.Join(mu.RequiredBy, " "))
+	}
+	sb.WriteString("\n[Mount]\n")
+	fmt.Fprintf(&sb, "What=%s\n", mu.What)
+	fmt.Fprintf(&sb, "Where=%s\n", mu.Where)
+	fmt.Fprintf(&sb, "Type=%s\n", mu.Type)
+	fmt.Fprintf(&sb, "Options=%s\n", mu.Options)
+	sb.WriteString("\n[Install]\n")
+	fmt.Fprintf(&sb, "WantedBy=%s\n", LocalFsTarget)
+	if len(mu.RequiredBy) > 0 {
+		fmt.Fprintf(&sb, "RequiredBy=%s\n", strings.Join(mu.RequiredBy, " "))
+	}
+	return sb.String()
+}
+
+// SystemdEscapePath reproduces the behaviour of `systemd-escape --path`. The
+// path is normalised and stripped of its leading and trailing slashes, before
+// each remaining slash is replaced with a dash. Any byte that is not an ASCII
+// alphanumeric character, ':', '_' or a non-leading '.' is hex-escaped (\xNN)
+func SystemdEscapePath(p string) string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if len(p) == 0 {
+		return "-"
+	}
+	var sb strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c == '/':
+			sb.WriteByte('-')
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == ':', c == '_':
+			sb.WriteByte(c)
+		case c == '.' && i > 0:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, `\x%02x`, c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestSystemdEscapePath(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Path           string
+		ExpectedOutput string
+	}{
+		{
+			Name:           "Root Directory",
+			Path:           "/",
+			ExpectedOutput: "-",
+		},
+		{
+			Name:           "Nested Directory",
+			Path:           "/mnt/ebs",
+			ExpectedOutput: "mnt-ebs",
+		},
+		{
+			Name:           "Redundant Slashes",
+			Path:           "//var//lib/postgresql/",
+			ExpectedOutput: "var-lib-postgresql",
+		},
+		{
+			Name:           "Dashes and Whitespace",
+			Path:           "/mnt/app-data/my data",
+			ExpectedOutput: `mnt-app\x2ddata-my\x20data`,
+		},
+		{
+			Name:           "Leading Dot",
+			Path:           "/.hidden/.cache",
+			ExpectedOutput: `\x2ehidden-.cache`,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			escaped := SystemdEscapePath(subtest.Path)
+			utils.CheckOutput("SystemdEscapePath()", t, subtest.ExpectedOutput, escaped)
+		})
+	}
+}
+
+func TestMountUnit(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		MountUnit      *MountUnit
+		ExpectedName   string
+		ExpectedOutput string
+	}{
+		{
+			Name: "Without Dependent Units",
+			MountUnit: &MountUnit{
+				What:    "/dev/disk/by-label/stateful",
+				Where:   "/mnt/ebs",
+				Type:    Ext4,
+				Options: "defaults",
+			},
+			ExpectedName: "mnt-ebs.mount",
+			ExpectedOutput: `# This file is managed by ebs-bootstrap. Manual changes will be overwritten
+[Unit]
+Description=Mount /dev/disk/by-label/stateful to /mnt/ebs
+
+[Mount]
+What=/dev/disk/by-label/stateful
+Where=/mnt/ebs
+Type=ext4
+Options=defaults
+
+[Install]
+WantedBy=local-fs.target
+`,
+		},
+		{
+			Name: "With Dependent Units",
+			MountUnit: &MountUnit{
+				What:       "/dev/disk/by-uuid/9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Where:      "/var/lib/postgresql",
+				Type:       Xfs,
+				Options:    "defaults,noatime",
+				RequiredBy: []string{"postgresql.service", "backup.service"},
+			},
+			ExpectedName: "var-lib-postgresql.mount",
+			ExpectedOutput: `# This file is managed by ebs-bootstrap. Manual changes will be overwritten
+[Unit]
+Description=Mount /dev/disk/by-uuid/9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a to /var/lib/postgresql
+Before=postgresql.service backup.service
+
+[Mount]
+What=/dev/disk/by-uuid/9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a
+Where=/var/lib/postgresql
+Type=xfs
+Options=defaults,noatime
+
+[Install]
+WantedBy=local-fs.target
+RequiredBy=postgresql.service backup.service
+`,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			utils.CheckOutput("mu.Name()", t, subtest.ExpectedName, subtest.MountUnit.Name())
+			utils.CheckOutput("mu.String()", t, subtest.ExpectedOutput, subtest.MountUnit.String())
+		})
+	}
+}
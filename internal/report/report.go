@@ -0,0 +1,102 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+type Reporter interface {
+	Report(event *model.Event)
+}
+
+func New(o model.Output) Reporter {
+	if o == model.JsonOutput {
+		return NewJsonReporter(os.Stdout)
+	}
+	return NewTextReporter()
+}
+
+// TextReporter preserves the human-readable output of ebs-bootstrap. Events
+// without a message are not printed, as they have no textual equivalent
+type TextReporter struct {
+	print func(format string, v ...any)
+	// The device of the last planned action. Planned actions are grouped
+	// under a heading for each device
+	device string
+}
+
+func NewTextReporter() *TextReporter {
+	return &TextReporter{
+		print: log.Printf,
+	}
+}
+
+func (tr *TextReporter) Report(e *model.Event) {
+	switch e.Kind {
+	case model.RunFailed:
+		tr.print("%s", e.Error)
+		return
+	case model.ActionPlanned:
+		if e.Device != tr.device {
+			tr.device = e.Device
+			tr.print("🔵 %s", e.Device)
+		}
+		tr.print("   %d. %s (%s)", e.Position, e.Message, e.Mode)
+		return
+	}
+	if len(e.Message) == 0 {
+		return
+	}
+	switch e.Kind {
+	case model.LayerWarning:
+		tr.print("🟠 %s", e.Message)
+	case model.ActionExecuted:
+		tr.print("⭐ %s", e.Message)
+	case model.ValidationPassed, model.PlanApplied:
+		tr.print("🟢 %s", e.Message)
+	case model.PlanCreated:
+		tr.print("🟣 %s", e.Message)
+	default:
+		tr.print("🔵 %s", e.Message)
+	}
+}
+
+// JsonReporter writes each event as a single line of JSON
+type JsonReporter struct {
+	encoder *json.Encoder
+}
+
+func NewJsonReporter(w io.Writer) *JsonReporter {
+	e := json.NewEncoder(w)
+	// Error messages can contain characters (e.g. '<' and '>') that
+	// should not be escaped for the benefit of the consumer
+	e.SetEscapeHTML(false)
+	return &JsonReporter{
+		encoder: e,
+	}
+}
+
+func (jr *JsonReporter) Report(e *model.Event) {
+	// Writing to the output stream is best-effort. An event should never
+	// cause a run to fail
+	_ = jr.encoder.Encode(e)
+}
+
+// MockReporter records every event that it receives
+type MockReporter struct {
+	Events []*model.Event
+}
+
+func NewMockReporter() *MockReporter {
+	return &MockReporter{
+		Events: []*model.Event{},
+	}
+}
+
+func (mr *MockReporter) Report(e *model.Event) {
+	mr.Events = append(mr.Events, e)
+}
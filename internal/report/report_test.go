@@ -0,0 +1,99 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestTextReporter(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Event          *model.Event
+		ExpectedOutput []string
+	}{
+		{
+			Name:           "Action Executed",
+			Event:          &model.Event{Kind: model.ActionExecuted, Message: "Successfully formatted /dev/xvdf to ext4"},
+			ExpectedOutput: []string{"⭐ Successfully formatted /dev/xvdf to ext4"},
+		},
+		{
+			Name:           "Layer Warning",
+			Event:          &model.Event{Kind: model.LayerWarning, Message: "Formatting larger disks can take several seconds ⌛"},
+			ExpectedOutput: []string{"🟠 Formatting larger disks can take several seconds ⌛"},
+		},
+		{
+			Name:           "Run Failed",
+			Event:          &model.Event{Kind: model.RunFailed, Error: "🔴 Healthcheck mode enabled. Refused to format /dev/xvdf to ext4"},
+			ExpectedOutput: []string{"🔴 Healthcheck mode enabled. Refused to format /dev/xvdf to ext4"},
+		},
+		{
+			Name:           "Plan Saved",
+			Event:          &model.Event{Kind: model.PlanSaved, Message: "Saved plan to plan.json. Apply it with: ebs-bootstrap apply plan.json"},
+			ExpectedOutput: []string{"🔵 Saved plan to plan.json. Apply it with: ebs-bootstrap apply plan.json"},
+		},
+		{
+			Name:           "Event Without Message",
+			Event:          &model.Event{Kind: model.LayerStarted, Layer: "FormatDeviceLayer"},
+			ExpectedOutput: []string{},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lines := []string{}
+			tr := &TextReporter{
+				print: func(format string, v ...any) {
+					lines = append(lines, fmt.Sprintf(format, v...))
+				},
+			}
+			tr.Report(subtest.Event)
+			utils.CheckOutput("tr.Report()", t, subtest.ExpectedOutput, lines)
+		})
+	}
+}
+
+func TestTextReporterPlan(t *testing.T) {
+	lines := []string{}
+	tr := &TextReporter{
+		print: func(format string, v ...any) {
+			lines = append(lines, fmt.Sprintf(format, v...))
+		},
+	}
+	for _, e := range []*model.Event{
+		{Kind: model.ActionPlanned, Device: "/dev/xvdf", Mode: model.Healthcheck, Message: "Format /dev/xvdf to ext4", Position: 1},
+		{Kind: model.ActionPlanned, Device: "/dev/xvdf", Mode: model.Healthcheck, Message: "Label /dev/xvdf to 'stateful'", Position: 2},
+		{Kind: model.ActionPlanned, Device: "/dev/xvdg", Mode: model.Force, Message: "Format /dev/xvdg to xfs", Position: 3},
+		{Kind: model.PlanCreated, Message: "Plan: 3 action(s) to be executed across 2 device(s)"},
+	} {
+		tr.Report(e)
+	}
+	utils.CheckOutput("tr.Report()", t, []string{
+		"🔵 /dev/xvdf",
+		"   1. Format /dev/xvdf to ext4 (healthcheck)",
+		"   2. Label /dev/xvdf to 'stateful' (healthcheck)",
+		"🔵 /dev/xvdg",
+		"   3. Format /dev/xvdg to xfs (force)",
+		"🟣 Plan: 3 action(s) to be executed across 2 device(s)",
+	}, lines)
+}
+
+func TestJsonReporter(t *testing.T) {
+	var buf bytes.Buffer
+	jr := NewJsonReporter(&buf)
+	jr.Report(&model.Event{
+		Kind:       model.ActionRefused,
+		Device:     "/dev/xvdf",
+		Action:     model.FormatAction,
+		Parameters: map[string]string{"device": "/dev/xvdf", "fileSystem": "ext4"},
+		Mode:       model.Healthcheck,
+		Error:      "🔴 Healthcheck mode enabled. Refused to format /dev/xvdf to ext4",
+	})
+	jr.Report(&model.Event{Kind: model.LayerStarted, Layer: "FormatDeviceLayer"})
+	expected := `{"event":"action.refused","device":"/dev/xvdf","action":"format","parameters":{"device":"/dev/xvdf","fileSystem":"ext4"},"mode":"healthcheck","error":"🔴 Healthcheck mode enabled. Refused to format /dev/xvdf to ext4"}
+{"event":"layer.started","layer":"FormatDeviceLayer"}
+`
+	utils.CheckOutput("jr.Report()", t, expected, buf.String())
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+type CryptsetupService interface {
+	GetEncryptedDevice(name string) (*model.EncryptedDevice, error)
+	Format(device string, keyFile string) error
+	Open(device string, name string, keyFile string) error
+	CreateEphemeralKey(keyFile string) error
+}
+
+type LinuxCryptsetupService struct {
+	runnerFactory utils.RunnerFactory
+}
+
+func NewLinuxCryptsetupService(rf utils.RunnerFactory) *LinuxCryptsetupService {
+	return &LinuxCryptsetupService{
+		runnerFactory: rf,
+	}
+}
+
+// GetEncryptedDevice retrieves an active mapping from the output of `cryptsetup status`.
+// The device that backs the mapping is reported by the indented device line
+//
+//	/dev/mapper/scratch is active.
+//	  type:    LUKS2
+//	  device:  /dev/nvme1n1
+//
+// cryptsetup reports an inactive mapping with a non-zero exit code. This is
+// distinguished from any other failure and reported as os.ErrNotExist
+func (lcs *LinuxCryptsetupService) GetEncryptedDevice(name string) (*model.EncryptedDevice, error) {
+	r := lcs.runnerFactory.Select(utils.Cryptsetup)
+	output, err := r.Command("status", name)
+	if err != nil {
+		if strings.Contains(err.Error(), "is inactive") {
+			return nil, os.ErrNotExist
+		}
+		return nil, err
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if device, found := strings.CutPrefix(strings.TrimSpace(line), "device:"); found {
+			return &model.EncryptedDevice{Name: name, Device: strings.TrimSpace(device)}, nil
+		}
+	}
+	return nil, fmt.Errorf("🔴 Failed to decode cryptsetup response")
+}
+
+// The --batch-mode flag prevents cryptsetup from seeking confirmation before the
+// device is overwritten. It is the responsibility of the caller to ensure that the
+// device does not already contain data
+func (lcs *LinuxCryptsetupService) Format(device string, keyFile string) error {
+	r := lcs.runnerFactory.Select(utils.Cryptsetup)
+	_, err := r.Command("luksFormat", "--batch-mode", "--type", "luks2", "--key-file", keyFile, device)
+	return err
+}
+
+func (lcs *LinuxCryptsetupService) Open(device string, name string, keyFile string) error {
+	r := lcs.runnerFactory.Select(utils.Cryptsetup)
+	_, err := r.Command("open", "--type", "luks2", "--key-file", keyFile, device, name)
+	return err
+}
+
+// CreateEphemeralKey writes a randomly generated key that is only readable by its
+// owner. An existing key is never overwritten, as it would render the device that
+// it was used to encrypt unreadable
+func (lcs *LinuxCryptsetupService) CreateEphemeralKey(keyFile string) error {
+	key := make([]byte, model.EphemeralKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("🔴 %s: Failed to generate ephemeral key: %v", keyFile, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0400)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(key); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
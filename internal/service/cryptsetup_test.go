@@ -0,0 +1,95 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestGetEncryptedDevice(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		RunnerOutput   string
+		RunnerError    error
+		ExpectedOutput *model.EncryptedDevice
+		ExpectedError  error
+	}{
+		{
+			Name: "Active Mapping",
+			RunnerOutput: `/dev/mapper/scratch is active.
+  type:    LUKS2
+  cipher:  aes-xts-plain64
+  keysize: 512 bits
+  key location: keyring
+  device:  /dev/loop0
+  loop:    /tmp/scratch.img
+  sector size:  512
+  offset:  32768 sectors
+  size:    172032 sectors
+  mode:    read/write`,
+			RunnerError:    nil,
+			ExpectedOutput: &model.EncryptedDevice{Name: "scratch", Device: "/dev/loop0"},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Inactive Mapping",
+			RunnerOutput:   "",
+			RunnerError:    fmt.Errorf("🔴 exit status 4: /dev/mapper/scratch is inactive."),
+			ExpectedOutput: nil,
+			ExpectedError:  os.ErrNotExist,
+		},
+		{
+			Name:           "Malformed Response",
+			RunnerOutput:   "/dev/mapper/scratch is active.",
+			RunnerError:    nil,
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Failed to decode cryptsetup response"),
+		},
+		{
+			Name:           "cryptsetup Error",
+			RunnerOutput:   "",
+			RunnerError:    fmt.Errorf("🔴 cryptsetup is either not installed or accessible from $PATH"),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 cryptsetup is either not installed or accessible from $PATH"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(utils.Cryptsetup, []string{"status", "scratch"}, subtest.RunnerOutput, subtest.RunnerError)
+			lcs := NewLinuxCryptsetupService(mrf)
+			ed, err := lcs.GetEncryptedDevice("scratch")
+			utils.CheckError("lcs.GetEncryptedDevice()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("lcs.GetEncryptedDevice()", t, subtest.ExpectedOutput, ed)
+		})
+	}
+}
+
+func TestCryptsetupFormatAndOpen(t *testing.T) {
+	mrf := utils.NewMockRunnerFactory(utils.Cryptsetup, []string{"luksFormat", "--batch-mode", "--type", "luks2", "--key-file", "/etc/secure.key", "/dev/loop0"}, "", nil)
+	err := NewLinuxCryptsetupService(mrf).Format("/dev/loop0", "/etc/secure.key")
+	utils.CheckError("lcs.Format()", t, nil, err)
+
+	mrf = utils.NewMockRunnerFactory(utils.Cryptsetup, []string{"open", "--type", "luks2", "--key-file", "/etc/secure.key", "/dev/loop0", "secure"}, "", nil)
+	err = NewLinuxCryptsetupService(mrf).Open("/dev/loop0", "secure", "/etc/secure.key")
+	utils.CheckError("lcs.Open()", t, nil, err)
+}
+
+func TestCreateEphemeralKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "keys", "scratch.key")
+	lcs := NewLinuxCryptsetupService(nil)
+
+	err := lcs.CreateEphemeralKey(keyFile)
+	utils.CheckError("lcs.CreateEphemeralKey()", t, nil, err)
+	info, err := os.Stat(keyFile)
+	utils.CheckError("os.Stat()", t, nil, err)
+	utils.CheckOutput("info.Size()", t, int64(model.EphemeralKeySize), info.Size())
+	utils.CheckOutput("info.Mode()", t, os.FileMode(0400), info.Mode().Perm())
+
+	// An existing key must never be overwritten
+	err = lcs.CreateEphemeralKey(keyFile)
+	utils.ExpectErr("lcs.CreateEphemeralKey()", t, true, err)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -11,7 +12,7 @@ import (
 )
 
 var deviceNameRegex = regexp.MustCompile(`^NAME="(.*)"`)
-var devicePropertiesRegex = regexp.MustCompile(`^LABEL="(.*)" FSTYPE="(.*)" MOUNTPOINT="(.*)"`)
+var devicePropertiesRegex = regexp.MustCompile(`^LABEL="(.*)" FSTYPE="(.*)" MOUNTPOINT="(.*)" UUID="(.*)"`)
 
 type DeviceService interface {
 	GetSize(name string) (uint64, error) // bytes
@@ -19,6 +20,8 @@ type DeviceService interface {
 	GetBlockDevice(name string) (*model.BlockDevice, error)
 	Mount(source string, target string, fs model.FileSystem, options model.MountOptions) error
 	Umount(source string, target string) error
+	GetSignatures(name string) ([]*model.Signature, error)
+	Wipe(name string) error
 }
 
 type LinuxDeviceService struct {
@@ -66,13 +69,13 @@ func (du *LinuxDeviceService) GetBlockDevices() ([]string, error) {
 
 func (du *LinuxDeviceService) GetBlockDevice(name string) (*model.BlockDevice, error) {
 	r := du.runnerFactory.Select(utils.Lsblk)
-	output, err := r.Command("--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT", "-P", name)
+	output, err := r.Command("--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT,UUID", "-P", name)
 	if err != nil {
 		return nil, err
 	}
 
 	matches := devicePropertiesRegex.FindStringSubmatch(output)
-	if len(matches) != 4 {
+	if len(matches) != 5 {
 		return nil, fmt.Errorf("🔴 Failed to decode lsblk response")
 	}
 
@@ -85,6 +88,7 @@ func (du *LinuxDeviceService) GetBlockDevice(name string) (*model.BlockDevice, e
 		Label:      matches[1],
 		FileSystem: fs,
 		MountPoint: matches[3],
+		UUID:       matches[4],
 	}, nil
 }
 
@@ -99,3 +103,42 @@ func (du *LinuxDeviceService) Umount(source string, target string) error {
 	_, err := r.Command(target)
 	return err
 }
+
+type wipefsResponse struct {
+	Signatures []struct {
+		Offset string `json:"offset"`
+		Type   string `json:"type"`
+	} `json:"signatures"`
+}
+
+// GetSignatures lists the signatures of a device without erasing them. wipefs
+// produces no output at all when a device does not have any signatures
+func (du *LinuxDeviceService) GetSignatures(name string) ([]*model.Signature, error) {
+	r := du.runnerFactory.Select(utils.WipeFs)
+	output, err := r.Command("--json", name)
+	if err != nil {
+		return nil, err
+	}
+	signatures := []*model.Signature{}
+	if len(strings.TrimSpace(output)) == 0 {
+		return signatures, nil
+	}
+	var wr wipefsResponse
+	if err := json.Unmarshal([]byte(output), &wr); err != nil {
+		return nil, fmt.Errorf("🔴 Failed to decode wipefs response: %v", err)
+	}
+	for _, s := range wr.Signatures {
+		offset, err := strconv.ParseUint(s.Offset, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("🔴 Failed to cast signature offset to unsigned 64-bit integer")
+		}
+		signatures = append(signatures, &model.Signature{Type: s.Type, Offset: offset})
+	}
+	return signatures, nil
+}
+
+func (du *LinuxDeviceService) Wipe(name string) error {
+	r := du.runnerFactory.Select(utils.WipeFs)
+	_, err := r.Command("--all", name)
+	return err
+}
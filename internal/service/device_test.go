@@ -126,14 +126,15 @@ func TestGetBlockDevice(t *testing.T) {
 			Name:         "lsblk=success",
 			Device:       "/dev/nvme1n1",
 			RunnerBinary: utils.Lsblk,
-			RunnerArgs:   []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT", "-P", "/dev/nvme1n1"},
-			RunnerOutput: `LABEL="external-vol" FSTYPE="xfs" MOUNTPOINT="/mnt/app"`,
+			RunnerArgs:   []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT,UUID", "-P", "/dev/nvme1n1"},
+			RunnerOutput: `LABEL="external-vol" FSTYPE="xfs" MOUNTPOINT="/mnt/app" UUID="9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a"`,
 			RunnerError:  nil,
 			ExpectedOutput: &model.BlockDevice{
 				Name:       "/dev/nvme1n1",
 				Label:      "external-vol",
 				FileSystem: model.Xfs,
 				MountPoint: "/mnt/app",
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
 			},
 			ExpectedError: nil,
 		},
@@ -141,8 +142,8 @@ func TestGetBlockDevice(t *testing.T) {
 			Name:         "lsblk=success + device=unformatted",
 			Device:       "/dev/nvme1n1",
 			RunnerBinary: utils.Lsblk,
-			RunnerArgs:   []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT", "-P", "/dev/nvme1n1"},
-			RunnerOutput: `LABEL="" FSTYPE="" MOUNTPOINT=""`,
+			RunnerArgs:   []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT,UUID", "-P", "/dev/nvme1n1"},
+			RunnerOutput: `LABEL="" FSTYPE="" MOUNTPOINT="" UUID=""`,
 			RunnerError:  nil,
 			ExpectedOutput: &model.BlockDevice{
 				Name:       "/dev/nvme1n1",
@@ -156,7 +157,7 @@ func TestGetBlockDevice(t *testing.T) {
 			Name:           "lsblk=success + malformed response",
 			Device:         "/dev/sdb",
 			RunnerBinary:   utils.Lsblk,
-			RunnerArgs:     []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT", "-P", "/dev/sdb"},
+			RunnerArgs:     []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT,UUID", "-P", "/dev/sdb"},
 			RunnerOutput:   "malformed",
 			RunnerError:    nil,
 			ExpectedOutput: nil,
@@ -166,8 +167,8 @@ func TestGetBlockDevice(t *testing.T) {
 			Name:           "lsblk=success + filesystem=unsupported",
 			Device:         "/dev/sdb",
 			RunnerBinary:   utils.Lsblk,
-			RunnerArgs:     []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT", "-P", "/dev/sdb"},
-			RunnerOutput:   `LABEL="" FSTYPE="jfs" MOUNTPOINT="/mnt/app"`,
+			RunnerArgs:     []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT,UUID", "-P", "/dev/sdb"},
+			RunnerOutput:   `LABEL="" FSTYPE="jfs" MOUNTPOINT="/mnt/app" UUID=""`,
 			RunnerError:    nil,
 			ExpectedOutput: nil,
 			ExpectedError:  fmt.Errorf("🔴 /dev/sdb: File system 'jfs' is not supported"),
@@ -176,7 +177,7 @@ func TestGetBlockDevice(t *testing.T) {
 			Name:           "lsblk=failure",
 			Device:         "/dev/sdc",
 			RunnerBinary:   utils.Lsblk,
-			RunnerArgs:     []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT", "-P", "/dev/sdc"},
+			RunnerArgs:     []string{"--nodeps", "-o", "LABEL,FSTYPE,MOUNTPOINT,UUID", "-P", "/dev/sdc"},
 			RunnerOutput:   "",
 			RunnerError:    fmt.Errorf("🔴 lsblk: /dev/sdc: not a block device"),
 			ExpectedOutput: nil,
@@ -283,3 +284,64 @@ func TestUmount(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSignatures(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Device         string
+		RunnerOutput   string
+		RunnerError    error
+		ExpectedOutput []*model.Signature
+		ExpectedError  error
+	}{
+		{
+			Name:   "wipefs=success + signatures=gpt",
+			Device: "/dev/xvdf",
+			RunnerOutput: `{
+   "signatures": [
+      {"device":"xvdf", "offset":"0x200", "type":"gpt", "uuid":null, "label":null},
+      {"device":"xvdf", "offset":"0x1fe", "type":"PMBR", "uuid":null, "label":null}
+   ]
+}`,
+			RunnerError: nil,
+			ExpectedOutput: []*model.Signature{
+				{Type: "gpt", Offset: 0x200},
+				{Type: "PMBR", Offset: 0x1fe},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "wipefs=success + signatures=none",
+			Device:         "/dev/xvdf",
+			RunnerOutput:   "",
+			RunnerError:    nil,
+			ExpectedOutput: []*model.Signature{},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "wipefs=success + offset=invalid",
+			Device:         "/dev/xvdf",
+			RunnerOutput:   `{"signatures": [{"offset":"zero", "type":"xfs"}]}`,
+			RunnerError:    nil,
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Failed to cast signature offset to unsigned 64-bit integer"),
+		},
+		{
+			Name:           "wipefs=failure",
+			Device:         "/dev/xvdz",
+			RunnerOutput:   "",
+			RunnerError:    fmt.Errorf("🔴 wipefs: error: /dev/xvdz: probing initialization failed: No such file or directory"),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 wipefs: error: /dev/xvdz: probing initialization failed: No such file or directory"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(utils.WipeFs, []string{"--json", subtest.Device}, subtest.RunnerOutput, subtest.RunnerError)
+			lds := NewLinuxDeviceService(mrf)
+			signatures, err := lds.GetSignatures(subtest.Device)
+			utils.CheckError("lds.GetSignatures()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("lds.GetSignatures()", t, subtest.ExpectedOutput, signatures)
+		})
+	}
+}
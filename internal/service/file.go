@@ -18,6 +18,8 @@ type FileService interface {
 	CreateDirectory(path string) error
 	ChangeOwner(file string, uid model.UserId, gid model.GroupId) error
 	ChangePermissions(file string, perms model.FilePermissions) error
+	ReadFile(file string) ([]byte, error)
+	WriteFile(file string, data []byte, perms model.FilePermissions) error
 }
 
 type UnixFileService struct{}
@@ -79,3 +81,35 @@ func (ufs *UnixFileService) ChangeOwner(file string, uid model.UserId, gid model
 func (ufs *UnixFileService) ChangePermissions(file string, perms model.FilePermissions) error {
 	return os.Chmod(file, perms.Perm())
 }
+
+func (ufs *UnixFileService) ReadFile(file string) ([]byte, error) {
+	return os.ReadFile(file)
+}
+
+// WriteFile atomically replaces the contents of a file. The data is written to a
+// temporary file in the same directory, which is then renamed over the original.
+// This guarantees that a reader never observes a partially written file
+func (ufs *UnixFileService) WriteFile(file string, data []byte, perms model.FilePermissions) error {
+	t, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return err
+	}
+	// Clean up the temporary file if it was not renamed
+	defer os.Remove(t.Name())
+	if _, err := t.Write(data); err != nil {
+		t.Close()
+		return err
+	}
+	if err := t.Sync(); err != nil {
+		t.Close()
+		return err
+	}
+	if err := t.Chmod(perms.Perm()); err != nil {
+		t.Close()
+		return err
+	}
+	if err := t.Close(); err != nil {
+		return err
+	}
+	return os.Rename(t.Name(), file)
+}
@@ -121,6 +121,37 @@ func TestDirectoryModifications(t *testing.T) {
 	utils.ExpectErr("ufs.ChangeOwner()", t, false, err)
 }
 
+func TestFileModifications(t *testing.T) {
+	ufs := NewUnixFileService()
+
+	// Create Temporary Directory
+	dir, err := directory()
+	utils.ExpectErr("temporaryDirectory()", t, false, err)
+	defer os.RemoveAll(dir)
+
+	// Atomically Write File Inside Temporary Directory
+	file := path.Join(dir, "fstab")
+	err = ufs.WriteFile(file, []byte("# fstab\n"), 0644)
+	utils.ExpectErr("ufs.WriteFile()", t, false, err)
+
+	// Atomically Replace File Contents
+	err = ufs.WriteFile(file, []byte("# fstab\nLABEL=data /mnt/data ext4 defaults 0 2\n"), 0600)
+	utils.ExpectErr("ufs.WriteFile()", t, false, err)
+
+	data, err := ufs.ReadFile(file)
+	utils.ExpectErr("ufs.ReadFile()", t, false, err)
+	utils.CheckOutput("ufs.ReadFile()", t, "# fstab\nLABEL=data /mnt/data ext4 defaults 0 2\n", string(data))
+
+	f, err := ufs.GetFile(file)
+	utils.ExpectErr("ufs.GetFile()", t, false, err)
+	utils.CheckOutput("f.Permissions", t, model.FilePermissions(0600), f.Permissions)
+
+	// No temporary files should remain in the directory
+	entries, err := os.ReadDir(dir)
+	utils.ExpectErr("os.ReadDir()", t, false, err)
+	utils.CheckOutput("len(entries)", t, 1, len(entries))
+}
+
 // Create a temporary file
 func regularFile() (string, error) {
 	file, err := os.CreateTemp("", "temp_file")
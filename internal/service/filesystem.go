@@ -1,9 +1,12 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
@@ -12,14 +15,36 @@ import (
 type FileSystemService interface {
 	GetSize(name string) (uint64, error)
 	GetFileSystem() model.FileSystem
-	Format(name string) error
+	Format(name string, options *model.FormatOptions) error
+	ValidateFormatOptions(options *model.FormatOptions) error
 	Label(name string, label string) error
+	SetUuid(name string, uuid string) error
 	Resize(name string) error
+	Check(name string, repair bool) (model.FsckResult, error)
 	GetMaximumLabelLength() int
 	DoesResizeRequireMount() bool
+	DoesResizeRequireUnmount() bool
 	DoesLabelRequireUnmount() bool
+	DoesFormatSupportLabel() bool
+	GetProperties(name string) (*model.FileSystemProperties, error)
+	Tune(name string, properties *model.FileSystemProperties) error
+	ValidateProperties(properties *model.FileSystemProperties) error
+	DoesTuneRequireUnmount() bool
 }
 
+const (
+	ext4DefaultBlockSize  = 4096
+	ext4MaximumInodeRatio = 67108864
+	ext4MaximumMountCount = 16000
+	// e2fsck reports the outcome of a check as a bit mask in its exit code. Any
+	// other bit signals that the check itself could not be completed
+	e2fsckCorrected   = 1
+	e2fsckReboot      = 2
+	e2fsckUncorrected = 4
+	// xfs_repair exits with 2 when the log must be replayed before a repair
+	xfsRepairDirtyLog = 2
+)
+
 type FileSystemServiceFactory interface {
 	Select(fs model.FileSystem) (FileSystemService, error)
 }
@@ -40,9 +65,19 @@ func (fsf *LinuxFileSystemServiceFactory) Select(fs model.FileSystem) (FileSyste
 		return NewExt4Service(fsf.RunnerFactory), nil
 	case model.Xfs:
 		return NewXfsService(fsf.RunnerFactory), nil
+	case model.Btrfs:
+		return NewBtrfsService(fsf.RunnerFactory), nil
+	case model.Swap:
+		return NewLinuxSwapService(fsf.RunnerFactory), nil
 	case model.Lvm:
 		//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
 		return nil, fmt.Errorf("A Physical Volume cannot be queried/modified")
+	case model.RaidMember:
+		//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+		return nil, fmt.Errorf("A RAID member cannot be queried/modified")
+	case model.Luks:
+		//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+		return nil, fmt.Errorf("A LUKS device cannot be queried/modified")
 	case model.Unformatted:
 		//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
 		return nil, fmt.Errorf("An unformatted file system can not be queried/modified")
@@ -64,54 +99,131 @@ func (es *Ext4Service) GetFileSystem() model.FileSystem {
 	return model.Ext4
 }
 
-func (es *Ext4Service) Format(name string) error {
+// The stride and stripe width of an ext4 file system are expressed in file system
+// blocks. When the block size is omitted, mkfs.ext4 selects a 4KiB block size
+// for any device that is large enough to be striped
+func (es *Ext4Service) Format(name string, options *model.FormatOptions) error {
+	args := []string{}
+	if options != nil {
+		bs := options.BlockSize
+		if bs > 0 {
+			args = append(args, "-b", strconv.FormatUint(bs, 10))
+		} else {
+			bs = ext4DefaultBlockSize
+		}
+		if options.InodeRatio > 0 {
+			args = append(args, "-i", strconv.FormatUint(options.InodeRatio, 10))
+		}
+		if options.ReservedBlocksPercent != nil {
+			args = append(args, "-m", strconv.FormatUint(*options.ReservedBlocksPercent, 10))
+		}
+		extended := []string{}
+		if options.LazyItableInit != nil {
+			extended = append(extended, "lazy_itable_init="+formatBool(*options.LazyItableInit))
+		}
+		if options.StripeUnit > 0 {
+			stride := options.StripeUnit * 1024 / bs
+			extended = append(extended, fmt.Sprintf("stride=%d", stride), fmt.Sprintf("stripe_width=%d", stride*options.StripeWidth))
+		}
+		if len(extended) > 0 {
+			args = append(args, "-E", strings.Join(extended, ","))
+		}
+		if len(options.Label) > 0 {
+			args = append(args, "-L", options.Label)
+		}
+	}
 	r := es.runnerFactory.Select(utils.MkfsExt4)
-	_, err := r.Command(name)
+	_, err := r.Command(append(args, name)...)
 	return err
 }
 
+func (es *Ext4Service) ValidateFormatOptions(options *model.FormatOptions) error {
+	if err := validateFormatOptions(es.GetFileSystem(), options, "blockSize", "inodeRatio", "reservedBlocksPercent", "lazyItableInit", "stripeUnit", "stripeWidth"); err != nil {
+		return err
+	}
+	if options == nil {
+		return nil
+	}
+	bs := options.BlockSize
+	if bs > 0 && !slices.Contains([]uint64{1024, 2048, 4096}, bs) {
+		return fmt.Errorf("A block size of %d is not supported by the %s file system. Expected one of 1024, 2048 or 4096", bs, es.GetFileSystem())
+	}
+	if bs == 0 {
+		bs = ext4DefaultBlockSize
+	}
+	if ir := options.InodeRatio; ir > 0 && (ir < bs || ir > ext4MaximumInodeRatio) {
+		return fmt.Errorf("An inode ratio of %d must be between the block size (%d) and %d", ir, bs, ext4MaximumInodeRatio)
+	}
+	if rbp := options.ReservedBlocksPercent; rbp != nil && *rbp > 50 {
+		return fmt.Errorf("A reserved blocks percentage of %d must not exceed 50", *rbp)
+	}
+	if options.StripeUnit*1024%bs != 0 {
+		return fmt.Errorf("A stripe unit of %dKiB must be a multiple of the block size (%d)", options.StripeUnit, bs)
+	}
+	return nil
+}
+
 func (es *Ext4Service) Label(name string, label string) error {
 	r := es.runnerFactory.Select(utils.E2Label)
 	_, err := r.Command(name, label)
 	return err
 }
 
+func (es *Ext4Service) SetUuid(name string, uuid string) error {
+	r := es.runnerFactory.Select(utils.Tune2fs)
+	_, err := r.Command("-U", uuid, name)
+	return err
+}
+
 func (es *Ext4Service) Resize(name string) error {
 	r := es.runnerFactory.Select(utils.Resize2fs)
 	_, err := r.Command(name)
 	return err
 }
 
+// A check (-n) never modifies the file system, and so reports any error that it
+// finds as uncorrected. A repair (-p) only corrects errors that can be corrected
+// safely without human intervention
+func (es *Ext4Service) Check(name string, repair bool) (model.FsckResult, error) {
+	flag := "-n"
+	if repair {
+		flag = "-p"
+	}
+	r := es.runnerFactory.Select(utils.E2fsck)
+	_, err := r.Command(flag, name)
+	if err == nil {
+		return model.FsckClean, nil
+	}
+	var ee *utils.ExitError
+	if !errors.As(err, &ee) || ee.Code&^(e2fsckCorrected|e2fsckReboot|e2fsckUncorrected) != 0 {
+		return "", err
+	}
+	if ee.Code&e2fsckUncorrected != 0 {
+		return model.FsckUncorrected, nil
+	}
+	return model.FsckCorrected, nil
+}
+
 func (es *Ext4Service) GetSize(name string) (uint64, error) {
-	r := es.runnerFactory.Select(utils.Tune2fs)
-	output, err := r.Command("-l", name)
+	sb, err := es.getSuperblock(name)
 	if err != nil {
 		return 0, err
 	}
-	// Regex (Block Size)
-	rebs := regexp.MustCompile(`Block size:\s+(\d+)`)
-	// Match (Block Size)
-	mbs := rebs.FindStringSubmatch(output)
-	if len(mbs) != 2 {
+	// String (Block Size)
+	sbs, found := sb["Block size"]
+	if !found {
 		return 0, fmt.Errorf("🔴 %s: Block size not found tune2fs output", name)
 	}
-	// String (Block Size)
-	sbs := mbs[1]
 	// Block Size
 	bs, err := strconv.ParseUint(sbs, 10, 64)
 	if err != nil {
 		return 0, fmt.Errorf("🔴 Failed to cast block size to unsigned 64-bit integer")
 	}
-
-	// Regex (Block Count)
-	rebc := regexp.MustCompile(`Block count:\s+(\d+)`)
-	// Match (Block Count)
-	mbc := rebc.FindStringSubmatch(output)
-	if len(mbs) != 2 {
+	// String (Block Count)
+	sbc, found := sb["Block count"]
+	if !found {
 		return 0, fmt.Errorf("🔴 %s: Block count not found tune2fs output", name)
 	}
-	// String (Block Count)
-	sbc := mbc[1]
 	// Block Count
 	bc, err := strconv.ParseUint(sbc, 10, 64)
 	if err != nil {
@@ -120,6 +232,90 @@ func (es *Ext4Service) GetSize(name string) (uint64, error) {
 	return bs * bc, nil
 }
 
+// tune2fs reports the reserved block count rather than the percentage that it was
+// assigned. The percentage is rounded to the nearest whole number, as a percentage
+// is rarely an exact multiple of the block count
+func (es *Ext4Service) GetProperties(name string) (*model.FileSystemProperties, error) {
+	sb, err := es.getSuperblock(name)
+	if err != nil {
+		return nil, err
+	}
+	fp := &model.FileSystemProperties{}
+	rbc, errRbc := strconv.ParseUint(sb["Reserved block count"], 10, 64)
+	bc, errBc := strconv.ParseUint(sb["Block count"], 10, 64)
+	if errRbc == nil && errBc == nil && bc > 0 {
+		rbp := (rbc*100 + bc/2) / bc
+		fp.ReservedBlocksPercent = &rbp
+	}
+	if mmc, err := strconv.ParseInt(sb["Maximum mount count"], 10, 64); err == nil {
+		fp.MaxMountCount = &mmc
+	}
+	switch sb["Errors behavior"] {
+	case "Continue":
+		fp.Errors = model.ErrorsContinue
+	case "Remount read-only":
+		fp.Errors = model.ErrorsRemountRo
+	case "Panic":
+		fp.Errors = model.ErrorsPanic
+	}
+	fp.Features = strings.Fields(sb["Filesystem features"])
+	return fp, nil
+}
+
+func (es *Ext4Service) Tune(name string, properties *model.FileSystemProperties) error {
+	args := []string{}
+	if properties.ReservedBlocksPercent != nil {
+		args = append(args, "-m", strconv.FormatUint(*properties.ReservedBlocksPercent, 10))
+	}
+	if properties.MaxMountCount != nil {
+		args = append(args, "-c", strconv.FormatInt(*properties.MaxMountCount, 10))
+	}
+	if len(properties.Errors) > 0 {
+		args = append(args, "-e", string(properties.Errors))
+	}
+	if len(properties.Features) > 0 {
+		args = append(args, "-O", strings.Join(properties.Features, ","))
+	}
+	r := es.runnerFactory.Select(utils.Tune2fs)
+	_, err := r.Command(append(args, name)...)
+	return err
+}
+
+func (es *Ext4Service) ValidateProperties(properties *model.FileSystemProperties) error {
+	if err := validateProperties(es.GetFileSystem(), properties, "reservedBlocksPercent", "maxMountCount", "errors", "features"); err != nil {
+		return err
+	}
+	if properties == nil {
+		return nil
+	}
+	if rbp := properties.ReservedBlocksPercent; rbp != nil && *rbp > 50 {
+		return fmt.Errorf("A reserved blocks percentage of %d must not exceed 50", *rbp)
+	}
+	if mmc := properties.MaxMountCount; mmc != nil && (*mmc < -1 || *mmc > ext4MaximumMountCount) {
+		return fmt.Errorf("A maximum mount count of %d must be between -1 and %d", *mmc, ext4MaximumMountCount)
+	}
+	return nil
+}
+
+// getSuperblock decodes the "key: value" lines of the superblock that is
+// listed by tune2fs
+func (es *Ext4Service) getSuperblock(name string) (map[string]string, error) {
+	r := es.runnerFactory.Select(utils.Tune2fs)
+	output, err := r.Command("-l", name)
+	if err != nil {
+		return nil, err
+	}
+	sb := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		sb[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return sb, nil
+}
+
 func (es *Ext4Service) GetMaximumLabelLength() int {
 	return 16
 }
@@ -128,10 +324,22 @@ func (es *Ext4Service) DoesResizeRequireMount() bool {
 	return false
 }
 
+func (es *Ext4Service) DoesResizeRequireUnmount() bool {
+	return false
+}
+
 func (es *Ext4Service) DoesLabelRequireUnmount() bool {
 	return false
 }
 
+func (es *Ext4Service) DoesFormatSupportLabel() bool {
+	return true
+}
+
+func (es *Ext4Service) DoesTuneRequireUnmount() bool {
+	return false
+}
+
 type XfsService struct {
 	runnerFactory utils.RunnerFactory
 }
@@ -144,24 +352,86 @@ func (es *XfsService) GetFileSystem() model.FileSystem {
 	return model.Xfs
 }
 
-func (xs *XfsService) Format(name string) error {
+func (xs *XfsService) Format(name string, options *model.FormatOptions) error {
+	args := []string{}
+	if options != nil {
+		if options.BlockSize > 0 {
+			args = append(args, "-b", fmt.Sprintf("size=%d", options.BlockSize))
+		}
+		if options.Reflink != nil {
+			args = append(args, "-m", "reflink="+formatBool(*options.Reflink))
+		}
+		if options.StripeUnit > 0 {
+			args = append(args, "-d", fmt.Sprintf("su=%dk,sw=%d", options.StripeUnit, options.StripeWidth))
+		}
+	}
 	r := xs.runnerFactory.Select(utils.MkfsXfs)
-	_, err := r.Command(name)
+	_, err := r.Command(append(args, name)...)
 	return err
 }
 
+func (xs *XfsService) ValidateFormatOptions(options *model.FormatOptions) error {
+	if err := validateFormatOptions(xs.GetFileSystem(), options, "blockSize", "reflink", "stripeUnit", "stripeWidth"); err != nil {
+		return err
+	}
+	if options == nil {
+		return nil
+	}
+	if bs := options.BlockSize; bs > 0 && (bs < 512 || bs > 65536 || bs&(bs-1) != 0) {
+		return fmt.Errorf("A block size of %d is not supported by the %s file system. Expected a power of two between 512 and 65536", bs, xs.GetFileSystem())
+	}
+	return nil
+}
+
 func (xs *XfsService) Label(name string, label string) error {
 	r := xs.runnerFactory.Select(utils.XfsAdmin)
 	_, err := r.Command("-L", label, name)
 	return err
 }
 
+// xfs_admin generates a new UUID when provided with "generate", rather than "random"
+func (xs *XfsService) SetUuid(name string, uuid string) error {
+	if uuid == model.RandomUuid {
+		uuid = "generate"
+	}
+	r := xs.runnerFactory.Select(utils.XfsAdmin)
+	_, err := r.Command("-U", uuid, name)
+	return err
+}
+
 func (es *XfsService) Resize(name string) error {
 	r := es.runnerFactory.Select(utils.XfsGrowfs)
 	_, err := r.Command(name)
 	return err
 }
 
+// xfs_repair does not distinguish a clean file system from one that was repaired.
+// Both are reported as clean. A file system with a dirty log can only be repaired
+// once it has been mounted, which replays the log
+func (xs *XfsService) Check(name string, repair bool) (model.FsckResult, error) {
+	args := []string{name}
+	if !repair {
+		args = append([]string{"-n"}, args...)
+	}
+	r := xs.runnerFactory.Select(utils.XfsRepair)
+	_, err := r.Command(args...)
+	if err == nil {
+		return model.FsckClean, nil
+	}
+	var ee *utils.ExitError
+	if !errors.As(err, &ee) {
+		return "", err
+	}
+	switch {
+	case ee.Code == 1:
+		return model.FsckUncorrected, nil
+	case ee.Code == xfsRepairDirtyLog && repair:
+		return "", fmt.Errorf("🔴 %s: The log of the %s file system must be replayed by mounting it, before it can be repaired", name, xs.GetFileSystem())
+	default:
+		return "", err
+	}
+}
+
 func (xs *XfsService) GetSize(name string) (uint64, error) {
 	r := xs.runnerFactory.Select(utils.XfsInfo)
 	output, err := r.Command(name)
@@ -192,6 +462,37 @@ func (xs *XfsService) GetSize(name string) (uint64, error) {
 	return bs * bc, nil
 }
 
+// The features of an xfs file system are reported by xfs_info as flags that
+// are assigned a value of 1 when enabled (e.g. bigtime=1)
+func (xs *XfsService) GetProperties(name string) (*model.FileSystemProperties, error) {
+	r := xs.runnerFactory.Select(utils.XfsInfo)
+	output, err := r.Command(name)
+	if err != nil {
+		return nil, err
+	}
+	// Regex (Feature)
+	ref := regexp.MustCompile(`(?:^|\s)([a-z0-9_]+)=1\b`)
+	fp := &model.FileSystemProperties{Features: []string{}}
+	for _, mf := range ref.FindAllStringSubmatch(output, -1) {
+		fp.Features = append(fp.Features, mf[1])
+	}
+	return fp, nil
+}
+
+func (xs *XfsService) Tune(name string, properties *model.FileSystemProperties) error {
+	features := []string{}
+	for _, f := range properties.Features {
+		features = append(features, f+"=1")
+	}
+	r := xs.runnerFactory.Select(utils.XfsAdmin)
+	_, err := r.Command("-O", strings.Join(features, ","), name)
+	return err
+}
+
+func (xs *XfsService) ValidateProperties(properties *model.FileSystemProperties) error {
+	return validateProperties(xs.GetFileSystem(), properties, "features")
+}
+
 func (es *XfsService) GetMaximumLabelLength() int {
 	return 12
 }
@@ -200,6 +501,220 @@ func (es *XfsService) DoesResizeRequireMount() bool {
 	return true
 }
 
+func (es *XfsService) DoesResizeRequireUnmount() bool {
+	return false
+}
+
 func (es *XfsService) DoesLabelRequireUnmount() bool {
 	return true
 }
+
+func (es *XfsService) DoesFormatSupportLabel() bool {
+	return false
+}
+
+func (es *XfsService) DoesTuneRequireUnmount() bool {
+	return true
+}
+
+type BtrfsService struct {
+	runnerFactory utils.RunnerFactory
+	deviceService *SysfsDeviceService
+}
+
+func NewBtrfsService(rc utils.RunnerFactory) *BtrfsService {
+	return &BtrfsService{
+		runnerFactory: rc,
+		deviceService: NewSysfsDeviceService(rc),
+	}
+}
+
+func (bs *BtrfsService) GetFileSystem() model.FileSystem {
+	return model.Btrfs
+}
+
+func (bs *BtrfsService) Format(name string, options *model.FormatOptions) error {
+	r := bs.runnerFactory.Select(utils.MkfsBtrfs)
+	_, err := r.Command(name)
+	return err
+}
+
+func (bs *BtrfsService) ValidateFormatOptions(options *model.FormatOptions) error {
+	return validateFormatOptions(bs.GetFileSystem(), options)
+}
+
+// A mounted btrfs file system can only be labelled through its mount point. The
+// btrfs tool refuses to label the underlying device while it is mounted
+func (bs *BtrfsService) Label(name string, label string) error {
+	target, err := bs.getMountPoint(name)
+	if err != nil {
+		return err
+	}
+	if len(target) == 0 {
+		target = name
+	}
+	r := bs.runnerFactory.Select(utils.Btrfs)
+	_, err = r.Command("filesystem", "label", target, label)
+	return err
+}
+
+// The devices of a multi-device btrfs file system legitimately share a UUID.
+// Therefore, the UUID of a btrfs file system is never changed
+func (bs *BtrfsService) SetUuid(name string, uuid string) error {
+	//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+	return fmt.Errorf("The UUID of the %s file system can not be changed", bs.GetFileSystem())
+}
+
+// The btrfs tool can only resize a mounted file system. Therefore, the provided
+// name is expected to be the mount point of the file system
+func (bs *BtrfsService) Resize(name string) error {
+	r := bs.runnerFactory.Select(utils.Btrfs)
+	_, err := r.Command("filesystem", "resize", "max", name)
+	return err
+}
+
+// The repair mode of btrfs check is considered dangerous by its own authors, and
+// so a btrfs file system is only ever checked
+func (bs *BtrfsService) Check(name string, repair bool) (model.FsckResult, error) {
+	if repair {
+		//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+		return "", fmt.Errorf("The %s file system can not be repaired automatically", bs.GetFileSystem())
+	}
+	r := bs.runnerFactory.Select(utils.Btrfs)
+	_, err := r.Command("check", "--readonly", name)
+	if err == nil {
+		return model.FsckClean, nil
+	}
+	var ee *utils.ExitError
+	if errors.As(err, &ee) && ee.Code == 1 {
+		return model.FsckUncorrected, nil
+	}
+	return "", err
+}
+
+// A btrfs file system can span multiple devices. The size that is reported is that
+// of the provided device, which is the portion of the file system that can grow
+// when the device is resized
+func (bs *BtrfsService) GetSize(name string) (uint64, error) {
+	r := bs.runnerFactory.Select(utils.Btrfs)
+	output, err := r.Command("filesystem", "show", "--raw", name)
+	if err != nil {
+		return 0, err
+	}
+	// Regex (Device)
+	red := regexp.MustCompile(`devid\s+\d+\s+size\s+(\d+)\s+used\s+\d+\s+path\s+(\S+)`)
+	// Matches (Device)
+	mds := red.FindAllStringSubmatch(output, -1)
+	var ss string
+	for _, md := range mds {
+		if md[2] == name || len(mds) == 1 {
+			ss = md[1]
+			break
+		}
+	}
+	if len(ss) == 0 {
+		return 0, fmt.Errorf("🔴 %s: Device size not found btrfs filesystem show output", name)
+	}
+	size, err := strconv.ParseUint(ss, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("🔴 Failed to cast device size to unsigned 64-bit integer")
+	}
+	return size, nil
+}
+
+func (bs *BtrfsService) GetProperties(name string) (*model.FileSystemProperties, error) {
+	return &model.FileSystemProperties{}, nil
+}
+
+func (bs *BtrfsService) Tune(name string, properties *model.FileSystemProperties) error {
+	//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+	return fmt.Errorf("The properties of the %s file system can not be tuned", bs.GetFileSystem())
+}
+
+func (bs *BtrfsService) ValidateProperties(properties *model.FileSystemProperties) error {
+	return validateProperties(bs.GetFileSystem(), properties)
+}
+
+// The btrfs label is stored in a 256 byte buffer, which includes the terminating
+// null character
+func (bs *BtrfsService) GetMaximumLabelLength() int {
+	return 255
+}
+
+func (bs *BtrfsService) DoesResizeRequireMount() bool {
+	return true
+}
+
+func (bs *BtrfsService) DoesResizeRequireUnmount() bool {
+	return false
+}
+
+func (bs *BtrfsService) DoesLabelRequireUnmount() bool {
+	return false
+}
+
+func (bs *BtrfsService) DoesFormatSupportLabel() bool {
+	return false
+}
+
+func (bs *BtrfsService) DoesTuneRequireUnmount() bool {
+	return false
+}
+
+// A btrfs file system reports an anonymous device number in the mount table. Its
+// mount point is therefore identified by the source of the mount
+func (bs *BtrfsService) getMountPoint(name string) (string, error) {
+	sysName, err := bs.deviceService.resolve(name)
+	if err != nil {
+		return "", err
+	}
+	return bs.deviceService.getMountPoint(sysName)
+}
+
+// validateFormatOptions rejects any option that is not supported by a file system.
+// A stripe unit and stripe width describe the same RAID array, and so must be
+// provided together
+func validateFormatOptions(fs model.FileSystem, options *model.FormatOptions, supported ...string) error {
+	if options == nil {
+		return nil
+	}
+	provided := []string{}
+	for _, o := range strings.Split(options.String(), ",") {
+		if name, _, found := strings.Cut(o, "="); found {
+			provided = append(provided, name)
+		}
+	}
+	for _, name := range provided {
+		if !slices.Contains(supported, name) {
+			return fmt.Errorf("Format option '%s' is not supported by the %s file system", name, fs)
+		}
+	}
+	if (options.StripeUnit > 0) != (options.StripeWidth > 0) {
+		return fmt.Errorf("A stripe unit and stripe width must be provided together")
+	}
+	return nil
+}
+
+// validateProperties rejects any property that can not be tuned on a file system
+func validateProperties(fs model.FileSystem, properties *model.FileSystemProperties, supported ...string) error {
+	if properties == nil {
+		return nil
+	}
+	for _, p := range strings.Split(properties.String(), ",") {
+		name, _, found := strings.Cut(p, "=")
+		if found && !slices.Contains(supported, name) {
+			return fmt.Errorf("Property '%s' can not be tuned on the %s file system", name, fs)
+		}
+	}
+	if _, err := model.ParseErrorBehaviour(string(properties.Errors)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
@@ -34,6 +34,20 @@ func TestFileSystemFactory(t *testing.T) {
 			ExpectedOutput: NewXfsService(nil),
 			ExpectedError:  nil,
 		},
+		{
+			Name:           "btrfs",
+			FileSystem:     model.Btrfs,
+			CmpOption:      cmp.AllowUnexported(BtrfsService{}, SysfsDeviceService{}),
+			ExpectedOutput: NewBtrfsService(nil),
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "swap",
+			FileSystem:     model.Swap,
+			CmpOption:      cmp.AllowUnexported(LinuxSwapService{}),
+			ExpectedOutput: NewLinuxSwapService(nil),
+			ExpectedError:  nil,
+		},
 		{
 			Name:           "brtfs",
 			FileSystem:     model.FileSystem("brtfs"),
@@ -69,6 +83,7 @@ func TestFormat(t *testing.T) {
 	subtests := []struct {
 		Name              string
 		Device            string
+		Options           *model.FormatOptions
 		FileSystemService func(rf utils.RunnerFactory) FileSystemService
 		RunnerBinary      utils.Binary
 		RunnerArgs        []string
@@ -96,6 +111,51 @@ func TestFormat(t *testing.T) {
 			RunnerError:       nil,
 			ExpectedError:     nil,
 		},
+		{
+			Name:              "btrfs",
+			Device:            "/dev/xvdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewBtrfsService(rf) },
+			RunnerBinary:      utils.MkfsBtrfs,
+			RunnerArgs:        []string{"/dev/xvdf"},
+			RunnerOutput:      "",
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:   "ext4 + Options",
+			Device: "/dev/md/scratch",
+			Options: &model.FormatOptions{
+				BlockSize:             4096,
+				InodeRatio:            65536,
+				ReservedBlocksPercent: uint64Ptr(0),
+				LazyItableInit:        boolPtr(false),
+				StripeUnit:            512,
+				StripeWidth:           2,
+				Label:                 "scratch",
+			},
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.MkfsExt4,
+			RunnerArgs:        []string{"-b", "4096", "-i", "65536", "-m", "0", "-E", "lazy_itable_init=0,stride=128,stripe_width=256", "-L", "scratch", "/dev/md/scratch"},
+			RunnerOutput:      "",
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:   "xfs + Options",
+			Device: "/dev/md/scratch",
+			Options: &model.FormatOptions{
+				BlockSize:   4096,
+				Reflink:     boolPtr(true),
+				StripeUnit:  512,
+				StripeWidth: 2,
+			},
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.MkfsXfs,
+			RunnerArgs:        []string{"-b", "size=4096", "-m", "reflink=1", "-d", "su=512k,sw=2", "/dev/md/scratch"},
+			RunnerOutput:      "",
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
 		{
 			Name:              "xfs + Error<existing_filesystem>",
 			Device:            "/dev/xvdf",
@@ -113,12 +173,120 @@ mkfs.xfs: Use the -f option to force overwrite.`),
 		t.Run(subtest.Name, func(t *testing.T) {
 			mrf := utils.NewMockRunnerFactory(subtest.RunnerBinary, subtest.RunnerArgs, subtest.RunnerOutput, subtest.RunnerError)
 			fss := subtest.FileSystemService(mrf)
-			err := fss.Format(subtest.Device)
+			err := fss.Format(subtest.Device, subtest.Options)
 			utils.CheckError("fss.Format()", t, subtest.ExpectedError, err)
 		})
 	}
 }
 
+func TestValidateFormatOptions(t *testing.T) {
+	subtests := []struct {
+		Name              string
+		FileSystemService FileSystemService
+		Options           *model.FormatOptions
+		ExpectedError     error
+	}{
+		{
+			Name:              "ext4 + Valid Options",
+			FileSystemService: NewExt4Service(nil),
+			Options: &model.FormatOptions{
+				BlockSize:             4096,
+				InodeRatio:            16384,
+				ReservedBlocksPercent: uint64Ptr(0),
+				LazyItableInit:        boolPtr(false),
+				StripeUnit:            64,
+				StripeWidth:           4,
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:              "ext4 + Unsupported Option",
+			FileSystemService: NewExt4Service(nil),
+			Options:           &model.FormatOptions{Reflink: boolPtr(true)},
+			ExpectedError:     fmt.Errorf("Format option 'reflink' is not supported by the ext4 file system"),
+		},
+		{
+			Name:              "ext4 + Invalid Block Size",
+			FileSystemService: NewExt4Service(nil),
+			Options:           &model.FormatOptions{BlockSize: 8192},
+			ExpectedError:     fmt.Errorf("A block size of 8192 is not supported by the ext4 file system. Expected one of 1024, 2048 or 4096"),
+		},
+		{
+			Name:              "ext4 + Inode Ratio Less Than Block Size",
+			FileSystemService: NewExt4Service(nil),
+			Options:           &model.FormatOptions{InodeRatio: 2048},
+			ExpectedError:     fmt.Errorf("An inode ratio of 2048 must be between the block size (4096) and 67108864"),
+		},
+		{
+			Name:              "ext4 + Excessive Reserved Blocks",
+			FileSystemService: NewExt4Service(nil),
+			Options:           &model.FormatOptions{ReservedBlocksPercent: uint64Ptr(75)},
+			ExpectedError:     fmt.Errorf("A reserved blocks percentage of 75 must not exceed 50"),
+		},
+		{
+			Name:              "ext4 + Stripe Unit Not a Multiple of Block Size",
+			FileSystemService: NewExt4Service(nil),
+			Options:           &model.FormatOptions{StripeUnit: 6, StripeWidth: 2},
+			ExpectedError:     fmt.Errorf("A stripe unit of 6KiB must be a multiple of the block size (4096)"),
+		},
+		{
+			Name:              "xfs + Valid Options",
+			FileSystemService: NewXfsService(nil),
+			Options:           &model.FormatOptions{BlockSize: 4096, Reflink: boolPtr(true), StripeUnit: 512, StripeWidth: 2},
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "xfs + Stripe Width Omitted",
+			FileSystemService: NewXfsService(nil),
+			Options:           &model.FormatOptions{StripeUnit: 512},
+			ExpectedError:     fmt.Errorf("A stripe unit and stripe width must be provided together"),
+		},
+		{
+			Name:              "xfs + Invalid Block Size",
+			FileSystemService: NewXfsService(nil),
+			Options:           &model.FormatOptions{BlockSize: 3000},
+			ExpectedError:     fmt.Errorf("A block size of 3000 is not supported by the xfs file system. Expected a power of two between 512 and 65536"),
+		},
+		{
+			Name:              "xfs + Unsupported Option",
+			FileSystemService: NewXfsService(nil),
+			Options:           &model.FormatOptions{ReservedBlocksPercent: uint64Ptr(0)},
+			ExpectedError:     fmt.Errorf("Format option 'reservedBlocksPercent' is not supported by the xfs file system"),
+		},
+		{
+			// The label is not a user-provided option
+			Name:              "btrfs + Label",
+			FileSystemService: NewBtrfsService(nil),
+			Options:           &model.FormatOptions{Label: "pool"},
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "btrfs + Unsupported Option",
+			FileSystemService: NewBtrfsService(nil),
+			Options:           &model.FormatOptions{BlockSize: 4096},
+			ExpectedError:     fmt.Errorf("Format option 'blockSize' is not supported by the btrfs file system"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			err := subtest.FileSystemService.ValidateFormatOptions(subtest.Options)
+			utils.CheckError("fss.ValidateFormatOptions()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func uint64Ptr(v uint64) *uint64 {
+	return &v
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
 func TestLabel(t *testing.T) {
 	subtests := []struct {
 		Name              string
@@ -174,6 +342,223 @@ func TestLabel(t *testing.T) {
 		})
 	}
 }
+
+func TestSetUuid(t *testing.T) {
+	subtests := []struct {
+		Name              string
+		Device            string
+		Uuid              string
+		FileSystemService func(rf utils.RunnerFactory) FileSystemService
+		RunnerBinary      utils.Binary
+		RunnerArgs        []string
+		RunnerError       error
+		ExpectedError     error
+	}{
+		{
+			Name:              "ext4 + Random",
+			Device:            "/dev/xvdf",
+			Uuid:              model.RandomUuid,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.Tune2fs,
+			RunnerArgs:        []string{"-U", "random", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "ext4 + Fixed",
+			Device:            "/dev/xvdf",
+			Uuid:              "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.Tune2fs,
+			RunnerArgs:        []string{"-U", "31cf3150-f4b1-409a-9887-7f9da1fdd99c", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "xfs + Random",
+			Device:            "/dev/xvdf",
+			Uuid:              model.RandomUuid,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsAdmin,
+			RunnerArgs:        []string{"-U", "generate", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "xfs + Fixed",
+			Device:            "/dev/xvdf",
+			Uuid:              "31cf3150-f4b1-409a-9887-7f9da1fdd99c",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsAdmin,
+			RunnerArgs:        []string{"-U", "31cf3150-f4b1-409a-9887-7f9da1fdd99c", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "btrfs",
+			Device:            "/dev/xvdf",
+			Uuid:              model.RandomUuid,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewBtrfsService(rf) },
+			ExpectedError:     fmt.Errorf("The UUID of the btrfs file system can not be changed"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(subtest.RunnerBinary, subtest.RunnerArgs, "", subtest.RunnerError)
+			fss := subtest.FileSystemService(mrf)
+			err := fss.SetUuid(subtest.Device, subtest.Uuid)
+			utils.CheckError("fss.SetUuid()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	subtests := []struct {
+		Name              string
+		Device            string
+		Repair            bool
+		FileSystemService func(rf utils.RunnerFactory) FileSystemService
+		RunnerBinary      utils.Binary
+		RunnerArgs        []string
+		RunnerError       error
+		ExpectedOutput    model.FsckResult
+		ExpectedError     error
+	}{
+		{
+			Name:              "ext4 + Check + Clean",
+			Device:            "/dev/xvdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.E2fsck,
+			RunnerArgs:        []string{"-n", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedOutput:    model.FsckClean,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "ext4 + Check + Uncorrected",
+			Device:            "/dev/xvdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.E2fsck,
+			RunnerArgs:        []string{"-n", "/dev/xvdf"},
+			RunnerError:       utils.NewExitError(4, "/dev/xvdf contains a file system with errors"),
+			ExpectedOutput:    model.FsckUncorrected,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "ext4 + Repair + Corrected",
+			Device:            "/dev/xvdf",
+			Repair:            true,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.E2fsck,
+			RunnerArgs:        []string{"-p", "/dev/xvdf"},
+			RunnerError:       utils.NewExitError(1, "/dev/xvdf: 11/65536 files"),
+			ExpectedOutput:    model.FsckCorrected,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "ext4 + Repair + Corrected + Reboot",
+			Device:            "/dev/xvdf",
+			Repair:            true,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.E2fsck,
+			RunnerArgs:        []string{"-p", "/dev/xvdf"},
+			RunnerError:       utils.NewExitError(3, "/dev/xvdf: 11/65536 files"),
+			ExpectedOutput:    model.FsckCorrected,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "ext4 + Repair + Uncorrected",
+			Device:            "/dev/xvdf",
+			Repair:            true,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.E2fsck,
+			RunnerArgs:        []string{"-p", "/dev/xvdf"},
+			RunnerError:       utils.NewExitError(4, "/dev/xvdf: UNEXPECTED INCONSISTENCY; RUN fsck MANUALLY."),
+			ExpectedOutput:    model.FsckUncorrected,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "ext4 + Operational Error",
+			Device:            "/dev/xvdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.E2fsck,
+			RunnerArgs:        []string{"-n", "/dev/xvdf"},
+			RunnerError:       utils.NewExitError(8, "e2fsck: No such file or directory"),
+			ExpectedOutput:    "",
+			ExpectedError:     utils.NewExitError(8, "e2fsck: No such file or directory"),
+		},
+		{
+			Name:              "xfs + Check + Clean",
+			Device:            "/dev/xvdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsRepair,
+			RunnerArgs:        []string{"-n", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedOutput:    model.FsckClean,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "xfs + Check + Uncorrected",
+			Device:            "/dev/xvdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsRepair,
+			RunnerArgs:        []string{"-n", "/dev/xvdf"},
+			RunnerError:       utils.NewExitError(1, "would have reset inode 131 nlinks"),
+			ExpectedOutput:    model.FsckUncorrected,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "xfs + Repair",
+			Device:            "/dev/xvdf",
+			Repair:            true,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsRepair,
+			RunnerArgs:        []string{"/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedOutput:    model.FsckClean,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "xfs + Repair + Dirty Log",
+			Device:            "/dev/xvdf",
+			Repair:            true,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsRepair,
+			RunnerArgs:        []string{"/dev/xvdf"},
+			RunnerError:       utils.NewExitError(2, "ERROR: The filesystem has valuable metadata changes in a log"),
+			ExpectedOutput:    "",
+			ExpectedError:     fmt.Errorf("🔴 /dev/xvdf: The log of the xfs file system must be replayed by mounting it, before it can be repaired"),
+		},
+		{
+			Name:              "btrfs + Check + Uncorrected",
+			Device:            "/dev/xvdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewBtrfsService(rf) },
+			RunnerBinary:      utils.Btrfs,
+			RunnerArgs:        []string{"check", "--readonly", "/dev/xvdf"},
+			RunnerError:       utils.NewExitError(1, "found 1 errors"),
+			ExpectedOutput:    model.FsckUncorrected,
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "btrfs + Repair",
+			Device:            "/dev/xvdf",
+			Repair:            true,
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewBtrfsService(rf) },
+			ExpectedOutput:    "",
+			ExpectedError:     fmt.Errorf("The btrfs file system can not be repaired automatically"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(subtest.RunnerBinary, subtest.RunnerArgs, "", subtest.RunnerError)
+			fss := subtest.FileSystemService(mrf)
+			result, err := fss.Check(subtest.Device, subtest.Repair)
+			utils.CheckError("fss.Check()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("fss.Check()", t, subtest.ExpectedOutput, result)
+		})
+	}
+}
+
 func TestResize(t *testing.T) {
 	subtests := []struct {
 		Name              string
@@ -205,6 +590,16 @@ func TestResize(t *testing.T) {
 			RunnerError:       nil,
 			ExpectedError:     nil,
 		},
+		{
+			Name:              "btrfs",
+			Device:            "/mnt/cache",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewBtrfsService(rf) },
+			RunnerBinary:      utils.Btrfs,
+			RunnerArgs:        []string{"filesystem", "resize", "max", "/mnt/cache"},
+			RunnerOutput:      "",
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
 		{
 			Name:              "xfs + error<permission_denied>",
 			Device:            "/dev/xvdf",
@@ -243,6 +638,11 @@ func TestGetFileSystem(t *testing.T) {
 			FileSystemService: NewXfsService(mrf),
 			ExpectedOutput:    model.Xfs,
 		},
+		{
+			Name:              "btrfs",
+			FileSystemService: NewBtrfsService(mrf),
+			ExpectedOutput:    model.Btrfs,
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -269,6 +669,11 @@ func TestGetMaximumLabelLength(t *testing.T) {
 			FileSystemService: NewXfsService(mrf),
 			ExpectedOutput:    12,
 		},
+		{
+			Name:              "btrfs",
+			FileSystemService: NewBtrfsService(mrf),
+			ExpectedOutput:    255,
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -295,6 +700,11 @@ func TestResizeRequireMount(t *testing.T) {
 			FileSystemService: NewXfsService(mrf),
 			ExpectedOutput:    true,
 		},
+		{
+			Name:              "btrfs",
+			FileSystemService: NewBtrfsService(mrf),
+			ExpectedOutput:    true,
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -321,6 +731,11 @@ func TestLabelRequireUnmount(t *testing.T) {
 			FileSystemService: NewXfsService(mrf),
 			ExpectedOutput:    true,
 		},
+		{
+			Name:              "btrfs",
+			FileSystemService: NewBtrfsService(mrf),
+			ExpectedOutput:    false,
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -387,6 +802,28 @@ func TestGetFileSystemSize(t *testing.T) {
 			ExpectedOutput:    0,
 			ExpectedError:     fmt.Errorf("🔴 /dev/vdc: No such file or directory"),
 		},
+		{
+			Name:              "success<btrfs>",
+			Device:            "/dev/vde",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewBtrfsService(rf) },
+			RunnerBinary:      utils.Btrfs,
+			RunnerArgs:        []string{"filesystem", "show", "--raw", "/dev/vde"},
+			RunnerOutputFile:  "testdata/btrfs_show.txt",
+			RunnerError:       nil,
+			ExpectedOutput:    5368709120, // devid(Size) of /dev/vde
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "failure<btrfs>",
+			Device:            "/dev/vdf",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewBtrfsService(rf) },
+			RunnerBinary:      utils.Btrfs,
+			RunnerArgs:        []string{"filesystem", "show", "--raw", "/dev/vdf"},
+			RunnerOutputFile:  "testdata/btrfs_show.txt",
+			RunnerError:       nil,
+			ExpectedOutput:    0,
+			ExpectedError:     fmt.Errorf("🔴 /dev/vdf: Device size not found btrfs filesystem show output"),
+		},
 	}
 	for _, subtest := range subtests {
 		t.Run(subtest.Name, func(t *testing.T) {
@@ -407,3 +844,249 @@ func TestGetFileSystemSize(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProperties(t *testing.T) {
+	subtests := []struct {
+		Name              string
+		Device            string
+		FileSystemService func(rf utils.RunnerFactory) FileSystemService
+		RunnerBinary      utils.Binary
+		RunnerArgs        []string
+		RunnerOutputFile  string
+		RunnerError       error
+		ExpectedOutput    *model.FileSystemProperties
+		ExpectedError     error
+	}{
+		{
+			Name:              "success<ext4>",
+			Device:            "/dev/vdb",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.Tune2fs,
+			RunnerArgs:        []string{"-l", "/dev/vdb"},
+			RunnerOutputFile:  "testdata/tune2fs.txt",
+			RunnerError:       nil,
+			ExpectedOutput: &model.FileSystemProperties{
+				ReservedBlocksPercent: uint64Ptr(5), // Reserved Block Count / Block Count
+				MaxMountCount:         int64Ptr(-1),
+				Errors:                model.ErrorsContinue,
+				Features:              []string{"has_journal", "ext_attr", "resize_inode", "dir_index", "filetype", "extent", "64bit", "flex_bg", "sparse_super", "large_file", "huge_file", "dir_nlink", "extra_isize", "metadata_csum"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:              "failure<ext4>",
+			Device:            "/dev/vdb",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.Tune2fs,
+			RunnerArgs:        []string{"-l", "/dev/vdb"},
+			RunnerError:       fmt.Errorf("🔴 tune2fs: No such file or directory while trying to open /dev/vdb"),
+			ExpectedOutput:    nil,
+			ExpectedError:     fmt.Errorf("🔴 tune2fs: No such file or directory while trying to open /dev/vdb"),
+		},
+		{
+			Name:              "success<xfs>",
+			Device:            "/dev/vdc",
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsInfo,
+			RunnerArgs:        []string{"/dev/vdc"},
+			RunnerOutputFile:  "testdata/xfs_info.txt",
+			RunnerError:       nil,
+			ExpectedOutput: &model.FileSystemProperties{
+				Features: []string{"projid32bit", "crc", "finobt", "sparse", "reflink", "ftype"},
+			},
+			ExpectedError: nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			var runnerOutput string
+			if len(subtest.RunnerOutputFile) > 0 {
+				data, err := os.ReadFile(subtest.RunnerOutputFile)
+				utils.ExpectErr("os.ReadFile()", t, false, err)
+				runnerOutput = string(data)
+			}
+
+			mrf := utils.NewMockRunnerFactory(subtest.RunnerBinary, subtest.RunnerArgs, runnerOutput, subtest.RunnerError)
+			fss := subtest.FileSystemService(mrf)
+			fp, err := fss.GetProperties(subtest.Device)
+			utils.CheckError("fss.GetProperties()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("fss.GetProperties()", t, subtest.ExpectedOutput, fp)
+		})
+	}
+}
+
+func TestTune(t *testing.T) {
+	subtests := []struct {
+		Name              string
+		Device            string
+		Properties        *model.FileSystemProperties
+		FileSystemService func(rf utils.RunnerFactory) FileSystemService
+		RunnerBinary      utils.Binary
+		RunnerArgs        []string
+		RunnerError       error
+		ExpectedError     error
+	}{
+		{
+			Name:   "ext4",
+			Device: "/dev/xvdf",
+			Properties: &model.FileSystemProperties{
+				ReservedBlocksPercent: uint64Ptr(0),
+				MaxMountCount:         int64Ptr(-1),
+				Errors:                model.ErrorsRemountRo,
+				Features:              []string{"fast_commit", "metadata_csum"},
+			},
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewExt4Service(rf) },
+			RunnerBinary:      utils.Tune2fs,
+			RunnerArgs:        []string{"-m", "0", "-c", "-1", "-e", "remount-ro", "-O", "fast_commit,metadata_csum", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:   "xfs",
+			Device: "/dev/xvdf",
+			Properties: &model.FileSystemProperties{
+				Features: []string{"bigtime", "inobtcount"},
+			},
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsAdmin,
+			RunnerArgs:        []string{"-O", "bigtime=1,inobtcount=1", "/dev/xvdf"},
+			RunnerError:       nil,
+			ExpectedError:     nil,
+		},
+		{
+			Name:   "xfs + Mounted",
+			Device: "/dev/xvdf",
+			Properties: &model.FileSystemProperties{
+				Features: []string{"bigtime"},
+			},
+			FileSystemService: func(rf utils.RunnerFactory) FileSystemService { return NewXfsService(rf) },
+			RunnerBinary:      utils.XfsAdmin,
+			RunnerArgs:        []string{"-O", "bigtime=1", "/dev/xvdf"},
+			RunnerError:       fmt.Errorf("🔴 xfs_admin: /dev/xvdf contains a mounted filesystem"),
+			ExpectedError:     fmt.Errorf("🔴 xfs_admin: /dev/xvdf contains a mounted filesystem"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(subtest.RunnerBinary, subtest.RunnerArgs, "", subtest.RunnerError)
+			fss := subtest.FileSystemService(mrf)
+			err := fss.Tune(subtest.Device, subtest.Properties)
+			utils.CheckError("fss.Tune()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestValidateProperties(t *testing.T) {
+	subtests := []struct {
+		Name              string
+		FileSystemService FileSystemService
+		Properties        *model.FileSystemProperties
+		ExpectedError     error
+	}{
+		{
+			Name:              "ext4 + Valid Properties",
+			FileSystemService: NewExt4Service(nil),
+			Properties: &model.FileSystemProperties{
+				ReservedBlocksPercent: uint64Ptr(1),
+				MaxMountCount:         int64Ptr(-1),
+				Errors:                model.ErrorsPanic,
+				Features:              []string{"fast_commit"},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:              "ext4 + Excessive Reserved Blocks",
+			FileSystemService: NewExt4Service(nil),
+			Properties:        &model.FileSystemProperties{ReservedBlocksPercent: uint64Ptr(60)},
+			ExpectedError:     fmt.Errorf("A reserved blocks percentage of 60 must not exceed 50"),
+		},
+		{
+			Name:              "ext4 + Invalid Maximum Mount Count",
+			FileSystemService: NewExt4Service(nil),
+			Properties:        &model.FileSystemProperties{MaxMountCount: int64Ptr(-2)},
+			ExpectedError:     fmt.Errorf("A maximum mount count of -2 must be between -1 and 16000"),
+		},
+		{
+			Name:              "ext4 + Unsupported Error Behaviour",
+			FileSystemService: NewExt4Service(nil),
+			Properties:        &model.FileSystemProperties{Errors: model.ErrorBehaviour("halt")},
+			ExpectedError:     fmt.Errorf("Error behaviour 'halt' is not supported"),
+		},
+		{
+			Name:              "xfs + Valid Properties",
+			FileSystemService: NewXfsService(nil),
+			Properties:        &model.FileSystemProperties{Features: []string{"bigtime"}},
+			ExpectedError:     nil,
+		},
+		{
+			Name:              "xfs + Unsupported Property",
+			FileSystemService: NewXfsService(nil),
+			Properties:        &model.FileSystemProperties{ReservedBlocksPercent: uint64Ptr(0)},
+			ExpectedError:     fmt.Errorf("Property 'reservedBlocksPercent' can not be tuned on the xfs file system"),
+		},
+		{
+			Name:              "btrfs + Unsupported Property",
+			FileSystemService: NewBtrfsService(nil),
+			Properties:        &model.FileSystemProperties{Features: []string{"quota"}},
+			ExpectedError:     fmt.Errorf("Property 'features' can not be tuned on the btrfs file system"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			err := subtest.FileSystemService.ValidateProperties(subtest.Properties)
+			utils.CheckError("fss.ValidateProperties()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestBtrfsLabel(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Device        string
+		Label         string
+		MountInfo     string
+		Runners       map[utils.Binary]*utils.MockRunner
+		ExpectedError error
+	}{
+		{
+			Name:      "Unmounted",
+			Device:    "/dev/xvdf",
+			Label:     "build-cache",
+			MountInfo: "",
+			Runners: map[utils.Binary]*utils.MockRunner{
+				utils.Btrfs: utils.NewMockRunner([]string{"filesystem", "label", "/dev/xvdf", "build-cache"}, "", nil),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:      "Mounted",
+			Device:    "/dev/xvdf",
+			Label:     "build-cache",
+			MountInfo: "39 22 0:45 / /mnt/cache rw,relatime shared:5 - btrfs /dev/xvdf rw\n",
+			Runners: map[utils.Binary]*utils.MockRunner{
+				utils.Btrfs: utils.NewMockRunner([]string{"filesystem", "label", "/mnt/cache", "build-cache"}, "", nil),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "Malformed mountinfo",
+			Device:        "/dev/xvdf",
+			Label:         "build-cache",
+			MountInfo:     "39 22 0:45 / /mnt/cache\n",
+			Runners:       map[utils.Binary]*utils.MockRunner{},
+			ExpectedError: fmt.Errorf("🔴 Failed to decode mountinfo"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			root := createFakeRoot(t, []*fakeBlockDevice{
+				{Name: "xvdf", Dev: "202:80", Sectors: 2097152},
+			}, subtest.MountInfo, nil)
+			mrf := utils.NewMockMultiRunnerFactory(subtest.Runners)
+			bs := NewBtrfsService(mrf)
+			bs.deviceService.root = root
+			err := bs.Label(subtest.Device, subtest.Label)
+			utils.CheckError("bs.Label()", t, subtest.ExpectedError, err)
+		})
+	}
+}
@@ -16,10 +16,11 @@ type LvmService interface {
 	GetLogicalVolumes() ([]*model.LogicalVolume, error)
 	CreatePhysicalVolume(name string) error
 	CreateVolumeGroup(name string, physicalVolume string) error
-	CreateLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) error
+	ExtendVolumeGroup(name string, physicalVolume string) error
+	CreateLogicalVolume(name string, volumeGroup string, size model.LvmSize) error
 	ActivateLogicalVolume(name string, volumeGroup string) error
 	ResizePhysicalVolume(name string) error
-	ResizeLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) error
+	ResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) error
 }
 
 type LinuxLvmService struct {
@@ -192,9 +193,16 @@ func (ls *LinuxLvmService) CreateVolumeGroup(name string, physicalVolume string)
 	return err
 }
 
-func (ls *LinuxLvmService) CreateLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) error {
+func (ls *LinuxLvmService) ExtendVolumeGroup(name string, physicalVolume string) error {
+	r := ls.runnerFactory.Select(utils.VgExtend)
+	_, err := r.Command(name, physicalVolume)
+	return err
+}
+
+func (ls *LinuxLvmService) CreateLogicalVolume(name string, volumeGroup string, size model.LvmSize) error {
 	r := ls.runnerFactory.Select(utils.LvCreate)
-	_, err := r.Command("-l", fmt.Sprintf("%d%%VG", volumeGroupPercent), "-n", name, volumeGroup)
+	flag, value := lvmSizeArgs(size)
+	_, err := r.Command(flag, value, "-n", name, volumeGroup)
 	return err
 }
 
@@ -210,8 +218,19 @@ func (ls *LinuxLvmService) ResizePhysicalVolume(name string) error {
 	return err
 }
 
-func (ls *LinuxLvmService) ResizeLogicalVolume(name string, volumeGroup string, volumeGroupPercent uint64) error {
+func (ls *LinuxLvmService) ResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) error {
 	r := ls.runnerFactory.Select(utils.LvExtend)
-	_, err := r.Command("-l", fmt.Sprintf("%d%%VG", volumeGroupPercent), fmt.Sprintf("%s/%s", volumeGroup, name))
+	flag, value := lvmSizeArgs(size)
+	_, err := r.Command(flag, value, fmt.Sprintf("%s/%s", volumeGroup, name))
 	return err
 }
+
+// lvmSizeArgs converts the size of a logical volume to the arguments of lvcreate and
+// lvextend. A percentage is expressed in extents (-l), while an absolute size
+// is expressed in bytes (-L)
+func lvmSizeArgs(size model.LvmSize) (string, string) {
+	if size.Unit == model.Bytes {
+		return "-L", fmt.Sprintf("%db", size.Value)
+	}
+	return "-l", fmt.Sprintf("%d%s", size.Value, size.Unit)
+}
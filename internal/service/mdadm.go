@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+type MdadmService interface {
+	GetArrays() ([]*model.RaidArray, error)
+	CreateArray(name string, level model.RaidLevel, chunkSize uint64, devices []string) error
+	AssembleArray(name string, devices []string) error
+}
+
+type LinuxMdadmService struct {
+	runnerFactory utils.RunnerFactory
+}
+
+func NewLinuxMdadmService(rf utils.RunnerFactory) *LinuxMdadmService {
+	return &LinuxMdadmService{
+		runnerFactory: rf,
+	}
+}
+
+// GetArrays retrieves every active array from the output of `mdadm --detail --scan --verbose`.
+// Each array is described by an ARRAY line, which is followed by an indented line
+// that lists its member devices
+//
+//	ARRAY /dev/md/scratch level=raid0 num-devices=2 metadata=1.2 name=host:scratch UUID=...
+//	   devices=/dev/nvme1n1,/dev/nvme2n1
+func (lms *LinuxMdadmService) GetArrays() ([]*model.RaidArray, error) {
+	r := lms.runnerFactory.Select(utils.Mdadm)
+	output, err := r.Command("--detail", "--scan", "--verbose")
+	if err != nil {
+		return nil, err
+	}
+	arrays := []*model.RaidArray{}
+	var ra *model.RaidArray
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "ARRAY" {
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("🔴 Failed to decode mdadm response")
+			}
+			// The name of an array is qualified by the name of the host that created it
+			// (e.g. host:scratch). If the name is not reported, we fall back to the
+			// name of the device
+			ra = &model.RaidArray{Name: path.Base(fields[1]), Devices: []string{}}
+			for _, field := range fields[2:] {
+				key, value, _ := strings.Cut(field, "=")
+				switch key {
+				case "level":
+					ra.Level = model.RaidLevel(value)
+				case "name":
+					ra.Name = value[strings.LastIndex(value, ":")+1:]
+				}
+			}
+			arrays = append(arrays, ra)
+			continue
+		}
+		if devices, found := strings.CutPrefix(fields[0], "devices="); found && ra != nil {
+			ra.Devices = strings.Split(devices, ",")
+		}
+	}
+	return arrays, nil
+}
+
+// The --run flag prevents mdadm from seeking confirmation when a member device
+// appears to already contain data
+func (lms *LinuxMdadmService) CreateArray(name string, level model.RaidLevel, chunkSize uint64, devices []string) error {
+	r := lms.runnerFactory.Select(utils.Mdadm)
+	args := []string{
+		"--create", path.Join(model.RaidDirectory, name),
+		"--run",
+		"--level=" + string(level),
+		fmt.Sprintf("--raid-devices=%d", len(devices)),
+	}
+	if chunkSize > 0 {
+		args = append(args, fmt.Sprintf("--chunk=%d", chunkSize))
+	}
+	_, err := r.Command(append(args, devices...)...)
+	return err
+}
+
+func (lms *LinuxMdadmService) AssembleArray(name string, devices []string) error {
+	r := lms.runnerFactory.Select(utils.Mdadm)
+	args := []string{"--assemble", path.Join(model.RaidDirectory, name)}
+	_, err := r.Command(append(args, devices...)...)
+	return err
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestGetArrays(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		RunnerOutput   string
+		RunnerError    error
+		ExpectedOutput []*model.RaidArray
+		ExpectedError  error
+	}{
+		{
+			Name: "Multiple Arrays",
+			RunnerOutput: `ARRAY /dev/md/scratch level=raid0 num-devices=2 metadata=1.2 name=ip-10-0-0-1:scratch UUID=3f5c1e2d:7a4e8b9c:0f1a2b3c:4d5e6f70
+   devices=/dev/nvme1n1,/dev/nvme2n1
+ARRAY /dev/md127 level=raid1 num-devices=2 metadata=1.2 UUID=0b1f2e3d:4c5b6a79:8e7d6c5b:4a392817
+   devices=/dev/nvme3n1,/dev/nvme4n1`,
+			RunnerError: nil,
+			ExpectedOutput: []*model.RaidArray{
+				{Name: "scratch", Level: model.Raid0, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}},
+				{Name: "md127", Level: model.Raid1, Devices: []string{"/dev/nvme3n1", "/dev/nvme4n1"}},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "No Arrays",
+			RunnerOutput:   "",
+			RunnerError:    nil,
+			ExpectedOutput: []*model.RaidArray{},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "mdadm Error",
+			RunnerOutput:   "",
+			RunnerError:    fmt.Errorf("🔴 mdadm is either not installed or accessible from $PATH"),
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 mdadm is either not installed or accessible from $PATH"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(utils.Mdadm, []string{"--detail", "--scan", "--verbose"}, subtest.RunnerOutput, subtest.RunnerError)
+			lms := NewLinuxMdadmService(mrf)
+			arrays, err := lms.GetArrays()
+			utils.CheckError("lms.GetArrays()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("lms.GetArrays()", t, subtest.ExpectedOutput, arrays)
+		})
+	}
+}
+
+func TestCreateArray(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Level         model.RaidLevel
+		ChunkSize     uint64
+		Devices       []string
+		RunnerArgs    []string
+		ExpectedError error
+	}{
+		{
+			Name:          "RAID0 + Chunk Size",
+			Level:         model.Raid0,
+			ChunkSize:     256,
+			Devices:       []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+			RunnerArgs:    []string{"--create", "/dev/md/scratch", "--run", "--level=raid0", "--raid-devices=2", "--chunk=256", "/dev/nvme1n1", "/dev/nvme2n1"},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "RAID1",
+			Level:         model.Raid1,
+			ChunkSize:     0,
+			Devices:       []string{"/dev/nvme1n1", "/dev/nvme2n1"},
+			RunnerArgs:    []string{"--create", "/dev/md/scratch", "--run", "--level=raid1", "--raid-devices=2", "/dev/nvme1n1", "/dev/nvme2n1"},
+			ExpectedError: nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(utils.Mdadm, subtest.RunnerArgs, "", nil)
+			lms := NewLinuxMdadmService(mrf)
+			err := lms.CreateArray("scratch", subtest.Level, subtest.ChunkSize, subtest.Devices)
+			utils.CheckError("lms.CreateArray()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestAssembleArray(t *testing.T) {
+	mrf := utils.NewMockRunnerFactory(utils.Mdadm, []string{"--assemble", "/dev/md/scratch", "/dev/nvme1n1", "/dev/nvme2n1"}, "", nil)
+	lms := NewLinuxMdadmService(mrf)
+	err := lms.AssembleArray("scratch", []string{"/dev/nvme1n1", "/dev/nvme2n1"})
+	utils.CheckError("lms.AssembleArray()", t, nil, err)
+}
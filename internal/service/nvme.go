@@ -7,6 +7,8 @@ import (
 	"strings"
 	"syscall"
 	"unsafe"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 )
 
 const (
@@ -128,22 +130,46 @@ func (d *NVMeIoctlResult) Syscall() error {
 
 type NVMeService interface {
 	GetBlockDeviceMapping(device string) (string, error)
+	GetVolumeId(device string) (string, error)
+	GetController(device string) (*model.NVMeController, error)
 }
 
-type AwsNitroNVMeService struct{}
+type AwsNitroNVMeService struct {
+	// identify issues the NVMe Identify Controller command to a device. It can be
+	// replaced to inject the identify data of devices that do not exist
+	identify func(device string) (*NVMeIoctlResult, error)
+}
 
 func NewAwsNitroNVMeService() *AwsNitroNVMeService {
-	return &AwsNitroNVMeService{}
+	return &AwsNitroNVMeService{
+		identify: func(device string) (*NVMeIoctlResult, error) {
+			nir := NewNVMeIoctlResult(device)
+			if err := nir.Syscall(); err != nil {
+				return nil, err
+			}
+			return nir, nil
+		},
+	}
 }
 
 func (ns *AwsNitroNVMeService) GetBlockDeviceMapping(device string) (string, error) {
-	nir := NewNVMeIoctlResult(device)
-	if err := nir.Syscall(); err != nil {
+	nir, err := ns.identify(device)
+	if err != nil {
 		return "", err
 	}
 	return ns.getBlockDeviceMapping(nir)
 }
 
+// GetVolumeId retrieves the id of the EBS volume that is exposed as an NVMe device.
+// An instance store volume has no volume id, so an empty string is returned
+func (ns *AwsNitroNVMeService) GetVolumeId(device string) (string, error) {
+	nir, err := ns.identify(device)
+	if err != nil {
+		return "", err
+	}
+	return ns.getVolumeId(nir)
+}
+
 func (ns *AwsNitroNVMeService) isEBSVolume(nir *NVMeIoctlResult) bool {
 	vid := nir.IdCtrl.Vid
 	mn := strings.TrimRightFunc(string(nir.IdCtrl.Mn[:]), ns.trimModelNumber)
@@ -187,6 +213,48 @@ func (ns *AwsNitroNVMeService) getBlockDeviceMapping(nir *NVMeIoctlResult) (stri
 	return bdm, nil
 }
 
+func (ns *AwsNitroNVMeService) GetController(device string) (*model.NVMeController, error) {
+	nir, err := ns.identify(device)
+	if err != nil {
+		return nil, err
+	}
+	return ns.getController(nir), nil
+}
+
+// The Identify Controller data of any NVMe device describes its model and serial
+// number. However, only an instance store volume has a virtual name
+func (ns *AwsNitroNVMeService) getController(nir *NVMeIoctlResult) *model.NVMeController {
+	nc := &model.NVMeController{
+		Name:   nir.Name,
+		Model:  strings.TrimRightFunc(string(nir.IdCtrl.Mn[:]), ns.trimModelNumber),
+		Serial: strings.TrimRightFunc(string(nir.IdCtrl.Sn[:]), ns.trimBlockDevice),
+	}
+	if ns.isInstanceStoreVolume(nir) {
+		vs := strings.TrimRightFunc(string(nir.IdCtrl.Vs.Bdev[:]), ns.trimBlockDevice)
+		if mbdm := instanceStoreRegex.FindStringSubmatch(vs); len(mbdm) == 3 {
+			nc.VirtualName = mbdm[1]
+		}
+	}
+	return nc
+}
+
+// The serial number of an EBS volume is its volume id without the dash (e.g.
+// vol0123456789abcdef0 -> vol-0123456789abcdef0)
+func (ns *AwsNitroNVMeService) getVolumeId(nir *NVMeIoctlResult) (string, error) {
+	if ns.isInstanceStoreVolume(nir) {
+		return "", nil
+	}
+	if !ns.isEBSVolume(nir) {
+		return "", fmt.Errorf("🔴 %s is not an AWS-managed NVME device", nir.Name)
+	}
+	sn := strings.TrimRightFunc(string(nir.IdCtrl.Sn[:]), ns.trimBlockDevice)
+	id, found := strings.CutPrefix(sn, "vol")
+	if !found {
+		return "", fmt.Errorf("🔴 %s: EBS serial number did not contain a volume id. Actual=%s", nir.Name, sn)
+	}
+	return "vol-" + strings.TrimPrefix(id, "-"), nil
+}
+
 func (ns *AwsNitroNVMeService) trimModelNumber(r rune) bool {
 	// Explanation:
 	// 	- Both the AWS EC2 and EBS team use the 0x20 (space) byte to pad out the Model Number
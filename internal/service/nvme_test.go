@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/reecetech/ebs-bootstrap/internal/model"
 	"github.com/reecetech/ebs-bootstrap/internal/utils"
 )
 
@@ -122,6 +123,160 @@ func TestGetBlockDeviceMapping(t *testing.T) {
 	}
 }
 
+func TestGetVolumeId(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Device         string
+		VendorId       uint16
+		ModelNumber    string
+		SerialNumber   string
+		ExpectedOutput string
+		ExpectedError  error
+	}{
+		{
+			Name:           "EBS NVMe Device",
+			Device:         "/dev/nvme1n1",
+			VendorId:       AMZN_NVME_VID,
+			ModelNumber:    AMZN_NVME_EBS_MN,
+			SerialNumber:   "vol0123456789abcdef0",
+			ExpectedOutput: "vol-0123456789abcdef0",
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "EBS NVMe Device + Short Volume Id",
+			Device:         "/dev/nvme1n1",
+			VendorId:       AMZN_NVME_VID,
+			ModelNumber:    AMZN_NVME_EBS_MN,
+			SerialNumber:   "vol01234567",
+			ExpectedOutput: "vol-01234567",
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Instance Store NVMe Device",
+			Device:         "/dev/nvme1n1",
+			VendorId:       AMZN_NVME_VID,
+			ModelNumber:    AMZN_NVME_INS_MN,
+			SerialNumber:   "AWS1A2B3C4D5E6F7G8H9",
+			ExpectedOutput: "",
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "EBS NVMe Device + Malformed Serial Number",
+			Device:         "/dev/nvme1n1",
+			VendorId:       AMZN_NVME_VID,
+			ModelNumber:    AMZN_NVME_EBS_MN,
+			SerialNumber:   "0123456789abcdef0",
+			ExpectedOutput: "",
+			ExpectedError:  fmt.Errorf("🔴 /dev/nvme1n1: EBS serial number did not contain a volume id. Actual=0123456789abcdef0"),
+		},
+		{
+			Name:           "Invalid NVMe Device (Unsupported Vendor ID)",
+			Device:         "/dev/nvme1n1",
+			VendorId:       UNSUPPORTED_NVME_VID,
+			ModelNumber:    AMZN_NVME_EBS_MN,
+			SerialNumber:   "vol0123456789abcdef0",
+			ExpectedOutput: "",
+			ExpectedError:  fmt.Errorf("🔴 /dev/nvme1n1 is not an AWS-managed NVME device"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			ns := NewAwsNitroNVMeService()
+			ns.identify = func(device string) (*NVMeIoctlResult, error) {
+				return &NVMeIoctlResult{
+					Name: device,
+					IdCtrl: nvmeIdentifyController{
+						Vid: subtest.VendorId,
+						Sn:  serialNumber(subtest.SerialNumber, SpaceByte),
+						Mn:  modelNumber(subtest.ModelNumber, SpaceByte),
+					},
+				}, nil
+			}
+			id, err := ns.GetVolumeId(subtest.Device)
+			utils.CheckError("ns.GetVolumeId()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("ns.GetVolumeId()", t, subtest.ExpectedOutput, id)
+		})
+	}
+}
+
+func TestGetController(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		ModelNumber    string
+		SerialNumber   string
+		BlockDevice    string
+		ExpectedOutput *model.NVMeController
+	}{
+		{
+			Name:         "EBS NVMe Device",
+			ModelNumber:  AMZN_NVME_EBS_MN,
+			SerialNumber: "vol0123456789abcdef0",
+			BlockDevice:  "/dev/sdb",
+			ExpectedOutput: &model.NVMeController{
+				Name:   "/dev/nvme1n1",
+				Model:  AMZN_NVME_EBS_MN,
+				Serial: "vol0123456789abcdef0",
+			},
+		},
+		{
+			Name:         "Instance Store NVMe Device",
+			ModelNumber:  AMZN_NVME_INS_MN,
+			SerialNumber: "AWS1A2B3C4D5E6F7G8H9",
+			BlockDevice:  "ephemeral1:none",
+			ExpectedOutput: &model.NVMeController{
+				Name:        "/dev/nvme1n1",
+				Model:       AMZN_NVME_INS_MN,
+				Serial:      "AWS1A2B3C4D5E6F7G8H9",
+				VirtualName: "ephemeral1",
+			},
+		},
+		{
+			Name:         "External NVMe Device",
+			ModelNumber:  UNSUPPORTED_NVME_MN,
+			SerialNumber: "S4EWNX0R123456",
+			BlockDevice:  "",
+			ExpectedOutput: &model.NVMeController{
+				Name:   "/dev/nvme1n1",
+				Model:  UNSUPPORTED_NVME_MN,
+				Serial: "S4EWNX0R123456",
+			},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			vsp, err := vendorSpecificPadding(subtest.ModelNumber)
+			utils.ExpectErr("vendorSpecificPadding()", t, false, err)
+			ns := NewAwsNitroNVMeService()
+			ns.identify = func(device string) (*NVMeIoctlResult, error) {
+				return &NVMeIoctlResult{
+					Name: device,
+					IdCtrl: nvmeIdentifyController{
+						Vid: AMZN_NVME_VID,
+						Sn:  serialNumber(subtest.SerialNumber, SpaceByte),
+						Mn:  modelNumber(subtest.ModelNumber, SpaceByte),
+						Vs: nvmeIdentifyControllerAmznVS{
+							Bdev: blockDevice(subtest.BlockDevice, vsp),
+						},
+					},
+				}, nil
+			}
+			nc, err := ns.GetController("/dev/nvme1n1")
+			utils.CheckError("ns.GetController()", t, nil, err)
+			utils.CheckOutput("ns.GetController()", t, subtest.ExpectedOutput, nc)
+		})
+	}
+}
+
+func serialNumber(input string, padding byte) [20]byte {
+	var sn [20]byte
+	// Copies input into sn[:]
+	copy(sn[:], input)
+	for i := len(input); i < 20; i++ {
+		sn[i] = padding
+	}
+	return sn
+}
+
 func modelNumber(input string, padding byte) [40]byte {
 	var mn [40]byte
 	// Copies input into mn[:]
@@ -11,6 +11,8 @@ type MockDeviceService struct {
 	StubGetBlockDevice  func(name string) (*model.BlockDevice, error)
 	StubMount           func(source string, target string, fs model.FileSystem, options model.MountOptions) error
 	StubUmount          func(source string, target string) error
+	StubGetSignatures   func(name string) ([]*model.Signature, error)
+	StubWipe            func(name string) error
 }
 
 func NewMockDeviceService() *MockDeviceService {
@@ -30,6 +32,12 @@ func NewMockDeviceService() *MockDeviceService {
 		StubUmount: func(source, target string) error {
 			return utils.NewNotImeplementedError("Umount()")
 		},
+		StubGetSignatures: func(name string) ([]*model.Signature, error) {
+			return nil, utils.NewNotImeplementedError("GetSignatures()")
+		},
+		StubWipe: func(name string) error {
+			return utils.NewNotImeplementedError("Wipe()")
+		},
 	}
 }
 
@@ -53,6 +61,14 @@ func (mds *MockDeviceService) Umount(source string, target string) error {
 	return mds.StubUmount(source, target)
 }
 
+func (mds *MockDeviceService) GetSignatures(name string) ([]*model.Signature, error) {
+	return mds.StubGetSignatures(name)
+}
+
+func (mds *MockDeviceService) Wipe(name string) error {
+	return mds.StubWipe(name)
+}
+
 type MockOwnerService struct {
 	StubGetCurrentUser  func() (*model.User, error)
 	StubGetCurrentGroup func() (*model.Group, error)
@@ -95,6 +111,8 @@ func (mos *MockOwnerService) GetGroup(grp string) (*model.Group, error) {
 
 type MockNVMeService struct {
 	StubGetBlockDeviceMapping func(device string) (string, error)
+	StubGetVolumeId           func(device string) (string, error)
+	StubGetController         func(device string) (*model.NVMeController, error)
 }
 
 func NewMockNVMeService() *MockNVMeService {
@@ -102,6 +120,12 @@ func NewMockNVMeService() *MockNVMeService {
 		StubGetBlockDeviceMapping: func(device string) (string, error) {
 			return "", utils.NewNotImeplementedError("GetBlockDeviceMapping()")
 		},
+		StubGetVolumeId: func(device string) (string, error) {
+			return "", utils.NewNotImeplementedError("GetVolumeId()")
+		},
+		StubGetController: func(device string) (*model.NVMeController, error) {
+			return nil, utils.NewNotImeplementedError("GetController()")
+		},
 	}
 }
 
@@ -109,6 +133,14 @@ func (mns *MockNVMeService) GetBlockDeviceMapping(device string) (string, error)
 	return mns.StubGetBlockDeviceMapping(device)
 }
 
+func (mns *MockNVMeService) GetVolumeId(device string) (string, error) {
+	return mns.StubGetVolumeId(device)
+}
+
+func (mns *MockNVMeService) GetController(device string) (*model.NVMeController, error) {
+	return mns.StubGetController(device)
+}
+
 // MockFileSystemServiceFactory uses the delegator pattern to inherit any error handling that
 // is implemented by FileSystemServiceFactory. This is useful for testing because we can
 // stub out the FileSystemService without having to match the error handling logic for FileSystemServiceFactory
@@ -134,14 +166,23 @@ func (mfsf *MockFileSystemServiceFactory) Select(fs model.FileSystem) (FileSyste
 }
 
 type MockFileSystemService struct {
-	StubGetSize                 func(name string) (uint64, error)
-	StubGetFileSystem           func() model.FileSystem
-	StubFormat                  func(name string) error
-	StubLabel                   func(name string, label string) error
-	StubResize                  func(name string) error
-	StubGetMaximumLabelLength   func() int
-	StubDoesResizeRequireMount  func() bool
-	StubDoesLabelRequireUnmount func() bool
+	StubGetSize                  func(name string) (uint64, error)
+	StubGetFileSystem            func() model.FileSystem
+	StubFormat                   func(name string, options *model.FormatOptions) error
+	StubValidateFormatOptions    func(options *model.FormatOptions) error
+	StubLabel                    func(name string, label string) error
+	StubSetUuid                  func(name string, uuid string) error
+	StubResize                   func(name string) error
+	StubCheck                    func(name string, repair bool) (model.FsckResult, error)
+	StubGetMaximumLabelLength    func() int
+	StubDoesResizeRequireMount   func() bool
+	StubDoesResizeRequireUnmount func() bool
+	StubDoesLabelRequireUnmount  func() bool
+	StubDoesFormatSupportLabel   func() bool
+	StubGetProperties            func(name string) (*model.FileSystemProperties, error)
+	StubTune                     func(name string, properties *model.FileSystemProperties) error
+	StubValidateProperties       func(properties *model.FileSystemProperties) error
+	StubDoesTuneRequireUnmount   func() bool
 }
 
 func NewMockFileSystemService() *MockFileSystemService {
@@ -152,24 +193,51 @@ func NewMockFileSystemService() *MockFileSystemService {
 		StubGetFileSystem: func() model.FileSystem {
 			return model.Unformatted
 		},
-		StubFormat: func(name string) error {
+		StubFormat: func(name string, options *model.FormatOptions) error {
 			return utils.NewNotImeplementedError("Format()")
 		},
+		StubValidateFormatOptions: func(options *model.FormatOptions) error {
+			return nil
+		},
 		StubLabel: func(name string, label string) error {
 			return utils.NewNotImeplementedError("Label()")
 		},
+		StubSetUuid: func(name string, uuid string) error {
+			return utils.NewNotImeplementedError("SetUuid()")
+		},
 		StubResize: func(name string) error {
 			return utils.NewNotImeplementedError("Resize()")
 		},
+		StubCheck: func(name string, repair bool) (model.FsckResult, error) {
+			return "", utils.NewNotImeplementedError("Check()")
+		},
 		StubGetMaximumLabelLength: func() int {
 			return 0
 		},
 		StubDoesResizeRequireMount: func() bool {
 			return false
 		},
+		StubDoesResizeRequireUnmount: func() bool {
+			return false
+		},
 		StubDoesLabelRequireUnmount: func() bool {
 			return false
 		},
+		StubDoesFormatSupportLabel: func() bool {
+			return false
+		},
+		StubGetProperties: func(name string) (*model.FileSystemProperties, error) {
+			return nil, utils.NewNotImeplementedError("GetProperties()")
+		},
+		StubTune: func(name string, properties *model.FileSystemProperties) error {
+			return utils.NewNotImeplementedError("Tune()")
+		},
+		StubValidateProperties: func(properties *model.FileSystemProperties) error {
+			return nil
+		},
+		StubDoesTuneRequireUnmount: func() bool {
+			return false
+		},
 	}
 }
 
@@ -181,18 +249,30 @@ func (mfs *MockFileSystemService) GetFileSystem() model.FileSystem {
 	return mfs.StubGetFileSystem()
 }
 
-func (mfs *MockFileSystemService) Format(name string) error {
-	return mfs.StubFormat(name)
+func (mfs *MockFileSystemService) Format(name string, options *model.FormatOptions) error {
+	return mfs.StubFormat(name, options)
+}
+
+func (mfs *MockFileSystemService) ValidateFormatOptions(options *model.FormatOptions) error {
+	return mfs.StubValidateFormatOptions(options)
 }
 
 func (mfs *MockFileSystemService) Label(name string, label string) error {
 	return mfs.StubLabel(name, label)
 }
 
+func (mfs *MockFileSystemService) SetUuid(name string, uuid string) error {
+	return mfs.StubSetUuid(name, uuid)
+}
+
 func (mfs *MockFileSystemService) Resize(name string) error {
 	return mfs.StubResize(name)
 }
 
+func (mfs *MockFileSystemService) Check(name string, repair bool) (model.FsckResult, error) {
+	return mfs.StubCheck(name, repair)
+}
+
 func (mfs *MockFileSystemService) GetMaximumLabelLength() int {
 	return mfs.StubGetMaximumLabelLength()
 }
@@ -201,15 +281,41 @@ func (mfs *MockFileSystemService) DoesResizeRequireMount() bool {
 	return mfs.StubDoesResizeRequireMount()
 }
 
+func (mfs *MockFileSystemService) DoesResizeRequireUnmount() bool {
+	return mfs.StubDoesResizeRequireUnmount()
+}
+
 func (mfs *MockFileSystemService) DoesLabelRequireUnmount() bool {
 	return mfs.StubDoesLabelRequireUnmount()
 }
 
+func (mfs *MockFileSystemService) DoesFormatSupportLabel() bool {
+	return mfs.StubDoesFormatSupportLabel()
+}
+
+func (mfs *MockFileSystemService) GetProperties(name string) (*model.FileSystemProperties, error) {
+	return mfs.StubGetProperties(name)
+}
+
+func (mfs *MockFileSystemService) Tune(name string, properties *model.FileSystemProperties) error {
+	return mfs.StubTune(name, properties)
+}
+
+func (mfs *MockFileSystemService) ValidateProperties(properties *model.FileSystemProperties) error {
+	return mfs.StubValidateProperties(properties)
+}
+
+func (mfs *MockFileSystemService) DoesTuneRequireUnmount() bool {
+	return mfs.StubDoesTuneRequireUnmount()
+}
+
 type MockFileService struct {
 	StubGetFile           func(file string) (*model.File, error)
 	StubCreateDirectory   func(p string) error
 	StubChangeOwner       func(p string, uid model.UserId, gid model.GroupId) error
 	StubChangePermissions func(p string, perms model.FilePermissions) error
+	StubReadFile          func(p string) ([]byte, error)
+	StubWriteFile         func(p string, data []byte, perms model.FilePermissions) error
 }
 
 func NewMockFileService() *MockFileService {
@@ -226,6 +332,12 @@ func NewMockFileService() *MockFileService {
 		StubChangePermissions: func(p string, perms model.FilePermissions) error {
 			return utils.NewNotImeplementedError("ChangePermissions()")
 		},
+		StubReadFile: func(p string) ([]byte, error) {
+			return nil, utils.NewNotImeplementedError("ReadFile()")
+		},
+		StubWriteFile: func(p string, data []byte, perms model.FilePermissions) error {
+			return utils.NewNotImeplementedError("WriteFile()")
+		},
 	}
 }
 
@@ -244,3 +356,243 @@ func (mfs *MockFileService) ChangeOwner(p string, uid model.UserId, gid model.Gr
 func (mfs *MockFileService) ChangePermissions(p string, perms model.FilePermissions) error {
 	return mfs.StubChangePermissions(p, perms)
 }
+
+func (mfs *MockFileService) ReadFile(p string) ([]byte, error) {
+	return mfs.StubReadFile(p)
+}
+
+func (mfs *MockFileService) WriteFile(p string, data []byte, perms model.FilePermissions) error {
+	return mfs.StubWriteFile(p, data, perms)
+}
+
+type MockLvmService struct {
+	StubGetDevices            func() ([]*model.Device, error)
+	StubGetPhysicalVolumes    func() ([]*model.PhysicalVolume, error)
+	StubGetVolumeGroups       func() ([]*model.VolumeGroup, error)
+	StubGetLogicalVolumes     func() ([]*model.LogicalVolume, error)
+	StubCreatePhysicalVolume  func(name string) error
+	StubCreateVolumeGroup     func(name string, physicalVolume string) error
+	StubExtendVolumeGroup     func(name string, physicalVolume string) error
+	StubCreateLogicalVolume   func(name string, volumeGroup string, size model.LvmSize) error
+	StubActivateLogicalVolume func(name string, volumeGroup string) error
+	StubResizePhysicalVolume  func(name string) error
+	StubResizeLogicalVolume   func(name string, volumeGroup string, size model.LvmSize) error
+}
+
+func NewMockLvmService() *MockLvmService {
+	return &MockLvmService{
+		StubGetDevices: func() ([]*model.Device, error) {
+			return nil, utils.NewNotImeplementedError("GetDevices()")
+		},
+		StubGetPhysicalVolumes: func() ([]*model.PhysicalVolume, error) {
+			return nil, utils.NewNotImeplementedError("GetPhysicalVolumes()")
+		},
+		StubGetVolumeGroups: func() ([]*model.VolumeGroup, error) {
+			return nil, utils.NewNotImeplementedError("GetVolumeGroups()")
+		},
+		StubGetLogicalVolumes: func() ([]*model.LogicalVolume, error) {
+			return nil, utils.NewNotImeplementedError("GetLogicalVolumes()")
+		},
+		StubCreatePhysicalVolume: func(name string) error {
+			return utils.NewNotImeplementedError("CreatePhysicalVolume()")
+		},
+		StubCreateVolumeGroup: func(name string, physicalVolume string) error {
+			return utils.NewNotImeplementedError("CreateVolumeGroup()")
+		},
+		StubExtendVolumeGroup: func(name string, physicalVolume string) error {
+			return utils.NewNotImeplementedError("ExtendVolumeGroup()")
+		},
+		StubCreateLogicalVolume: func(name string, volumeGroup string, size model.LvmSize) error {
+			return utils.NewNotImeplementedError("CreateLogicalVolume()")
+		},
+		StubActivateLogicalVolume: func(name string, volumeGroup string) error {
+			return utils.NewNotImeplementedError("ActivateLogicalVolume()")
+		},
+		StubResizePhysicalVolume: func(name string) error {
+			return utils.NewNotImeplementedError("ResizePhysicalVolume()")
+		},
+		StubResizeLogicalVolume: func(name string, volumeGroup string, size model.LvmSize) error {
+			return utils.NewNotImeplementedError("ResizeLogicalVolume()")
+		},
+	}
+}
+
+func (mls *MockLvmService) GetDevices() ([]*model.Device, error) {
+	return mls.StubGetDevices()
+}
+
+func (mls *MockLvmService) GetPhysicalVolumes() ([]*model.PhysicalVolume, error) {
+	return mls.StubGetPhysicalVolumes()
+}
+
+func (mls *MockLvmService) GetVolumeGroups() ([]*model.VolumeGroup, error) {
+	return mls.StubGetVolumeGroups()
+}
+
+func (mls *MockLvmService) GetLogicalVolumes() ([]*model.LogicalVolume, error) {
+	return mls.StubGetLogicalVolumes()
+}
+
+func (mls *MockLvmService) CreatePhysicalVolume(name string) error {
+	return mls.StubCreatePhysicalVolume(name)
+}
+
+func (mls *MockLvmService) CreateVolumeGroup(name string, physicalVolume string) error {
+	return mls.StubCreateVolumeGroup(name, physicalVolume)
+}
+
+func (mls *MockLvmService) ExtendVolumeGroup(name string, physicalVolume string) error {
+	return mls.StubExtendVolumeGroup(name, physicalVolume)
+}
+
+func (mls *MockLvmService) CreateLogicalVolume(name string, volumeGroup string, size model.LvmSize) error {
+	return mls.StubCreateLogicalVolume(name, volumeGroup, size)
+}
+
+func (mls *MockLvmService) ActivateLogicalVolume(name string, volumeGroup string) error {
+	return mls.StubActivateLogicalVolume(name, volumeGroup)
+}
+
+func (mls *MockLvmService) ResizePhysicalVolume(name string) error {
+	return mls.StubResizePhysicalVolume(name)
+}
+
+func (mls *MockLvmService) ResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) error {
+	return mls.StubResizeLogicalVolume(name, volumeGroup, size)
+}
+
+type MockSystemdService struct {
+	StubDaemonReload func() error
+	StubEnableUnit   func(name string) error
+}
+
+func NewMockSystemdService() *MockSystemdService {
+	return &MockSystemdService{
+		StubDaemonReload: func() error {
+			return utils.NewNotImeplementedError("DaemonReload()")
+		},
+		StubEnableUnit: func(name string) error {
+			return utils.NewNotImeplementedError("EnableUnit()")
+		},
+	}
+}
+
+func (mss *MockSystemdService) DaemonReload() error {
+	return mss.StubDaemonReload()
+}
+
+func (mss *MockSystemdService) EnableUnit(name string) error {
+	return mss.StubEnableUnit(name)
+}
+
+type MockMdadmService struct {
+	StubGetArrays     func() ([]*model.RaidArray, error)
+	StubCreateArray   func(name string, level model.RaidLevel, chunkSize uint64, devices []string) error
+	StubAssembleArray func(name string, devices []string) error
+}
+
+func NewMockMdadmService() *MockMdadmService {
+	return &MockMdadmService{
+		StubGetArrays: func() ([]*model.RaidArray, error) {
+			return nil, utils.NewNotImeplementedError("GetArrays()")
+		},
+		StubCreateArray: func(name string, level model.RaidLevel, chunkSize uint64, devices []string) error {
+			return utils.NewNotImeplementedError("CreateArray()")
+		},
+		StubAssembleArray: func(name string, devices []string) error {
+			return utils.NewNotImeplementedError("AssembleArray()")
+		},
+	}
+}
+
+func (mms *MockMdadmService) GetArrays() ([]*model.RaidArray, error) {
+	return mms.StubGetArrays()
+}
+
+func (mms *MockMdadmService) CreateArray(name string, level model.RaidLevel, chunkSize uint64, devices []string) error {
+	return mms.StubCreateArray(name, level, chunkSize, devices)
+}
+
+func (mms *MockMdadmService) AssembleArray(name string, devices []string) error {
+	return mms.StubAssembleArray(name, devices)
+}
+
+type MockCryptsetupService struct {
+	StubGetEncryptedDevice func(name string) (*model.EncryptedDevice, error)
+	StubFormat             func(device string, keyFile string) error
+	StubOpen               func(device string, name string, keyFile string) error
+	StubCreateEphemeralKey func(keyFile string) error
+}
+
+func NewMockCryptsetupService() *MockCryptsetupService {
+	return &MockCryptsetupService{
+		StubGetEncryptedDevice: func(name string) (*model.EncryptedDevice, error) {
+			return nil, utils.NewNotImeplementedError("GetEncryptedDevice()")
+		},
+		StubFormat: func(device string, keyFile string) error {
+			return utils.NewNotImeplementedError("Format()")
+		},
+		StubOpen: func(device string, name string, keyFile string) error {
+			return utils.NewNotImeplementedError("Open()")
+		},
+		StubCreateEphemeralKey: func(keyFile string) error {
+			return utils.NewNotImeplementedError("CreateEphemeralKey()")
+		},
+	}
+}
+
+func (mcs *MockCryptsetupService) GetEncryptedDevice(name string) (*model.EncryptedDevice, error) {
+	return mcs.StubGetEncryptedDevice(name)
+}
+
+func (mcs *MockCryptsetupService) Format(device string, keyFile string) error {
+	return mcs.StubFormat(device, keyFile)
+}
+
+func (mcs *MockCryptsetupService) Open(device string, name string, keyFile string) error {
+	return mcs.StubOpen(device, name, keyFile)
+}
+
+func (mcs *MockCryptsetupService) CreateEphemeralKey(keyFile string) error {
+	return mcs.StubCreateEphemeralKey(keyFile)
+}
+
+type MockSwapService struct {
+	StubGetSwapArea func(name string) (*model.SwapArea, error)
+	StubActivate    func(name string, priority *int) error
+	StubDeactivate  func(name string) error
+	StubCreateFile  func(name string, size uint64) error
+}
+
+func NewMockSwapService() *MockSwapService {
+	return &MockSwapService{
+		StubGetSwapArea: func(name string) (*model.SwapArea, error) {
+			return nil, utils.NewNotImeplementedError("GetSwapArea()")
+		},
+		StubActivate: func(name string, priority *int) error {
+			return utils.NewNotImeplementedError("Activate()")
+		},
+		StubDeactivate: func(name string) error {
+			return utils.NewNotImeplementedError("Deactivate()")
+		},
+		StubCreateFile: func(name string, size uint64) error {
+			return utils.NewNotImeplementedError("CreateFile()")
+		},
+	}
+}
+
+func (mss *MockSwapService) GetSwapArea(name string) (*model.SwapArea, error) {
+	return mss.StubGetSwapArea(name)
+}
+
+func (mss *MockSwapService) Activate(name string, priority *int) error {
+	return mss.StubActivate(name, priority)
+}
+
+func (mss *MockSwapService) Deactivate(name string) error {
+	return mss.StubDeactivate(name)
+}
+
+func (mss *MockSwapService) CreateFile(name string, size uint64) error {
+	return mss.StubCreateFile(name, size)
+}
@@ -0,0 +1,1115 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+const (
+	// Identifiers (device ids and inode numbers) that are fabricated by the
+	// simulation start from this offset to avoid colliding with those of the host
+	SimulatedIdOffset = uint64(1) << 62
+	// The permissions of the root directory of a freshly formatted file system
+	SimulatedRootPermissions = model.FilePermissions(0755)
+)
+
+// Simulation is an in-memory model of the host, which is lazily seeded from the
+// live services. Plan mode mutates it instead of the host, so that each layer
+// observes the state that earlier actions would produce
+type Simulation struct {
+	deviceService     DeviceService
+	fileService       FileService
+	lvmService        LvmService
+	mdadmService      MdadmService
+	cryptsetupService CryptsetupService
+	swapService       SwapService
+	blockDevices      map[string]*model.BlockDevice
+	blockDeviceSizes  map[string]uint64
+	fileSystemSizes   map[string]uint64
+	// The file systems that were formatted or repaired during the simulation, and
+	// are therefore known to be free of errors
+	checked map[string]bool
+	// The properties of each file system that was formatted or tuned during the simulation
+	fileSystemProperties map[string]*model.FileSystemProperties
+	files                map[string]*model.File
+	contents             map[string][]byte
+	// The root directory of each file system that has been formatted or
+	// unmounted during the simulation (keyed by device)
+	roots map[string]*model.File
+	// The directories that have been hidden by a simulated mount (keyed by path)
+	covered map[string]*model.File
+	lvm     *simulatedLvm
+	arrays  []*model.RaidArray
+	// The active mappings of LUKS devices (keyed by name)
+	encryptedDevices map[string]*model.EncryptedDevice
+	// The devices that have been formatted as LUKS devices during the simulation
+	formatted map[string]bool
+	// The signatures of each device that was created or wiped during the simulation
+	signatures map[string][]*model.Signature
+	// The swap areas that were activated (or deactivated, if nil) during the simulation
+	swapAreas map[string]*model.SwapArea
+	id        uint64
+}
+
+type simulatedLvm struct {
+	devices         []*model.Device
+	physicalVolumes []*model.PhysicalVolume
+	volumeGroups    []*model.VolumeGroup
+	logicalVolumes  []*model.LogicalVolume
+}
+
+func NewSimulation(ds DeviceService, fs FileService, ls LvmService, ms MdadmService, cs CryptsetupService, ss SwapService) *Simulation {
+	return &Simulation{
+		deviceService:        ds,
+		fileService:          fs,
+		lvmService:           ls,
+		mdadmService:         ms,
+		cryptsetupService:    cs,
+		swapService:          ss,
+		encryptedDevices:     map[string]*model.EncryptedDevice{},
+		formatted:            map[string]bool{},
+		signatures:           map[string][]*model.Signature{},
+		swapAreas:            map[string]*model.SwapArea{},
+		blockDevices:         map[string]*model.BlockDevice{},
+		blockDeviceSizes:     map[string]uint64{},
+		fileSystemSizes:      map[string]uint64{},
+		fileSystemProperties: map[string]*model.FileSystemProperties{},
+		checked:              map[string]bool{},
+		files:                map[string]*model.File{},
+		contents:             map[string][]byte{},
+		roots:                map[string]*model.File{},
+		covered:              map[string]*model.File{},
+		id:                   SimulatedIdOffset,
+	}
+}
+
+func (s *Simulation) nextId() uint64 {
+	s.id++
+	return s.id
+}
+
+func (s *Simulation) nextUuid() string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012x", s.nextId()&0xffffffffffff)
+}
+
+func (s *Simulation) getBlockDevice(name string) (*model.BlockDevice, error) {
+	bd, found := s.blockDevices[name]
+	if found {
+		return bd, nil
+	}
+	bd, err := s.deviceService.GetBlockDevice(name)
+	if err != nil {
+		return nil, err
+	}
+	c := *bd
+	s.blockDevices[name] = &c
+	return &c, nil
+}
+
+func (s *Simulation) getBlockDeviceSize(name string) (uint64, error) {
+	size, found := s.blockDeviceSizes[name]
+	if found {
+		return size, nil
+	}
+	size, err := s.deviceService.GetSize(name)
+	if err != nil {
+		return 0, err
+	}
+	s.blockDeviceSizes[name] = size
+	return size, nil
+}
+
+func (s *Simulation) getFile(p string) (*model.File, error) {
+	f, found := s.files[p]
+	if found {
+		return f, nil
+	}
+	f, err := s.fileService.GetFile(p)
+	if err != nil {
+		return nil, err
+	}
+	c := *f
+	s.files[p] = &c
+	return &c, nil
+}
+
+// A file system can be referenced by either its device or its mount point
+func (s *Simulation) getDeviceName(name string) string {
+	for _, bd := range s.blockDevices {
+		if len(bd.MountPoint) > 0 && bd.MountPoint == name {
+			return bd.Name
+		}
+	}
+	return name
+}
+
+func (s *Simulation) getSwapArea(name string) (*model.SwapArea, error) {
+	sa, found := s.swapAreas[name]
+	if found {
+		return sa, nil
+	}
+	sa, err := s.swapService.GetSwapArea(name)
+	if err != nil {
+		return nil, err
+	}
+	s.swapAreas[name] = sa
+	return sa, nil
+}
+
+func (s *Simulation) getLvm() (*simulatedLvm, error) {
+	if s.lvm != nil {
+		return s.lvm, nil
+	}
+	ds, err := s.lvmService.GetDevices()
+	if err != nil {
+		return nil, err
+	}
+	pvs, err := s.lvmService.GetPhysicalVolumes()
+	if err != nil {
+		return nil, err
+	}
+	vgs, err := s.lvmService.GetVolumeGroups()
+	if err != nil {
+		return nil, err
+	}
+	lvs, err := s.lvmService.GetLogicalVolumes()
+	if err != nil {
+		return nil, err
+	}
+	s.lvm = &simulatedLvm{
+		devices:         ds,
+		physicalVolumes: pvs,
+		volumeGroups:    vgs,
+		logicalVolumes:  lvs,
+	}
+	return s.lvm, nil
+}
+
+// The size of a volume group is the sum of the sizes of its physical volumes
+func (s *Simulation) resizeVolumeGroup(lvm *simulatedLvm, name string) {
+	size := uint64(0)
+	for _, vg := range lvm.volumeGroups {
+		if vg.Name != name {
+			continue
+		}
+		for _, pv := range lvm.physicalVolumes {
+			if pv.Name == vg.PhysicalVolume {
+				size += pv.Size
+			}
+		}
+	}
+	for _, vg := range lvm.volumeGroups {
+		if vg.Name == name {
+			vg.Size = size
+		}
+	}
+}
+
+func (s *Simulation) getVolumeGroupSize(lvm *simulatedLvm, name string) (uint64, error) {
+	for _, vg := range lvm.volumeGroups {
+		if vg.Name == name {
+			return vg.Size, nil
+		}
+	}
+	return 0, fmt.Errorf("🔴 %s: Volume group does not exist", name)
+}
+
+// getLogicalVolumeSize resolves the size of a logical volume, which can be relative
+// to the size or the free space of its volume group, to a number of bytes
+func (s *Simulation) getLogicalVolumeSize(lvm *simulatedLvm, volumeGroup string, size model.LvmSize) (uint64, error) {
+	vgs, err := s.getVolumeGroupSize(lvm, volumeGroup)
+	if err != nil {
+		return 0, err
+	}
+	switch size.Unit {
+	case model.VolumeGroupPercent:
+		return vgs * size.Value / 100, nil
+	case model.FreePercent:
+		free := vgs
+		for _, lv := range lvm.logicalVolumes {
+			if lv.VolumeGroup == volumeGroup {
+				free -= lv.Size
+			}
+		}
+		return free * size.Value / 100, nil
+	default:
+		return size.Value, nil
+	}
+}
+
+func (s *Simulation) getLogicalVolume(lvm *simulatedLvm, name string, volumeGroup string) (*model.LogicalVolume, error) {
+	for _, lv := range lvm.logicalVolumes {
+		if lv.Name == name && lv.VolumeGroup == volumeGroup {
+			return lv, nil
+		}
+	}
+	return nil, fmt.Errorf("🔴 %s/%s: Logical volume does not exist", volumeGroup, name)
+}
+
+func (s *Simulation) getArrays() ([]*model.RaidArray, error) {
+	if s.arrays != nil {
+		return s.arrays, nil
+	}
+	arrays, err := s.mdadmService.GetArrays()
+	if err != nil {
+		return nil, err
+	}
+	s.arrays = arrays
+	return s.arrays, nil
+}
+
+func (s *Simulation) getEncryptedDevice(name string) (*model.EncryptedDevice, error) {
+	ed, found := s.encryptedDevices[name]
+	if found {
+		return ed, nil
+	}
+	ed, err := s.cryptsetupService.GetEncryptedDevice(name)
+	if err != nil {
+		return nil, err
+	}
+	c := *ed
+	s.encryptedDevices[name] = &c
+	return &c, nil
+}
+
+type SimulatedDeviceService struct {
+	simulation *Simulation
+}
+
+func NewSimulatedDeviceService(s *Simulation) *SimulatedDeviceService {
+	return &SimulatedDeviceService{
+		simulation: s,
+	}
+}
+
+func (sds *SimulatedDeviceService) GetSize(name string) (uint64, error) {
+	return sds.simulation.getBlockDeviceSize(name)
+}
+
+func (sds *SimulatedDeviceService) GetBlockDevices() ([]string, error) {
+	return sds.simulation.deviceService.GetBlockDevices()
+}
+
+func (sds *SimulatedDeviceService) GetBlockDevice(name string) (*model.BlockDevice, error) {
+	bd, err := sds.simulation.getBlockDevice(name)
+	if err != nil {
+		return nil, err
+	}
+	c := *bd
+	return &c, nil
+}
+
+func (sds *SimulatedDeviceService) Mount(source string, target string, fs model.FileSystem, options model.MountOptions) error {
+	s := sds.simulation
+	bd, err := s.getBlockDevice(source)
+	if err != nil {
+		return err
+	}
+	// A remount only modifies the mount options, which are not modelled
+	if slices.Contains(strings.Split(string(options), ","), "remount") {
+		return nil
+	}
+	d, err := s.getFile(target)
+	if err != nil {
+		return err
+	}
+	// The mount point now resolves to the root directory of the file system. If
+	// the root directory has not been observed during the simulation, we assume
+	// that it shares the ownership and permissions of the directory it hides
+	root, found := s.roots[source]
+	if !found {
+		root = &model.File{
+			Type:        model.Directory,
+			DeviceId:    s.nextId(),
+			InodeNo:     s.nextId(),
+			UserId:      d.UserId,
+			GroupId:     d.GroupId,
+			Permissions: d.Permissions,
+		}
+	}
+	f := *root
+	f.Path = d.Path
+	s.covered[target] = d
+	s.files[target] = &f
+	bd.MountPoint = d.Path
+	return nil
+}
+
+func (sds *SimulatedDeviceService) Umount(source string, target string) error {
+	s := sds.simulation
+	bd, err := s.getBlockDevice(source)
+	if err != nil {
+		return err
+	}
+	root, err := s.getFile(target)
+	if err != nil {
+		return err
+	}
+	s.roots[source] = root
+	// Restore the directory that was hidden by the mount. If the mount predates the
+	// simulation, we assume the directory belongs to the file system of its parent
+	d, found := s.covered[target]
+	if !found {
+		parent, err := s.getFile(path.Dir(target))
+		if err != nil {
+			return err
+		}
+		c := *root
+		c.DeviceId = parent.DeviceId
+		c.InodeNo = s.nextId()
+		d = &c
+	}
+	delete(s.covered, target)
+	s.files[target] = d
+	bd.MountPoint = ""
+	return nil
+}
+
+// A device that was created during the simulation does not have any signatures
+// until it is formatted. The signatures of any other device are deferred to the host
+func (sds *SimulatedDeviceService) GetSignatures(name string) ([]*model.Signature, error) {
+	s := sds.simulation
+	if signatures, found := s.signatures[name]; found {
+		return slices.Clone(signatures), nil
+	}
+	return s.deviceService.GetSignatures(name)
+}
+
+func (sds *SimulatedDeviceService) Wipe(name string) error {
+	s := sds.simulation
+	bd, err := s.getBlockDevice(name)
+	if err != nil {
+		return err
+	}
+	bd.FileSystem = model.Unformatted
+	bd.Label = ""
+	bd.UUID = ""
+	s.signatures[name] = []*model.Signature{}
+	return nil
+}
+
+type SimulatedFileService struct {
+	simulation *Simulation
+}
+
+func NewSimulatedFileService(s *Simulation) *SimulatedFileService {
+	return &SimulatedFileService{
+		simulation: s,
+	}
+}
+
+func (sfs *SimulatedFileService) GetFile(file string) (*model.File, error) {
+	f, err := sfs.simulation.getFile(file)
+	if err != nil {
+		return nil, err
+	}
+	c := *f
+	return &c, nil
+}
+
+func (sfs *SimulatedFileService) CreateDirectory(p string) error {
+	s := sfs.simulation
+	f, err := s.getFile(p)
+	if err == nil {
+		if f.Type != model.Directory {
+			return fmt.Errorf("🔴 %s: File is not a directory", p)
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	// Recursively create any parent directories that do not exist
+	parent := path.Dir(p)
+	if err := sfs.CreateDirectory(parent); err != nil {
+		return err
+	}
+	pf, err := s.getFile(parent)
+	if err != nil {
+		return err
+	}
+	s.files[p] = &model.File{
+		Path:        p,
+		Type:        model.Directory,
+		DeviceId:    pf.DeviceId,
+		InodeNo:     s.nextId(),
+		UserId:      model.UserId(os.Getuid()),
+		GroupId:     model.GroupId(os.Getgid()),
+		Permissions: model.FilePermissions(DefaultDirectoryPermissions),
+	}
+	return nil
+}
+
+func (sfs *SimulatedFileService) ChangeOwner(file string, uid model.UserId, gid model.GroupId) error {
+	f, err := sfs.simulation.getFile(file)
+	if err != nil {
+		return err
+	}
+	f.UserId = uid
+	f.GroupId = gid
+	return nil
+}
+
+func (sfs *SimulatedFileService) ChangePermissions(file string, perms model.FilePermissions) error {
+	f, err := sfs.simulation.getFile(file)
+	if err != nil {
+		return err
+	}
+	f.Permissions = perms
+	return nil
+}
+
+func (sfs *SimulatedFileService) ReadFile(file string) ([]byte, error) {
+	data, found := sfs.simulation.contents[file]
+	if found {
+		return data, nil
+	}
+	return sfs.simulation.fileService.ReadFile(file)
+}
+
+func (sfs *SimulatedFileService) WriteFile(file string, data []byte, perms model.FilePermissions) error {
+	s := sfs.simulation
+	s.contents[file] = data
+	f, err := s.getFile(file)
+	if err == nil {
+		f.Permissions = perms
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+	s.files[file] = &model.File{
+		Path:        file,
+		Type:        model.RegularFile,
+		InodeNo:     s.nextId(),
+		UserId:      model.UserId(os.Getuid()),
+		GroupId:     model.GroupId(os.Getgid()),
+		Permissions: perms,
+	}
+	return nil
+}
+
+type SimulatedFileSystemServiceFactory struct {
+	simulation               *Simulation
+	fileSystemServiceFactory FileSystemServiceFactory
+}
+
+func NewSimulatedFileSystemServiceFactory(s *Simulation, fssf FileSystemServiceFactory) *SimulatedFileSystemServiceFactory {
+	return &SimulatedFileSystemServiceFactory{
+		simulation:               s,
+		fileSystemServiceFactory: fssf,
+	}
+}
+
+func (sfsf *SimulatedFileSystemServiceFactory) Select(fs model.FileSystem) (FileSystemService, error) {
+	fss, err := sfsf.fileSystemServiceFactory.Select(fs)
+	if err != nil {
+		return nil, err
+	}
+	return NewSimulatedFileSystemService(sfsf.simulation, fss), nil
+}
+
+// SimulatedFileSystemService delegates any query that describes the behaviour of a
+// file system to the FileSystemService it wraps, while simulating any modification
+type SimulatedFileSystemService struct {
+	simulation        *Simulation
+	fileSystemService FileSystemService
+}
+
+func NewSimulatedFileSystemService(s *Simulation, fss FileSystemService) *SimulatedFileSystemService {
+	return &SimulatedFileSystemService{
+		simulation:        s,
+		fileSystemService: fss,
+	}
+}
+
+func (sfs *SimulatedFileSystemService) GetSize(name string) (uint64, error) {
+	size, found := sfs.simulation.fileSystemSizes[name]
+	if found {
+		return size, nil
+	}
+	return sfs.fileSystemService.GetSize(name)
+}
+
+func (sfs *SimulatedFileSystemService) GetFileSystem() model.FileSystem {
+	return sfs.fileSystemService.GetFileSystem()
+}
+
+func (sfs *SimulatedFileSystemService) Format(name string, options *model.FormatOptions) error {
+	s := sfs.simulation
+	bd, err := s.getBlockDevice(name)
+	if err != nil {
+		return err
+	}
+	size, err := s.getBlockDeviceSize(name)
+	if err != nil {
+		return err
+	}
+	bd.FileSystem = sfs.GetFileSystem()
+	bd.Label = ""
+	if options != nil && sfs.DoesFormatSupportLabel() {
+		bd.Label = options.Label
+	}
+	// A freshly formatted file system is assigned a new UUID
+	bd.UUID = s.nextUuid()
+	s.fileSystemSizes[name] = size
+	// The properties that mkfs assigns to a new file system are not known
+	s.fileSystemProperties[name] = &model.FileSystemProperties{}
+	s.checked[name] = true
+	s.roots[name] = &model.File{
+		Type:        model.Directory,
+		DeviceId:    s.nextId(),
+		InodeNo:     s.nextId(),
+		UserId:      0,
+		GroupId:     0,
+		Permissions: SimulatedRootPermissions,
+	}
+	return nil
+}
+
+func (sfs *SimulatedFileSystemService) Label(name string, label string) error {
+	bd, err := sfs.simulation.getBlockDevice(name)
+	if err != nil {
+		return err
+	}
+	bd.Label = label
+	return nil
+}
+
+func (sfs *SimulatedFileSystemService) SetUuid(name string, uuid string) error {
+	s := sfs.simulation
+	bd, err := s.getBlockDevice(name)
+	if err != nil {
+		return err
+	}
+	if uuid == model.RandomUuid {
+		uuid = s.nextUuid()
+	}
+	bd.UUID = uuid
+	return nil
+}
+
+func (sfs *SimulatedFileSystemService) Resize(name string) error {
+	s := sfs.simulation
+	device := s.getDeviceName(name)
+	size, err := s.getBlockDeviceSize(device)
+	if err != nil {
+		return err
+	}
+	s.fileSystemSizes[device] = size
+	return nil
+}
+
+// A repair is assumed to correct every error of a file system
+func (sfs *SimulatedFileSystemService) Check(name string, repair bool) (model.FsckResult, error) {
+	s := sfs.simulation
+	if repair {
+		s.checked[name] = true
+		return model.FsckCorrected, nil
+	}
+	if s.checked[name] {
+		return model.FsckClean, nil
+	}
+	return sfs.fileSystemService.Check(name, false)
+}
+
+func (sfs *SimulatedFileSystemService) GetMaximumLabelLength() int {
+	return sfs.fileSystemService.GetMaximumLabelLength()
+}
+
+func (sfs *SimulatedFileSystemService) DoesResizeRequireMount() bool {
+	return sfs.fileSystemService.DoesResizeRequireMount()
+}
+
+func (sfs *SimulatedFileSystemService) DoesResizeRequireUnmount() bool {
+	return sfs.fileSystemService.DoesResizeRequireUnmount()
+}
+
+func (sfs *SimulatedFileSystemService) DoesLabelRequireUnmount() bool {
+	return sfs.fileSystemService.DoesLabelRequireUnmount()
+}
+
+func (sfs *SimulatedFileSystemService) DoesFormatSupportLabel() bool {
+	return sfs.fileSystemService.DoesFormatSupportLabel()
+}
+
+func (sfs *SimulatedFileSystemService) ValidateFormatOptions(options *model.FormatOptions) error {
+	return sfs.fileSystemService.ValidateFormatOptions(options)
+}
+
+func (sfs *SimulatedFileSystemService) GetProperties(name string) (*model.FileSystemProperties, error) {
+	fp, found := sfs.simulation.fileSystemProperties[name]
+	if found {
+		c := *fp
+		return &c, nil
+	}
+	return sfs.fileSystemService.GetProperties(name)
+}
+
+// The tuned properties are merged with the current properties of the file system
+func (sfs *SimulatedFileSystemService) Tune(name string, properties *model.FileSystemProperties) error {
+	fp, err := sfs.GetProperties(name)
+	if err != nil {
+		return err
+	}
+	if properties.ReservedBlocksPercent != nil {
+		fp.ReservedBlocksPercent = properties.ReservedBlocksPercent
+	}
+	if properties.MaxMountCount != nil {
+		fp.MaxMountCount = properties.MaxMountCount
+	}
+	if len(properties.Errors) > 0 {
+		fp.Errors = properties.Errors
+	}
+	for _, f := range properties.Features {
+		if !slices.Contains(fp.Features, f) {
+			fp.Features = append(slices.Clone(fp.Features), f)
+		}
+	}
+	sfs.simulation.fileSystemProperties[name] = fp
+	return nil
+}
+
+func (sfs *SimulatedFileSystemService) ValidateProperties(properties *model.FileSystemProperties) error {
+	return sfs.fileSystemService.ValidateProperties(properties)
+}
+
+func (sfs *SimulatedFileSystemService) DoesTuneRequireUnmount() bool {
+	return sfs.fileSystemService.DoesTuneRequireUnmount()
+}
+
+type SimulatedLvmService struct {
+	simulation *Simulation
+}
+
+func NewSimulatedLvmService(s *Simulation) *SimulatedLvmService {
+	return &SimulatedLvmService{
+		simulation: s,
+	}
+}
+
+func (sls *SimulatedLvmService) GetDevices() ([]*model.Device, error) {
+	lvm, err := sls.simulation.getLvm()
+	if err != nil {
+		return nil, err
+	}
+	ds := make([]*model.Device, len(lvm.devices))
+	for i, d := range lvm.devices {
+		c := *d
+		ds[i] = &c
+	}
+	return ds, nil
+}
+
+func (sls *SimulatedLvmService) GetPhysicalVolumes() ([]*model.PhysicalVolume, error) {
+	lvm, err := sls.simulation.getLvm()
+	if err != nil {
+		return nil, err
+	}
+	pvs := make([]*model.PhysicalVolume, len(lvm.physicalVolumes))
+	for i, pv := range lvm.physicalVolumes {
+		c := *pv
+		pvs[i] = &c
+	}
+	return pvs, nil
+}
+
+func (sls *SimulatedLvmService) GetVolumeGroups() ([]*model.VolumeGroup, error) {
+	lvm, err := sls.simulation.getLvm()
+	if err != nil {
+		return nil, err
+	}
+	vgs := make([]*model.VolumeGroup, len(lvm.volumeGroups))
+	for i, vg := range lvm.volumeGroups {
+		c := *vg
+		vgs[i] = &c
+	}
+	return vgs, nil
+}
+
+func (sls *SimulatedLvmService) GetLogicalVolumes() ([]*model.LogicalVolume, error) {
+	lvm, err := sls.simulation.getLvm()
+	if err != nil {
+		return nil, err
+	}
+	lvs := make([]*model.LogicalVolume, len(lvm.logicalVolumes))
+	for i, lv := range lvm.logicalVolumes {
+		c := *lv
+		lvs[i] = &c
+	}
+	return lvs, nil
+}
+
+func (sls *SimulatedLvmService) CreatePhysicalVolume(name string) error {
+	s := sls.simulation
+	lvm, err := s.getLvm()
+	if err != nil {
+		return err
+	}
+	bd, err := s.getBlockDevice(name)
+	if err != nil {
+		return err
+	}
+	size, err := s.getBlockDeviceSize(name)
+	if err != nil {
+		return err
+	}
+	bd.FileSystem = model.Lvm
+	lvm.devices = append(lvm.devices, &model.Device{Name: name, Size: size})
+	lvm.physicalVolumes = append(lvm.physicalVolumes, &model.PhysicalVolume{Name: name, Size: size})
+	return nil
+}
+
+func (sls *SimulatedLvmService) CreateVolumeGroup(name string, physicalVolume string) error {
+	s := sls.simulation
+	lvm, err := s.getLvm()
+	if err != nil {
+		return err
+	}
+	lvm.volumeGroups = append(lvm.volumeGroups, &model.VolumeGroup{
+		Name:           name,
+		PhysicalVolume: physicalVolume,
+		State:          model.VolumeGroupInactive,
+	})
+	s.resizeVolumeGroup(lvm, name)
+	return nil
+}
+
+func (sls *SimulatedLvmService) ExtendVolumeGroup(name string, physicalVolume string) error {
+	s := sls.simulation
+	lvm, err := s.getLvm()
+	if err != nil {
+		return err
+	}
+	if _, err := s.getVolumeGroupSize(lvm, name); err != nil {
+		return err
+	}
+	lvm.volumeGroups = append(lvm.volumeGroups, &model.VolumeGroup{
+		Name:           name,
+		PhysicalVolume: physicalVolume,
+		State:          model.VolumeGroupInactive,
+	})
+	s.resizeVolumeGroup(lvm, name)
+	return nil
+}
+
+func (sls *SimulatedLvmService) CreateLogicalVolume(name string, volumeGroup string, size model.LvmSize) error {
+	s := sls.simulation
+	lvm, err := s.getLvm()
+	if err != nil {
+		return err
+	}
+	bytes, err := s.getLogicalVolumeSize(lvm, volumeGroup, size)
+	if err != nil {
+		return err
+	}
+	lvm.logicalVolumes = append(lvm.logicalVolumes, &model.LogicalVolume{
+		Name:        name,
+		VolumeGroup: volumeGroup,
+		State:       model.LogicalVolumeActive,
+		Size:        bytes,
+	})
+	// A newly created logical volume is exposed to the host as an unformatted block device
+	ldn := fmt.Sprintf("/dev/%s/%s", volumeGroup, name)
+	s.blockDevices[ldn] = &model.BlockDevice{Name: ldn}
+	s.blockDeviceSizes[ldn] = bytes
+	s.signatures[ldn] = []*model.Signature{}
+	return nil
+}
+
+func (sls *SimulatedLvmService) ActivateLogicalVolume(name string, volumeGroup string) error {
+	s := sls.simulation
+	lvm, err := s.getLvm()
+	if err != nil {
+		return err
+	}
+	lv, err := s.getLogicalVolume(lvm, name, volumeGroup)
+	if err != nil {
+		return err
+	}
+	lv.State = model.LogicalVolumeActive
+	return nil
+}
+
+func (sls *SimulatedLvmService) ResizePhysicalVolume(name string) error {
+	s := sls.simulation
+	lvm, err := s.getLvm()
+	if err != nil {
+		return err
+	}
+	size, err := s.getBlockDeviceSize(name)
+	if err != nil {
+		return err
+	}
+	for _, pv := range lvm.physicalVolumes {
+		if pv.Name == name {
+			pv.Size = size
+		}
+	}
+	for _, d := range lvm.devices {
+		if d.Name == name {
+			d.Size = size
+		}
+	}
+	for _, vg := range lvm.volumeGroups {
+		if vg.PhysicalVolume == name {
+			s.resizeVolumeGroup(lvm, vg.Name)
+		}
+	}
+	return nil
+}
+
+func (sls *SimulatedLvmService) ResizeLogicalVolume(name string, volumeGroup string, size model.LvmSize) error {
+	s := sls.simulation
+	lvm, err := s.getLvm()
+	if err != nil {
+		return err
+	}
+	lv, err := s.getLogicalVolume(lvm, name, volumeGroup)
+	if err != nil {
+		return err
+	}
+	bytes, err := s.getLogicalVolumeSize(lvm, volumeGroup, size)
+	if err != nil {
+		return err
+	}
+	// An extension by a percentage of free space is relative to the current size
+	if size.Unit == model.FreePercent {
+		bytes += lv.Size
+	}
+	lv.Size = bytes
+	s.blockDeviceSizes[fmt.Sprintf("/dev/%s/%s", volumeGroup, name)] = lv.Size
+	return nil
+}
+
+// SimulatedSystemdService discards any request made of the service manager. The
+// units that it would act upon are already written to the simulated host by the
+// SimulatedFileService
+type SimulatedSystemdService struct {
+	simulation *Simulation
+}
+
+func NewSimulatedSystemdService(s *Simulation) *SimulatedSystemdService {
+	return &SimulatedSystemdService{
+		simulation: s,
+	}
+}
+
+func (sss *SimulatedSystemdService) DaemonReload() error {
+	return nil
+}
+
+func (sss *SimulatedSystemdService) EnableUnit(name string) error {
+	return nil
+}
+
+type SimulatedMdadmService struct {
+	simulation *Simulation
+}
+
+func NewSimulatedMdadmService(s *Simulation) *SimulatedMdadmService {
+	return &SimulatedMdadmService{
+		simulation: s,
+	}
+}
+
+func (sms *SimulatedMdadmService) GetArrays() ([]*model.RaidArray, error) {
+	arrays, err := sms.simulation.getArrays()
+	if err != nil {
+		return nil, err
+	}
+	ras := make([]*model.RaidArray, len(arrays))
+	for i, ra := range arrays {
+		c := *ra
+		c.Devices = slices.Clone(ra.Devices)
+		ras[i] = &c
+	}
+	return ras, nil
+}
+
+// The capacity of a newly created array is the sum of the sizes of its members for
+// RAID0 (striping) and the size of the smallest member for RAID1 (mirroring). The
+// space that is reserved for the superblock of each member is not modelled
+func (sms *SimulatedMdadmService) CreateArray(name string, level model.RaidLevel, chunkSize uint64, devices []string) error {
+	s := sms.simulation
+	size := uint64(0)
+	for i, d := range devices {
+		bd, err := s.getBlockDevice(d)
+		if err != nil {
+			return err
+		}
+		ds, err := s.getBlockDeviceSize(d)
+		if err != nil {
+			return err
+		}
+		bd.FileSystem = model.RaidMember
+		switch {
+		case level == model.Raid0:
+			size += ds
+		case i == 0 || ds < size:
+			size = ds
+		}
+	}
+	if err := sms.addArray(&model.RaidArray{Name: name, Level: level, Devices: slices.Clone(devices)}); err != nil {
+		return err
+	}
+	// A newly created array is exposed to the host as an unformatted block device
+	rdn := path.Join(model.RaidDirectory, name)
+	s.blockDevices[rdn] = &model.BlockDevice{Name: rdn}
+	s.blockDeviceSizes[rdn] = size
+	s.signatures[rdn] = []*model.Signature{}
+	return nil
+}
+
+// An assembled array exposes the contents that were written to it before it was
+// stopped. These contents can not be predicted, so the block device of the array
+// is not simulated. Any subsequent query of it is deferred to the host
+func (sms *SimulatedMdadmService) AssembleArray(name string, devices []string) error {
+	return sms.addArray(&model.RaidArray{Name: name, Devices: slices.Clone(devices)})
+}
+
+func (sms *SimulatedMdadmService) addArray(ra *model.RaidArray) error {
+	arrays, err := sms.simulation.getArrays()
+	if err != nil {
+		return err
+	}
+	sms.simulation.arrays = append(arrays, ra)
+	return nil
+}
+
+type SimulatedCryptsetupService struct {
+	simulation *Simulation
+}
+
+func NewSimulatedCryptsetupService(s *Simulation) *SimulatedCryptsetupService {
+	return &SimulatedCryptsetupService{
+		simulation: s,
+	}
+}
+
+func (scs *SimulatedCryptsetupService) GetEncryptedDevice(name string) (*model.EncryptedDevice, error) {
+	ed, err := scs.simulation.getEncryptedDevice(name)
+	if err != nil {
+		return nil, err
+	}
+	c := *ed
+	return &c, nil
+}
+
+func (scs *SimulatedCryptsetupService) Format(device string, keyFile string) error {
+	s := scs.simulation
+	bd, err := s.getBlockDevice(device)
+	if err != nil {
+		return err
+	}
+	bd.FileSystem = model.Luks
+	bd.Label = ""
+	bd.UUID = s.nextUuid()
+	s.formatted[device] = true
+	return nil
+}
+
+// A LUKS device that was formatted during the simulation is opened as an unformatted
+// block device, with the capacity of the device it is backed by. The space that is
+// reserved for the LUKS header is not modelled. The contents of a LUKS device that
+// predates the simulation can not be predicted, so any subsequent query of its
+// block device is deferred to the host
+func (scs *SimulatedCryptsetupService) Open(device string, name string, keyFile string) error {
+	s := scs.simulation
+	s.encryptedDevices[name] = &model.EncryptedDevice{Name: name, Device: device}
+	if !s.formatted[device] {
+		return nil
+	}
+	size, err := s.getBlockDeviceSize(device)
+	if err != nil {
+		return err
+	}
+	mdn := path.Join(model.MapperDirectory, name)
+	s.blockDevices[mdn] = &model.BlockDevice{Name: mdn}
+	s.blockDeviceSizes[mdn] = size
+	s.signatures[mdn] = []*model.Signature{}
+	return nil
+}
+
+func (scs *SimulatedCryptsetupService) CreateEphemeralKey(keyFile string) error {
+	s := scs.simulation
+	s.files[keyFile] = &model.File{
+		Path:        keyFile,
+		Type:        model.RegularFile,
+		InodeNo:     s.nextId(),
+		UserId:      model.UserId(os.Getuid()),
+		GroupId:     model.GroupId(os.Getgid()),
+		Permissions: model.FilePermissions(0400),
+	}
+	return nil
+}
+
+type SimulatedSwapService struct {
+	simulation *Simulation
+}
+
+func NewSimulatedSwapService(s *Simulation) *SimulatedSwapService {
+	return &SimulatedSwapService{
+		simulation: s,
+	}
+}
+
+func (sss *SimulatedSwapService) GetSwapArea(name string) (*model.SwapArea, error) {
+	sa, err := sss.simulation.getSwapArea(name)
+	if err != nil || sa == nil {
+		return nil, err
+	}
+	c := *sa
+	return &c, nil
+}
+
+// The priority that the kernel assigns to a swap area without a priority is not
+// known, and is left as 0
+func (sss *SimulatedSwapService) Activate(name string, priority *int) error {
+	s := sss.simulation
+	sa := &model.SwapArea{Name: name, Size: s.fileSystemSizes[name]}
+	if priority != nil {
+		sa.Priority = *priority
+	}
+	s.swapAreas[name] = sa
+	if bd, found := s.blockDevices[name]; found {
+		bd.MountPoint = model.SwapMountPoint
+	}
+	return nil
+}
+
+func (sss *SimulatedSwapService) Deactivate(name string) error {
+	s := sss.simulation
+	s.swapAreas[name] = nil
+	if bd, found := s.blockDevices[name]; found {
+		bd.MountPoint = ""
+	}
+	return nil
+}
+
+func (sss *SimulatedSwapService) CreateFile(name string, size uint64) error {
+	s := sss.simulation
+	if _, err := s.getFile(name); err == nil {
+		return fmt.Errorf("🔴 %s: File already exists", name)
+	}
+	parent, err := s.getFile(path.Dir(name))
+	if err != nil {
+		return err
+	}
+	s.files[name] = &model.File{
+		Path:        name,
+		Type:        model.RegularFile,
+		DeviceId:    parent.DeviceId,
+		InodeNo:     s.nextId(),
+		UserId:      model.UserId(os.Getuid()),
+		GroupId:     model.GroupId(os.Getgid()),
+		Permissions: model.SwapFilePermissions,
+	}
+	s.fileSystemSizes[name] = size
+	return nil
+}
@@ -0,0 +1,356 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestSimulatedFormatAndMount(t *testing.T) {
+	mds := NewMockDeviceService()
+	mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		return &model.BlockDevice{Name: name, FileSystem: model.Unformatted}, nil
+	}
+	mds.StubGetSize = func(name string) (uint64, error) {
+		return 1024, nil
+	}
+	mfs := NewMockFileService()
+	mfs.StubGetFile = func(file string) (*model.File, error) {
+		switch file {
+		case "/mnt":
+			return &model.File{Path: file, Type: model.Directory, DeviceId: 1, InodeNo: 2, Permissions: 0755}, nil
+		default:
+			return nil, os.ErrNotExist
+		}
+	}
+	mfss := NewMockFileSystemService()
+	mfss.StubGetFileSystem = func() model.FileSystem {
+		return model.Ext4
+	}
+
+	s := NewSimulation(mds, mfs, NewMockLvmService(), NewMockMdadmService(), NewMockCryptsetupService(), NewMockSwapService())
+	sds := NewSimulatedDeviceService(s)
+	sfs := NewSimulatedFileService(s)
+	sfss := NewSimulatedFileSystemService(s, mfss)
+
+	err := sfss.Format("/dev/xvdf", nil)
+	utils.CheckError("sfss.Format()", t, nil, err)
+	err = sfss.Label("/dev/xvdf", "external-vol")
+	utils.CheckError("sfss.Label()", t, nil, err)
+	err = sfs.CreateDirectory("/mnt/app")
+	utils.CheckError("sfs.CreateDirectory()", t, nil, err)
+
+	d, err := sfs.GetFile("/mnt/app")
+	utils.CheckError("sfs.GetFile()", t, nil, err)
+	utils.CheckOutput("d.DeviceId", t, uint64(1), d.DeviceId)
+
+	err = sds.Mount("/dev/xvdf", "/mnt/app", model.Ext4, "defaults")
+	utils.CheckError("sds.Mount()", t, nil, err)
+
+	bd, err := sds.GetBlockDevice("/dev/xvdf")
+	utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+	utils.CheckOutput("sds.GetBlockDevice()", t, &model.BlockDevice{
+		Name:       "/dev/xvdf",
+		Label:      "external-vol",
+		FileSystem: model.Ext4,
+		MountPoint: "/mnt/app",
+		UUID:       "00000000-0000-4000-8000-000000000001",
+	}, bd)
+
+	// A freshly formatted file system has a root directory owned by root
+	r, err := sfs.GetFile("/mnt/app")
+	utils.CheckError("sfs.GetFile()", t, nil, err)
+	utils.CheckOutput("r.UserId", t, model.UserId(0), r.UserId)
+	utils.CheckOutput("r.Permissions", t, SimulatedRootPermissions, r.Permissions)
+
+	// Modifications to the mounted root directory are retained after an unmount
+	err = sfs.ChangePermissions("/mnt/app", 0700)
+	utils.CheckError("sfs.ChangePermissions()", t, nil, err)
+	err = sds.Umount("/dev/xvdf", "/mnt/app")
+	utils.CheckError("sds.Umount()", t, nil, err)
+
+	d, err = sfs.GetFile("/mnt/app")
+	utils.CheckError("sfs.GetFile()", t, nil, err)
+	utils.CheckOutput("d.DeviceId", t, uint64(1), d.DeviceId)
+
+	err = sds.Mount("/dev/xvdf", "/mnt/app", model.Ext4, "defaults")
+	utils.CheckError("sds.Mount()", t, nil, err)
+	r, err = sfs.GetFile("/mnt/app")
+	utils.CheckError("sfs.GetFile()", t, nil, err)
+	utils.CheckOutput("r.Permissions", t, model.FilePermissions(0700), r.Permissions)
+}
+
+func TestSimulatedLvm(t *testing.T) {
+	mds := NewMockDeviceService()
+	mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		return &model.BlockDevice{Name: name, FileSystem: model.Unformatted}, nil
+	}
+	mds.StubGetSize = func(name string) (uint64, error) {
+		return 1000, nil
+	}
+	mls := NewMockLvmService()
+	mls.StubGetDevices = func() ([]*model.Device, error) {
+		return []*model.Device{}, nil
+	}
+	mls.StubGetPhysicalVolumes = func() ([]*model.PhysicalVolume, error) {
+		return []*model.PhysicalVolume{}, nil
+	}
+	mls.StubGetVolumeGroups = func() ([]*model.VolumeGroup, error) {
+		return []*model.VolumeGroup{}, nil
+	}
+	mls.StubGetLogicalVolumes = func() ([]*model.LogicalVolume, error) {
+		return []*model.LogicalVolume{}, nil
+	}
+
+	s := NewSimulation(mds, NewMockFileService(), mls, NewMockMdadmService(), NewMockCryptsetupService(), NewMockSwapService())
+	sds := NewSimulatedDeviceService(s)
+	sls := NewSimulatedLvmService(s)
+
+	err := sls.CreatePhysicalVolume("/dev/xvdf")
+	utils.CheckError("sls.CreatePhysicalVolume()", t, nil, err)
+	err = sls.CreateVolumeGroup("ifmx-etc", "/dev/xvdf")
+	utils.CheckError("sls.CreateVolumeGroup()", t, nil, err)
+	err = sls.CreateLogicalVolume("ifmx-etc", "ifmx-etc", model.LvmSize{Value: 50, Unit: model.VolumeGroupPercent})
+	utils.CheckError("sls.CreateLogicalVolume()", t, nil, err)
+
+	bd, err := sds.GetBlockDevice("/dev/xvdf")
+	utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+	utils.CheckOutput("bd.FileSystem", t, model.Lvm, bd.FileSystem)
+
+	lvs, err := sls.GetLogicalVolumes()
+	utils.CheckError("sls.GetLogicalVolumes()", t, nil, err)
+	utils.CheckOutput("sls.GetLogicalVolumes()", t, []*model.LogicalVolume{
+		{Name: "ifmx-etc", VolumeGroup: "ifmx-etc", State: model.LogicalVolumeActive, Size: 500},
+	}, lvs)
+
+	size, err := sds.GetSize("/dev/ifmx-etc/ifmx-etc")
+	utils.CheckError("sds.GetSize()", t, nil, err)
+	utils.CheckOutput("sds.GetSize()", t, uint64(500), size)
+
+	err = sls.ResizeLogicalVolume("ifmx-etc", "ifmx-etc", model.LvmSize{Value: 100, Unit: model.VolumeGroupPercent})
+	utils.CheckError("sls.ResizeLogicalVolume()", t, nil, err)
+	size, err = sds.GetSize("/dev/ifmx-etc/ifmx-etc")
+	utils.CheckError("sds.GetSize()", t, nil, err)
+	utils.CheckOutput("sds.GetSize()", t, uint64(1000), size)
+}
+
+func TestSimulatedMdadm(t *testing.T) {
+	sizes := map[string]uint64{"/dev/nvme1n1": 1000, "/dev/nvme2n1": 800}
+	subtests := []struct {
+		Name         string
+		Level        model.RaidLevel
+		ExpectedSize uint64
+	}{
+		{
+			Name:         "RAID0",
+			Level:        model.Raid0,
+			ExpectedSize: 1800,
+		},
+		{
+			Name:         "RAID1",
+			Level:        model.Raid1,
+			ExpectedSize: 800,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mds := NewMockDeviceService()
+			mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+				return &model.BlockDevice{Name: name, FileSystem: model.Unformatted}, nil
+			}
+			mds.StubGetSize = func(name string) (uint64, error) {
+				return sizes[name], nil
+			}
+			mms := NewMockMdadmService()
+			mms.StubGetArrays = func() ([]*model.RaidArray, error) {
+				return []*model.RaidArray{}, nil
+			}
+
+			s := NewSimulation(mds, NewMockFileService(), NewMockLvmService(), mms, NewMockCryptsetupService(), NewMockSwapService())
+			sds := NewSimulatedDeviceService(s)
+			sms := NewSimulatedMdadmService(s)
+
+			err := sms.CreateArray("scratch", subtest.Level, 0, []string{"/dev/nvme1n1", "/dev/nvme2n1"})
+			utils.CheckError("sms.CreateArray()", t, nil, err)
+
+			arrays, err := sms.GetArrays()
+			utils.CheckError("sms.GetArrays()", t, nil, err)
+			utils.CheckOutput("sms.GetArrays()", t, []*model.RaidArray{
+				{Name: "scratch", Level: subtest.Level, Devices: []string{"/dev/nvme1n1", "/dev/nvme2n1"}},
+			}, arrays)
+
+			bd, err := sds.GetBlockDevice("/dev/nvme1n1")
+			utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+			utils.CheckOutput("bd.FileSystem", t, model.RaidMember, bd.FileSystem)
+
+			bd, err = sds.GetBlockDevice("/dev/md/scratch")
+			utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+			utils.CheckOutput("bd.FileSystem", t, model.Unformatted, bd.FileSystem)
+
+			size, err := sds.GetSize("/dev/md/scratch")
+			utils.CheckError("sds.GetSize()", t, nil, err)
+			utils.CheckOutput("sds.GetSize()", t, subtest.ExpectedSize, size)
+		})
+	}
+}
+
+func TestSimulatedCryptsetup(t *testing.T) {
+	mds := NewMockDeviceService()
+	mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		return &model.BlockDevice{Name: name, FileSystem: model.Unformatted}, nil
+	}
+	mds.StubGetSize = func(name string) (uint64, error) {
+		return 1000, nil
+	}
+	mcs := NewMockCryptsetupService()
+	mcs.StubGetEncryptedDevice = func(name string) (*model.EncryptedDevice, error) {
+		return nil, os.ErrNotExist
+	}
+
+	s := NewSimulation(mds, NewMockFileService(), NewMockLvmService(), NewMockMdadmService(), mcs, NewMockSwapService())
+	sds := NewSimulatedDeviceService(s)
+	sfs := NewSimulatedFileService(s)
+	scs := NewSimulatedCryptsetupService(s)
+
+	_, err := scs.GetEncryptedDevice("scratch")
+	utils.CheckError("scs.GetEncryptedDevice()", t, os.ErrNotExist, err)
+
+	err = scs.CreateEphemeralKey("/run/ebs-bootstrap/keys/scratch.key")
+	utils.CheckError("scs.CreateEphemeralKey()", t, nil, err)
+	f, err := sfs.GetFile("/run/ebs-bootstrap/keys/scratch.key")
+	utils.CheckError("sfs.GetFile()", t, nil, err)
+	utils.CheckOutput("f.Permissions", t, model.FilePermissions(0400), f.Permissions)
+
+	err = scs.Format("/dev/nvme1n1", "/run/ebs-bootstrap/keys/scratch.key")
+	utils.CheckError("scs.Format()", t, nil, err)
+	bd, err := sds.GetBlockDevice("/dev/nvme1n1")
+	utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+	utils.CheckOutput("bd.FileSystem", t, model.Luks, bd.FileSystem)
+
+	err = scs.Open("/dev/nvme1n1", "scratch", "/run/ebs-bootstrap/keys/scratch.key")
+	utils.CheckError("scs.Open()", t, nil, err)
+	ed, err := scs.GetEncryptedDevice("scratch")
+	utils.CheckError("scs.GetEncryptedDevice()", t, nil, err)
+	utils.CheckOutput("scs.GetEncryptedDevice()", t, &model.EncryptedDevice{Name: "scratch", Device: "/dev/nvme1n1"}, ed)
+
+	bd, err = sds.GetBlockDevice("/dev/mapper/scratch")
+	utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+	utils.CheckOutput("bd.FileSystem", t, model.Unformatted, bd.FileSystem)
+	size, err := sds.GetSize("/dev/mapper/scratch")
+	utils.CheckError("sds.GetSize()", t, nil, err)
+	utils.CheckOutput("sds.GetSize()", t, uint64(1000), size)
+}
+
+func TestSimulatedWipe(t *testing.T) {
+	mds := NewMockDeviceService()
+	mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		return &model.BlockDevice{Name: name, FileSystem: model.Unformatted}, nil
+	}
+	mds.StubGetSignatures = func(name string) ([]*model.Signature, error) {
+		return []*model.Signature{{Type: "dos", Offset: 0x1fe}}, nil
+	}
+
+	s := NewSimulation(mds, NewMockFileService(), NewMockLvmService(), NewMockMdadmService(), NewMockCryptsetupService(), NewMockSwapService())
+	sds := NewSimulatedDeviceService(s)
+
+	signatures, err := sds.GetSignatures("/dev/xvdf")
+	utils.CheckError("sds.GetSignatures()", t, nil, err)
+	utils.CheckOutput("sds.GetSignatures()", t, []*model.Signature{{Type: "dos", Offset: 0x1fe}}, signatures)
+
+	err = sds.Wipe("/dev/xvdf")
+	utils.CheckError("sds.Wipe()", t, nil, err)
+	signatures, err = sds.GetSignatures("/dev/xvdf")
+	utils.CheckError("sds.GetSignatures()", t, nil, err)
+	utils.CheckOutput("sds.GetSignatures()", t, []*model.Signature{}, signatures)
+}
+
+func TestSimulatedTune(t *testing.T) {
+	reserved := uint64(5)
+	mfss := NewMockFileSystemService()
+	mfss.StubGetProperties = func(name string) (*model.FileSystemProperties, error) {
+		return &model.FileSystemProperties{
+			ReservedBlocksPercent: &reserved,
+			Errors:                model.ErrorsContinue,
+			Features:              []string{"has_journal"},
+		}, nil
+	}
+
+	s := NewSimulation(NewMockDeviceService(), NewMockFileService(), NewMockLvmService(), NewMockMdadmService(), NewMockCryptsetupService(), NewMockSwapService())
+	sfss := NewSimulatedFileSystemService(s, mfss)
+
+	zero := uint64(0)
+	err := sfss.Tune("/dev/xvdf", &model.FileSystemProperties{
+		ReservedBlocksPercent: &zero,
+		Features:              []string{"fast_commit"},
+	})
+	utils.CheckError("sfss.Tune()", t, nil, err)
+
+	fp, err := sfss.GetProperties("/dev/xvdf")
+	utils.CheckError("sfss.GetProperties()", t, nil, err)
+	utils.CheckOutput("sfss.GetProperties()", t, &model.FileSystemProperties{
+		ReservedBlocksPercent: &zero,
+		Errors:                model.ErrorsContinue,
+		Features:              []string{"has_journal", "fast_commit"},
+	}, fp)
+}
+
+func TestSimulatedSwap(t *testing.T) {
+	mds := NewMockDeviceService()
+	mds.StubGetBlockDevice = func(name string) (*model.BlockDevice, error) {
+		return &model.BlockDevice{Name: name, FileSystem: model.Swap}, nil
+	}
+	mfs := NewMockFileService()
+	mfs.StubGetFile = func(file string) (*model.File, error) {
+		switch file {
+		case "/mnt":
+			return &model.File{Path: file, Type: model.Directory, DeviceId: 1, InodeNo: 2, Permissions: 0755}, nil
+		default:
+			return nil, os.ErrNotExist
+		}
+	}
+	mss := NewMockSwapService()
+	mss.StubGetSwapArea = func(name string) (*model.SwapArea, error) {
+		return nil, nil
+	}
+
+	s := NewSimulation(mds, mfs, NewMockLvmService(), NewMockMdadmService(), NewMockCryptsetupService(), mss)
+	sds := NewSimulatedDeviceService(s)
+	sfs := NewSimulatedFileService(s)
+	sss := NewSimulatedSwapService(s)
+
+	// An active swap area is reported as mounted to [SWAP]
+	_, err := sds.GetBlockDevice("/dev/nvme1n1")
+	utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+	priority := 10
+	err = sss.Activate("/dev/nvme1n1", &priority)
+	utils.CheckError("sss.Activate()", t, nil, err)
+	bd, err := sds.GetBlockDevice("/dev/nvme1n1")
+	utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+	utils.CheckOutput("bd.MountPoint", t, model.SwapMountPoint, bd.MountPoint)
+	sa, err := sss.GetSwapArea("/dev/nvme1n1")
+	utils.CheckError("sss.GetSwapArea()", t, nil, err)
+	utils.CheckOutput("sss.GetSwapArea()", t, &model.SwapArea{Name: "/dev/nvme1n1", Priority: 10}, sa)
+
+	err = sss.Deactivate("/dev/nvme1n1")
+	utils.CheckError("sss.Deactivate()", t, nil, err)
+	sa, err = sss.GetSwapArea("/dev/nvme1n1")
+	utils.CheckError("sss.GetSwapArea()", t, nil, err)
+	utils.CheckOutput("sss.GetSwapArea()", t, (*model.SwapArea)(nil), sa)
+
+	err = sss.CreateFile("/mnt/swapfile", 4294967296)
+	utils.CheckError("sss.CreateFile()", t, nil, err)
+	f, err := sfs.GetFile("/mnt/swapfile")
+	utils.CheckError("sfs.GetFile()", t, nil, err)
+	utils.CheckOutput("f.Permissions", t, model.SwapFilePermissions, f.Permissions)
+	err = sss.CreateFile("/mnt/swapfile", 4294967296)
+	utils.CheckError("sss.CreateFile()", t, fmt.Errorf("🔴 /mnt/swapfile: File already exists"), err)
+
+	err = sss.Activate("/mnt/swapfile", nil)
+	utils.CheckError("sss.Activate()", t, nil, err)
+	sa, err = sss.GetSwapArea("/mnt/swapfile")
+	utils.CheckError("sss.GetSwapArea()", t, nil, err)
+	utils.CheckOutput("sss.GetSwapArea()", t, &model.SwapArea{Name: "/mnt/swapfile", Size: 4294967296}, sa)
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+)
+
+// The signatures of the file systems and containers that are recognised by
+// ebs-bootstrap, and their location relative to the start of the device
+const (
+	ext4SuperblockOffset  = 0x400
+	ext4MagicOffset       = ext4SuperblockOffset + 0x38
+	ext4CompatOffset      = ext4SuperblockOffset + 0x5c
+	ext4IncompatOffset    = ext4SuperblockOffset + 0x60
+	ext4UuidOffset        = ext4SuperblockOffset + 0x68
+	ext4LabelOffset       = ext4SuperblockOffset + 0x78
+	ext4LabelSize         = 16
+	ext4Magic             = 0xef53
+	ext4CompatHasJournal  = 0x4
+	ext4IncompatExtents   = 0x40
+	ext4IncompatFlexBg    = 0x200
+	xfsMagic              = "XFSB"
+	xfsUuidOffset         = 0x20
+	xfsLabelOffset        = 0x6c
+	xfsLabelSize          = 12
+	btrfsSuperblockOffset = 0x10000
+	btrfsUuidOffset       = btrfsSuperblockOffset + 0x20
+	btrfsMagicOffset      = btrfsSuperblockOffset + 0x40
+	btrfsLabelOffset      = btrfsSuperblockOffset + 0x12b
+	btrfsLabelSize        = 256
+	btrfsMagic            = "_BHRfS_M"
+	luksMagic             = "LUKS\xba\xbe"
+	luksLabelOffset       = 0x18
+	luksLabelSize         = 48
+	luksUuidOffset        = 0xa8
+	luksUuidSize          = 40
+	mdMagic               = 0xa92b4efc
+	mdUuidOffset          = 0x10
+	mdNameOffset          = 0x20
+	mdNameSize            = 32
+	lvmLabel              = "LABELONE"
+	lvmType               = "LVM2 001"
+	lvmLabelSectors       = 4
+	lvmUuidSize           = 32
+	luks2SecondaryMagic   = "SKUL\xba\xbe"
+	luks2SecondaryOffset  = 0x4000
+	swapPageSize          = 0x1000
+	swapMagicOffset       = swapPageSize - 10
+	swapLastPageOffset    = 0x404
+	swapUuidOffset        = 0x40c
+	swapLabelOffset       = 0x41c
+	swapLabelSize         = 16
+	zfsUberblockMagic     = 0x00bab10c
+	zfsLabelSize          = 0x40000
+	zfsUberblockOffset    = 0x20000
+	gptHeaderOffset       = 0x200
+	mbrSignatureOffset    = 0x1fe
+	mbrPartitionOffset    = 0x1be
+	mbrProtectiveType     = 0xee
+	sectorSize            = 512
+)
+
+// The magic strings of swap areas that were created by either version of mkswap
+var swapMagics = []string{"SWAPSPACE2", "SWAP-SPACE"}
+
+// A superblock describes the signature that was found at the start (or the end)
+// of a block device. A device without a recognised signature is unformatted
+type superblock struct {
+	fileSystem model.FileSystem
+	label      string
+	uuid       string
+}
+
+// probeSuperblock identifies the contents of a device from its signature, in the
+// same manner as blkid. A RAID member is probed first, as the data of a RAID1
+// member with a trailing superblock is itself a valid file system
+func probeSuperblock(r io.ReaderAt, size uint64) (*superblock, error) {
+	probes := []func(io.ReaderAt, uint64) (*superblock, error){
+		probeRaidMember,
+		probeLuks,
+		probeLvm,
+		probeXfs,
+		probeExt,
+		probeBtrfs,
+		probeSwap,
+	}
+	for _, probe := range probes {
+		sb, err := probe(r, size)
+		if err != nil {
+			return nil, err
+		}
+		if sb != nil {
+			return sb, nil
+		}
+	}
+	return &superblock{fileSystem: model.Unformatted}, nil
+}
+
+// readAt tolerates a device that is smaller than the region that is read, as
+// the device can not contain a signature beyond its end
+func readAt(r io.ReaderAt, offset int64, length int) ([]byte, error) {
+	b := make([]byte, length)
+	n, err := r.ReadAt(b, offset)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if n < length {
+		return nil, nil
+	}
+	return b, nil
+}
+
+func probeExt(r io.ReaderAt, _ uint64) (*superblock, error) {
+	b, err := readAt(r, ext4SuperblockOffset, 0x400)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	at := func(offset int) []byte { return b[offset-ext4SuperblockOffset:] }
+	if binary.LittleEndian.Uint16(at(ext4MagicOffset)) != ext4Magic {
+		return nil, nil
+	}
+	// The features of the file system distinguish ext4 from its predecessors
+	fs := model.FileSystem("ext2")
+	compat := binary.LittleEndian.Uint32(at(ext4CompatOffset))
+	incompat := binary.LittleEndian.Uint32(at(ext4IncompatOffset))
+	if incompat&(ext4IncompatExtents|ext4IncompatFlexBg) != 0 {
+		fs = model.Ext4
+	} else if compat&ext4CompatHasJournal != 0 {
+		fs = model.FileSystem("ext3")
+	}
+	return &superblock{
+		fileSystem: fs,
+		label:      cString(at(ext4LabelOffset)[:ext4LabelSize]),
+		uuid:       formatUuid(at(ext4UuidOffset)[:16]),
+	}, nil
+}
+
+func probeXfs(r io.ReaderAt, _ uint64) (*superblock, error) {
+	b, err := readAt(r, 0, xfsLabelOffset+xfsLabelSize)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	if string(b[:len(xfsMagic)]) != xfsMagic {
+		return nil, nil
+	}
+	return &superblock{
+		fileSystem: model.Xfs,
+		label:      cString(b[xfsLabelOffset : xfsLabelOffset+xfsLabelSize]),
+		uuid:       formatUuid(b[xfsUuidOffset : xfsUuidOffset+16]),
+	}, nil
+}
+
+func probeBtrfs(r io.ReaderAt, _ uint64) (*superblock, error) {
+	b, err := readAt(r, btrfsSuperblockOffset, btrfsLabelOffset+btrfsLabelSize-btrfsSuperblockOffset)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	at := func(offset int) []byte { return b[offset-btrfsSuperblockOffset:] }
+	if string(at(btrfsMagicOffset)[:len(btrfsMagic)]) != btrfsMagic {
+		return nil, nil
+	}
+	return &superblock{
+		fileSystem: model.Btrfs,
+		label:      cString(at(btrfsLabelOffset)[:btrfsLabelSize]),
+		uuid:       formatUuid(at(btrfsUuidOffset)[:16]),
+	}, nil
+}
+
+// Only LUKS2 headers have a label. The label of a LUKS1 header is left empty
+func probeLuks(r io.ReaderAt, _ uint64) (*superblock, error) {
+	b, err := readAt(r, 0, luksUuidOffset+luksUuidSize)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	if string(b[:len(luksMagic)]) != luksMagic {
+		return nil, nil
+	}
+	sb := &superblock{
+		fileSystem: model.Luks,
+		uuid:       cString(b[luksUuidOffset : luksUuidOffset+luksUuidSize]),
+	}
+	if binary.BigEndian.Uint16(b[len(luksMagic):]) == 2 {
+		sb.label = cString(b[luksLabelOffset : luksLabelOffset+luksLabelSize])
+	}
+	return sb, nil
+}
+
+// The superblock of an mdadm array is located at the start (v1.1), 4KiB from the
+// start (v1.2) or near the end (v1.0) of each member device
+func probeRaidMember(r io.ReaderAt, size uint64) (*superblock, error) {
+	offsets := []int64{0, 0x1000}
+	if sectors := size / sectorSize; sectors >= 16 {
+		offsets = append(offsets, int64(((sectors-16)&^7)*sectorSize))
+	}
+	for _, offset := range offsets {
+		b, err := readAt(r, offset, mdNameOffset+mdNameSize)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil || binary.LittleEndian.Uint32(b) != mdMagic {
+			continue
+		}
+		return &superblock{
+			fileSystem: model.RaidMember,
+			label:      cString(b[mdNameOffset : mdNameOffset+mdNameSize]),
+			uuid:       formatUuid(b[mdUuidOffset : mdUuidOffset+16]),
+		}, nil
+	}
+	return nil, nil
+}
+
+// The label of an LVM physical volume can be written to any of the first four
+// sectors of a device. The label points to the header of the physical volume,
+// which begins with its uuid
+func probeLvm(r io.ReaderAt, _ uint64) (*superblock, error) {
+	for sector := int64(0); sector < lvmLabelSectors; sector++ {
+		b, err := readAt(r, sector*sectorSize, sectorSize)
+		if err != nil || b == nil {
+			return nil, err
+		}
+		if string(b[:len(lvmLabel)]) != lvmLabel || string(b[0x18:0x18+len(lvmType)]) != lvmType {
+			continue
+		}
+		offset := int(binary.LittleEndian.Uint32(b[0x14:]))
+		if offset+lvmUuidSize > sectorSize {
+			return nil, fmt.Errorf("🔴 Failed to decode LVM label")
+		}
+		uuid := b[offset : offset+lvmUuidSize]
+		return &superblock{
+			fileSystem: model.Lvm,
+			uuid: fmt.Sprintf("%s-%s-%s-%s-%s-%s-%s",
+				uuid[0:6], uuid[6:10], uuid[10:14], uuid[14:18], uuid[18:22], uuid[22:26], uuid[26:32]),
+		}, nil
+	}
+	return nil, nil
+}
+
+// The header of a swap area occupies the first page of the device, and ends with its
+// magic string. Only the header of the current version (SWAPSPACE2) has a label
+// and uuid. The page size is assumed to be 4KiB
+func probeSwap(r io.ReaderAt, _ uint64) (*superblock, error) {
+	b, err := readAt(r, 0, swapPageSize)
+	if err != nil || b == nil {
+		return nil, err
+	}
+	magic := string(b[swapMagicOffset:])
+	if !slices.Contains(swapMagics, magic) {
+		return nil, nil
+	}
+	sb := &superblock{fileSystem: model.Swap}
+	if magic == swapMagics[0] {
+		sb.label = cString(b[swapLabelOffset : swapLabelOffset+swapLabelSize])
+		sb.uuid = formatUuid(b[swapUuidOffset : swapUuidOffset+16])
+	}
+	return sb, nil
+}
+
+// probeSwapSize calculates the size of a swap area from the index of its last
+// page, which is recorded in its header. A size of 0 is reported for a device
+// that is not a swap area
+func probeSwapSize(r io.ReaderAt) (uint64, error) {
+	b, err := readAt(r, 0, swapPageSize)
+	if err != nil || b == nil || string(b[swapMagicOffset:]) != swapMagics[0] {
+		return 0, err
+	}
+	return (uint64(binary.LittleEndian.Uint32(b[swapLastPageOffset:])) + 1) * swapPageSize, nil
+}
+
+// cString returns the contents of a null-padded string
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+func formatUuid(b []byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// A signature that was found by probeSignatures. The length of its magic string
+// is retained, so that the signature can be erased
+type signature struct {
+	model.Signature
+	length int
+}
+
+// probeSignatures reports every signature of a device, in the manner of wipefs.
+// Unlike probeSuperblock, the search does not stop at the first signature, and
+// includes partition tables, as well as the secondary copies of any structure
+func probeSignatures(r io.ReaderAt, size uint64) ([]*signature, error) {
+	signatures := []*signature{}
+	match := func(t string, offset int64, magic []byte) error {
+		b, err := readAt(r, offset, len(magic))
+		if err != nil {
+			return err
+		}
+		if b != nil && bytes.Equal(b, magic) {
+			signatures = append(signatures, &signature{
+				Signature: model.Signature{Type: t, Offset: uint64(offset)},
+				length:    len(magic),
+			})
+		}
+		return nil
+	}
+	le32 := func(v uint32) []byte { return binary.LittleEndian.AppendUint32(nil, v) }
+	le64 := func(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+	be64 := func(v uint64) []byte { return binary.BigEndian.AppendUint64(nil, v) }
+
+	// The type of an ext file system is determined from its superblock
+	sb, err := probeExt(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if sb != nil {
+		if err := match(string(sb.fileSystem), ext4MagicOffset, []byte{0x53, 0xef}); err != nil {
+			return nil, err
+		}
+	}
+	checks := []struct {
+		t      string
+		offset int64
+		magic  []byte
+	}{
+		{string(model.Xfs), 0, []byte(xfsMagic)},
+		{string(model.Btrfs), btrfsMagicOffset, []byte(btrfsMagic)},
+		{string(model.Luks), 0, []byte(luksMagic)},
+		{string(model.Luks), luks2SecondaryOffset, []byte(luks2SecondaryMagic)},
+		{string(model.RaidMember), 0, le32(mdMagic)},
+		{string(model.RaidMember), 0x1000, le32(mdMagic)},
+		{"zfs_member", zfsUberblockOffset, le64(zfsUberblockMagic)},
+		{"zfs_member", zfsUberblockOffset, be64(zfsUberblockMagic)},
+		{"zfs_member", zfsLabelSize + zfsUberblockOffset, le64(zfsUberblockMagic)},
+		{"zfs_member", zfsLabelSize + zfsUberblockOffset, be64(zfsUberblockMagic)},
+		{"gpt", gptHeaderOffset, []byte(gptSignature)},
+	}
+	for _, magic := range swapMagics {
+		checks = append(checks, struct {
+			t      string
+			offset int64
+			magic  []byte
+		}{"swap", swapMagicOffset, []byte(magic)})
+	}
+	for _, c := range checks {
+		if err := match(c.t, c.offset, c.magic); err != nil {
+			return nil, err
+		}
+	}
+	for sector := int64(0); sector < lvmLabelSectors; sector++ {
+		if err := match(string(model.Lvm), sector*sectorSize, []byte(lvmLabel)); err != nil {
+			return nil, err
+		}
+	}
+	if sectors := size / sectorSize; sectors >= 16 {
+		if err := match(string(model.RaidMember), int64(((sectors-16)&^7)*sectorSize), le32(mdMagic)); err != nil {
+			return nil, err
+		}
+		// The backup GPT header occupies the last sector of the device
+		if err := match("gpt", int64((sectors-1)*sectorSize), []byte(gptSignature)); err != nil {
+			return nil, err
+		}
+	}
+	// A boot sector is only considered a partition table if it describes at least
+	// one partition. A protective MBR describes a single partition of type 0xee
+	mbr, err := readAt(r, 0, sectorSize)
+	if err != nil {
+		return nil, err
+	}
+	if mbr != nil && binary.LittleEndian.Uint16(mbr[mbrSignatureOffset:]) == mbrSignature {
+		t := ""
+		for i := 0; i < 4; i++ {
+			switch mbr[mbrPartitionOffset+i*16+4] {
+			case 0:
+				continue
+			case mbrProtectiveType:
+				t = "PMBR"
+			default:
+				if len(t) == 0 {
+					t = "dos"
+				}
+			}
+		}
+		if len(t) > 0 {
+			signatures = append(signatures, &signature{
+				Signature: model.Signature{Type: t, Offset: mbrSignatureOffset},
+				length:    2,
+			})
+		}
+	}
+	slices.SortStableFunc(signatures, func(a, b *signature) int {
+		return cmp.Compare(a.Offset, b.Offset)
+	})
+	return signatures, nil
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+const (
+	swapMaximumLabelLength = 16
+)
+
+type SwapService interface {
+	GetSwapArea(name string) (*model.SwapArea, error)
+	Activate(name string, priority *int) error
+	Deactivate(name string) error
+	CreateFile(name string, size uint64) error
+}
+
+// LinuxSwapService is also the FileSystemService of the swap file system. This
+// allows a swap device to be formatted, labelled and resized by the same layers
+// as a device with any other file system
+type LinuxSwapService struct {
+	runnerFactory utils.RunnerFactory
+	// The table of active swap areas. A temporary file can be substituted when testing
+	swaps string
+}
+
+func NewLinuxSwapService(rf utils.RunnerFactory) *LinuxSwapService {
+	return &LinuxSwapService{
+		runnerFactory: rf,
+		swaps:         "/proc/swaps",
+	}
+}
+
+// GetSwapArea reports the active swap area of a device or file, or nil if it is
+// not active. The kernel lists each swap area by the canonical path of its device
+// or file, so any symbolic link (e.g. /dev/xvdf -> nvme1n1) is resolved beforehand
+func (lss *LinuxSwapService) GetSwapArea(name string) (*model.SwapArea, error) {
+	p, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	f, err := os.Open(lss.swaps)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	areas, err := parseSwaps(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, area := range areas {
+		if area.Name == p {
+			return area, nil
+		}
+	}
+	return nil, nil
+}
+
+func (lss *LinuxSwapService) Activate(name string, priority *int) error {
+	args := []string{}
+	if priority != nil {
+		args = append(args, "-p", strconv.Itoa(*priority))
+	}
+	r := lss.runnerFactory.Select(utils.Swapon)
+	_, err := r.Command(append(args, name)...)
+	return err
+}
+
+func (lss *LinuxSwapService) Deactivate(name string) error {
+	r := lss.runnerFactory.Select(utils.Swapoff)
+	_, err := r.Command(name)
+	return err
+}
+
+// A swap file must not contain any holes. Therefore, every block of the file is
+// allocated up front, rather than extending a sparse file. The file is created
+// with the permissions that swapon expects, so that its contents are never exposed.
+// A file that could not be formatted is removed, so that it is created again
+func (lss *LinuxSwapService) CreateFile(name string, size uint64) error {
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, model.SwapFilePermissions.Perm())
+	if err != nil {
+		return err
+	}
+	// The permissions of a new file are masked by the umask of the process
+	if err := f.Chmod(model.SwapFilePermissions.Perm()); err != nil {
+		f.Close()
+		os.Remove(name)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(name)
+		return err
+	}
+	r := lss.runnerFactory.Select(utils.Fallocate)
+	if _, err := r.Command("-l", strconv.FormatUint(size, 10), name); err != nil {
+		os.Remove(name)
+		return err
+	}
+	if err := lss.Format(name, nil); err != nil {
+		os.Remove(name)
+		return err
+	}
+	return nil
+}
+
+func (lss *LinuxSwapService) GetFileSystem() model.FileSystem {
+	return model.Swap
+}
+
+func (lss *LinuxSwapService) Format(name string, options *model.FormatOptions) error {
+	args := []string{}
+	if options != nil && len(options.Label) > 0 {
+		args = append(args, "-L", options.Label)
+	}
+	r := lss.runnerFactory.Select(utils.Mkswap)
+	_, err := r.Command(append(args, name)...)
+	return err
+}
+
+func (lss *LinuxSwapService) ValidateFormatOptions(options *model.FormatOptions) error {
+	return validateFormatOptions(lss.GetFileSystem(), options)
+}
+
+func (lss *LinuxSwapService) Label(name string, label string) error {
+	r := lss.runnerFactory.Select(utils.Swaplabel)
+	_, err := r.Command("-L", label, name)
+	return err
+}
+
+func (lss *LinuxSwapService) SetUuid(name string, uuid string) error {
+	//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+	return fmt.Errorf("The UUID of the %s file system can not be changed", lss.GetFileSystem())
+}
+
+// A swap area can not be grown in place. Instead, the swap area is created again
+// to span the entire device. The label and uuid of the swap area are retained, so
+// that any reference to the swap area remains valid
+func (lss *LinuxSwapService) Resize(name string) error {
+	r := lss.runnerFactory.Select(utils.Swaplabel)
+	output, err := r.Command(name)
+	if err != nil {
+		return err
+	}
+	args := []string{}
+	// Regex (Label)
+	rel := regexp.MustCompile(`(?m)^LABEL:\s*(.*?)\s*$`)
+	if ml := rel.FindStringSubmatch(output); len(ml) == 2 && len(ml[1]) > 0 {
+		args = append(args, "-L", ml[1])
+	}
+	// Regex (UUID)
+	reu := regexp.MustCompile(`(?m)^UUID:\s*(\S+)`)
+	if mu := reu.FindStringSubmatch(output); len(mu) == 2 {
+		args = append(args, "-U", mu[1])
+	}
+	r = lss.runnerFactory.Select(utils.Mkswap)
+	_, err = r.Command(append(args, name)...)
+	return err
+}
+
+func (lss *LinuxSwapService) Check(name string, repair bool) (model.FsckResult, error) {
+	//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+	return "", fmt.Errorf("The %s file system can not be checked", lss.GetFileSystem())
+}
+
+// The size of a swap area is read from its header, as it can not be queried
+// through any tool while the swap area is inactive
+func (lss *LinuxSwapService) GetSize(name string) (uint64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, fmt.Errorf("🔴 %s: Failed to open swap area: %v", name, err)
+	}
+	defer f.Close()
+	size, err := probeSwapSize(f)
+	if err != nil {
+		return 0, fmt.Errorf("🔴 %s: Failed to read swap area header: %v", name, err)
+	}
+	if size == 0 {
+		return 0, fmt.Errorf("🔴 %s: Failed to decode swap area header", name)
+	}
+	return size, nil
+}
+
+func (lss *LinuxSwapService) GetProperties(name string) (*model.FileSystemProperties, error) {
+	return &model.FileSystemProperties{}, nil
+}
+
+func (lss *LinuxSwapService) Tune(name string, properties *model.FileSystemProperties) error {
+	//lint:ignore ST1005 This Error Message Is Supposed to Be Prepended With A Device Name
+	return fmt.Errorf("The properties of the %s file system can not be tuned", lss.GetFileSystem())
+}
+
+func (lss *LinuxSwapService) ValidateProperties(properties *model.FileSystemProperties) error {
+	return validateProperties(lss.GetFileSystem(), properties)
+}
+
+func (lss *LinuxSwapService) GetMaximumLabelLength() int {
+	return swapMaximumLabelLength
+}
+
+func (lss *LinuxSwapService) DoesResizeRequireMount() bool {
+	return false
+}
+
+// An active swap area is in use by the kernel, and so can not be created again
+func (lss *LinuxSwapService) DoesResizeRequireUnmount() bool {
+	return true
+}
+
+func (lss *LinuxSwapService) DoesLabelRequireUnmount() bool {
+	return false
+}
+
+func (lss *LinuxSwapService) DoesFormatSupportLabel() bool {
+	return true
+}
+
+func (lss *LinuxSwapService) DoesTuneRequireUnmount() bool {
+	return false
+}
+
+// parseSwaps decodes the table of active swap areas. The size of each swap area
+// is reported in KiB, and any whitespace in its path is escaped as in mountinfo
+//
+//	Filename				Type		Size		Used		Priority
+//	/dev/nvme1n1                            partition	8388604		0		-2
+func parseSwaps(r io.Reader) ([]*model.SwapArea, error) {
+	areas := []*model.SwapArea{}
+	scanner := bufio.NewScanner(r)
+	for i := 0; scanner.Scan(); i++ {
+		fields := strings.Fields(scanner.Text())
+		if i == 0 || len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("🔴 Failed to decode /proc/swaps")
+		}
+		size, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("🔴 Failed to cast swap area size to unsigned 64-bit integer")
+		}
+		priority, err := strconv.Atoi(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("🔴 Failed to cast swap area priority to integer")
+		}
+		areas = append(areas, &model.SwapArea{
+			Name:     unescapeMountInfo(fields[0]),
+			Size:     size * 1024,
+			Priority: priority,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return areas, nil
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func swapContents(label string, uuid []byte, lastPage uint32) []byte {
+	b := make([]byte, swapPageSize)
+	binary.LittleEndian.PutUint32(b[0x400:], 1)
+	binary.LittleEndian.PutUint32(b[swapLastPageOffset:], lastPage)
+	copy(b[swapUuidOffset:], uuid)
+	copy(b[swapLabelOffset:], label)
+	copy(b[swapMagicOffset:], swapMagics[0])
+	return b
+}
+
+func TestParseSwaps(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Swaps          string
+		ExpectedOutput []*model.SwapArea
+		ExpectedError  error
+	}{
+		{
+			Name: "Active Swap Areas",
+			Swaps: `Filename				Type		Size		Used		Priority
+/dev/nvme1n1                            partition	8388604		0		10
+/mnt/app\040data/swapfile               file		2097148		0		-2
+`,
+			ExpectedOutput: []*model.SwapArea{
+				{Name: "/dev/nvme1n1", Size: 8589930496, Priority: 10},
+				{Name: "/mnt/app data/swapfile", Size: 2147479552, Priority: -2},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "No Active Swap Areas",
+			Swaps:          "Filename				Type		Size		Used		Priority\n",
+			ExpectedOutput: []*model.SwapArea{},
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Malformed Table",
+			Swaps: `Filename				Type		Size		Used		Priority
+/dev/nvme1n1                            partition	8388604
+`,
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 Failed to decode /proc/swaps"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			areas, err := parseSwaps(strings.NewReader(subtest.Swaps))
+			utils.CheckError("parseSwaps()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("parseSwaps()", t, subtest.ExpectedOutput, areas)
+		})
+	}
+}
+
+func TestGetSwapArea(t *testing.T) {
+	root := t.TempDir()
+	device := filepath.Join(root, "nvme1n1")
+	if err := os.WriteFile(device, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "xvdf")
+	if err := os.Symlink(device, link); err != nil {
+		t.Fatal(err)
+	}
+	inactive := filepath.Join(root, "nvme2n1")
+	if err := os.WriteFile(inactive, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	swaps := filepath.Join(root, "swaps")
+	if err := os.WriteFile(swaps, []byte(fmt.Sprintf("Filename Type Size Used Priority\n%s partition 8388604 0 10\n", device)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	subtests := []struct {
+		Name           string
+		Device         string
+		ExpectedOutput *model.SwapArea
+		ExpectedError  error
+	}{
+		{
+			Name:           "Active Swap Area",
+			Device:         device,
+			ExpectedOutput: &model.SwapArea{Name: device, Size: 8589930496, Priority: 10},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Active Swap Area By Symbolic Link",
+			Device:         link,
+			ExpectedOutput: &model.SwapArea{Name: device, Size: 8589930496, Priority: 10},
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Inactive Swap Area",
+			Device:         inactive,
+			ExpectedOutput: nil,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Swap File Does Not Exist",
+			Device:         filepath.Join(root, "swapfile"),
+			ExpectedOutput: nil,
+			ExpectedError:  nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lss := &LinuxSwapService{swaps: swaps}
+			area, err := lss.GetSwapArea(subtest.Device)
+			utils.CheckError("lss.GetSwapArea()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("lss.GetSwapArea()", t, subtest.ExpectedOutput, area)
+		})
+	}
+}
+
+func TestSwapActivate(t *testing.T) {
+	ten := 10
+	subtests := []struct {
+		Name          string
+		Priority      *int
+		RunnerArgs    []string
+		ExpectedError error
+	}{
+		{
+			Name:          "Priority",
+			Priority:      &ten,
+			RunnerArgs:    []string{"-p", "10", "/dev/xvdf"},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "No Priority",
+			Priority:      nil,
+			RunnerArgs:    []string{"/dev/xvdf"},
+			ExpectedError: nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(utils.Swapon, subtest.RunnerArgs, "", nil)
+			lss := NewLinuxSwapService(mrf)
+			err := lss.Activate("/dev/xvdf", subtest.Priority)
+			utils.CheckError("lss.Activate()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestSwapDeactivate(t *testing.T) {
+	mrf := utils.NewMockRunnerFactory(utils.Swapoff, []string{"/dev/xvdf"}, "", nil)
+	lss := NewLinuxSwapService(mrf)
+	utils.CheckError("lss.Deactivate()", t, nil, lss.Deactivate("/dev/xvdf"))
+}
+
+func TestSwapFormat(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Options       *model.FormatOptions
+		RunnerArgs    []string
+		ExpectedError error
+	}{
+		{
+			Name:          "Label",
+			Options:       &model.FormatOptions{Label: "swap"},
+			RunnerArgs:    []string{"-L", "swap", "/dev/xvdf"},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "No Options",
+			Options:       nil,
+			RunnerArgs:    []string{"/dev/xvdf"},
+			ExpectedError: nil,
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(utils.Mkswap, subtest.RunnerArgs, "", nil)
+			lss := NewLinuxSwapService(mrf)
+			err := lss.Format("/dev/xvdf", subtest.Options)
+			utils.CheckError("lss.Format()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestSwapResize(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Runners       map[utils.Binary]*utils.MockRunner
+		ExpectedError error
+	}{
+		{
+			Name: "Label and UUID Are Retained",
+			Runners: map[utils.Binary]*utils.MockRunner{
+				utils.Swaplabel: utils.NewMockRunner([]string{"/dev/xvdf"}, "LABEL: swap\nUUID:  31cf3150-f4b1-409a-9887-7f9da1fdd99c", nil),
+				utils.Mkswap:    utils.NewMockRunner([]string{"-L", "swap", "-U", "31cf3150-f4b1-409a-9887-7f9da1fdd99c", "/dev/xvdf"}, "", nil),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "No Label",
+			Runners: map[utils.Binary]*utils.MockRunner{
+				utils.Swaplabel: utils.NewMockRunner([]string{"/dev/xvdf"}, "UUID:  31cf3150-f4b1-409a-9887-7f9da1fdd99c", nil),
+				utils.Mkswap:    utils.NewMockRunner([]string{"-U", "31cf3150-f4b1-409a-9887-7f9da1fdd99c", "/dev/xvdf"}, "", nil),
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name: "Failed to Read Swap Area",
+			Runners: map[utils.Binary]*utils.MockRunner{
+				utils.Swaplabel: utils.NewMockRunner([]string{"/dev/xvdf"}, "", fmt.Errorf("🔴 swaplabel: /dev/xvdf: not a valid swap partition")),
+			},
+			ExpectedError: fmt.Errorf("🔴 swaplabel: /dev/xvdf: not a valid swap partition"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockMultiRunnerFactory(subtest.Runners)
+			lss := NewLinuxSwapService(mrf)
+			err := lss.Resize("/dev/xvdf")
+			utils.CheckError("lss.Resize()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestSwapGetSize(t *testing.T) {
+	root := t.TempDir()
+	swap := filepath.Join(root, "swap")
+	if err := os.WriteFile(swap, swapContents("swap", make([]byte, 16), 262143), 0600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(root, "empty")
+	if err := os.WriteFile(empty, make([]byte, swapPageSize), 0600); err != nil {
+		t.Fatal(err)
+	}
+	subtests := []struct {
+		Name           string
+		Device         string
+		ExpectedOutput uint64
+		ExpectedError  error
+	}{
+		{
+			Name:           "Swap Area",
+			Device:         swap,
+			ExpectedOutput: 1073741824,
+			ExpectedError:  nil,
+		},
+		{
+			Name:           "Not a Swap Area",
+			Device:         empty,
+			ExpectedOutput: 0,
+			ExpectedError:  fmt.Errorf("🔴 %s: Failed to decode swap area header", empty),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lss := NewLinuxSwapService(nil)
+			size, err := lss.GetSize(subtest.Device)
+			utils.CheckError("lss.GetSize()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("lss.GetSize()", t, subtest.ExpectedOutput, size)
+		})
+	}
+}
+
+func TestSwapCreateFile(t *testing.T) {
+	subtests := []struct {
+		Name           string
+		Runners        func(name string) map[utils.Binary]*utils.MockRunner
+		ExpectedExists bool
+		ExpectedError  error
+	}{
+		{
+			Name: "Swap File Created",
+			Runners: func(name string) map[utils.Binary]*utils.MockRunner {
+				return map[utils.Binary]*utils.MockRunner{
+					utils.Fallocate: utils.NewMockRunner([]string{"-l", "1073741824", name}, "", nil),
+					utils.Mkswap:    utils.NewMockRunner([]string{name}, "", nil),
+				}
+			},
+			ExpectedExists: true,
+			ExpectedError:  nil,
+		},
+		{
+			Name: "Swap File Removed After Failure",
+			Runners: func(name string) map[utils.Binary]*utils.MockRunner {
+				return map[utils.Binary]*utils.MockRunner{
+					utils.Fallocate: utils.NewMockRunner([]string{"-l", "1073741824", name}, "", fmt.Errorf("🔴 fallocate: No space left on device")),
+				}
+			},
+			ExpectedExists: false,
+			ExpectedError:  fmt.Errorf("🔴 fallocate: No space left on device"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "swapfile")
+			mrf := utils.NewMockMultiRunnerFactory(subtest.Runners(name))
+			lss := NewLinuxSwapService(mrf)
+			err := lss.CreateFile(name, 1073741824)
+			utils.CheckError("lss.CreateFile()", t, subtest.ExpectedError, err)
+			info, err := os.Stat(name)
+			utils.CheckOutput("os.Stat()", t, subtest.ExpectedExists, err == nil)
+			if err == nil {
+				utils.CheckOutput("info.Mode()", t, model.SwapFilePermissions.Perm(), info.Mode().Perm())
+			}
+		})
+	}
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+const (
+	// The lsblk utility hides RAM disks, which are identified by their major number
+	ramDiskMajor = 1
+	gptSignature = "EFI PART"
+	mbrSignature = 0xaa55
+	// The ioctl that instructs the kernel to re-read the partition table of a device
+	blkRrPart = 0x125f
+)
+
+// SysfsDeviceService queries block devices without relying on any external binary.
+// The attributes of each device are read from sysfs, its mount point from the
+// mount table of the current process and its contents from the signature at the
+// start of the device. A device is still mounted with mount(8), which interprets
+// the mount options that are never passed to the kernel (e.g. nofail)
+type SysfsDeviceService struct {
+	// The root of the file system hierarchy. A temporary directory that mimics
+	// /sys, /proc and /dev can be substituted when testing
+	root          string
+	runnerFactory utils.RunnerFactory
+}
+
+func NewSysfsDeviceService(rc utils.RunnerFactory) *SysfsDeviceService {
+	return &SysfsDeviceService{
+		root:          "/",
+		runnerFactory: rc,
+	}
+}
+
+func (sds *SysfsDeviceService) GetSize(name string) (uint64, error) {
+	sysName, err := sds.resolve(name)
+	if err != nil {
+		return 0, err
+	}
+	return sds.getSize(sysName)
+}
+
+// GetBlockDevices lists the same devices as `lsblk --nodeps`. Empty devices (e.g. an
+// unused loop device) and RAM disks are omitted
+func (sds *SysfsDeviceService) GetBlockDevices() ([]string, error) {
+	entries, err := os.ReadDir(sds.path("/sys/block"))
+	if err != nil {
+		return nil, err
+	}
+	d := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		size, err := sds.getSize(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			continue
+		}
+		major, _, err := sds.getDeviceNumber(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if major == ramDiskMajor {
+			continue
+		}
+		d = append(d, "/dev/"+entry.Name())
+	}
+	return d, nil
+}
+
+func (sds *SysfsDeviceService) GetBlockDevice(name string) (*model.BlockDevice, error) {
+	sysName, err := sds.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	size, err := sds.getSize(sysName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(sds.path("/dev", sysName))
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: Failed to open block device: %v", name, err)
+	}
+	defer f.Close()
+	sb, err := probeSuperblock(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: Failed to probe block device: %v", name, err)
+	}
+	// A file system that is recognised, but not supported (e.g. ext3), is reported
+	// as unknown. It is left untouched, as a device with any other file system
+	fs, err := model.ParseFileSystem(string(sb.fileSystem))
+	if err != nil {
+		fs = model.Unknown
+	}
+	mp, err := sds.getMountPoint(sysName)
+	if err != nil {
+		return nil, err
+	}
+	if len(mp) == 0 && fs == model.Swap {
+		mp, err = sds.getSwapMountPoint(sysName)
+		if err != nil {
+			return nil, err
+		}
+	}
+	pu, err := sds.getPartUuid(sysName)
+	if err != nil {
+		return nil, err
+	}
+	holders, err := sds.getHolders(sysName)
+	if err != nil {
+		return nil, err
+	}
+	return &model.BlockDevice{
+		Name:       name,
+		Label:      sb.label,
+		FileSystem: fs,
+		MountPoint: mp,
+		UUID:       sb.uuid,
+		PartUUID:   pu,
+		Size:       size,
+		Rotational: sds.readAttribute(sds.getQueue(sysName), "rotational") == "1",
+		ReadOnly:   sds.readAttribute(sds.path("/sys/class/block", sysName), "ro") == "1",
+		Holders:    holders,
+	}, nil
+}
+
+func (sds *SysfsDeviceService) Mount(source string, target string, fs model.FileSystem, options model.MountOptions) error {
+	r := sds.runnerFactory.Select(utils.Mount)
+	_, err := r.Command(source, "-t", string(fs), "-o", string(options), target)
+	return err
+}
+
+func (sds *SysfsDeviceService) Umount(source string, target string) error {
+	r := sds.runnerFactory.Select(utils.Umount)
+	_, err := r.Command(target)
+	return err
+}
+
+// GetSignatures reports every signature of a device, in the same manner as
+// `wipefs --no-act`
+func (sds *SysfsDeviceService) GetSignatures(name string) ([]*model.Signature, error) {
+	signatures, err := sds.probeSignatures(name)
+	if err != nil {
+		return nil, err
+	}
+	ms := make([]*model.Signature, len(signatures))
+	for i, s := range signatures {
+		ms[i] = &s.Signature
+	}
+	return ms, nil
+}
+
+// Wipe erases the magic string of every signature of a device, in the same manner
+// as `wipefs --all`. The remainder of the device is left untouched. The kernel is
+// then asked to re-read the partition table of the device, so that it no longer
+// reports any partition that was erased. A partition does not have a partition
+// table of its own, and so is skipped
+func (sds *SysfsDeviceService) Wipe(name string) error {
+	signatures, err := sds.probeSignatures(name)
+	if err != nil {
+		return err
+	}
+	sysName, err := sds.resolve(name)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(sds.path("/dev", sysName), os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("🔴 %s: Failed to open block device: %v", name, err)
+	}
+	defer f.Close()
+	for _, s := range signatures {
+		if _, err := f.WriteAt(make([]byte, s.length), int64(s.Offset)); err != nil {
+			return fmt.Errorf("🔴 %s: Failed to erase %s signature: %v", name, s.Type, err)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("🔴 %s: Failed to erase signatures: %v", name, err)
+	}
+	if _, found := sds.getParent(sysName); found {
+		return nil
+	}
+	// A device node that is substituted when testing is a regular file
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("🔴 %s: Failed to erase signatures: %v", name, err)
+	}
+	if fi.Mode()&os.ModeDevice == 0 {
+		return nil
+	}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), blkRrPart, 0); errno != 0 {
+		return fmt.Errorf("🔴 %s: Failed to re-read partition table: %v", name, errno)
+	}
+	return nil
+}
+
+func (sds *SysfsDeviceService) probeSignatures(name string) ([]*signature, error) {
+	sysName, err := sds.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	size, err := sds.getSize(sysName)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(sds.path("/dev", sysName))
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: Failed to open block device: %v", name, err)
+	}
+	defer f.Close()
+	signatures, err := probeSignatures(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("🔴 %s: Failed to probe block device: %v", name, err)
+	}
+	return signatures, nil
+}
+
+func (sds *SysfsDeviceService) path(elem ...string) string {
+	return filepath.Join(append([]string{sds.root}, elem...)...)
+}
+
+// A device can be referenced by a symbolic link (e.g. /dev/mapper/<name> -> ../dm-0).
+// The name of the device node that the link resolves to is the name of the device
+// in sysfs
+func (sds *SysfsDeviceService) resolve(name string) (string, error) {
+	p, err := filepath.EvalSymlinks(sds.path(name))
+	if err != nil {
+		return "", fmt.Errorf("🔴 %s: Block device does not exist", name)
+	}
+	sysName := filepath.Base(p)
+	if _, err := os.Stat(sds.path("/sys/class/block", sysName)); err != nil {
+		return "", fmt.Errorf("🔴 %s: Not a block device", name)
+	}
+	return sysName, nil
+}
+
+func (sds *SysfsDeviceService) readAttribute(dir string, attribute string) string {
+	b, err := os.ReadFile(filepath.Join(dir, attribute))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(b))
+}
+
+// The size of a block device is always reported in 512-byte sectors, regardless
+// of the logical block size of the device
+func (sds *SysfsDeviceService) getSize(sysName string) (uint64, error) {
+	s := sds.readAttribute(sds.path("/sys/class/block", sysName), "size")
+	sectors, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("🔴 /dev/%s: Failed to cast block device size to unsigned 64-bit integer", sysName)
+	}
+	return sectors * sectorSize, nil
+}
+
+func (sds *SysfsDeviceService) getDeviceNumber(sysName string) (uint64, uint64, error) {
+	s := sds.readAttribute(sds.path("/sys/class/block", sysName), "dev")
+	major, minor, found := strings.Cut(s, ":")
+	if !found {
+		return 0, 0, fmt.Errorf("🔴 /dev/%s: Failed to decode device number", sysName)
+	}
+	ma, err := strconv.ParseUint(major, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("🔴 /dev/%s: Failed to decode device number", sysName)
+	}
+	mi, err := strconv.ParseUint(minor, 10, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("🔴 /dev/%s: Failed to decode device number", sysName)
+	}
+	return ma, mi, nil
+}
+
+// A partition is nested within the sysfs directory of its parent device and does
+// not have a queue of its own
+func (sds *SysfsDeviceService) getParent(sysName string) (string, bool) {
+	dir := sds.path("/sys/class/block", sysName)
+	if _, err := os.Stat(filepath.Join(dir, "partition")); err != nil {
+		return "", false
+	}
+	p, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", false
+	}
+	return filepath.Base(filepath.Dir(p)), true
+}
+
+func (sds *SysfsDeviceService) getQueue(sysName string) string {
+	if parent, found := sds.getParent(sysName); found {
+		sysName = parent
+	}
+	return sds.path("/sys/class/block", sysName, "queue")
+}
+
+func (sds *SysfsDeviceService) getHolders(sysName string) ([]string, error) {
+	entries, err := os.ReadDir(sds.path("/sys/class/block", sysName, "holders"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	holders := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		holders = append(holders, "/dev/"+entry.Name())
+	}
+	return holders, nil
+}
+
+// getMountPoint retrieves the mount point of a device from /proc/self/mountinfo. The
+// mount is identified by the device number of the block device, or by its source
+// for a file system (e.g. btrfs) that reports an anonymous device number
+//
+//	36 25 259:1 / /mnt/app rw,relatime shared:1 - ext4 /dev/nvme1n1 rw
+//
+// A bind mount of a directory within the file system is only reported when the
+// file system is not mounted in its entirety
+func (sds *SysfsDeviceService) getMountPoint(sysName string) (string, error) {
+	major, minor, err := sds.getDeviceNumber(sysName)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(sds.path("/proc/self/mountinfo"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	dn := fmt.Sprintf("%d:%d", major, minor)
+	mp := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		separator := -1
+		for i, field := range fields {
+			if field == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator < 5 || separator+2 >= len(fields) {
+			return "", fmt.Errorf("🔴 Failed to decode mountinfo")
+		}
+		if fields[2] != dn && !sds.isSource(fields[separator+2], sysName) {
+			continue
+		}
+		if fields[3] == "/" {
+			return unescapeMountInfo(fields[4]), nil
+		}
+		if len(mp) == 0 {
+			mp = unescapeMountInfo(fields[4])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return mp, nil
+}
+
+// An active swap area is not mounted. In the same manner as lsblk, a device that is
+// listed in /proc/swaps is reported with a mount point of [SWAP]
+func (sds *SysfsDeviceService) getSwapMountPoint(sysName string) (string, error) {
+	f, err := os.Open(sds.path("/proc/swaps"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer f.Close()
+	areas, err := parseSwaps(f)
+	if err != nil {
+		return "", err
+	}
+	for _, area := range areas {
+		if sds.isSource(area.Name, sysName) {
+			return model.SwapMountPoint, nil
+		}
+	}
+	return "", nil
+}
+
+func (sds *SysfsDeviceService) isSource(source string, sysName string) bool {
+	if !strings.HasPrefix(source, "/dev/") {
+		return false
+	}
+	if path.Base(source) == sysName {
+		return true
+	}
+	p, err := filepath.EvalSymlinks(sds.path(source))
+	return err == nil && filepath.Base(p) == sysName
+}
+
+// getPartUuid retrieves the unique id of a partition from the partition table of its
+// parent device. The id of a GPT partition is its unique partition GUID. The id of
+// an MBR partition is the disk signature, followed by the number of the partition
+func (sds *SysfsDeviceService) getPartUuid(sysName string) (string, error) {
+	parent, found := sds.getParent(sysName)
+	if !found {
+		return "", nil
+	}
+	number, err := strconv.ParseUint(sds.readAttribute(sds.path("/sys/class/block", sysName), "partition"), 10, 32)
+	if err != nil {
+		return "", fmt.Errorf("🔴 /dev/%s: Failed to decode partition number", sysName)
+	}
+	lbs, err := strconv.ParseInt(sds.readAttribute(sds.path("/sys/class/block", parent, "queue"), "logical_block_size"), 10, 64)
+	if err != nil || lbs == 0 {
+		lbs = sectorSize
+	}
+	f, err := os.Open(sds.path("/dev", parent))
+	if err != nil {
+		return "", fmt.Errorf("🔴 /dev/%s: Failed to open block device: %v", parent, err)
+	}
+	defer f.Close()
+
+	header, err := readAt(f, lbs, 92)
+	if err != nil {
+		return "", err
+	}
+	if header != nil && string(header[:len(gptSignature)]) == gptSignature {
+		entries := int64(binary.LittleEndian.Uint64(header[72:]))
+		count := uint64(binary.LittleEndian.Uint32(header[80:]))
+		size := int64(binary.LittleEndian.Uint32(header[84:]))
+		if number == 0 || number > count {
+			return "", nil
+		}
+		entry, err := readAt(f, entries*lbs+int64(number-1)*size, 32)
+		if err != nil || entry == nil {
+			return "", err
+		}
+		// The first three components of a GUID are stored in little-endian order
+		g := entry[16:32]
+		return fmt.Sprintf("%08x-%04x-%04x-%x-%x",
+			binary.LittleEndian.Uint32(g[0:4]), binary.LittleEndian.Uint16(g[4:6]), binary.LittleEndian.Uint16(g[6:8]), g[8:10], g[10:16]), nil
+	}
+	mbr, err := readAt(f, 0, sectorSize)
+	if err != nil || mbr == nil {
+		return "", err
+	}
+	if binary.LittleEndian.Uint16(mbr[510:]) != mbrSignature {
+		return "", nil
+	}
+	return fmt.Sprintf("%08x-%02x", binary.LittleEndian.Uint32(mbr[440:]), number), nil
+}
+
+// unescapeMountInfo reverses the octal escape sequences (e.g. \040 for a space)
+// that the kernel uses to represent whitespace and backslashes in mountinfo
+func unescapeMountInfo(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				sb.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,531 @@
+package service
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/model"
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+// A block device within a fake sysfs and devtmpfs. The contents of the device
+// node are the leading bytes of the device
+type fakeBlockDevice struct {
+	Name       string
+	Parent     string
+	Partition  uint32
+	Dev        string
+	Sectors    uint64
+	Rotational bool
+	ReadOnly   bool
+	Holders    []string
+	Contents   []byte
+}
+
+func createFakeRoot(t *testing.T, devices []*fakeBlockDevice, mountInfo string, links map[string]string) string {
+	root := t.TempDir()
+	write := func(p string, data []byte) {
+		p = filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link := func(target string, p string) {
+		p = filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	flag := func(b bool) []byte {
+		if b {
+			return []byte("1\n")
+		}
+		return []byte("0\n")
+	}
+	for _, d := range devices {
+		// As in sysfs, a partition is nested within the directory of its parent
+		dir := filepath.Join("/sys/devices/virtual/block", d.Name)
+		if len(d.Parent) > 0 {
+			dir = filepath.Join("/sys/devices/virtual/block", d.Parent, d.Name)
+			write(filepath.Join(dir, "partition"), []byte(fmt.Sprintf("%d\n", d.Partition)))
+		} else {
+			write(filepath.Join(dir, "queue", "rotational"), flag(d.Rotational))
+			write(filepath.Join(dir, "queue", "logical_block_size"), []byte("512\n"))
+			link(filepath.Join("../devices/virtual/block", d.Name), filepath.Join("/sys/block", d.Name))
+		}
+		write(filepath.Join(dir, "dev"), []byte(d.Dev+"\n"))
+		write(filepath.Join(dir, "size"), []byte(fmt.Sprintf("%d\n", d.Sectors)))
+		write(filepath.Join(dir, "ro"), flag(d.ReadOnly))
+		if err := os.MkdirAll(filepath.Join(root, dir, "holders"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, h := range d.Holders {
+			write(filepath.Join(dir, "holders", h), nil)
+		}
+		rel, _ := filepath.Rel(filepath.Join(root, "/sys/class/block"), filepath.Join(root, dir))
+		link(rel, filepath.Join("/sys/class/block", d.Name))
+		write(filepath.Join("/dev", d.Name), d.Contents)
+	}
+	for p, target := range links {
+		link(target, p)
+	}
+	write("/proc/self/mountinfo", []byte(mountInfo))
+	return root
+}
+
+func ext4Contents(label string, uuid []byte) []byte {
+	b := make([]byte, 0x800)
+	binary.LittleEndian.PutUint16(b[ext4MagicOffset:], ext4Magic)
+	binary.LittleEndian.PutUint32(b[ext4IncompatOffset:], ext4IncompatExtents)
+	copy(b[ext4UuidOffset:], uuid)
+	copy(b[ext4LabelOffset:], label)
+	return b
+}
+
+// An ext3 file system has a journal, but none of the features of ext4
+func ext3Contents(label string, uuid []byte) []byte {
+	b := make([]byte, 0x800)
+	binary.LittleEndian.PutUint16(b[ext4MagicOffset:], ext4Magic)
+	binary.LittleEndian.PutUint32(b[ext4CompatOffset:], ext4CompatHasJournal)
+	copy(b[ext4UuidOffset:], uuid)
+	copy(b[ext4LabelOffset:], label)
+	return b
+}
+
+func TestSysfsGetBlockDevices(t *testing.T) {
+	root := createFakeRoot(t, []*fakeBlockDevice{
+		{Name: "nvme0n1", Dev: "259:0", Sectors: 16777216},
+		{Name: "nvme0n1p1", Parent: "nvme0n1", Partition: 1, Dev: "259:1", Sectors: 16775168},
+		{Name: "loop0", Dev: "7:0", Sectors: 0},
+		{Name: "ram0", Dev: "1:0", Sectors: 8192},
+		{Name: "xvdf", Dev: "202:80", Sectors: 2097152},
+	}, "", nil)
+	sds := &SysfsDeviceService{root: root}
+	devices, err := sds.GetBlockDevices()
+	utils.CheckError("sds.GetBlockDevices()", t, nil, err)
+	utils.CheckOutput("sds.GetBlockDevices()", t, []string{"/dev/nvme0n1", "/dev/xvdf"}, devices)
+}
+
+func TestSysfsGetBlockDevice(t *testing.T) {
+	uuid := []byte{0x9a, 0x9e, 0x3d, 0x3c, 0x4d, 0x5b, 0x4b, 0x8f, 0xa1, 0xc2, 0x3f, 0x9e, 0x8d, 0x7c, 0x6b, 0x5a}
+
+	xfs := make([]byte, 0x200)
+	copy(xfs, xfsMagic)
+	copy(xfs[xfsUuidOffset:], uuid)
+	copy(xfs[xfsLabelOffset:], "scratch")
+
+	btrfs := make([]byte, btrfsSuperblockOffset+0x400)
+	copy(btrfs[btrfsMagicOffset:], btrfsMagic)
+	copy(btrfs[btrfsUuidOffset:], uuid)
+	copy(btrfs[btrfsLabelOffset:], "pool")
+
+	luks := make([]byte, 0x200)
+	copy(luks, luksMagic)
+	binary.BigEndian.PutUint16(luks[len(luksMagic):], 2)
+	copy(luks[luksLabelOffset:], "secure")
+	copy(luks[luksUuidOffset:], "4b6e0d38-6b94-4d0f-a3d0-7d6a3e2bd6a4")
+
+	raid := make([]byte, 0x2000)
+	binary.LittleEndian.PutUint32(raid[0x1000:], mdMagic)
+	copy(raid[0x1000+mdUuidOffset:], uuid)
+	copy(raid[0x1000+mdNameOffset:], "ip-10-0-0-1:scratch")
+
+	lvm := make([]byte, 0x800)
+	copy(lvm[sectorSize:], lvmLabel)
+	binary.LittleEndian.PutUint32(lvm[sectorSize+0x14:], 0x20)
+	copy(lvm[sectorSize+0x18:], lvmType)
+	copy(lvm[sectorSize+0x20:], "Xc2Qb1eVq3zN0dLRa8qpDbF5tW7mGx9H")
+
+	// A GPT disk whose first partition has a unique GUID of 0fc63daf-8483-4772-8e79-3d69d8477de4
+	gpt := make([]byte, 0x800)
+	copy(gpt[sectorSize:], gptSignature)
+	binary.LittleEndian.PutUint64(gpt[sectorSize+72:], 2)
+	binary.LittleEndian.PutUint32(gpt[sectorSize+80:], 128)
+	binary.LittleEndian.PutUint32(gpt[sectorSize+84:], 128)
+	copy(gpt[2*sectorSize+16:], []byte{0xaf, 0x3d, 0xc6, 0x0f, 0x83, 0x84, 0x72, 0x47, 0x8e, 0x79, 0x3d, 0x69, 0xd8, 0x47, 0x7d, 0xe4})
+
+	mbr := make([]byte, sectorSize)
+	binary.LittleEndian.PutUint32(mbr[440:], 0x5a3c9e01)
+	binary.LittleEndian.PutUint16(mbr[510:], mbrSignature)
+
+	root := createFakeRoot(t, []*fakeBlockDevice{
+		{Name: "xvdf", Dev: "202:80", Sectors: 2097152, Rotational: true, Contents: ext4Contents(`a "quoted" label`, uuid)},
+		{Name: "xvdg", Dev: "202:96", Sectors: 2097152, ReadOnly: true, Contents: xfs},
+		{Name: "xvdh", Dev: "202:112", Sectors: 2097152, Contents: btrfs},
+		{Name: "xvdi", Dev: "202:128", Sectors: 2097152, Holders: []string{"dm-0"}, Contents: luks},
+		{Name: "xvdj", Dev: "202:144", Sectors: 2097152, Holders: []string{"md127"}, Contents: raid},
+		{Name: "xvdk", Dev: "202:160", Sectors: 2097152, Holders: []string{"dm-1"}, Contents: lvm},
+		{Name: "xvdl", Dev: "202:176", Sectors: 2097152, Contents: make([]byte, 0x20000)},
+		{Name: "xvdm", Dev: "202:192", Sectors: 2097152, Contents: gpt},
+		{Name: "xvdm1", Parent: "xvdm", Partition: 1, Dev: "202:193", Sectors: 2095104, Contents: ext4Contents("root", uuid)},
+		{Name: "xvdn", Dev: "202:208", Sectors: 2097152, Contents: mbr},
+		{Name: "xvdn2", Parent: "xvdn", Partition: 2, Dev: "202:210", Sectors: 1024},
+		{Name: "xvdo", Dev: "202:224", Sectors: 2097152, Contents: ext3Contents("legacy", uuid)},
+		{Name: "dm-0", Dev: "253:0", Sectors: 2093056},
+	}, `22 1 202:193 / / rw,relatime shared:1 - ext4 /dev/xvdm1 rw
+36 22 202:80 / /mnt/app\040data rw,relatime shared:2 - ext4 /dev/xvdf rw
+37 22 202:96 /logs /var/log/app rw,relatime shared:3 - xfs /dev/xvdg rw
+38 22 202:96 / /mnt/scratch rw,relatime shared:4 - xfs /dev/xvdg rw
+39 22 0:45 / /mnt/pool rw,relatime shared:5 - btrfs /dev/xvdh rw
+`, map[string]string{
+		"/dev/mapper/secure": "../dm-0",
+	})
+	subtests := []struct {
+		Name           string
+		Device         string
+		ExpectedOutput *model.BlockDevice
+		ExpectedError  error
+	}{
+		{
+			Name:   "ext4 With Quoted Label",
+			Device: "/dev/xvdf",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdf",
+				MountPoint: "/mnt/app data",
+				FileSystem: model.Ext4,
+				Label:      `a "quoted" label`,
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Size:       1073741824,
+				Rotational: true,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "xfs With Bind Mount",
+			Device: "/dev/xvdg",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdg",
+				MountPoint: "/mnt/scratch",
+				FileSystem: model.Xfs,
+				Label:      "scratch",
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Size:       1073741824,
+				ReadOnly:   true,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "btrfs With Anonymous Device Number",
+			Device: "/dev/xvdh",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdh",
+				MountPoint: "/mnt/pool",
+				FileSystem: model.Btrfs,
+				Label:      "pool",
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Size:       1073741824,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "LUKS2 Device",
+			Device: "/dev/xvdi",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdi",
+				FileSystem: model.Luks,
+				Label:      "secure",
+				UUID:       "4b6e0d38-6b94-4d0f-a3d0-7d6a3e2bd6a4",
+				Size:       1073741824,
+				Holders:    []string{"/dev/dm-0"},
+			},
+		},
+		{
+			Name:   "RAID Member",
+			Device: "/dev/xvdj",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdj",
+				FileSystem: model.RaidMember,
+				Label:      "ip-10-0-0-1:scratch",
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Size:       1073741824,
+				Holders:    []string{"/dev/md127"},
+			},
+		},
+		{
+			Name:   "LVM Physical Volume",
+			Device: "/dev/xvdk",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdk",
+				FileSystem: model.Lvm,
+				UUID:       "Xc2Qb1-eVq3-zN0d-LRa8-qpDb-F5tW-7mGx9H",
+				Size:       1073741824,
+				Holders:    []string{"/dev/dm-1"},
+			},
+		},
+		{
+			Name:   "Unformatted",
+			Device: "/dev/xvdl",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdl",
+				FileSystem: model.Unformatted,
+				Size:       1073741824,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "GPT Partition",
+			Device: "/dev/xvdm1",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdm1",
+				MountPoint: "/",
+				FileSystem: model.Ext4,
+				Label:      "root",
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				PartUUID:   "0fc63daf-8483-4772-8e79-3d69d8477de4",
+				Size:       1072693248,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "MBR Partition",
+			Device: "/dev/xvdn2",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdn2",
+				FileSystem: model.Unformatted,
+				PartUUID:   "5a3c9e01-02",
+				Size:       524288,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "Unsupported ext3",
+			Device: "/dev/xvdo",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/xvdo",
+				FileSystem: model.Unknown,
+				Label:      "legacy",
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Size:       1073741824,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "Symbolic Link",
+			Device: "/dev/mapper/secure",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/mapper/secure",
+				FileSystem: model.Unformatted,
+				Size:       1071644672,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:           "Device Does Not Exist",
+			Device:         "/dev/xvdz",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdz: Block device does not exist"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sds := &SysfsDeviceService{root: root}
+			bd, err := sds.GetBlockDevice(subtest.Device)
+			utils.CheckError("sds.GetBlockDevice()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("sds.GetBlockDevice()", t, subtest.ExpectedOutput, bd)
+		})
+	}
+}
+
+func TestSysfsGetBlockDeviceSwap(t *testing.T) {
+	uuid := []byte{0x9a, 0x9e, 0x3d, 0x3c, 0x4d, 0x5b, 0x4b, 0x8f, 0xa1, 0xc2, 0x3f, 0x9e, 0x8d, 0x7c, 0x6b, 0x5a}
+	root := createFakeRoot(t, []*fakeBlockDevice{
+		{Name: "nvme1n1", Dev: "259:2", Sectors: 2097152, Contents: swapContents("swap", uuid, 262143)},
+		{Name: "nvme2n1", Dev: "259:3", Sectors: 2097152, Contents: swapContents("", uuid, 262143)},
+	}, "", nil)
+	// Much like lsblk, an active swap area is reported as mounted to [SWAP]
+	swaps := "Filename\t\t\t\tType\t\tSize\t\tUsed\t\tPriority\n/dev/nvme1n1                            partition\t1048572\t\t0\t\t-2\n"
+	if err := os.WriteFile(filepath.Join(root, "/proc/swaps"), []byte(swaps), 0644); err != nil {
+		t.Fatal(err)
+	}
+	subtests := []struct {
+		Name           string
+		Device         string
+		ExpectedOutput *model.BlockDevice
+	}{
+		{
+			Name:   "Active Swap Area",
+			Device: "/dev/nvme1n1",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/nvme1n1",
+				MountPoint: model.SwapMountPoint,
+				FileSystem: model.Swap,
+				Label:      "swap",
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Size:       1073741824,
+				Holders:    []string{},
+			},
+		},
+		{
+			Name:   "Inactive Swap Area",
+			Device: "/dev/nvme2n1",
+			ExpectedOutput: &model.BlockDevice{
+				Name:       "/dev/nvme2n1",
+				FileSystem: model.Swap,
+				UUID:       "9a9e3d3c-4d5b-4b8f-a1c2-3f9e8d7c6b5a",
+				Size:       1073741824,
+				Holders:    []string{},
+			},
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sds := &SysfsDeviceService{root: root}
+			bd, err := sds.GetBlockDevice(subtest.Device)
+			utils.CheckError("sds.GetBlockDevice()", t, nil, err)
+			utils.CheckOutput("sds.GetBlockDevice()", t, subtest.ExpectedOutput, bd)
+		})
+	}
+}
+
+func TestSysfsMount(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		Options       model.MountOptions
+		RunnerArgs    []string
+		RunnerError   error
+		ExpectedError error
+	}{
+		{
+			Name:          "mount=success",
+			Options:       model.MountOptions("defaults,nofail"),
+			RunnerArgs:    []string{"/dev/xvdf", "-t", "xfs", "-o", "defaults,nofail", "/mnt/app"},
+			RunnerError:   nil,
+			ExpectedError: nil,
+		},
+		{
+			Name:          "mount=failure",
+			Options:       model.MountOptions("defaults"),
+			RunnerArgs:    []string{"/dev/xvdf", "-t", "xfs", "-o", "defaults", "/mnt/app"},
+			RunnerError:   fmt.Errorf("🔴 mount: /mnt/app: special device /dev/xvdf does not exist"),
+			ExpectedError: fmt.Errorf("🔴 mount: /mnt/app: special device /dev/xvdf does not exist"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			mrf := utils.NewMockRunnerFactory(utils.Mount, subtest.RunnerArgs, "", subtest.RunnerError)
+			sds := NewSysfsDeviceService(mrf)
+			err := sds.Mount("/dev/xvdf", "/mnt/app", model.Xfs, subtest.Options)
+			utils.CheckErrorGlob("sds.Mount()", t, subtest.ExpectedError, err)
+		})
+	}
+}
+
+func TestSysfsUmount(t *testing.T) {
+	mrf := utils.NewMockRunnerFactory(utils.Umount, []string{"/mnt/app"}, "", nil)
+	sds := NewSysfsDeviceService(mrf)
+	err := sds.Umount("/dev/xvdf", "/mnt/app")
+	utils.CheckError("sds.Umount()", t, nil, err)
+}
+
+func TestSysfsGetSignatures(t *testing.T) {
+	uuid := []byte{0x9a, 0x9e, 0x3d, 0x3c, 0x4d, 0x5b, 0x4b, 0x8f, 0xa1, 0xc2, 0x3f, 0x9e, 0x8d, 0x7c, 0x6b, 0x5a}
+
+	// A GPT partition table is preceded by a protective MBR and is backed up
+	// to the last sector of the device
+	gpt := make([]byte, 64*sectorSize)
+	gpt[mbrPartitionOffset+4] = mbrProtectiveType
+	binary.LittleEndian.PutUint16(gpt[mbrSignatureOffset:], mbrSignature)
+	copy(gpt[gptHeaderOffset:], gptSignature)
+	copy(gpt[len(gpt)-sectorSize:], gptSignature)
+
+	// A boot sector without any partition entries is not a partition table
+	boot := make([]byte, 8*sectorSize)
+	binary.LittleEndian.PutUint16(boot[mbrSignatureOffset:], mbrSignature)
+
+	swap := make([]byte, 8*sectorSize)
+	copy(swap[swapMagicOffset:], "SWAPSPACE2")
+
+	root := createFakeRoot(t, []*fakeBlockDevice{
+		{Name: "xvdf", Dev: "202:80", Sectors: 64, Contents: gpt},
+		{Name: "xvdg", Dev: "202:96", Sectors: 8, Contents: ext4Contents("data", uuid)},
+		{Name: "xvdh", Dev: "202:112", Sectors: 8, Contents: boot},
+		{Name: "xvdi", Dev: "202:128", Sectors: 8, Contents: swap},
+	}, "", nil)
+
+	subtests := []struct {
+		Name           string
+		Device         string
+		ExpectedOutput []*model.Signature
+		ExpectedError  error
+	}{
+		{
+			Name:   "GPT Partition Table",
+			Device: "/dev/xvdf",
+			ExpectedOutput: []*model.Signature{
+				{Type: "PMBR", Offset: mbrSignatureOffset},
+				{Type: "gpt", Offset: gptHeaderOffset},
+				{Type: "gpt", Offset: 63 * sectorSize},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:   "ext4 File System",
+			Device: "/dev/xvdg",
+			ExpectedOutput: []*model.Signature{
+				{Type: "ext4", Offset: ext4MagicOffset},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "Empty Boot Sector",
+			Device:         "/dev/xvdh",
+			ExpectedOutput: []*model.Signature{},
+			ExpectedError:  nil,
+		},
+		{
+			Name:   "Swap Area",
+			Device: "/dev/xvdi",
+			ExpectedOutput: []*model.Signature{
+				{Type: "swap", Offset: swapMagicOffset},
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:           "Device Does Not Exist",
+			Device:         "/dev/xvdz",
+			ExpectedOutput: nil,
+			ExpectedError:  fmt.Errorf("🔴 /dev/xvdz: Block device does not exist"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			sds := &SysfsDeviceService{root: root}
+			signatures, err := sds.GetSignatures(subtest.Device)
+			utils.CheckError("sds.GetSignatures()", t, subtest.ExpectedError, err)
+			utils.CheckOutput("sds.GetSignatures()", t, subtest.ExpectedOutput, signatures)
+		})
+	}
+}
+
+func TestSysfsWipe(t *testing.T) {
+	gpt := make([]byte, 64*sectorSize)
+	gpt[mbrPartitionOffset+4] = mbrProtectiveType
+	binary.LittleEndian.PutUint16(gpt[mbrSignatureOffset:], mbrSignature)
+	copy(gpt[gptHeaderOffset:], gptSignature)
+	copy(gpt[len(gpt)-sectorSize:], gptSignature)
+
+	root := createFakeRoot(t, []*fakeBlockDevice{
+		{Name: "xvdf", Dev: "202:80", Sectors: 64, Contents: gpt},
+	}, "", nil)
+	sds := &SysfsDeviceService{root: root}
+	err := sds.Wipe("/dev/xvdf")
+	utils.CheckError("sds.Wipe()", t, nil, err)
+
+	signatures, err := sds.GetSignatures("/dev/xvdf")
+	utils.CheckError("sds.GetSignatures()", t, nil, err)
+	utils.CheckOutput("sds.GetSignatures()", t, []*model.Signature{}, signatures)
+
+	// Only the magic strings are erased. The partition entries are left untouched
+	b, err := os.ReadFile(filepath.Join(root, "/dev/xvdf"))
+	utils.CheckError("os.ReadFile()", t, nil, err)
+	utils.CheckOutput("b[mbrPartitionOffset+4]", t, byte(mbrProtectiveType), b[mbrPartitionOffset+4])
+}
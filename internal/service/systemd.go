@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+type SystemdService interface {
+	DaemonReload() error
+	EnableUnit(name string) error
+}
+
+type LinuxSystemdService struct {
+	runnerFactory utils.RunnerFactory
+}
+
+func NewLinuxSystemdService(rf utils.RunnerFactory) *LinuxSystemdService {
+	return &LinuxSystemdService{
+		runnerFactory: rf,
+	}
+}
+
+func (lss *LinuxSystemdService) DaemonReload() error {
+	r := lss.runnerFactory.Select(utils.Systemctl)
+	_, err := r.Command("daemon-reload")
+	return err
+}
+
+func (lss *LinuxSystemdService) EnableUnit(name string) error {
+	r := lss.runnerFactory.Select(utils.Systemctl)
+	_, err := r.Command("enable", name)
+	return err
+}
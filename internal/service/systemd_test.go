@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/reecetech/ebs-bootstrap/internal/utils"
+)
+
+func TestLinuxSystemdService(t *testing.T) {
+	subtests := []struct {
+		Name          string
+		RunnerFactory utils.RunnerFactory
+		Call          func(lss *LinuxSystemdService) error
+		ExpectedError error
+	}{
+		{
+			Name:          "Daemon Reload",
+			RunnerFactory: utils.NewMockRunnerFactory(utils.Systemctl, []string{"daemon-reload"}, "", nil),
+			Call: func(lss *LinuxSystemdService) error {
+				return lss.DaemonReload()
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "Enable Unit",
+			RunnerFactory: utils.NewMockRunnerFactory(utils.Systemctl, []string{"enable", "mnt-ebs.mount"}, "", nil),
+			Call: func(lss *LinuxSystemdService) error {
+				return lss.EnableUnit("mnt-ebs.mount")
+			},
+			ExpectedError: nil,
+		},
+		{
+			Name:          "Enable Unit + Error",
+			RunnerFactory: utils.NewMockRunnerFactory(utils.Systemctl, []string{"enable", "mnt-ebs.mount"}, "", fmt.Errorf("🔴 exit status 1: Failed to enable unit")),
+			Call: func(lss *LinuxSystemdService) error {
+				return lss.EnableUnit("mnt-ebs.mount")
+			},
+			ExpectedError: fmt.Errorf("🔴 exit status 1: Failed to enable unit"),
+		},
+	}
+	for _, subtest := range subtests {
+		t.Run(subtest.Name, func(t *testing.T) {
+			lss := NewLinuxSystemdService(subtest.RunnerFactory)
+			err := subtest.Call(lss)
+			utils.CheckError("lss", t, subtest.ExpectedError, err)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"slices"
@@ -10,27 +11,41 @@ import (
 type Binary string
 
 const (
-	Lsblk     Binary = "lsblk"
-	MkfsExt4  Binary = "mkfs.ext4"
-	E2Label   Binary = "e2label"
-	MkfsXfs   Binary = "mkfs.xfs"
-	XfsAdmin  Binary = "xfs_admin"
-	Mount     Binary = "mount"
-	Umount    Binary = "umount"
-	BlockDev  Binary = "blockdev"
-	Tune2fs   Binary = "tune2fs"
-	XfsInfo   Binary = "xfs_info"
-	Resize2fs Binary = "resize2fs"
-	XfsGrowfs Binary = "xfs_growfs"
-	Pvs       Binary = "pvs"
-	PvCreate  Binary = "pvcreate"
-	PvResize  Binary = "pvresize"
-	Vgs       Binary = "vgs"
-	VgCreate  Binary = "vgcreate"
-	Lvs       Binary = "lvs"
-	LvCreate  Binary = "lvcreate"
-	LvChange  Binary = "lvchange"
-	LvExtend  Binary = "lvextend"
+	Lsblk      Binary = "lsblk"
+	MkfsExt4   Binary = "mkfs.ext4"
+	E2Label    Binary = "e2label"
+	MkfsXfs    Binary = "mkfs.xfs"
+	XfsAdmin   Binary = "xfs_admin"
+	Mount      Binary = "mount"
+	Umount     Binary = "umount"
+	BlockDev   Binary = "blockdev"
+	Tune2fs    Binary = "tune2fs"
+	XfsInfo    Binary = "xfs_info"
+	Resize2fs  Binary = "resize2fs"
+	XfsGrowfs  Binary = "xfs_growfs"
+	Pvs        Binary = "pvs"
+	PvCreate   Binary = "pvcreate"
+	PvResize   Binary = "pvresize"
+	Vgs        Binary = "vgs"
+	VgCreate   Binary = "vgcreate"
+	VgExtend   Binary = "vgextend"
+	Lvs        Binary = "lvs"
+	LvCreate   Binary = "lvcreate"
+	LvChange   Binary = "lvchange"
+	LvExtend   Binary = "lvextend"
+	Systemctl  Binary = "systemctl"
+	MkfsBtrfs  Binary = "mkfs.btrfs"
+	Btrfs      Binary = "btrfs"
+	Mdadm      Binary = "mdadm"
+	Cryptsetup Binary = "cryptsetup"
+	WipeFs     Binary = "wipefs"
+	E2fsck     Binary = "e2fsck"
+	XfsRepair  Binary = "xfs_repair"
+	Mkswap     Binary = "mkswap"
+	Swapon     Binary = "swapon"
+	Swapoff    Binary = "swapoff"
+	Swaplabel  Binary = "swaplabel"
+	Fallocate  Binary = "fallocate"
 )
 
 type RunnerFactory interface {
@@ -60,6 +75,26 @@ func (mrf *MockRunnerFactory) Select(binary Binary) Runner {
 	return NewMockRunner(mrf.expectedArgs, mrf.output, mrf.err)
 }
 
+// MockMultiRunnerFactory is suitable for services that invoke more than one
+// binary. Each binary is paired with its own MockRunner
+type MockMultiRunnerFactory struct {
+	runners map[Binary]*MockRunner
+}
+
+func NewMockMultiRunnerFactory(runners map[Binary]*MockRunner) *MockMultiRunnerFactory {
+	return &MockMultiRunnerFactory{
+		runners: runners,
+	}
+}
+
+func (mmrf *MockMultiRunnerFactory) Select(binary Binary) Runner {
+	r, exists := mmrf.runners[binary]
+	if !exists {
+		return NewMockRunner(nil, "", fmt.Errorf("🔴 Unexpected Binary encountered: %s", binary))
+	}
+	return r
+}
+
 type ExecRunnerFactory struct {
 	runners map[Binary]*ExecRunner
 }
@@ -129,11 +164,33 @@ func (er *ExecRunner) Command(arg ...string) (string, error) {
 	o, err := cmd.CombinedOutput()
 	output := strings.TrimRight(string(o), "\n")
 	if err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && ee.Exited() {
+			return "", NewExitError(ee.ExitCode(), output)
+		}
 		return "", fmt.Errorf("🔴 %s: %s", err, output)
 	}
 	return output, err
 }
 
+// ExitError preserves the exit code of a binary that exited unsuccessfully. Certain
+// binaries (e.g. e2fsck) encode the outcome of an operation in their exit code
+type ExitError struct {
+	Code   int
+	Output string
+}
+
+func NewExitError(code int, output string) *ExitError {
+	return &ExitError{
+		Code:   code,
+		Output: output,
+	}
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("🔴 exit status %d: %s", e.Code, e.Output)
+}
+
 func (er *ExecRunner) isValid() bool {
 	if !er.isValidated {
 		_, err := er.lookPath(string(er.binary))